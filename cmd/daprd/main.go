@@ -11,6 +11,7 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/dapr/dapr/pkg/logsampler"
 	"github.com/dapr/dapr/pkg/runtime"
 	"github.com/dapr/dapr/pkg/version"
 	"github.com/dapr/kit/logger"
@@ -120,11 +121,15 @@ import (
 	http_middleware_loader "github.com/dapr/dapr/pkg/components/middleware/http"
 	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
 	"github.com/valyala/fasthttp"
+
+	// gRPC Middleware
+	grpc_middleware_loader "github.com/dapr/dapr/pkg/grpc"
+	grpc_middleware "github.com/dapr/dapr/pkg/middleware/grpc"
 )
 
 var (
 	log        = logger.NewLogger("dapr.runtime")
-	logContrib = logger.NewLogger("dapr.contrib")
+	logContrib = logsampler.NewLogger(logger.NewLogger("dapr.contrib"))
 )
 
 func main() {
@@ -431,6 +436,9 @@ func main() {
 				return handler
 			}),
 		),
+		runtime.WithGRPCMiddleware(
+			grpc_middleware_loader.NewMiddleware(grpc_middleware.RateLimitName, grpc_middleware.NewRateLimitMiddleware),
+		),
 	)
 	if err != nil {
 		log.Fatalf("fatal error from runtime: %s", err)
@@ -11,9 +11,11 @@ import (
 
 	"k8s.io/klog"
 
+	dapr_config "github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/metrics"
 	"github.com/dapr/dapr/pkg/operator"
 	"github.com/dapr/dapr/pkg/operator/monitoring"
+	"github.com/dapr/dapr/pkg/profiling"
 	"github.com/dapr/dapr/pkg/signals"
 	"github.com/dapr/dapr/pkg/version"
 	"github.com/dapr/kit/logger"
@@ -23,6 +25,8 @@ var log = logger.NewLogger("dapr.operator")
 var config string
 var certChainPath string
 var disableLeaderElection bool
+var webhookCertDir string
+var profilingEndpoint string
 
 const (
 	defaultCredentialsPath = "/var/run/dapr/credentials"
@@ -36,7 +40,15 @@ func main() {
 	log.Infof("starting Dapr Operator -- version %s -- commit %s", version.Version(), version.Commit())
 
 	ctx := signals.Context()
-	operator.NewOperator(config, certChainPath, !disableLeaderElection).Run(ctx)
+	if profilingEndpoint != "" {
+		pusher, err := profiling.NewPusher("dapr-operator", nil, dapr_config.ProfilingSpec{Endpoint: profilingEndpoint})
+		if err != nil {
+			log.Warnf("failed to start continuous profiling: %s", err)
+		} else {
+			go pusher.Run(ctx)
+		}
+	}
+	operator.NewOperator(config, certChainPath, !disableLeaderElection, webhookCertDir).Run(ctx)
 
 	shutdownDuration := 5 * time.Second
 	log.Infof("allowing %s for graceful shutdown to complete", shutdownDuration)
@@ -59,6 +71,8 @@ func init() {
 	flag.StringVar(&certChainPath, "certchain", defaultCredentialsPath, "Path to the credentials directory holding the cert chain")
 
 	flag.BoolVar(&disableLeaderElection, "disable-leader-election", false, "Disable leader election for controller manager. ")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "", "Path to the directory holding the tls.crt and tls.key of the component validation webhook. The webhook is disabled when empty")
+	flag.StringVar(&profilingEndpoint, "profiling-endpoint", "", "The Pyroscope compatible endpoint the profiles are continuously pushed to. Continuous profiling is disabled when empty")
 
 	flag.Parse()
 
@@ -28,6 +28,7 @@ type config struct {
 	raftPeers        []raft.PeerInfo
 	raftInMemEnabled bool
 	raftLogStorePath string
+	raftSnapshotOpts raft.SnapshotOptions
 
 	// Placement server configurations
 	placementPort int
@@ -36,6 +37,10 @@ type config struct {
 	tlsEnabled    bool
 
 	replicationFactor int
+	namespaceTenancy  bool
+
+	// Continuous profiling endpoint, disabled when empty
+	profilingEndpoint string
 
 	// Log and metrics configurations
 	loggerOptions   logger.Options
@@ -61,11 +66,16 @@ func newConfig() *config {
 	flag.StringVar(&cfg.raftPeerString, "initial-cluster", cfg.raftPeerString, "raft cluster peers")
 	flag.BoolVar(&cfg.raftInMemEnabled, "inmem-store-enabled", cfg.raftInMemEnabled, "Enable in-memory log and snapshot store unless --raft-logstore-path is set")
 	flag.StringVar(&cfg.raftLogStorePath, "raft-logstore-path", cfg.raftLogStorePath, "raft log store path.")
+	flag.DurationVar(&cfg.raftSnapshotOpts.Interval, "raft-snapshot-interval", cfg.raftSnapshotOpts.Interval, "Interval to take snapshots of the placement state, 0 to only take the raft log compaction snapshots")
+	flag.StringVar(&cfg.raftSnapshotOpts.ExportPath, "raft-snapshot-export-path", cfg.raftSnapshotOpts.ExportPath, "Directory the leader exports the periodic snapshots to")
+	flag.StringVar(&cfg.raftSnapshotOpts.RestorePath, "raft-snapshot-restore-path", cfg.raftSnapshotOpts.RestorePath, "Exported snapshot file to restore when the placement cluster starts without state")
 	flag.IntVar(&cfg.placementPort, "port", cfg.placementPort, "sets the gRPC port for the placement service")
 	flag.IntVar(&cfg.healthzPort, "healthz-port", cfg.healthzPort, "sets the HTTP port for the healthz server")
 	flag.StringVar(&cfg.certChainPath, "certchain", cfg.certChainPath, "Path to the credentials directory holding the cert chain")
 	flag.BoolVar(&cfg.tlsEnabled, "tls-enabled", cfg.tlsEnabled, "Should TLS be enabled for the placement gRPC server")
 	flag.IntVar(&cfg.replicationFactor, "replicationFactor", defaultReplicationFactor, "sets the replication factor for actor distribution on vnodes")
+	flag.BoolVar(&cfg.namespaceTenancy, "namespace-tenancy", false, "Compute and disseminate the actor placement tables per namespace of the Dapr runtimes")
+	flag.StringVar(&cfg.profilingEndpoint, "profiling-endpoint", "", "The Pyroscope compatible endpoint the profiles are continuously pushed to. Continuous profiling is disabled when empty")
 
 	cfg.loggerOptions = logger.DefaultOptions()
 	cfg.loggerOptions.AttachCmdFlags(flag.StringVar, flag.BoolVar)
@@ -13,6 +13,7 @@ import (
 	"syscall"
 	"time"
 
+	dapr_config "github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/fswatcher"
 	"github.com/dapr/dapr/pkg/health"
@@ -20,6 +21,7 @@ import (
 	"github.com/dapr/dapr/pkg/placement/hashing"
 	"github.com/dapr/dapr/pkg/placement/monitoring"
 	"github.com/dapr/dapr/pkg/placement/raft"
+	"github.com/dapr/dapr/pkg/profiling"
 	"github.com/dapr/dapr/pkg/version"
 	"github.com/dapr/kit/logger"
 )
@@ -49,8 +51,17 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if cfg.profilingEndpoint != "" {
+		pusher, err := profiling.NewPusher("dapr-placement", nil, dapr_config.ProfilingSpec{Endpoint: cfg.profilingEndpoint})
+		if err != nil {
+			log.Warnf("failed to start continuous profiling: %s", err)
+		} else {
+			go pusher.Run(context.Background())
+		}
+	}
+
 	// Start Raft cluster.
-	raftServer := raft.New(cfg.raftID, cfg.raftInMemEnabled, cfg.raftPeers, cfg.raftLogStorePath)
+	raftServer := raft.New(cfg.raftID, cfg.raftInMemEnabled, cfg.raftPeers, cfg.raftLogStorePath, cfg.raftSnapshotOpts)
 	if raftServer == nil {
 		log.Fatal("failed to create raft server.")
 	}
@@ -61,7 +72,7 @@ func main() {
 
 	// Start Placement gRPC server.
 	hashing.SetReplicationFactor(cfg.replicationFactor)
-	apiServer := placement.NewPlacementService(raftServer)
+	apiServer := placement.NewPlacementService(raftServer, cfg.namespaceTenancy)
 	var certChain *credentials.CertChain
 	if cfg.tlsEnabled {
 		certChain = loadCertChains(cfg.certChainPath)
@@ -136,6 +147,11 @@ func loadCertChains(certChainPath string) *credentials.CertChain {
 	}
 
 	log.Info("tls certificates loaded successfully")
+	if expiry, err := chain.CertExpiry(); err != nil {
+		log.Warnf("failed to read the issuer cert expiry: %s", err)
+	} else {
+		monitoring.RecordIssuerCertExpiry(expiry)
+	}
 
 	return chain
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/dapr/dapr/pkg/fswatcher"
 	"github.com/dapr/dapr/pkg/health"
 	"github.com/dapr/dapr/pkg/metrics"
+	"github.com/dapr/dapr/pkg/profiling"
 	"github.com/dapr/dapr/pkg/sentry"
 	"github.com/dapr/dapr/pkg/sentry/config"
 	"github.com/dapr/dapr/pkg/sentry/monitoring"
@@ -33,12 +34,21 @@ const (
 	defaultDaprSystemConfigName = "daprsystem"
 
 	healthzPort = 8080
+
+	defaultCRLPort = 8082
 )
 
 func main() {
 	configName := flag.String("config", defaultDaprSystemConfigName, "Path to config file, or name of a configuration object")
 	credsPath := flag.String("issuer-credentials", defaultCredentialsPath, "Path to the credentials directory holding the issuer data")
 	trustDomain := flag.String("trust-domain", "localhost", "The CA trust domain")
+	crlPort := flag.Int("crl-port", defaultCRLPort, "The port the certificate revocation list is served on. Set to 0 to disable")
+	crlURL := flag.String("crl-url", "", "The CRL distribution URL embedded in workload certificates")
+	signer := flag.String("signer", "", "The external CA issuing workload certificates, e.g. vault. Leave empty to use the built-in issuer")
+	signerAddress := flag.String("signer-address", "", "The address of the external CA")
+	signerPath := flag.String("signer-path", "", "The path of the signing endpoint of the external CA, e.g. pki/sign/dapr")
+	signerTokenFile := flag.String("signer-token-file", "", "Path to the file holding the token used to authenticate with the external CA")
+	auditSink := flag.String("audit-log", "", "Where the audit events of the processed CSRs are written, e.g. stdout. Leave empty to disable")
 
 	loggerOptions := logger.DefaultOptions()
 	loggerOptions.AttachCmdFlags(flag.StringVar, flag.BoolVar)
@@ -81,6 +91,23 @@ func main() {
 	config.IssuerKeyPath = issuerKeyPath
 	config.RootCertPath = rootCertPath
 	config.TrustDomain = *trustDomain
+	config.RevokedSerialsPath = filepath.Join(*credsPath, credentials.RevokedSerialsFilename)
+	config.CRLPort = *crlPort
+	config.CRLDistributionURL = *crlURL
+	config.Signer = *signer
+	config.SignerAddress = *signerAddress
+	config.SignerPath = *signerPath
+	config.SignerTokenPath = *signerTokenFile
+	config.AuditSink = *auditSink
+
+	if config.Profiling.Endpoint != "" {
+		pusher, err := profiling.NewPusher("dapr-sentry", nil, config.Profiling)
+		if err != nil {
+			log.Warnf("failed to start continuous profiling: %s", err)
+		} else {
+			go pusher.Run(ctx)
+		}
+	}
 
 	watchDir := filepath.Dir(config.IssuerCertPath)
 
@@ -37,6 +37,10 @@ type actor struct {
 	// lastUsedTime is the time when the last actor call holds lock. This is used to calculate
 	// the duration of ongoing calls to time out.
 	lastUsedTime time.Time
+	// idleTimeoutExtension is added to the idle timeout when the app asks to postpone the deactivation.
+	idleTimeoutExtension atomic.Duration
+	// deactivationWarned is true when the app was warned of the deactivation since the last actor call.
+	deactivationWarned atomic.Bool
 
 	// disposed is true when actor is already disposed.
 	disposed bool
@@ -73,6 +77,18 @@ func (a *actor) channel() chan struct{} {
 
 // lock holds the lock for turn-based concurrency.
 func (a *actor) lock() error {
+	if err := a.lockTurn(); err != nil {
+		return err
+	}
+	a.lastUsedTime = time.Now().UTC()
+	a.idleTimeoutExtension.Store(0)
+	a.deactivationWarned.Store(false)
+	return nil
+}
+
+// lockTurn holds the lock for turn-based concurrency without marking the actor as used, for the
+// calls of the runtime to the actor that must not postpone its deactivation.
+func (a *actor) lockTurn() error {
 	pending := a.pendingActorCalls.Inc()
 	diag.DefaultMonitoring.ReportActorPendingCalls(a.actorType, pending)
 	a.concurrencyLock.Lock()
@@ -80,10 +96,20 @@ func (a *actor) lock() error {
 		a.unlock()
 		return ErrActorDisposed
 	}
-	a.lastUsedTime = time.Now().UTC()
 	return nil
 }
 
+// markDeactivationWarned returns true if the app was not warned of the deactivation yet since the last actor call.
+func (a *actor) markDeactivationWarned() bool {
+	return a.deactivationWarned.CAS(false, true)
+}
+
+// extendIdleTimeout postpones the deactivation of the idle actor and allows the app to be warned again.
+func (a *actor) extendIdleTimeout(d time.Duration) {
+	a.idleTimeoutExtension.Add(d)
+	a.deactivationWarned.Store(false)
+}
+
 // unlock releases the lock for turn-based concurrency. If disposeCh is available,
 // it will close the channel to notify runtime to dispose actor.
 func (a *actor) unlock() {
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	nethttp "net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"github.com/cenkalti/backoff/v4"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
+	"go.uber.org/atomic"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -24,11 +26,13 @@ import (
 	"github.com/dapr/components-contrib/state"
 	"github.com/dapr/dapr/pkg/actors/internal"
 	"github.com/dapr/dapr/pkg/channel"
+	"github.com/dapr/dapr/pkg/concurrency"
 	"github.com/dapr/dapr/pkg/config"
 	dapr_credentials "github.com/dapr/dapr/pkg/credentials"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	"github.com/dapr/dapr/pkg/health"
+	"github.com/dapr/dapr/pkg/logsampler"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	"github.com/dapr/dapr/pkg/modes"
 	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
@@ -40,9 +44,17 @@ import (
 const (
 	daprSeparator        = "||"
 	metadataPartitionKey = "partitionKey"
+	metadataTTLInSeconds = "ttlInSeconds"
+
+	// trigger types of the actor trigger delay metric.
+	triggerTypeReminder = "reminder"
+	triggerTypeTimer    = "timer"
 )
 
-var log = logger.NewLogger("dapr.runtime.actor")
+// broadcastMetadata marks the invocation of a broadcast fanned out to this host by another host.
+const broadcastMetadata = "dapr-actor-broadcast"
+
+var log = logsampler.NewLogger(logger.NewLogger("dapr.runtime.actor"))
 
 // Actors allow calling into virtual actors as well as actor state management
 type Actors interface {
@@ -58,6 +70,13 @@ type Actors interface {
 	DeleteTimer(ctx context.Context, req *DeleteTimerRequest) error
 	IsActorHosted(ctx context.Context, req *ActorHostedRequest) bool
 	GetActiveActorsCount(ctx context.Context) []ActiveActorsCount
+	IsPlacementConnected() bool
+	IsPlacementTableReady() bool
+	GetActorStateKey(storeName, actorType, actorID, key string) (string, error)
+	Drain()
+	Undrain()
+	GetDrainStatus(ctx context.Context) DrainStatus
+	Broadcast(ctx context.Context, req *BroadcastRequest) (*BroadcastResponse, error)
 }
 
 type actorsRuntime struct {
@@ -80,6 +99,10 @@ type actorsRuntime struct {
 	appHealthy          bool
 	certChain           *dapr_credentials.CertChain
 	tracingSpec         config.TracingSpec
+	draining            atomic.Bool
+	drained             atomic.Bool
+	// drainEpoch is incremented by each drain and undrain, so that the drain loop of an undrained runtime stops.
+	drainEpoch atomic.Int64
 }
 
 // ActiveActorsCount contain actorType and count of actors each type has
@@ -88,6 +111,13 @@ type ActiveActorsCount struct {
 	Count int    `json:"count"`
 }
 
+// DrainStatus reports the progress of draining the actors hosted by the runtime.
+type DrainStatus struct {
+	Draining        bool                `json:"draining"`
+	Completed       bool                `json:"completed"`
+	RemainingActors []ActiveActorsCount `json:"remainingActors"`
+}
+
 const (
 	incompatibleStateStore = "state store does not support transactions which actors require to save state - please see https://docs.dapr.io/operations/components/setup-state-store/supported-state-stores/"
 )
@@ -152,11 +182,12 @@ func (a *actorsRuntime) Init() error {
 		a.drainRebalancedActors()
 		a.evaluateReminders()
 	}
-	appHealthFn := func() bool { return a.appHealthy }
+	// A draining runtime reports itself unhealthy so that placement moves its actors to other hosts.
+	appHealthFn := func() bool { return a.isAppHealthy() && !a.draining.Load() }
 
 	a.placement = internal.NewActorPlacement(
 		a.config.PlacementAddresses, a.certChain,
-		a.config.AppID, hostname, a.config.HostedActorTypes,
+		a.config.AppID, a.config.Namespace, hostname, a.config.HostedActorTypes, a.config.PlacementHints,
 		appHealthFn,
 		afterTableUpdateFn)
 
@@ -170,21 +201,31 @@ func (a *actorsRuntime) Init() error {
 	// disconnect from placement to remove the node from consistent hashing ring.
 	// i.e if app is busy state, the healthz status would be flaky, which leads to frequent
 	// actor rebalancing. It will impact the entire service.
-	go a.startAppHealthCheck(
-		health.WithFailureThreshold(4),
-		health.WithInterval(5*time.Second),
-		health.WithRequestTimeout(2*time.Second))
+	if a.config.AppHealthFn == nil {
+		go a.startAppHealthCheck(
+			health.WithFailureThreshold(4),
+			health.WithInterval(5*time.Second),
+			health.WithRequestTimeout(2*time.Second))
+	}
 
 	return nil
 }
 
+// isAppHealthy returns the result of the app health probe of the runtime when it is given in the
+// configuration, and of the probe of the actor runtime otherwise.
+func (a *actorsRuntime) isAppHealthy() bool {
+	if a.config.AppHealthFn != nil {
+		return a.config.AppHealthFn()
+	}
+	return a.appHealthy
+}
+
 func (a *actorsRuntime) startAppHealthCheck(opts ...health.Option) {
 	if len(a.config.HostedActorTypes) == 0 {
 		return
 	}
 
-	healthAddress := fmt.Sprintf("%s/healthz", a.appChannel.GetBaseAddress())
-	ch := health.StartEndpointHealthCheck(healthAddress, opts...)
+	ch := health.StartAppHealthCheck(a.config.AppHealthProbe, a.appChannel.GetBaseAddress(), opts...)
 	for {
 		a.appHealthy = <-ch
 	}
@@ -225,6 +266,64 @@ func (a *actorsRuntime) deactivateActor(actorType, actorID string) error {
 	return nil
 }
 
+// warnActorDeactivation notifies the app that the idle actor is about to be deactivated.
+// The app can postpone the deactivation by returning a DeactivationWarningResponse.
+// The warning takes the turn of the actor like an actor call, without marking the actor as used.
+func (a *actorsRuntime) warnActorDeactivation(actorInstance *actor, deactivateIn time.Duration) error {
+	err := actorInstance.lockTurn()
+	if err != nil {
+		return err
+	}
+	defer actorInstance.unlock()
+
+	// an actor call that took the turn first resets the warning, the actor is no longer idle
+	if !actorInstance.deactivationWarned.Load() {
+		return nil
+	}
+
+	b, err := json.Marshal(&DeactivationWarningRequest{
+		DeactivateIn: deactivateIn.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req := invokev1.NewInvokeMethodRequest(fmt.Sprintf("actors/%s/%s/deactivate-warning", actorInstance.actorType, actorInstance.actorID))
+	req.WithHTTPExtension(nethttp.MethodPost, "")
+	req.WithRawData(b, invokev1.JSONContentType)
+
+	// TODO Propagate context
+	ctx := context.Background()
+	resp, err := a.appChannel.InvokeMethod(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	_, body := resp.RawData()
+	if resp.Status().Code != nethttp.StatusOK {
+		return errors.Errorf("error from actor service: %s", string(body))
+	}
+	if len(body) == 0 {
+		return nil
+	}
+
+	var warningResp DeactivationWarningResponse
+	if err = json.Unmarshal(body, &warningResp); err != nil {
+		return errors.Wrap(err, "error parsing deactivation warning response")
+	}
+	if warningResp.ExtendIdleTimeout == "" {
+		return nil
+	}
+
+	extension, err := time.ParseDuration(warningResp.ExtendIdleTimeout)
+	if err != nil || extension <= 0 {
+		return errors.Errorf("invalid idle timeout extension %s", warningResp.ExtendIdleTimeout)
+	}
+	actorInstance.extendIdleTimeout(extension)
+	log.Debugf("extended idle timeout of actor type=%s, id=%s by %s", actorInstance.actorType, actorInstance.actorID, extension)
+	return nil
+}
+
 func (a *actorsRuntime) getActorTypeAndIDFromKey(key string) (string, string) {
 	arr := a.decomposeCompositeKey(key)
 	return arr[0], arr[1]
@@ -241,8 +340,9 @@ func (a *actorsRuntime) startDeactivationTicker(interval, actorIdleTimeout time.
 					return true
 				}
 
+				idleTimeout := actorIdleTimeout + actorInstance.idleTimeoutExtension.Load()
 				durationPassed := t.Sub(actorInstance.lastUsedTime)
-				if durationPassed >= actorIdleTimeout {
+				if durationPassed >= idleTimeout {
 					go func(actorKey string) {
 						actorType, actorID := a.getActorTypeAndIDFromKey(actorKey)
 						err := a.deactivateActor(actorType, actorID)
@@ -250,6 +350,14 @@ func (a *actorsRuntime) startDeactivationTicker(interval, actorIdleTimeout time.
 							log.Warnf("failed to deactivate actor %s: %s", actorKey, err)
 						}
 					}(key.(string))
+				} else if window, ok := a.config.DeactivationWarningWindows[actorInstance.actorType]; ok &&
+					durationPassed >= idleTimeout-window && actorInstance.markDeactivationWarned() {
+					go func(actorInstance *actor, deactivateIn time.Duration) {
+						err := a.warnActorDeactivation(actorInstance, deactivateIn)
+						if err != nil {
+							log.Warnf("failed to warn actor type=%s, id=%s of deactivation: %s", actorInstance.actorType, actorInstance.actorID, err)
+						}
+					}(actorInstance, idleTimeout-durationPassed)
 				}
 
 				return true
@@ -259,6 +367,10 @@ func (a *actorsRuntime) startDeactivationTicker(interval, actorIdleTimeout time.
 }
 
 func (a *actorsRuntime) Call(ctx context.Context, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	if _, ok := req.Metadata()[broadcastMetadata]; ok {
+		return a.callBroadcast(ctx, req)
+	}
+
 	a.placement.WaitUntilPlacementTableIsReady()
 
 	actor := req.Actor()
@@ -418,12 +530,16 @@ func (a *actorsRuntime) TransactionalStateOperation(ctx context.Context, req *Tr
 			if err != nil {
 				return err
 			}
+			upsertMetadata, err := operationMetadata(metadata, upsert.Metadata)
+			if err != nil {
+				return err
+			}
 			key := a.constructActorStateKey(req.ActorType, req.ActorID, upsert.Key)
 			operations = append(operations, state.TransactionalStateOperation{
 				Request: state.SetRequest{
 					Key:      key,
 					Value:    upsert.Value,
-					Metadata: metadata,
+					Metadata: upsertMetadata,
 				},
 				Operation: state.Upsert,
 			})
@@ -454,12 +570,47 @@ func (a *actorsRuntime) TransactionalStateOperation(ctx context.Context, req *Tr
 	return err
 }
 
+// operationMetadata merges the metadata of an actor state operation, such as its ttlInSeconds,
+// with the actor metadata. The actor metadata can't be overridden by the operation.
+func operationMetadata(actorMetadata, opMetadata map[string]string) (map[string]string, error) {
+	if len(opMetadata) == 0 {
+		return actorMetadata, nil
+	}
+
+	if ttl, ok := opMetadata[metadataTTLInSeconds]; ok {
+		if _, err := strconv.ParseInt(ttl, 10, 64); err != nil {
+			return nil, errors.Errorf("invalid %s value %s: must be an integer", metadataTTLInSeconds, ttl)
+		}
+	}
+
+	md := make(map[string]string, len(opMetadata)+len(actorMetadata))
+	for k, v := range opMetadata {
+		md[k] = v
+	}
+	for k, v := range actorMetadata {
+		md[k] = v
+	}
+	return md, nil
+}
+
 func (a *actorsRuntime) IsActorHosted(ctx context.Context, req *ActorHostedRequest) bool {
 	key := a.constructCompositeKey(req.ActorType, req.ActorID)
 	_, exists := a.actorsTable.Load(key)
 	return exists
 }
 
+// GetActorStateKey returns the key under which the actor state key is saved, so it can be
+// included in a transaction of the state API. The store must be the actor state store.
+func (a *actorsRuntime) GetActorStateKey(storeName, actorType, actorID, key string) (string, error) {
+	if a.config.StateStoreName == "" || storeName != a.config.StateStoreName {
+		return "", errors.Errorf("state store %s is not the actor state store", storeName)
+	}
+	if actorType == "" || actorID == "" {
+		return "", errors.New("actor type and actor id are required for actor state keys")
+	}
+	return a.constructActorStateKey(actorType, actorID, key), nil
+}
+
 func (a *actorsRuntime) constructActorStateKey(actorType, actorID, key string) string {
 	return a.constructCompositeKey(a.config.AppID, actorType, actorID, key)
 }
@@ -478,55 +629,127 @@ func (a *actorsRuntime) drainRebalancedActors() {
 			address, _ := a.placement.LookupActor(actorType, actorID)
 			if address != "" && !a.isActorLocal(address, a.config.HostAddress, a.config.Port) {
 				// actor has been moved to a different host, deactivate when calls are done
-				// cancel any reminders
-				reminders := a.reminders[actorType]
-				for _, r := range reminders {
-					if r.ActorType == actorType && r.ActorID == actorID {
-						reminderKey := a.constructCompositeKey(actorKey, r.Name)
-						stopChan, exists := a.activeReminders.Load(reminderKey)
-						if exists {
-							close(stopChan.(chan bool))
-							a.activeReminders.Delete(reminderKey)
-						}
-					}
-				}
-
-				actor := value.(*actor)
-				if a.config.DrainRebalancedActors {
-					// wait until actor isn't busy or timeout hits
-					if actor.isBusy() {
-						select {
-						case <-time.After(a.config.DrainOngoingCallTimeout):
-							break
-						case <-actor.channel():
-							// if a call comes in from the actor for state changes, that's still allowed
-							break
-						}
-					}
-				}
-
-				// don't allow state changes
-				a.actorsTable.Delete(key)
-
+				a.drainActor(actorKey, value.(*actor))
 				diag.DefaultMonitoring.ActorRebalanced(actorType)
-
-				for {
-					// wait until actor is not busy, then deactivate
-					if !actor.isBusy() {
-						err := a.deactivateActor(actorType, actorID)
-						if err != nil {
-							log.Warnf("failed to deactivate actor %s: %s", actorKey, err)
-						}
-						break
-					}
-					time.Sleep(time.Millisecond * 500)
-				}
 			}
 		}(key, value, &wg)
 		return true
 	})
 }
 
+// Drain disconnects the runtime from placement and deactivates all active actors, honoring the
+// drain settings of each actor type. It returns immediately; use GetDrainStatus to follow the progress.
+func (a *actorsRuntime) Drain() {
+	if !a.draining.CAS(false, true) {
+		return
+	}
+	epoch := a.drainEpoch.Inc()
+
+	log.Info("draining actors")
+	go func() {
+		// actors activated by calls routed with a stale placement table are drained in the next pass
+		for {
+			if a.drainEpoch.Load() != epoch {
+				// undrained
+				return
+			}
+
+			var wg sync.WaitGroup
+			drained := 0
+			a.actorsTable.Range(func(key interface{}, value interface{}) bool {
+				wg.Add(1)
+				drained++
+				go func(key interface{}, value interface{}, wg *sync.WaitGroup) {
+					defer wg.Done()
+					a.drainActor(key.(string), value.(*actor))
+				}(key, value, &wg)
+				return true
+			})
+			wg.Wait()
+
+			if drained == 0 {
+				break
+			}
+		}
+
+		if a.drainEpoch.Load() == epoch {
+			a.drained.Store(true)
+			log.Info("actors drained")
+		}
+	}()
+}
+
+// Undrain stops draining the actors: the runtime reports itself healthy again, so that placement
+// places actors on it again. The actors already deactivated are activated again on their next call.
+func (a *actorsRuntime) Undrain() {
+	if !a.draining.Load() {
+		return
+	}
+
+	a.drainEpoch.Inc()
+	a.drained.Store(false)
+	a.draining.Store(false)
+	log.Info("actors undrained")
+}
+
+// GetDrainStatus returns the progress of draining the actors started by Drain.
+func (a *actorsRuntime) GetDrainStatus(ctx context.Context) DrainStatus {
+	return DrainStatus{
+		Draining:        a.draining.Load(),
+		Completed:       a.drained.Load(),
+		RemainingActors: a.GetActiveActorsCount(ctx),
+	}
+}
+
+// drainActor cancels the reminders of the actor and deactivates it when its calls are done.
+func (a *actorsRuntime) drainActor(actorKey string, actor *actor) {
+	actorType, actorID := a.getActorTypeAndIDFromKey(actorKey)
+
+	// cancel any reminders
+	a.remindersLock.RLock()
+	reminders := a.reminders[actorType]
+	a.remindersLock.RUnlock()
+	for _, r := range reminders {
+		if r.ActorType == actorType && r.ActorID == actorID {
+			reminderKey := a.constructCompositeKey(actorKey, r.Name)
+			stopChan, exists := a.activeReminders.Load(reminderKey)
+			if exists {
+				close(stopChan.(chan bool))
+				a.activeReminders.Delete(reminderKey)
+			}
+		}
+	}
+
+	entityConfig := a.config.entityConfig(actorType)
+	if entityConfig.DrainRebalancedActors {
+		// wait until actor isn't busy or timeout hits
+		if actor.isBusy() {
+			select {
+			case <-time.After(entityConfig.DrainOngoingCallTimeout):
+				break
+			case <-actor.channel():
+				// if a call comes in from the actor for state changes, that's still allowed
+				break
+			}
+		}
+	}
+
+	// don't allow state changes
+	a.actorsTable.Delete(actorKey)
+
+	for {
+		// wait until actor is not busy, then deactivate
+		if !actor.isBusy() {
+			err := a.deactivateActor(actorType, actorID)
+			if err != nil {
+				log.Warnf("failed to deactivate actor %s: %s", actorKey, err)
+			}
+			break
+		}
+		time.Sleep(time.Millisecond * 500)
+	}
+}
+
 func (a *actorsRuntime) evaluateReminders() {
 	a.evaluationLock.Lock()
 	defer a.evaluationLock.Unlock()
@@ -666,6 +889,7 @@ func (a *actorsRuntime) startReminder(reminder *Reminder, stopChannel chan bool)
 			break
 		}
 
+		diag.DefaultMonitoring.ActorTriggerDelay(reminder.ActorType, triggerTypeReminder, time.Since(nextInvokeTime))
 		err = a.executeReminder(reminder.ActorType, reminder.ActorID, reminder.DueTime, reminder.Period, reminder.Name, reminder.Data)
 		if err != nil {
 			log.Errorf("error executing reminder: %s", err)
@@ -687,7 +911,8 @@ func (a *actorsRuntime) startReminder(reminder *Reminder, stopChannel chan bool)
 			go func(ticker *time.Ticker, actorType, actorID, reminder, dueTime, period string, data interface{}) {
 				for {
 					select {
-					case <-ticker.C:
+					case tickTime := <-ticker.C:
+						diag.DefaultMonitoring.ActorTriggerDelay(actorType, triggerTypeReminder, time.Since(tickTime))
 						err := a.executeReminder(actorType, actorID, dueTime, period, reminder, data)
 						if err != nil {
 							log.Debugf("error invoking reminder on actor %s: %s", a.constructCompositeKey(actorType, actorID), err)
@@ -873,6 +1098,7 @@ func (a *actorsRuntime) CreateTimer(ctx context.Context, req *CreateTimerRequest
 	a.activeTimers.Store(timerKey, stop)
 
 	go func(stop chan (bool), req *CreateTimerRequest) {
+		scheduledTime := time.Now().Add(dueTime)
 		time.Sleep(dueTime)
 
 		// Check if timer is still active
@@ -884,6 +1110,7 @@ func (a *actorsRuntime) CreateTimer(ctx context.Context, req *CreateTimerRequest
 			break
 		}
 
+		diag.DefaultMonitoring.ActorTriggerDelay(req.ActorType, triggerTypeTimer, time.Since(scheduledTime))
 		err := a.executeTimer(req.ActorType, req.ActorID, req.Name, req.DueTime,
 			req.Period, req.Callback, req.Data)
 		if err != nil {
@@ -895,9 +1122,10 @@ func (a *actorsRuntime) CreateTimer(ctx context.Context, req *CreateTimerRequest
 
 		for {
 			select {
-			case <-ticker.C:
+			case tickTime := <-ticker.C:
 				_, exists := a.actorsTable.Load(actorKey)
 				if exists {
+					diag.DefaultMonitoring.ActorTriggerDelay(req.ActorType, triggerTypeTimer, time.Since(tickTime))
 					err := a.executeTimer(req.ActorType, req.ActorID, req.Name, req.DueTime,
 						req.Period, req.Callback, req.Data)
 					if err != nil {
@@ -1078,6 +1306,144 @@ func (a *actorsRuntime) GetActiveActorsCount(ctx context.Context) []ActiveActors
 	return activeActorsCount
 }
 
+// Broadcast invokes a method on all the activated instances of an actor type on this host,
+// and on the other hosts of the actor type in the placement tables when the request is global.
+func (a *actorsRuntime) Broadcast(ctx context.Context, req *BroadcastRequest) (*BroadcastResponse, error) {
+	if req.Global && a.placement == nil {
+		return nil, errors.New("actors: placement is not initialized")
+	}
+
+	resp := a.broadcastLocal(ctx, req)
+	if !req.Global {
+		return resp, nil
+	}
+
+	a.placement.WaitUntilPlacementTableIsReady()
+	hostname := fmt.Sprintf("%s:%d", a.config.HostAddress, a.config.Port)
+	lock := sync.Mutex{}
+	limiter := concurrency.NewLimiter(concurrency.DefaultLimit)
+	for host, appID := range a.placement.ActorTypeHosts(req.ActorType) {
+		if host == hostname {
+			continue
+		}
+		host, appID := host, appID
+		fn := func(param interface{}) {
+			hostResp, err := a.broadcastRemote(ctx, host, appID, req)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				log.Debugf("actors: error broadcasting method %s of actor type %s to host %s: %s", req.Method, req.ActorType, host, err)
+				resp.Failed = append(resp.Failed, BroadcastFailure{Host: host, Error: err.Error()})
+				return
+			}
+			resp.Invoked += hostResp.Invoked
+			resp.Failed = append(resp.Failed, hostResp.Failed...)
+		}
+		limiter.Execute(fn, nil)
+	}
+	limiter.Wait()
+
+	return resp, nil
+}
+
+func (a *actorsRuntime) broadcastLocal(ctx context.Context, req *BroadcastRequest) *BroadcastResponse {
+	actorIDs := []string{}
+	a.actorsTable.Range(func(key, value interface{}) bool {
+		actorType, actorID := a.getActorTypeAndIDFromKey(key.(string))
+		if actorType == req.ActorType {
+			actorIDs = append(actorIDs, actorID)
+		}
+		return true
+	})
+
+	resp := &BroadcastResponse{}
+	lock := sync.Mutex{}
+	limiter := concurrency.NewLimiter(concurrency.DefaultLimit)
+	for _, actorID := range actorIDs {
+		fn := func(param interface{}) {
+			actorID := param.(string)
+			invokeReq := invokev1.NewInvokeMethodRequest(req.Method).
+				WithActor(req.ActorType, actorID).
+				WithRawData(req.Data, req.ContentType).
+				WithMetadata(req.Metadata)
+			_, err := a.callLocalActor(ctx, invokeReq)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				resp.Failed = append(resp.Failed, BroadcastFailure{ActorID: actorID, Error: err.Error()})
+				return
+			}
+			resp.Invoked++
+		}
+		limiter.Execute(fn, actorID)
+	}
+	limiter.Wait()
+
+	return resp
+}
+
+// broadcastRemote fans the broadcast out to the actor instances of a remote host. The call isn't retried,
+// as a retry after a timeout would invoke the instances of the host again: the host is reported as failed.
+func (a *actorsRuntime) broadcastRemote(ctx context.Context, targetAddress, targetID string, req *BroadcastRequest) (*BroadcastResponse, error) {
+	md := map[string][]string{broadcastMetadata: {"true"}}
+	for k, v := range req.Metadata {
+		md[k] = v
+	}
+	invokeReq := invokev1.NewInvokeMethodRequest(req.Method).
+		WithActor(req.ActorType, "").
+		WithRawData(req.Data, req.ContentType).
+		WithMetadata(md)
+
+	resp, err := a.callRemoteActor(ctx, targetAddress, targetID, invokeReq)
+	if err != nil {
+		return nil, err
+	}
+
+	_, data := resp.RawData()
+	var hostResp BroadcastResponse
+	if err := json.Unmarshal(data, &hostResp); err != nil {
+		return nil, errors.Wrap(err, "failed to decode broadcast response")
+	}
+	return &hostResp, nil
+}
+
+// callBroadcast invokes a broadcast fanned out to this host by another host on the local actor instances.
+func (a *actorsRuntime) callBroadcast(ctx context.Context, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	md := map[string][]string{}
+	for k, v := range req.Metadata() {
+		if k != broadcastMetadata {
+			md[k] = v.GetValues()
+		}
+	}
+	contentType, data := req.RawData()
+
+	resp := a.broadcastLocal(ctx, &BroadcastRequest{
+		ActorType:   req.Actor().GetActorType(),
+		Method:      req.Message().GetMethod(),
+		Data:        data,
+		ContentType: contentType,
+		Metadata:    md,
+	})
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	return invokev1.NewInvokeMethodResponse(nethttp.StatusOK, "", nil).WithRawData(b, invokev1.JSONContentType), nil
+}
+
+// IsPlacementConnected returns true when the actor runtime is connected to the placement service.
+func (a *actorsRuntime) IsPlacementConnected() bool {
+	return a.placement != nil && a.placement.IsConnected()
+}
+
+// IsPlacementTableReady returns true when the actor runtime received the placement tables and they are not
+// locked for an update.
+func (a *actorsRuntime) IsPlacementTableReady() bool {
+	return a.placement != nil && a.placement.IsTableReady()
+}
+
 // Stop closes all network connections and resources used in actor runtime
 func (a *actorsRuntime) Stop() {
 	if a.placement != nil {
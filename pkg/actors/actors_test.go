@@ -8,6 +8,7 @@ package actors
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
 	"sync"
@@ -209,6 +210,122 @@ func TestActorIsNotDeactivated(t *testing.T) {
 	assert.True(t, exists)
 }
 
+type deactivationWarningAppChannel struct {
+	mockAppChannel
+	response []byte
+	methods  chan string
+}
+
+func (m *deactivationWarningAppChannel) InvokeMethod(ctx context.Context, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	if m.methods != nil {
+		m.methods <- req.Message().Method
+	}
+	resp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+	resp.WithRawData(m.response, invokev1.JSONContentType)
+	return resp, nil
+}
+
+func TestActorDeactivationWarning(t *testing.T) {
+	actorType, actorID := getTestActorTypeAndID()
+
+	t.Run("app is warned before deactivation", func(t *testing.T) {
+		appChannel := &deactivationWarningAppChannel{methods: make(chan string, 10)}
+		testActorsRuntime := newTestActorsRuntimeWithMock(appChannel)
+		testActorsRuntime.config.DeactivationWarningWindows = map[string]time.Duration{actorType: time.Second * 2}
+
+		deactivateActorWithDuration(testActorsRuntime, actorType, actorID, time.Second*3)
+
+		select {
+		case method := <-appChannel.methods:
+			assert.Equal(t, fmt.Sprintf("actors/%s/%s/deactivate-warning", actorType, actorID), method)
+		case <-time.After(time.Second * 3):
+			assert.Fail(t, "deactivation warning was not sent")
+		}
+	})
+
+	t.Run("app is not warned without a window for the actor type", func(t *testing.T) {
+		appChannel := &deactivationWarningAppChannel{methods: make(chan string, 10)}
+		testActorsRuntime := newTestActorsRuntimeWithMock(appChannel)
+
+		deactivateActorWithDuration(testActorsRuntime, actorType, actorID, time.Second*5)
+
+		select {
+		case method := <-appChannel.methods:
+			assert.Fail(t, "unexpected call", method)
+		case <-time.After(time.Second * 3):
+		}
+	})
+
+	t.Run("app extends the idle timeout", func(t *testing.T) {
+		appChannel := &deactivationWarningAppChannel{response: []byte(`{"extendIdleTimeout": "1m"}`)}
+		testActorsRuntime := newTestActorsRuntimeWithMock(appChannel)
+		act := newActor(actorType, actorID)
+		act.markDeactivationWarned()
+
+		err := testActorsRuntime.warnActorDeactivation(act, time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Minute, act.idleTimeoutExtension.Load())
+		assert.True(t, act.markDeactivationWarned())
+	})
+
+	t.Run("invalid idle timeout extension", func(t *testing.T) {
+		appChannel := &deactivationWarningAppChannel{response: []byte(`{"extendIdleTimeout": "soon"}`)}
+		testActorsRuntime := newTestActorsRuntimeWithMock(appChannel)
+		act := newActor(actorType, actorID)
+		act.markDeactivationWarned()
+
+		err := testActorsRuntime.warnActorDeactivation(act, time.Second)
+		assert.Error(t, err)
+		assert.Equal(t, time.Duration(0), act.idleTimeoutExtension.Load())
+	})
+
+	t.Run("warning waits for the turn of the actor", func(t *testing.T) {
+		appChannel := &deactivationWarningAppChannel{methods: make(chan string, 10)}
+		testActorsRuntime := newTestActorsRuntimeWithMock(appChannel)
+		act := newActor(actorType, actorID)
+		act.markDeactivationWarned()
+		assert.NoError(t, act.lockTurn())
+
+		go testActorsRuntime.warnActorDeactivation(act, time.Second)
+
+		select {
+		case method := <-appChannel.methods:
+			assert.Fail(t, "unexpected call", method)
+		case <-time.After(time.Millisecond * 500):
+		}
+		act.unlock()
+		select {
+		case <-appChannel.methods:
+		case <-time.After(time.Second * 3):
+			assert.Fail(t, "deactivation warning was not sent")
+		}
+	})
+
+	t.Run("warning is dropped after an actor call", func(t *testing.T) {
+		appChannel := &deactivationWarningAppChannel{methods: make(chan string, 10)}
+		testActorsRuntime := newTestActorsRuntimeWithMock(appChannel)
+		act := newActor(actorType, actorID)
+		act.markDeactivationWarned()
+		assert.NoError(t, act.lock())
+		act.unlock()
+
+		assert.NoError(t, testActorsRuntime.warnActorDeactivation(act, time.Second))
+		assert.Len(t, appChannel.methods, 0)
+	})
+
+	t.Run("actor call resets the warning", func(t *testing.T) {
+		act := newActor(actorType, actorID)
+		assert.True(t, act.markDeactivationWarned())
+		assert.False(t, act.markDeactivationWarned())
+		act.extendIdleTimeout(time.Minute)
+
+		assert.NoError(t, act.lock())
+		act.unlock()
+		assert.Equal(t, time.Duration(0), act.idleTimeoutExtension.Load())
+		assert.True(t, act.markDeactivationWarned())
+	})
+}
+
 func TestTimerExecution(t *testing.T) {
 	testActorsRuntime := newTestActorsRuntime()
 	actorType, actorID := getTestActorTypeAndID()
@@ -652,6 +769,28 @@ func TestConstructActorStateKey(t *testing.T) {
 	assert.Equal(t, TestKeyName, keys[3])
 }
 
+func TestGetActorStateKey(t *testing.T) {
+	testActorsRuntime := newTestActorsRuntime()
+	testActorsRuntime.config.StateStoreName = "actorStore"
+	actorType, actorID := getTestActorTypeAndID()
+
+	t.Run("actor state store", func(t *testing.T) {
+		key, err := testActorsRuntime.GetActorStateKey("actorStore", actorType, actorID, TestKeyName)
+		assert.NoError(t, err)
+		assert.Equal(t, testActorsRuntime.constructActorStateKey(actorType, actorID, TestKeyName), key)
+	})
+
+	t.Run("other state store", func(t *testing.T) {
+		_, err := testActorsRuntime.GetActorStateKey("otherStore", actorType, actorID, TestKeyName)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing actor id", func(t *testing.T) {
+		_, err := testActorsRuntime.GetActorStateKey("actorStore", actorType, "", TestKeyName)
+		assert.Error(t, err)
+	})
+}
+
 func TestGetState(t *testing.T) {
 	testActorRuntime := newTestActorsRuntime()
 	actorType, actorID := getTestActorTypeAndID()
@@ -886,6 +1025,79 @@ func TestTransactionalState(t *testing.T) {
 		assert.NotNil(t, err)
 		assert.Equal(t, "operation type Wrong not supported", err.Error())
 	})
+
+	t.Run("Upsert with ttl metadata", func(t *testing.T) {
+		testActorRuntime := newTestActorsRuntime()
+		actorType, actorID := getTestActorTypeAndID()
+		store := &captureTransactionalStore{fakeStateStore: testActorRuntime.store.(*fakeStateStore)}
+		testActorRuntime.transactionalStore = store
+
+		fakeCallAndActivateActor(testActorRuntime, actorType, actorID)
+
+		err := testActorRuntime.TransactionalStateOperation(ctx, &TransactionalRequest{
+			ActorType: actorType,
+			ActorID:   actorID,
+			Operations: []TransactionalOperation{
+				{
+					Operation: Upsert,
+					Request: map[string]interface{}{
+						"key":      "key1",
+						"value":    "fakeData",
+						"metadata": map[string]interface{}{"ttlInSeconds": "60", "partitionKey": "other"},
+					},
+				},
+				{
+					Operation: Upsert,
+					Request: TransactionalUpsert{
+						Key:   "key2",
+						Value: "fakeData",
+					},
+				},
+			},
+		})
+		assert.Nil(t, err)
+
+		if assert.Len(t, store.requests, 1) {
+			ops := store.requests[0].Operations
+			md := ops[0].Request.(state.SetRequest).Metadata
+			assert.Equal(t, "60", md["ttlInSeconds"])
+			assert.Equal(t, testActorRuntime.constructCompositeKey(TestAppID, actorType, actorID), md["partitionKey"])
+			assert.NotContains(t, ops[1].Request.(state.SetRequest).Metadata, "ttlInSeconds")
+		}
+	})
+
+	t.Run("Upsert with invalid ttl - should fail", func(t *testing.T) {
+		testActorRuntime := newTestActorsRuntime()
+		actorType, actorID := getTestActorTypeAndID()
+
+		fakeCallAndActivateActor(testActorRuntime, actorType, actorID)
+
+		err := testActorRuntime.TransactionalStateOperation(ctx, &TransactionalRequest{
+			ActorType: actorType,
+			ActorID:   actorID,
+			Operations: []TransactionalOperation{
+				{
+					Operation: Upsert,
+					Request: TransactionalUpsert{
+						Key:      "key1",
+						Value:    "fakeData",
+						Metadata: map[string]string{"ttlInSeconds": "1m"},
+					},
+				},
+			},
+		})
+		assert.NotNil(t, err)
+	})
+}
+
+type captureTransactionalStore struct {
+	*fakeStateStore
+	requests []*state.TransactionalStateRequest
+}
+
+func (c *captureTransactionalStore) Multi(request *state.TransactionalStateRequest) error {
+	c.requests = append(c.requests, request)
+	return c.fakeStateStore.Multi(request)
 }
 
 func TestGetOrCreateActor(t *testing.T) {
@@ -942,6 +1154,16 @@ func TestActorsAppHealthCheck(t *testing.T) {
 	assert.False(t, testActorRuntime.appHealthy)
 }
 
+func TestActorsAppHealthFn(t *testing.T) {
+	testActorRuntime := newTestActorsRuntime()
+	appHealthy := false
+	testActorRuntime.config.AppHealthFn = func() bool { return appHealthy }
+
+	assert.False(t, testActorRuntime.isAppHealthy())
+	appHealthy = true
+	assert.True(t, testActorRuntime.isAppHealthy())
+}
+
 func TestShutdown(t *testing.T) {
 	testActorRuntime := newTestActorsRuntime()
 
@@ -977,6 +1199,75 @@ func TestConfig(t *testing.T) {
 	assert.Equal(t, "default", c.Namespace)
 }
 
+func TestEntitiesConfig(t *testing.T) {
+	drainRebalancedActors := false
+	c := NewConfig("localhost:5050", "app1", []string{"placement:5050"}, []string{"cat", "dog", "fish"}, 3500, "1s", "2s", "3s", true, "default")
+	c.SetEntitiesConfig([]config.EntityConfig{
+		{
+			Entities:                []string{"cat", "dog"},
+			DrainOngoingCallTimeout: "10s",
+		},
+		{
+			Entities:                []string{"fish"},
+			DrainOngoingCallTimeout: "invalid",
+			DrainRebalancedActors:   &drainRebalancedActors,
+		},
+	})
+
+	t.Run("overridden timeout", func(t *testing.T) {
+		ec := c.entityConfig("dog")
+		assert.Equal(t, 10*time.Second, ec.DrainOngoingCallTimeout)
+		assert.True(t, ec.DrainRebalancedActors)
+	})
+
+	t.Run("overridden drain flag with invalid timeout", func(t *testing.T) {
+		ec := c.entityConfig("fish")
+		assert.Equal(t, 3*time.Second, ec.DrainOngoingCallTimeout)
+		assert.False(t, ec.DrainRebalancedActors)
+	})
+
+	t.Run("actor type without overrides", func(t *testing.T) {
+		ec := c.entityConfig("bird")
+		assert.Equal(t, 3*time.Second, ec.DrainOngoingCallTimeout)
+		assert.True(t, ec.DrainRebalancedActors)
+	})
+}
+
+func TestDrain(t *testing.T) {
+	ctx := context.Background()
+	testActorRuntime := newTestActorsRuntime()
+
+	fakeCallAndActivateActor(testActorRuntime, "cat", "abcd")
+	fakeCallAndActivateActor(testActorRuntime, "dog", "xyz")
+
+	drainStatus := testActorRuntime.GetDrainStatus(ctx)
+	assert.False(t, drainStatus.Draining)
+	assert.False(t, drainStatus.Completed)
+
+	testActorRuntime.Drain()
+	// calling Drain again is a no-op
+	testActorRuntime.Drain()
+
+	assert.Eventually(t, func() bool {
+		return testActorRuntime.GetDrainStatus(ctx).Completed
+	}, time.Second*5, time.Millisecond*100)
+
+	drainStatus = testActorRuntime.GetDrainStatus(ctx)
+	assert.True(t, drainStatus.Draining)
+	assert.Empty(t, drainStatus.RemainingActors)
+
+	testActorRuntime.Undrain()
+
+	drainStatus = testActorRuntime.GetDrainStatus(ctx)
+	assert.False(t, drainStatus.Draining)
+	assert.False(t, drainStatus.Completed)
+
+	// actors activated after the undrain are kept
+	fakeCallAndActivateActor(testActorRuntime, "cat", "abcd")
+	time.Sleep(200 * time.Millisecond)
+	assert.NotEmpty(t, testActorRuntime.GetDrainStatus(ctx).RemainingActors)
+}
+
 func TestHostValidation(t *testing.T) {
 	t.Run("kubernetes mode with mTLS, missing namespace", func(t *testing.T) {
 		err := ValidateHostEnvironment(true, modes.KubernetesMode, "")
@@ -1003,3 +1294,65 @@ func TestHostValidation(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestBroadcast(t *testing.T) {
+	t.Run("invoke activated actors of the type", func(t *testing.T) {
+		testActorRuntime := newTestActorsRuntime()
+		fakeCallAndActivateActor(testActorRuntime, "cat", "abcd")
+		fakeCallAndActivateActor(testActorRuntime, "cat", "efgh")
+		fakeCallAndActivateActor(testActorRuntime, "dog", "xyz")
+
+		resp, err := testActorRuntime.Broadcast(context.Background(), &BroadcastRequest{
+			ActorType: "cat",
+			Method:    "invalidate",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, resp.Invoked)
+		assert.Empty(t, resp.Failed)
+	})
+
+	t.Run("report failed actors", func(t *testing.T) {
+		testActorRuntime := newTestActorsRuntime()
+		fakeCallAndActivateActor(testActorRuntime, "cat", "abcd")
+		act := testActorRuntime.getOrCreateActor("cat", "efgh")
+		act.lock()
+		act.channel()
+		act.unlock()
+
+		resp, err := testActorRuntime.Broadcast(context.Background(), &BroadcastRequest{
+			ActorType: "cat",
+			Method:    "invalidate",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resp.Invoked)
+		assert.Equal(t, 1, len(resp.Failed))
+		assert.Equal(t, "efgh", resp.Failed[0].ActorID)
+	})
+
+	t.Run("global broadcast without placement", func(t *testing.T) {
+		testActorRuntime := newTestActorsRuntime()
+
+		_, err := testActorRuntime.Broadcast(context.Background(), &BroadcastRequest{
+			ActorType: "cat",
+			Method:    "invalidate",
+			Global:    true,
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("broadcast fanned out by another host", func(t *testing.T) {
+		testActorRuntime := newTestActorsRuntime()
+		fakeCallAndActivateActor(testActorRuntime, "cat", "abcd")
+
+		req := invokev1.NewInvokeMethodRequest("invalidate").
+			WithActor("cat", "").
+			WithMetadata(map[string][]string{broadcastMetadata: {"true"}})
+		resp, err := testActorRuntime.Call(context.Background(), req)
+		assert.NoError(t, err)
+
+		var broadcastResp BroadcastResponse
+		_, data := resp.RawData()
+		assert.NoError(t, json.Unmarshal(data, &broadcastResp))
+		assert.Equal(t, 1, broadcastResp.Invoked)
+	})
+}
@@ -0,0 +1,30 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package actors
+
+// BroadcastRequest is the request object for invoking a method on all the activated instances of an actor type
+type BroadcastRequest struct {
+	ActorType   string
+	Method      string
+	Data        []byte
+	ContentType string
+	Metadata    map[string][]string
+	// Global fans the invocation out to all the hosts of the actor type in the placement tables.
+	Global bool
+}
+
+// BroadcastResponse is the result of a broadcast invocation
+type BroadcastResponse struct {
+	Invoked int                `json:"invoked"`
+	Failed  []BroadcastFailure `json:"failed,omitempty"`
+}
+
+// BroadcastFailure is an actor instance or a host a broadcast invocation failed on
+type BroadcastFailure struct {
+	ActorID string `json:"actorId,omitempty"`
+	Host    string `json:"host,omitempty"`
+	Error   string `json:"error"`
+}
@@ -5,7 +5,11 @@
 
 package actors
 
-import "time"
+import (
+	"time"
+
+	"github.com/dapr/dapr/pkg/config"
+)
 
 // Config is the actor runtime configuration
 type Config struct {
@@ -20,6 +24,20 @@ type Config struct {
 	DrainOngoingCallTimeout       time.Duration
 	DrainRebalancedActors         bool
 	Namespace                     string
+	DeactivationWarningWindows    map[string]time.Duration
+	PlacementHints                map[string]string
+	StateStoreName                string
+	EntityConfigs                 map[string]EntityConfig
+	AppHealthProbe                string
+	// AppHealthFn returns the health of the app when the runtime probes it, in which case the
+	// actor runtime doesn't run its own probe.
+	AppHealthFn func() bool
+}
+
+// EntityConfig holds the drain settings of an actor type.
+type EntityConfig struct {
+	DrainOngoingCallTimeout time.Duration
+	DrainRebalancedActors   bool
 }
 
 const (
@@ -63,3 +81,43 @@ func NewConfig(hostAddress, appID string, placementAddresses []string, hostedAct
 
 	return c
 }
+
+// SetEntitiesConfig applies the per actor type drain overrides of the application config.
+// Invalid durations are ignored in favor of the runtime wide setting, like in NewConfig.
+func (c *Config) SetEntitiesConfig(entitiesConfig []config.EntityConfig) {
+	if len(entitiesConfig) == 0 {
+		return
+	}
+
+	c.EntityConfigs = make(map[string]EntityConfig)
+	for _, ec := range entitiesConfig {
+		entityConfig := EntityConfig{
+			DrainOngoingCallTimeout: c.DrainOngoingCallTimeout,
+			DrainRebalancedActors:   c.DrainRebalancedActors,
+		}
+
+		drainCallDuration, err := time.ParseDuration(ec.DrainOngoingCallTimeout)
+		if err == nil {
+			entityConfig.DrainOngoingCallTimeout = drainCallDuration
+		}
+		if ec.DrainRebalancedActors != nil {
+			entityConfig.DrainRebalancedActors = *ec.DrainRebalancedActors
+		}
+
+		for _, actorType := range ec.Entities {
+			c.EntityConfigs[actorType] = entityConfig
+		}
+	}
+}
+
+// entityConfig returns the drain settings of the given actor type.
+func (c *Config) entityConfig(actorType string) EntityConfig {
+	if entityConfig, ok := c.EntityConfigs[actorType]; ok {
+		return entityConfig
+	}
+
+	return EntityConfig{
+		DrainOngoingCallTimeout: c.DrainOngoingCallTimeout,
+		DrainRebalancedActors:   c.DrainRebalancedActors,
+	}
+}
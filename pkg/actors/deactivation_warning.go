@@ -0,0 +1,16 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package actors
+
+// DeactivationWarningRequest is the payload that is sent to an Actor SDK API before an idle actor is deactivated
+type DeactivationWarningRequest struct {
+	DeactivateIn string `json:"deactivateIn"`
+}
+
+// DeactivationWarningResponse is the optional payload returned by an Actor SDK API to postpone the deactivation
+type DeactivationWarningResponse struct {
+	ExtendIdleTimeout string `json:"extendIdleTimeout,omitempty"`
+}
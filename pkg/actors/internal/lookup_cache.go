@@ -0,0 +1,94 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package internal
+
+import (
+	"sync"
+
+	"go.uber.org/atomic"
+)
+
+const (
+	// lookupCacheMaxSize is the number of actor addresses cached for a version of the placement tables.
+	lookupCacheMaxSize = 10000
+	// lookupLatencySampleRate records the latency of one lookup out of this many.
+	lookupLatencySampleRate = 100
+)
+
+type lookupCacheKey struct {
+	actorType string
+	actorID   string
+}
+
+type lookupCacheEntry struct {
+	name  string
+	appID string
+}
+
+// lookupCacheTable holds the actor addresses resolved in a version of the placement tables.
+type lookupCacheTable struct {
+	version string
+	entries sync.Map
+	size    atomic.Int32
+}
+
+// lookupCache caches the host address and app ID an actor resolves to in the placement tables.
+// Lookups take no lock: the cache holds a table per version of the placement tables, replaced
+// when the placement tables are updated, so that the addresses resolved in a previous version
+// are dropped with their table.
+type lookupCache struct {
+	maxSize int32
+	table   atomic.Value
+	lookups atomic.Uint32
+}
+
+func newLookupCache(maxSize int) *lookupCache {
+	c := &lookupCache{maxSize: int32(maxSize)}
+	c.reset("")
+	return c
+}
+
+// get returns the cached address of the actor, and the table the address resolved on a miss is cached in.
+func (c *lookupCache) get(actorType, actorID string) (name, appID string, table *lookupCacheTable, ok bool) {
+	table = c.table.Load().(*lookupCacheTable)
+	v, ok := table.entries.Load(lookupCacheKey{actorType: actorType, actorID: actorID})
+	if !ok {
+		return "", "", table, false
+	}
+	e := v.(lookupCacheEntry)
+	return e.name, e.appID, table, true
+}
+
+// set caches the address of the actor in the table returned by get. When the table is full,
+// an arbitrary address is evicted.
+func (c *lookupCache) set(table *lookupCacheTable, actorType, actorID, name, appID string) {
+	key := lookupCacheKey{actorType: actorType, actorID: actorID}
+	if _, loaded := table.entries.LoadOrStore(key, lookupCacheEntry{name: name, appID: appID}); loaded {
+		return
+	}
+	if table.size.Inc() <= c.maxSize {
+		return
+	}
+	table.entries.Range(func(k, _ interface{}) bool {
+		if k == key {
+			return true
+		}
+		if _, deleted := table.entries.LoadAndDelete(k); deleted {
+			table.size.Dec()
+		}
+		return false
+	})
+}
+
+// reset replaces the table of the cache when the placement tables are updated to version.
+func (c *lookupCache) reset(version string) {
+	c.table.Store(&lookupCacheTable{version: version})
+}
+
+// sampled returns whether the latency of the current lookup is recorded.
+func (c *lookupCache) sampled() bool {
+	return c.lookups.Inc()%lookupLatencySampleRate == 0
+}
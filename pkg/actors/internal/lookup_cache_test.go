@@ -0,0 +1,77 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCache(t *testing.T) {
+	t.Run("cached address", func(t *testing.T) {
+		c := newLookupCache(10)
+		c.reset("1")
+		_, _, table, ok := c.get("actorOne", "id0")
+		assert.False(t, ok)
+		assert.Equal(t, "1", table.version)
+		c.set(table, "actorOne", "id0", "host0", "app0")
+
+		name, appID, _, ok := c.get("actorOne", "id0")
+		assert.True(t, ok)
+		assert.Equal(t, "host0", name)
+		assert.Equal(t, "app0", appID)
+
+		_, _, _, ok = c.get("actorOne", "id1")
+		assert.False(t, ok)
+	})
+
+	t.Run("reset on table update", func(t *testing.T) {
+		c := newLookupCache(10)
+		c.reset("1")
+		_, _, table, _ := c.get("actorOne", "id0")
+		c.set(table, "actorOne", "id0", "host0", "app0")
+		c.reset("2")
+
+		_, _, table, ok := c.get("actorOne", "id0")
+		assert.False(t, ok)
+		assert.Equal(t, "2", table.version)
+	})
+
+	t.Run("ignore address resolved in stale table", func(t *testing.T) {
+		c := newLookupCache(10)
+		c.reset("1")
+		_, _, stale, _ := c.get("actorOne", "id0")
+		c.reset("2")
+		c.set(stale, "actorOne", "id0", "host0", "app0")
+
+		_, _, _, ok := c.get("actorOne", "id0")
+		assert.False(t, ok)
+	})
+
+	t.Run("evict an address when full", func(t *testing.T) {
+		c := newLookupCache(2)
+		_, _, table, _ := c.get("actorOne", "id0")
+		c.set(table, "actorOne", "id0", "host0", "app0")
+		c.set(table, "actorOne", "id1", "host0", "app0")
+		c.set(table, "actorOne", "id2", "host0", "app0")
+
+		assert.Equal(t, int32(2), table.size.Load())
+		_, _, _, ok := c.get("actorOne", "id2")
+		assert.True(t, ok)
+	})
+
+	t.Run("sample one lookup out of the rate", func(t *testing.T) {
+		c := newLookupCache(10)
+		sampled := 0
+		for i := 0; i < 10*lookupLatencySampleRate; i++ {
+			if c.sampled() {
+				sampled++
+			}
+		}
+		assert.Equal(t, 10, sampled)
+	})
+}
@@ -12,36 +12,53 @@ import (
 	"sync"
 	"time"
 
+	"go.uber.org/atomic"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	dapr_credentials "github.com/dapr/dapr/pkg/credentials"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
+	"github.com/dapr/dapr/pkg/logsampler"
 	"github.com/dapr/dapr/pkg/placement/hashing"
 	v1pb "github.com/dapr/dapr/pkg/proto/placement/v1"
 	"github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/dapr/kit/logger"
 )
 
-var log = logger.NewLogger("dapr.runtime.actor.internal.placement")
+var log = logsampler.NewLogger(logger.NewLogger("dapr.runtime.actor.internal.placement"))
 
 const (
-	lockOperation   = "lock"
-	unlockOperation = "unlock"
-	updateOperation = "update"
+	lockOperation        = "lock"
+	unlockOperation      = "unlock"
+	updateOperation      = "update"
+	updateDeltaOperation = "update-delta"
 
 	placementReconnectInterval    = 500 * time.Millisecond
 	statusReportHeartbeatInterval = 1 * time.Second
 
 	grpcServiceConfig = `{"loadBalancingPolicy":"round_robin"}`
+
+	// namespaceMetadataKey is the metadata key of the stream to placement reporting the namespace of the runtime,
+	// used by placement to isolate the hashing tables of each namespace.
+	namespaceMetadataKey = "dapr-namespace"
+	// deltaUpdatesMetadataKey is the metadata key of the stream to placement reporting that the runtime
+	// can apply the tables of the changed actor types only.
+	deltaUpdatesMetadataKey = "dapr-placement-delta-updates"
+	// placementHintsMetadataKey is the metadata key of the stream to placement reporting the placement hints
+	// of the hosted actor types, as <actor type>=<mode> values.
+	placementHintsMetadataKey = "dapr-placement-hints"
 )
 
 // ActorPlacement maintains membership of actor instances and consistent hash
 // tables to discover the actor while interacting with Placement service.
 type ActorPlacement struct {
 	actorTypes []string
-	appID      string
+	// placementHints is the placement hint mode of the actor types, reported to placement.
+	placementHints map[string]string
+	appID          string
+	namespace      string
 	// runtimeHostname is the address and port of the runtime
 	runtimeHostName string
 
@@ -49,8 +66,9 @@ type ActorPlacement struct {
 	serverAddr []string
 	// serverIndex is the the current index of placement servers in serverAddr.
 	serverIndex int
-	// streamConnAlive is the status of stream connection alive.
-	streamConnAlive bool
+	// streamConnAlive is the status of stream connection alive. It is read by the health checks
+	// while the receiver loop changes it.
+	streamConnAlive atomic.Bool
 	// streamConnectedCh is the channel to notify that the stream
 	// between runtime and placement is connected.
 	streamConnectedCh chan struct{}
@@ -66,11 +84,13 @@ type ActorPlacement struct {
 	placementTables *hashing.ConsistentHashTables
 	// placementTableLock is the lock for placementTables.
 	placementTableLock *sync.RWMutex
+	// lookupCache caches the actor addresses resolved in placementTables.
+	lookupCache *lookupCache
 
 	// unblockSignal is the channel to unblock table locking.
 	unblockSignal chan struct{}
 	// tableIsBlocked is the status of table lock.
-	tableIsBlocked bool
+	tableIsBlocked atomic.Bool
 	// operationUpdateLock is the lock for three stage commit.
 	operationUpdateLock *sync.Mutex
 
@@ -102,18 +122,21 @@ func addDNSResolverPrefix(addr []string) []string {
 // NewActorPlacement initializes ActorPlacement for the actor service.
 func NewActorPlacement(
 	serverAddr []string, clientCert *dapr_credentials.CertChain,
-	appID, runtimeHostName string, actorTypes []string,
+	appID, namespace, runtimeHostName string, actorTypes []string, placementHints map[string]string,
 	appHealthFn func() bool,
 	afterTableUpdateFn func()) *ActorPlacement {
 	return &ActorPlacement{
 		actorTypes:      actorTypes,
+		placementHints:  placementHints,
 		appID:           appID,
+		namespace:       namespace,
 		runtimeHostName: runtimeHostName,
 		serverAddr:      addDNSResolverPrefix(serverAddr),
 		serverIndex:     0,
 
 		placementTableLock:  &sync.RWMutex{},
 		placementTables:     &hashing.ConsistentHashTables{Entries: make(map[string]*hashing.Consistent)},
+		lookupCache:         newLookupCache(lookupCacheMaxSize),
 		clientCert:          clientCert,
 		operationUpdateLock: &sync.Mutex{},
 
@@ -129,7 +152,7 @@ func NewActorPlacement(
 func (p *ActorPlacement) Start() {
 	// streamConnAlive represents the status of stream channel. This must be changed in receiver loop.
 	// This flag reduces the unnecessary request retry.
-	p.streamConnAlive = true
+	p.streamConnAlive.Store(true)
 	p.streamConnectedCh = make(chan struct{})
 	p.serverIndex = 0
 	p.shutdown = false
@@ -149,8 +172,8 @@ func (p *ActorPlacement) Start() {
 			}
 
 			// TODO: we may need to handle specific errors later.
-			if !p.streamConnAlive || err != nil {
-				p.streamConnAlive = false
+			if !p.streamConnAlive.Load() || err != nil {
+				p.streamConnAlive.Store(false)
 				p.closeStream()
 
 				s, ok := status.FromError(err)
@@ -165,7 +188,7 @@ func (p *ActorPlacement) Start() {
 				if newStream != nil {
 					p.clientConn = newConn
 					p.clientStream = newStream
-					p.streamConnAlive = true
+					p.streamConnAlive.Store(true)
 					close(p.streamConnectedCh)
 					p.streamConnectedCh = make(chan struct{})
 				}
@@ -184,7 +207,7 @@ func (p *ActorPlacement) Start() {
 		defer p.shutdownConnLoop.Done()
 		for !p.shutdown {
 			// Wait until stream is reconnected.
-			if !p.streamConnAlive {
+			if !p.streamConnAlive.Load() {
 				<-p.streamConnectedCh
 			}
 
@@ -218,7 +241,7 @@ func (p *ActorPlacement) Start() {
 			}
 
 			// No delay if stream connection is not alive.
-			if p.streamConnAlive {
+			if p.streamConnAlive.Load() {
 				diag.DefaultMonitoring.ActorStatusReported("send")
 				time.Sleep(statusReportHeartbeatInterval)
 			}
@@ -293,7 +316,16 @@ func (p *ActorPlacement) establishStreamConn() (v1pb.Placement_ReportDaprStatusC
 		}
 
 		client := v1pb.NewPlacementClient(conn)
-		stream, err := client.ReportDaprStatus(context.Background())
+		ctx := metadata.AppendToOutgoingContext(context.Background(), deltaUpdatesMetadataKey, "true")
+		if p.namespace != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, namespaceMetadataKey, p.namespace)
+		}
+		for _, actorType := range p.actorTypes {
+			if mode, ok := p.placementHints[actorType]; ok {
+				ctx = metadata.AppendToOutgoingContext(ctx, placementHintsMetadataKey, actorType+"="+mode)
+			}
+		}
+		stream, err := client.ReportDaprStatus(ctx)
 		if err != nil {
 			goto NEXT_SERVER
 		}
@@ -331,30 +363,42 @@ func (p *ActorPlacement) onPlacementOrder(in *v1pb.PlacementOrder) {
 		p.unblockPlacements()
 
 	case updateOperation:
-		p.updatePlacements(in.Tables)
+		p.updatePlacements(in.Tables, false)
+
+	case updateDeltaOperation:
+		p.updatePlacements(in.Tables, true)
 	}
 }
 
 func (p *ActorPlacement) blockPlacements() {
 	p.unblockSignal = make(chan struct{})
-	p.tableIsBlocked = true
+	p.tableIsBlocked.Store(true)
 }
 
 func (p *ActorPlacement) unblockPlacements() {
-	if p.tableIsBlocked {
-		p.tableIsBlocked = false
+	if p.tableIsBlocked.CAS(true, false) {
 		close(p.unblockSignal)
 	}
 }
 
-func (p *ActorPlacement) updatePlacements(in *v1pb.PlacementTables) {
+// updatePlacements updates the placement tables. Full updates replace all the tables, delta updates
+// only replace the tables of the actor types they hold and remove the actor types with empty tables.
+func (p *ActorPlacement) updatePlacements(in *v1pb.PlacementTables, delta bool) {
 	if in.Version == p.placementTables.Version {
 		return
 	}
 
 	p.placementTableLock.Lock()
 
+	if !delta {
+		p.placementTables.Entries = make(map[string]*hashing.Consistent, len(in.Entries))
+	}
 	for k, v := range in.Entries {
+		if delta && len(v.LoadMap) == 0 {
+			delete(p.placementTables.Entries, k)
+			continue
+		}
+
 		loadMap := map[string]*hashing.Host{}
 		for lk, lv := range v.LoadMap {
 			loadMap[lk] = hashing.NewHost(lv.Name, lv.Id, lv.Load, lv.Port)
@@ -362,6 +406,7 @@ func (p *ActorPlacement) updatePlacements(in *v1pb.PlacementTables) {
 		p.placementTables.Entries[k] = hashing.NewFromExisting(v.Hosts, v.SortedSet, loadMap)
 	}
 	p.placementTables.Version = in.Version
+	p.lookupCache.reset(in.Version)
 
 	p.afterTableUpdateFn()
 
@@ -370,15 +415,75 @@ func (p *ActorPlacement) updatePlacements(in *v1pb.PlacementTables) {
 	log.Infof("placement tables updated, version: %s", in.GetVersion())
 }
 
+// IsConnected returns true when the stream to the placement service is connected.
+func (p *ActorPlacement) IsConnected() bool {
+	return p.streamConnAlive.Load()
+}
+
+// IsTableReady returns true when the placement tables were received and are not locked for an update.
+func (p *ActorPlacement) IsTableReady() bool {
+	if p.tableIsBlocked.Load() {
+		return false
+	}
+
+	p.placementTableLock.RLock()
+	defer p.placementTableLock.RUnlock()
+	return p.placementTables.Version != ""
+}
+
 // WaitUntilPlacementTableIsReady waits until placement table is until table lock is unlocked.
 func (p *ActorPlacement) WaitUntilPlacementTableIsReady() {
-	if p.tableIsBlocked {
+	if p.tableIsBlocked.Load() {
 		<-p.unblockSignal
 	}
 }
 
 // LookupActor resolves to actor service instance address using consistent hashing table.
+// The resolved addresses are cached until the table is updated.
 func (p *ActorPlacement) LookupActor(actorType, actorID string) (string, string) {
+	if !p.lookupCache.sampled() {
+		name, appID, _ := p.lookupActor(actorType, actorID)
+		return name, appID
+	}
+
+	start := time.Now()
+	name, appID, cached := p.lookupActor(actorType, actorID)
+	diag.DefaultMonitoring.ActorLookupCompleted(actorType, cached, time.Since(start))
+	return name, appID
+}
+
+// lookupActor resolves the address of the actor from the cache, or from the tables on a miss.
+func (p *ActorPlacement) lookupActor(actorType, actorID string) (string, string, bool) {
+	name, appID, table, ok := p.lookupCache.get(actorType, actorID)
+	if ok {
+		return name, appID, true
+	}
+
+	name, appID = p.lookupActorInTables(actorType, actorID)
+	if name != "" {
+		p.lookupCache.set(table, actorType, actorID, name, appID)
+	}
+	return name, appID, false
+}
+
+// ActorTypeHosts returns the app ID of each host of the actor type in the placement tables, keyed by host address.
+func (p *ActorPlacement) ActorTypeHosts(actorType string) map[string]string {
+	p.placementTableLock.RLock()
+	defer p.placementTableLock.RUnlock()
+
+	hosts := map[string]string{}
+	t := p.placementTables.Entries[actorType]
+	if t == nil {
+		return hosts
+	}
+	_, _, hostMap, _ := t.GetInternals()
+	for name, host := range hostMap {
+		hosts[name] = host.AppID
+	}
+	return hosts
+}
+
+func (p *ActorPlacement) lookupActorInTables(actorType, actorID string) (string, string) {
 	if p.placementTables == nil {
 		return "", ""
 	}
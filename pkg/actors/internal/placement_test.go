@@ -59,7 +59,7 @@ func TestPlacementStream_RoundRobin(t *testing.T) {
 	noopTableUpdateFunc := func() {}
 
 	testPlacement := NewActorPlacement(
-		address, nil, "testAppID", "127.0.0.1:1000", []string{"actorOne", "actorTwo"},
+		address, nil, "testAppID", "", "127.0.0.1:1000", []string{"actorOne", "actorTwo"}, nil,
 		appHealthFunc, noopTableUpdateFunc)
 
 	t.Run("found leader placement in a round robin way", func(t *testing.T) {
@@ -108,7 +108,7 @@ func TestAppHealthyStatus(t *testing.T) {
 	appHealthFunc := func() bool { return true }
 	noopTableUpdateFunc := func() {}
 	testPlacement := NewActorPlacement(
-		[]string{address}, nil, "testAppID", "127.0.0.1:1000", []string{"actorOne", "actorTwo"},
+		[]string{address}, nil, "testAppID", "", "127.0.0.1:1000", []string{"actorOne", "actorTwo"}, nil,
 		appHealthFunc, noopTableUpdateFunc)
 
 	// act
@@ -135,15 +135,16 @@ func TestOnPlacementOrder(t *testing.T) {
 	tableUpdateFunc := func() { tableUpdateCount++ }
 	testPlacement := NewActorPlacement(
 		[]string{}, nil,
-		"testAppID", "127.0.0.1:1000",
-		[]string{"actorOne", "actorTwo"},
+		"testAppID", "", "127.0.0.1:1000",
+		[]string{"actorOne", "actorTwo"}, nil,
 		appHealthFunc, tableUpdateFunc)
 
 	t.Run("lock operation", func(t *testing.T) {
+		assert.False(t, testPlacement.IsTableReady(), "no tables received")
 		testPlacement.onPlacementOrder(&placementv1pb.PlacementOrder{
 			Operation: "lock",
 		})
-		assert.True(t, testPlacement.tableIsBlocked)
+		assert.True(t, testPlacement.tableIsBlocked.Load())
 	})
 
 	t.Run("update operation", func(t *testing.T) {
@@ -158,6 +159,7 @@ func TestOnPlacementOrder(t *testing.T) {
 		})
 
 		assert.Equal(t, 1, tableUpdateCount)
+		assert.False(t, testPlacement.IsTableReady(), "tables are locked")
 
 		// no update with the same table version
 		testPlacement.onPlacementOrder(&placementv1pb.PlacementOrder{
@@ -171,11 +173,62 @@ func TestOnPlacementOrder(t *testing.T) {
 		assert.Equal(t, 1, tableUpdateCount)
 	})
 
+	t.Run("update-delta operation", func(t *testing.T) {
+		testTable := func(host string) *placementv1pb.PlacementTable {
+			return &placementv1pb.PlacementTable{
+				LoadMap: map[string]*placementv1pb.Host{
+					host: {Name: host, Id: "testAppID", Load: 1},
+				},
+			}
+		}
+		testPlacement.onPlacementOrder(&placementv1pb.PlacementOrder{
+			Operation: "update",
+			Tables: &placementv1pb.PlacementTables{
+				Version: "2",
+				Entries: map[string]*placementv1pb.PlacementTable{
+					"actorOne": testTable("127.0.0.1:1000"),
+					"actorTwo": testTable("127.0.0.1:1000"),
+				},
+			},
+		})
+		assert.Equal(t, 2, len(testPlacement.placementTables.Entries))
+
+		testPlacement.onPlacementOrder(&placementv1pb.PlacementOrder{
+			Operation: "update-delta",
+			Tables: &placementv1pb.PlacementTables{
+				Version: "3",
+				Entries: map[string]*placementv1pb.PlacementTable{
+					"actorTwo":   {},
+					"actorThree": testTable("127.0.0.1:1001"),
+				},
+			},
+		})
+
+		assert.Equal(t, "3", testPlacement.placementTables.Version)
+		assert.Equal(t, 2, len(testPlacement.placementTables.Entries))
+		assert.NotNil(t, testPlacement.placementTables.Entries["actorOne"])
+		assert.Nil(t, testPlacement.placementTables.Entries["actorTwo"])
+		assert.NotNil(t, testPlacement.placementTables.Entries["actorThree"])
+	})
+
+	t.Run("update operation replaces all tables", func(t *testing.T) {
+		testPlacement.onPlacementOrder(&placementv1pb.PlacementOrder{
+			Operation: "update",
+			Tables: &placementv1pb.PlacementTables{
+				Version: "4",
+				Entries: map[string]*placementv1pb.PlacementTable{},
+			},
+		})
+
+		assert.Equal(t, 0, len(testPlacement.placementTables.Entries))
+	})
+
 	t.Run("unlock operation", func(t *testing.T) {
 		testPlacement.onPlacementOrder(&placementv1pb.PlacementOrder{
 			Operation: "unlock",
 		})
-		assert.False(t, testPlacement.tableIsBlocked)
+		assert.False(t, testPlacement.tableIsBlocked.Load())
+		assert.True(t, testPlacement.IsTableReady())
 	})
 }
 
@@ -184,8 +237,8 @@ func TestWaitUntilPlacementTableIsReady(t *testing.T) {
 	tableUpdateFunc := func() {}
 	testPlacement := NewActorPlacement(
 		[]string{}, nil,
-		"testAppID", "127.0.0.1:1000",
-		[]string{"actorOne", "actorTwo"},
+		"testAppID", "", "127.0.0.1:1000",
+		[]string{"actorOne", "actorTwo"}, nil,
 		appHealthFunc, tableUpdateFunc)
 
 	testPlacement.onPlacementOrder(&placementv1pb.PlacementOrder{Operation: "lock"})
@@ -212,8 +265,8 @@ func TestLookupActor(t *testing.T) {
 	tableUpdateFunc := func() {}
 	testPlacement := NewActorPlacement(
 		[]string{}, nil,
-		"testAppID", "127.0.0.1:1000",
-		[]string{"actorOne", "actorTwo"},
+		"testAppID", "", "127.0.0.1:1000",
+		[]string{"actorOne", "actorTwo"}, nil,
 		appHealthFunc, tableUpdateFunc)
 
 	t.Run("Placementtable is unset", func(t *testing.T) {
@@ -247,6 +300,63 @@ func TestLookupActor(t *testing.T) {
 	})
 }
 
+func BenchmarkLookupActor(b *testing.B) {
+	testPlacement := NewActorPlacement(
+		[]string{}, nil,
+		"testAppID", "", "127.0.0.1:1000",
+		[]string{"actorOne"}, nil,
+		func() bool { return true }, func() {})
+	hashing.SetReplicationFactor(100)
+	actorOneHashing := hashing.NewConsistentHash()
+	for i := 0; i < 10; i++ {
+		actorOneHashing.Add(fmt.Sprintf("127.0.0.%d:1000", i), fmt.Sprintf("app%d", i), 0)
+	}
+	testPlacement.placementTables = &hashing.ConsistentHashTables{
+		Version: "1",
+		Entries: map[string]*hashing.Consistent{"actorOne": actorOneHashing},
+	}
+	testPlacement.lookupCache.reset("1")
+	actorIDs := make([]string, 1000)
+	for i := range actorIDs {
+		actorIDs[i] = fmt.Sprintf("id%d", i)
+	}
+
+	b.Run("tables", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				testPlacement.lookupActorInTables("actorOne", actorIDs[i%len(actorIDs)])
+			}
+		})
+	})
+
+	b.Run("cache", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				testPlacement.LookupActor("actorOne", actorIDs[i%len(actorIDs)])
+			}
+		})
+	})
+}
+
+func TestActorTypeHosts(t *testing.T) {
+	testPlacement := NewActorPlacement(
+		[]string{}, nil,
+		"testAppID", "", "127.0.0.1:1000",
+		[]string{"actorOne"}, nil,
+		func() bool { return true }, func() {})
+
+	actorOneHashing := hashing.NewConsistentHash()
+	actorOneHashing.Add("127.0.0.1:1000", "testAppID", 0)
+	actorOneHashing.Add("127.0.0.1:2000", "otherAppID", 0)
+	testPlacement.placementTables.Entries["actorOne"] = actorOneHashing
+
+	assert.Equal(t, map[string]string{
+		"127.0.0.1:1000": "testAppID",
+		"127.0.0.1:2000": "otherAppID",
+	}, testPlacement.ActorTypeHosts("actorOne"))
+	assert.Empty(t, testPlacement.ActorTypeHosts("nonExistingActorType"))
+}
+
 func newTestServer() (string, *testServer, func()) {
 	port, _ := freeport.GetFreePort()
 	conn := fmt.Sprintf("127.0.0.1:%d", port)
@@ -27,10 +27,12 @@ type TransactionalOperation struct {
 	Request   interface{}   `json:"request"`
 }
 
-// TransactionalUpsert defines a key/value pair for an upsert operation
+// TransactionalUpsert defines a key/value pair for an upsert operation.
+// Metadata is passed to the state store, e.g. ttlInSeconds for state stores that support TTLs.
 type TransactionalUpsert struct {
-	Key   string      `json:"key"`
-	Value interface{} `json:"value"`
+	Key      string            `json:"key"`
+	Value    interface{}       `json:"value"`
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // TransactionalDelete defined a delete operation
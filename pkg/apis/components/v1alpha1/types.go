@@ -50,14 +50,19 @@ type MetadataItem struct {
 }
 
 // SecretKeyRef is a reference to a secret holding the value for the metadata item. Name is the secret name, and key is the field in the secret.
+// Store overrides the secret store of the component for this metadata item.
 type SecretKeyRef struct {
 	Name string `json:"name"`
 	Key  string `json:"key"`
+	// +optional
+	Store string `json:"store,omitempty"`
 }
 
 // Auth represents authentication details for the component
 type Auth struct {
 	SecretStore string `json:"secretStore"`
+	// +optional
+	FallbackSecretStores []string `json:"fallbackSecretStores,omitempty"`
 }
 
 // +kubebuilder:object:root=true
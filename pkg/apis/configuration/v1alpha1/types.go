@@ -30,6 +30,8 @@ type ConfigurationSpec struct {
 	// +optional
 	HTTPPipelineSpec PipelineSpec `json:"httpPipeline,omitempty"`
 	// +optional
+	GRPCPipelineSpec PipelineSpec `json:"grpcPipeline,omitempty"`
+	// +optional
 	TracingSpec TracingSpec `json:"tracing,omitempty"`
 	// +kubebuilder:default={enabled:true}
 	MetricSpec MetricSpec `json:"metric,omitempty"`
@@ -41,6 +43,109 @@ type ConfigurationSpec struct {
 	AccessControlSpec AccessControlSpec `json:"accessControl,omitempty"`
 	// +optional
 	NameResolutionSpec NameResolutionSpec `json:"nameResolution,omitempty"`
+	// +optional
+	ServiceInvocation ServiceInvocationSpec `json:"serviceInvocation,omitempty"`
+	// +optional
+	Actors ActorsSpec `json:"actors,omitempty"`
+	// +optional
+	MaxBodySize MaxBodySizeSpec `json:"maxBodySize,omitempty"`
+	// +optional
+	Idempotency IdempotencySpec `json:"idempotency,omitempty"`
+	// +optional
+	ComponentQuotas []ComponentQuotaSpec `json:"componentQuotas,omitempty"`
+	// +optional
+	Profiling ProfilingSpec `json:"profiling,omitempty"`
+	// +optional
+	GC GCSpec `json:"gc,omitempty"`
+}
+
+// ComponentQuotaSpec caps the number and types of components the namespaces it applies to may declare
+type ComponentQuotaSpec struct {
+	// Namespaces the quota applies to, all namespaces when empty
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// MaxComponents is the maximum number of components of a namespace, unlimited when 0
+	// +optional
+	MaxComponents int `json:"maxComponents,omitempty"`
+	// DeniedTypes are the component types the namespaces may not declare, e.g. bindings.cron
+	// +optional
+	DeniedTypes []string `json:"deniedTypes,omitempty"`
+}
+
+// IdempotencySpec configures the deduplication of publish and output binding requests with an Idempotency-Key header
+type IdempotencySpec struct {
+	// +optional
+	Window string `json:"window,omitempty"`
+	// +optional
+	Store string `json:"store,omitempty"`
+	// +optional
+	MaxKeys int `json:"maxKeys,omitempty"`
+}
+
+// ProfilingSpec configures the continuous profiling, which pushes pprof profiles to a Pyroscope compatible endpoint
+type ProfilingSpec struct {
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	// +optional
+	ProfileTypes []string `json:"profileTypes,omitempty"`
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// GCSpec tunes the garbage collector of the runtime with the equivalent of GOGC and a soft memory limit
+type GCSpec struct {
+	// +optional
+	Percent int `json:"percent,omitempty"`
+	// +optional
+	MemoryLimit string `json:"memoryLimit,omitempty"`
+}
+
+// MaxBodySizeSpec lowers the maximum request body size in MB of the HTTP API for groups of endpoints
+type MaxBodySizeSpec struct {
+	// +optional
+	State int `json:"state,omitempty"`
+	// +optional
+	PubSub int `json:"pubsub,omitempty"`
+	// +optional
+	Invocation int `json:"invocation,omitempty"`
+	// +optional
+	Bindings int `json:"bindings,omitempty"`
+}
+
+// ActorsSpec configures the actor runtime
+type ActorsSpec struct {
+	// +optional
+	DeactivationWarnings []ActorDeactivationWarning `json:"deactivationWarnings,omitempty"`
+	// +optional
+	PlacementHints []ActorPlacementHint `json:"placementHints,omitempty"`
+}
+
+// ActorDeactivationWarning defines how long before an idle actor of the given type is deactivated the app is warned
+type ActorDeactivationWarning struct {
+	ActorType string `json:"actorType"`
+	Window    string `json:"window"`
+}
+
+// ActorPlacementHint defines how the placement service rebalances the actors of the given type: sticky or rebalance-eagerly
+type ActorPlacementHint struct {
+	ActorType string `json:"actorType"`
+	Mode      string `json:"mode"`
+}
+
+// ServiceInvocationSpec configures how the sidecar handles service invocation calls to its app
+type ServiceInvocationSpec struct {
+	// +optional
+	ResponseHeaders HeaderFilterSpec `json:"responseHeaders,omitempty"`
+}
+
+// HeaderFilterSpec defines which headers are forwarded
+type HeaderFilterSpec struct {
+	// +optional
+	Allow []string `json:"allow,omitempty"`
+	// +optional
+	Deny []string `json:"deny,omitempty"`
 }
 
 // NameResolutionSpec is the spec for name resolution configuration
@@ -64,11 +169,30 @@ type SecretsScope struct {
 	AllowedSecrets []string `json:"allowedSecrets,omitempty"`
 	// +optional
 	DeniedSecrets []string `json:"deniedSecrets,omitempty"`
+	// +optional
+	Cache SecretsCacheSpec `json:"cache,omitempty"`
+}
+
+// SecretsCacheSpec configures the caching of secrets read from a secret store
+type SecretsCacheSpec struct {
+	TTL string `json:"ttl"`
+	// +optional
+	MaxEntries int `json:"maxEntries,omitempty"`
 }
 
 // PipelineSpec defines the middleware pipeline
 type PipelineSpec struct {
 	Handlers []HandlerSpec `json:"handlers"`
+	// Outbound middlewares are applied to the service invocation requests of the HTTP and gRPC APIs sent to other apps
+	// +optional
+	Outbound []HandlerSpec `json:"outbound,omitempty"`
+	// +optional
+	DecisionLog DecisionLogSpec `json:"decisionLog,omitempty"`
+}
+
+// DecisionLogSpec configures the sink receiving the allow/deny decisions of the middlewares in the pipeline
+type DecisionLogSpec struct {
+	Sink string `json:"sink"`
 }
 
 // HandlerSpec defines a request handlers
@@ -85,6 +209,10 @@ type MTLSSpec struct {
 	WorkloadCertTTL string `json:"workloadCertTTL"`
 	// +optional
 	AllowedClockSkew string `json:"allowedClockSkew"`
+	// +optional
+	SPIFFEPathTemplate string `json:"spiffePathTemplate,omitempty"`
+	// +optional
+	ExtraURISANs []string `json:"extraURISANs,omitempty"`
 }
 
 // SelectorSpec selects target services to which the handler is to be applied
@@ -102,6 +230,22 @@ type SelectorField struct {
 type TracingSpec struct {
 	SamplingRate string     `json:"samplingRate"`
 	Zipkin       ZipkinSpec `json:"zipkin"`
+	// +optional
+	MaxBaggageSize int `json:"maxBaggageSize,omitempty"`
+	// +optional
+	APISamplingRates APISamplingRatesSpec `json:"apiSamplingRates,omitempty"`
+}
+
+// APISamplingRatesSpec defines the sampling rate of each API type
+type APISamplingRatesSpec struct {
+	// +optional
+	Invocation string `json:"invocation,omitempty"`
+	// +optional
+	PubSub string `json:"pubsub,omitempty"`
+	// +optional
+	State string `json:"state,omitempty"`
+	// +optional
+	Actors string `json:"actors,omitempty"`
 }
 
 // ZipkinSpec defines Zipkin trace configurations
@@ -112,6 +256,18 @@ type ZipkinSpec struct {
 // MetricSpec defines metrics configuration
 type MetricSpec struct {
 	Enabled bool `json:"enabled"`
+	// +optional
+	HTTP MetricHTTPSpec `json:"http,omitempty"`
+}
+
+// MetricHTTPSpec defines the labels recorded on HTTP server metrics
+type MetricHTTPSpec struct {
+	// +optional
+	ExcludedLabels []string `json:"excludedLabels,omitempty"`
+	// +optional
+	PathTemplates []string `json:"pathTemplates,omitempty"`
+	// +optional
+	CollapseUnmatchedPaths bool `json:"collapseUnmatchedPaths,omitempty"`
 }
 
 // AppPolicySpec defines the policy data structure for each app
@@ -133,6 +289,8 @@ type AppOperationAction struct {
 	// +optional
 	HTTPVerb []string `json:"httpVerb" yaml:"httpVerb"`
 	Action   string   `json:"action" yaml:"action"`
+	// +optional
+	Claims map[string]string `json:"claims,omitempty" yaml:"claims,omitempty"`
 }
 
 // AccessControlSpec is the spec object in ConfigurationSpec
@@ -143,6 +301,18 @@ type AccessControlSpec struct {
 	TrustDomain string `json:"trustDomain" yaml:"trustDomain"`
 	// +optional
 	AppPolicies []AppPolicySpec `json:"policies" yaml:"policies"`
+	// +optional
+	JWT JWTSpec `json:"jwt,omitempty" yaml:"jwt,omitempty"`
+}
+
+// JWTSpec defines how bearer tokens presented on service invocation calls are validated
+type JWTSpec struct {
+	// +optional
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// +optional
+	Audience string `json:"audience,omitempty" yaml:"audience,omitempty"`
+	// +optional
+	PublicKeys string `json:"publicKeys,omitempty" yaml:"publicKeys,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -24,6 +24,21 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APISamplingRatesSpec) DeepCopyInto(out *APISamplingRatesSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APISamplingRatesSpec.
+func (in *APISamplingRatesSpec) DeepCopy() *APISamplingRatesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APISamplingRatesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccessControlSpec) DeepCopyInto(out *AccessControlSpec) {
 	*out = *in
@@ -34,6 +49,7 @@ func (in *AccessControlSpec) DeepCopyInto(out *AccessControlSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.JWT = in.JWT
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessControlSpec.
@@ -46,6 +62,61 @@ func (in *AccessControlSpec) DeepCopy() *AccessControlSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActorDeactivationWarning) DeepCopyInto(out *ActorDeactivationWarning) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActorDeactivationWarning.
+func (in *ActorDeactivationWarning) DeepCopy() *ActorDeactivationWarning {
+	if in == nil {
+		return nil
+	}
+	out := new(ActorDeactivationWarning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActorPlacementHint) DeepCopyInto(out *ActorPlacementHint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActorPlacementHint.
+func (in *ActorPlacementHint) DeepCopy() *ActorPlacementHint {
+	if in == nil {
+		return nil
+	}
+	out := new(ActorPlacementHint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActorsSpec) DeepCopyInto(out *ActorsSpec) {
+	*out = *in
+	if in.DeactivationWarnings != nil {
+		in, out := &in.DeactivationWarnings, &out.DeactivationWarnings
+		*out = make([]ActorDeactivationWarning, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlacementHints != nil {
+		in, out := &in.PlacementHints, &out.PlacementHints
+		*out = make([]ActorPlacementHint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActorsSpec.
+func (in *ActorsSpec) DeepCopy() *ActorsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ActorsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppOperationAction) DeepCopyInto(out *AppOperationAction) {
 	*out = *in
@@ -54,6 +125,13 @@ func (in *AppOperationAction) DeepCopyInto(out *AppOperationAction) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppOperationAction.
@@ -88,6 +166,31 @@ func (in *AppPolicySpec) DeepCopy() *AppPolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentQuotaSpec) DeepCopyInto(out *ComponentQuotaSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedTypes != nil {
+		in, out := &in.DeniedTypes, &out.DeniedTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentQuotaSpec.
+func (in *ComponentQuotaSpec) DeepCopy() *ComponentQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Configuration) DeepCopyInto(out *Configuration) {
 	*out = *in
@@ -150,12 +253,26 @@ func (in *ConfigurationList) DeepCopyObject() runtime.Object {
 func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 	*out = *in
 	in.HTTPPipelineSpec.DeepCopyInto(&out.HTTPPipelineSpec)
+	in.GRPCPipelineSpec.DeepCopyInto(&out.GRPCPipelineSpec)
 	out.TracingSpec = in.TracingSpec
-	out.MetricSpec = in.MetricSpec
-	out.MTLSSpec = in.MTLSSpec
+	in.MetricSpec.DeepCopyInto(&out.MetricSpec)
+	in.MTLSSpec.DeepCopyInto(&out.MTLSSpec)
 	in.Secrets.DeepCopyInto(&out.Secrets)
 	in.AccessControlSpec.DeepCopyInto(&out.AccessControlSpec)
 	in.NameResolutionSpec.DeepCopyInto(&out.NameResolutionSpec)
+	in.ServiceInvocation.DeepCopyInto(&out.ServiceInvocation)
+	in.Actors.DeepCopyInto(&out.Actors)
+	out.MaxBodySize = in.MaxBodySize
+	out.Idempotency = in.Idempotency
+	if in.ComponentQuotas != nil {
+		in, out := &in.ComponentQuotas, &out.ComponentQuotas
+		*out = make([]ComponentQuotaSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Profiling.DeepCopyInto(&out.Profiling)
+	out.GC = in.GC
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationSpec.
@@ -168,6 +285,21 @@ func (in *ConfigurationSpec) DeepCopy() *ConfigurationSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DecisionLogSpec) DeepCopyInto(out *DecisionLogSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DecisionLogSpec.
+func (in *DecisionLogSpec) DeepCopy() *DecisionLogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DecisionLogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DynamicValue) DeepCopyInto(out *DynamicValue) {
 	*out = *in
@@ -184,6 +316,21 @@ func (in *DynamicValue) DeepCopy() *DynamicValue {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GCSpec) DeepCopyInto(out *GCSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCSpec.
+func (in *GCSpec) DeepCopy() *GCSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GCSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HandlerSpec) DeepCopyInto(out *HandlerSpec) {
 	*out = *in
@@ -200,9 +347,69 @@ func (in *HandlerSpec) DeepCopy() *HandlerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HeaderFilterSpec) DeepCopyInto(out *HeaderFilterSpec) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deny != nil {
+		in, out := &in.Deny, &out.Deny
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HeaderFilterSpec.
+func (in *HeaderFilterSpec) DeepCopy() *HeaderFilterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HeaderFilterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdempotencySpec) DeepCopyInto(out *IdempotencySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdempotencySpec.
+func (in *IdempotencySpec) DeepCopy() *IdempotencySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IdempotencySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JWTSpec) DeepCopyInto(out *JWTSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JWTSpec.
+func (in *JWTSpec) DeepCopy() *JWTSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JWTSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MTLSSpec) DeepCopyInto(out *MTLSSpec) {
 	*out = *in
+	if in.ExtraURISANs != nil {
+		in, out := &in.ExtraURISANs, &out.ExtraURISANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MTLSSpec.
@@ -215,9 +422,50 @@ func (in *MTLSSpec) DeepCopy() *MTLSSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaxBodySizeSpec) DeepCopyInto(out *MaxBodySizeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaxBodySizeSpec.
+func (in *MaxBodySizeSpec) DeepCopy() *MaxBodySizeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaxBodySizeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricHTTPSpec) DeepCopyInto(out *MetricHTTPSpec) {
+	*out = *in
+	if in.ExcludedLabels != nil {
+		in, out := &in.ExcludedLabels, &out.ExcludedLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PathTemplates != nil {
+		in, out := &in.PathTemplates, &out.PathTemplates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricHTTPSpec.
+func (in *MetricHTTPSpec) DeepCopy() *MetricHTTPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricHTTPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricSpec) DeepCopyInto(out *MetricSpec) {
 	*out = *in
+	in.HTTP.DeepCopyInto(&out.HTTP)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricSpec.
@@ -256,6 +504,14 @@ func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Outbound != nil {
+		in, out := &in.Outbound, &out.Outbound
+		*out = make([]HandlerSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.DecisionLog = in.DecisionLog
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
@@ -268,6 +524,48 @@ func (in *PipelineSpec) DeepCopy() *PipelineSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProfilingSpec) DeepCopyInto(out *ProfilingSpec) {
+	*out = *in
+	if in.ProfileTypes != nil {
+		in, out := &in.ProfileTypes, &out.ProfileTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProfilingSpec.
+func (in *ProfilingSpec) DeepCopy() *ProfilingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProfilingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretsCacheSpec) DeepCopyInto(out *SecretsCacheSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsCacheSpec.
+func (in *SecretsCacheSpec) DeepCopy() *SecretsCacheSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretsCacheSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretsScope) DeepCopyInto(out *SecretsScope) {
 	*out = *in
@@ -281,6 +579,7 @@ func (in *SecretsScope) DeepCopyInto(out *SecretsScope) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	out.Cache = in.Cache
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsScope.
@@ -350,10 +649,27 @@ func (in *SelectorSpec) DeepCopy() *SelectorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceInvocationSpec) DeepCopyInto(out *ServiceInvocationSpec) {
+	*out = *in
+	in.ResponseHeaders.DeepCopyInto(&out.ResponseHeaders)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceInvocationSpec.
+func (in *ServiceInvocationSpec) DeepCopy() *ServiceInvocationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceInvocationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TracingSpec) DeepCopyInto(out *TracingSpec) {
 	*out = *in
 	out.Zipkin = in.Zipkin
+	out.APISamplingRates = in.APISamplingRates
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TracingSpec.
@@ -28,6 +28,9 @@ type SubscriptionSpec struct {
 	Topic      string `json:"topic"`
 	Route      string `json:"route"`
 	Pubsubname string `json:"pubsubname"`
+	// The optional metadata of the subscription, e.g. the handler options.
+	// +optional
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -27,16 +27,16 @@ type Channel struct {
 	baseAddress      string
 	ch               chan int
 	tracingSpec      config.TracingSpec
-	appMetadataToken string
+	appMetadataToken *auth.Token
 }
 
 // CreateLocalChannel creates a gRPC connection with user code
-func CreateLocalChannel(port, maxConcurrency int, conn *grpc.ClientConn, spec config.TracingSpec) *Channel {
+func CreateLocalChannel(port, maxConcurrency int, conn *grpc.ClientConn, spec config.TracingSpec, appToken *auth.Token) *Channel {
 	c := &Channel{
 		client:           conn,
 		baseAddress:      fmt.Sprintf("%s:%d", channel.DefaultChannelAddress, port),
 		tracingSpec:      spec,
-		appMetadataToken: auth.GetAppToken(),
+		appMetadataToken: appToken,
 	}
 	if maxConcurrency > 0 {
 		c.ch = make(chan int, maxConcurrency)
@@ -76,8 +76,8 @@ func (g *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 	clientV1 := runtimev1pb.NewAppCallbackClient(g.client)
 	grpcMetadata := invokev1.InternalMetadataToGrpcMetadata(ctx, req.Metadata(), true)
 
-	if g.appMetadataToken != "" {
-		grpcMetadata.Set(auth.APITokenHeader, g.appMetadataToken)
+	if token := g.appMetadataToken.Value(); token != "" {
+		grpcMetadata.Set(auth.APITokenHeader, token)
 	}
 
 	// Prepare gRPC Metadata
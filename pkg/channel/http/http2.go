@@ -0,0 +1,59 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/http2"
+)
+
+// newHTTP2Client returns a client that multiplexes the requests to the app over HTTP/2,
+// in cleartext (h2c) if tlsConfig is nil.
+func newHTTP2Client(tlsConfig *tls.Config) *http.Client {
+	transport := &http2.Transport{DisableCompression: true}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	} else {
+		transport.AllowHTTP = true
+		transport.DialTLS = func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		}
+	}
+	return &http.Client{Transport: transport}
+}
+
+// doHTTP2 sends a fasthttp request through an HTTP/2 client and fills resp with the response.
+func doHTTP2(ctx context.Context, client *http.Client, req *fasthttp.Request, resp *fasthttp.Response) error {
+	httpReq, err := http.NewRequestWithContext(ctx, string(req.Header.Method()), req.URI().String(), bytes.NewReader(req.Body()))
+	if err != nil {
+		return err
+	}
+	req.Header.VisitAll(func(key []byte, value []byte) {
+		httpReq.Header.Add(string(key), string(value))
+	})
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+
+	resp.SetStatusCode(httpResp.StatusCode)
+	for key, values := range httpResp.Header {
+		for _, value := range values {
+			resp.Header.Add(key, value)
+		}
+	}
+	_, err = io.Copy(resp.BodyWriter(), httpResp.Body)
+	return err
+}
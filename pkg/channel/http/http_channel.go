@@ -9,6 +9,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net/http"
 	"strconv"
 	"time"
 
@@ -31,25 +32,51 @@ const (
 	HTTPStatusCode = "http.status_code"
 	httpScheme     = "http"
 	httpsScheme    = "https"
+
+	// maxConnWaitTimeout is how long a request waits for a free connection when the connections to the app are limited.
+	maxConnWaitTimeout = time.Minute
 )
 
+// ConnectionConfig configures the connections of the channel to the app.
+type ConnectionConfig struct {
+	// MaxConns limits the HTTP/1.1 connections to the app, with the requests waiting
+	// for a free connection once it is reached. 0 means no limit.
+	MaxConns int
+	// MaxIdleConnDuration is how long an idle HTTP/1.1 connection is kept open.
+	// 0 means the default of fasthttp.
+	MaxIdleConnDuration time.Duration
+	// HTTP2 multiplexes the requests over HTTP/2, in cleartext (h2c) unless SSL is enabled.
+	HTTP2 bool
+}
+
 // Channel is an HTTP implementation of an AppChannel
 type Channel struct {
 	client         *fasthttp.Client
+	http2Client    *http.Client
 	baseAddress    string
 	ch             chan int
 	tracingSpec    config.TracingSpec
-	appHeaderToken string
+	appHeaderToken *auth.Token
 }
 
 // CreateLocalChannel creates an HTTP AppChannel
+func CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, tlsConfig *tls.Config) (channel.AppChannel, error) {
+	return CreateLocalChannelWithConnectionConfig(port, maxConcurrency, spec, sslEnabled, tlsConfig, ConnectionConfig{})
+}
+
+// CreateLocalChannelWithConnectionConfig creates an HTTP AppChannel whose connections to the app are configured by connConfig
 // nolint:gosec
-func CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool) (channel.AppChannel, error) {
+func CreateLocalChannelWithConnectionConfig(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, tlsConfig *tls.Config, connConfig ConnectionConfig) (channel.AppChannel, error) {
 	scheme := httpScheme
 	if sslEnabled {
 		scheme = httpsScheme
 	}
 
+	appToken, err := auth.LoadAppToken()
+	if err != nil {
+		return nil, err
+	}
+
 	c := &Channel{
 		client: &fasthttp.Client{
 			MaxConnsPerHost:           1000000,
@@ -57,11 +84,30 @@ func CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEn
 		},
 		baseAddress:    fmt.Sprintf("%s://%s:%d", scheme, channel.DefaultChannelAddress, port),
 		tracingSpec:    spec,
-		appHeaderToken: auth.GetAppToken(),
+		appHeaderToken: appToken,
+	}
+
+	if connConfig.MaxConns > 0 {
+		c.client.MaxConnsPerHost = connConfig.MaxConns
+		c.client.MaxConnWaitTimeout = maxConnWaitTimeout
+	}
+	if connConfig.MaxIdleConnDuration > 0 {
+		c.client.MaxIdleConnDuration = connConfig.MaxIdleConnDuration
 	}
 
 	if sslEnabled {
-		c.client.TLSConfig = &tls.Config{InsecureSkipVerify: true}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		c.client.TLSConfig = tlsConfig
+	}
+
+	if connConfig.HTTP2 {
+		if sslEnabled {
+			c.http2Client = newHTTP2Client(tlsConfig)
+		} else {
+			c.http2Client = newHTTP2Client(nil)
+		}
 	}
 
 	if maxConcurrency > 0 {
@@ -114,7 +160,7 @@ func (h *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 
 	// Send request to user application
 	var resp = fasthttp.AcquireResponse()
-	err := h.client.Do(channelReq, resp)
+	err := h.do(ctx, channelReq, resp)
 	defer func() {
 		fasthttp.ReleaseRequest(channelReq)
 		fasthttp.ReleaseResponse(resp)
@@ -132,6 +178,17 @@ func (h *Channel) invokeMethodV1(ctx context.Context, req *invokev1.InvokeMethod
 	return rsp, nil
 }
 
+// do sends the request to the app, giving up at the deadline of ctx if it has one.
+func (h *Channel) do(ctx context.Context, req *fasthttp.Request, resp *fasthttp.Response) error {
+	if h.http2Client != nil {
+		return doHTTP2(ctx, h.http2Client, req, resp)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		return h.client.DoDeadline(req, resp, deadline)
+	}
+	return h.client.Do(req, resp)
+}
+
 func (h *Channel) constructRequest(ctx context.Context, req *invokev1.InvokeMethodRequest) *fasthttp.Request {
 	var channelReq = fasthttp.AcquireRequest()
 
@@ -153,14 +210,14 @@ func (h *Channel) constructRequest(ctx context.Context, req *invokev1.InvokeMeth
 		channelReq.Header.Set("tracestate", ts)
 	}
 
-	if h.appHeaderToken != "" {
-		channelReq.Header.Set(auth.APITokenHeader, h.appHeaderToken)
+	if token := h.appHeaderToken.Value(); token != "" {
+		channelReq.Header.Set(auth.APITokenHeader, token)
 	}
 
-	// Set Content body and types
+	// Set Content body and types. The body is not copied as req outlives channelReq.
 	contentType, body := req.RawData()
 	channelReq.Header.SetContentType(contentType)
-	channelReq.SetBody(body)
+	channelReq.SetBodyRaw(body)
 
 	return channelReq
 }
@@ -177,12 +234,13 @@ func (h *Channel) parseChannelResponse(req *invokev1.InvokeMethodRequest, resp *
 	} else {
 		statusCode = resp.StatusCode()
 		contentType = (string)(resp.Header.ContentType())
-		body = resp.Body()
+		// Take over the body buffer instead of copying it before resp is released.
+		body = resp.SwapBody(nil)
 	}
 
 	// Convert status code
 	rsp := invokev1.NewInvokeMethodResponse(int32(statusCode), "", nil)
-	rsp.WithFastHTTPHeaders(&resp.Header).WithRawData(body, contentType)
+	rsp.WithFastHTTPHeaders(&resp.Header).WithRawDataNoCopy(body, contentType)
 
 	return rsp
 }
@@ -6,18 +6,24 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/dapr/dapr/pkg/config"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 type testConcurrencyHandler struct {
@@ -61,6 +67,23 @@ func (t *testHandlerHeaders) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, string(rsp))
 }
 
+type testEchoHandler struct {
+}
+
+func (t *testEchoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	io.Copy(w, r.Body)
+}
+
+type testProtoHandler struct {
+}
+
+func (t *testProtoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("X-Header", r.Header.Get("X-Header"))
+	w.WriteHeader(http.StatusAccepted)
+	io.WriteString(w, r.Proto)
+}
+
 // testHTTPHandler is used for querystring test
 type testHTTPHandler struct {
 	serverURL string
@@ -210,6 +233,92 @@ func TestInvokeWithHeaders(t *testing.T) {
 	testServer.Close()
 }
 
+func TestInvokeWithLargePayload(t *testing.T) {
+	ctx := context.Background()
+	testServer := httptest.NewServer(&testEchoHandler{})
+	c := Channel{baseAddress: testServer.URL, client: &fasthttp.Client{}}
+
+	invoke := func(payload []byte) []byte {
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithHTTPExtension(http.MethodPost, "")
+		req.WithRawData(payload, "application/octet-stream")
+		response, err := c.InvokeMethod(ctx, req)
+		assert.NoError(t, err)
+		_, body := response.RawData()
+		return body
+	}
+
+	first := bytes.Repeat([]byte("a"), 4*1024*1024)
+	second := bytes.Repeat([]byte("b"), 4*1024*1024)
+	firstBody := invoke(first)
+	secondBody := invoke(second)
+
+	// The response bodies are taken over from the released responses and must not share their buffers.
+	assert.Equal(t, first, firstBody)
+	assert.Equal(t, second, secondBody)
+	testServer.Close()
+}
+
+func TestInvokeWithHTTP2(t *testing.T) {
+	ctx := context.Background()
+	testServer := httptest.NewServer(h2c.NewHandler(&testProtoHandler{}, &http2.Server{}))
+	c := Channel{baseAddress: testServer.URL, client: &fasthttp.Client{}, http2Client: newHTTP2Client(nil)}
+
+	req := invokev1.NewInvokeMethodRequest("method")
+	req.WithMetadata(map[string][]string{"X-Header": {"value"}})
+	req.WithHTTPExtension(http.MethodPost, "")
+
+	// act
+	response, err := c.InvokeMethod(ctx, req)
+
+	// assert
+	assert.NoError(t, err)
+	contentType, body := response.RawData()
+	assert.Equal(t, "HTTP/2.0", string(body))
+	assert.Equal(t, "text/plain", contentType)
+	assert.Equal(t, int32(http.StatusAccepted), response.Status().Code)
+	assert.Equal(t, "value", response.Headers()["X-Header"].Values[0])
+	testServer.Close()
+}
+
+type testSlowHandler struct {
+	delay time.Duration
+}
+
+func (t *testSlowHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(t.delay)
+	io.WriteString(w, "done")
+}
+
+func TestInvokeMethodDeadline(t *testing.T) {
+	testServer := httptest.NewServer(h2c.NewHandler(&testSlowHandler{delay: time.Second}, &http2.Server{}))
+	defer testServer.Close()
+
+	invoke := func(c *Channel) (*invokev1.InvokeMethodResponse, time.Duration) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		req := invokev1.NewInvokeMethodRequest("method")
+		req.WithHTTPExtension(http.MethodPost, "")
+
+		start := time.Now()
+		response, err := c.InvokeMethod(ctx, req)
+		assert.NoError(t, err)
+		return response, time.Since(start)
+	}
+
+	t.Run("http/1.1", func(t *testing.T) {
+		response, elapsed := invoke(&Channel{baseAddress: testServer.URL, client: &fasthttp.Client{}})
+		assert.Equal(t, int32(http.StatusInternalServerError), response.Status().Code)
+		assert.Less(t, int64(elapsed), int64(500*time.Millisecond))
+	})
+
+	t.Run("http/2", func(t *testing.T) {
+		response, elapsed := invoke(&Channel{baseAddress: testServer.URL, client: &fasthttp.Client{}, http2Client: newHTTP2Client(nil)})
+		assert.Equal(t, int32(http.StatusInternalServerError), response.Status().Code)
+		assert.Less(t, int64(elapsed), int64(500*time.Millisecond))
+	})
+}
+
 func TestContentType(t *testing.T) {
 	ctx := context.Background()
 	t.Run("default application/json", func(t *testing.T) {
@@ -274,7 +383,7 @@ func TestAppToken(t *testing.T) {
 	t.Run("token present", func(t *testing.T) {
 		ctx := context.Background()
 		testServer := httptest.NewServer(&testHandlerHeaders{})
-		c := Channel{baseAddress: testServer.URL, client: &fasthttp.Client{}, appHeaderToken: "token1"}
+		c := Channel{baseAddress: testServer.URL, client: &fasthttp.Client{}, appHeaderToken: auth.NewToken("token1")}
 
 		req := invokev1.NewInvokeMethodRequest("method")
 		req.WithHTTPExtension(http.MethodPost, "")
@@ -322,7 +431,7 @@ func TestAppToken(t *testing.T) {
 
 func TestCreateChannel(t *testing.T) {
 	t.Run("ssl scheme", func(t *testing.T) {
-		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, true)
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, true, nil)
 		assert.NoError(t, err)
 
 		b := ch.GetBaseAddress()
@@ -330,10 +439,38 @@ func TestCreateChannel(t *testing.T) {
 	})
 
 	t.Run("non-ssl scheme", func(t *testing.T) {
-		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, false)
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, false, nil)
 		assert.NoError(t, err)
 
 		b := ch.GetBaseAddress()
 		assert.Equal(t, b, "http://127.0.0.1:3000")
 	})
+
+	t.Run("ssl with tls config", func(t *testing.T) {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, true, tlsConfig)
+		assert.NoError(t, err)
+		assert.Equal(t, tlsConfig, ch.(*Channel).client.TLSConfig)
+	})
+
+	t.Run("connection config", func(t *testing.T) {
+		connConfig := ConnectionConfig{
+			MaxConns:            100,
+			MaxIdleConnDuration: time.Minute,
+			HTTP2:               true,
+		}
+		ch, err := CreateLocalChannelWithConnectionConfig(3000, 0, config.TracingSpec{}, false, nil, connConfig)
+		assert.NoError(t, err)
+		c := ch.(*Channel)
+		assert.Equal(t, 100, c.client.MaxConnsPerHost)
+		assert.Equal(t, maxConnWaitTimeout, c.client.MaxConnWaitTimeout)
+		assert.Equal(t, time.Minute, c.client.MaxIdleConnDuration)
+		assert.NotNil(t, c.http2Client)
+	})
+
+	t.Run("no http2 by default", func(t *testing.T) {
+		ch, err := CreateLocalChannel(3000, 0, config.TracingSpec{}, false, nil)
+		assert.NoError(t, err)
+		assert.Nil(t, ch.(*Channel).http2Client)
+	})
 }
@@ -15,11 +15,12 @@ import (
 
 	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	config "github.com/dapr/dapr/pkg/config/modes"
+	"github.com/dapr/dapr/pkg/logsampler"
 	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
 	"github.com/dapr/kit/logger"
 )
 
-var log = logger.NewLogger("dapr.runtime.components")
+var log = logsampler.NewLogger(logger.NewLogger("dapr.runtime.components"))
 
 const (
 	operatorCallTimeout = time.Second * 5
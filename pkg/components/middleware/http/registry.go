@@ -58,7 +58,11 @@ func (p *httpMiddlewareRegistry) Register(components ...Middleware) {
 // Create instantiates a HTTP middleware based on `name`.
 func (p *httpMiddlewareRegistry) Create(name, version string, metadata middleware.Metadata) (http_middleware.Middleware, error) {
 	if method, ok := p.getMiddleware(name, version); ok {
-		return method(metadata), nil
+		// factories return no middleware when the metadata is invalid
+		if handler := method(metadata); handler != nil {
+			return handler, nil
+		}
+		return nil, errors.Errorf("HTTP middleware %s/%s failed to initialize", name, version)
 	}
 	return nil, errors.Errorf("HTTP middleware %s/%s has not been registered", name, version)
 }
@@ -70,6 +70,24 @@ func TestRegistry(t *testing.T) {
 		assert.Equal(t, fmt.Sprintf("%v", mockV2), fmt.Sprintf("%v", pV2))
 	})
 
+	t.Run("middleware fails to initialize", func(t *testing.T) {
+		const (
+			middlewareName = "badMiddleware"
+			componentName  = "middleware.http." + middlewareName
+		)
+
+		testRegistry.Register(http.New(middlewareName, func(h.Metadata) http_middleware.Middleware {
+			return nil
+		}))
+
+		// act
+		p, actualError := testRegistry.Create(componentName, "v1", h.Metadata{})
+
+		// assert
+		assert.Nil(t, p)
+		assert.EqualError(t, actualError, "HTTP middleware middleware.http.badMiddleware/v1 failed to initialize")
+	})
+
 	t.Run("middleware is not registered", func(t *testing.T) {
 		const (
 			middlewareName = "fakeMiddleware"
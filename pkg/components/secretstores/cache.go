@@ -0,0 +1,138 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package secretstores
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dapr/components-contrib/secretstores"
+)
+
+// DefaultCacheMaxEntries is the default number of secrets a cached secret store keeps.
+const DefaultCacheMaxEntries = 1000
+
+// CacheInvalidator is implemented by secret stores that cache secrets.
+type CacheInvalidator interface {
+	InvalidateCache()
+}
+
+type cachedSecret struct {
+	resp    secretstores.GetSecretResponse
+	expires time.Time
+}
+
+// CachedSecretStore is a secret store that caches the secrets it gets from the wrapped secret store.
+type CachedSecretStore struct {
+	secretstores.SecretStore
+
+	ttl        time.Duration
+	maxEntries int
+	lock       sync.Mutex
+	entries    map[string]cachedSecret
+	now        func() time.Time
+}
+
+// NewCachedSecretStore returns a secret store that caches the secrets of the given secret store for the ttl.
+func NewCachedSecretStore(store secretstores.SecretStore, ttl time.Duration, maxEntries int) *CachedSecretStore {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+
+	return &CachedSecretStore{
+		SecretStore: store,
+		ttl:         ttl,
+		maxEntries:  maxEntries,
+		entries:     map[string]cachedSecret{},
+		now:         time.Now,
+	}
+}
+
+// GetSecret returns the cached secret, or gets it from the wrapped secret store and caches it.
+func (c *CachedSecretStore) GetSecret(req secretstores.GetSecretRequest) (secretstores.GetSecretResponse, error) {
+	key := cacheKey(req)
+
+	c.lock.Lock()
+	entry, ok := c.entries[key]
+	c.lock.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		return entry.resp, nil
+	}
+
+	resp, err := c.SecretStore.GetSecret(req)
+	if err != nil {
+		return resp, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.evict()
+	c.entries[key] = cachedSecret{
+		resp:    resp,
+		expires: c.now().Add(c.ttl),
+	}
+	return resp, nil
+}
+
+// Close closes the wrapped secret store.
+func (c *CachedSecretStore) Close() error {
+	if closer, ok := c.SecretStore.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// InvalidateCache removes all cached secrets.
+func (c *CachedSecretStore) InvalidateCache() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = map[string]cachedSecret{}
+}
+
+// evict makes room for a new entry by removing expired entries and, when the cache is
+// still full, the entry that expires first. The lock must be held.
+func (c *CachedSecretStore) evict() {
+	if len(c.entries) < c.maxEntries {
+		return
+	}
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	for len(c.entries) >= c.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		found := false
+		for k, entry := range c.entries {
+			if !found || entry.expires.Before(oldest) {
+				oldestKey, oldest, found = k, entry.expires, true
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+}
+
+func cacheKey(req secretstores.GetSecretRequest) string {
+	keys := make([]string, 0, len(req.Metadata))
+	for k := range req.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(req.Name)
+	for _, k := range keys {
+		b.WriteString("|" + k + "=" + req.Metadata[k])
+	}
+	return b.String()
+}
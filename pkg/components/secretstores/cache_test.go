@@ -0,0 +1,96 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package secretstores
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dapr/components-contrib/secretstores"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingSecretStore struct {
+	calls int
+}
+
+func (c *countingSecretStore) Init(metadata secretstores.Metadata) error {
+	return nil
+}
+
+func (c *countingSecretStore) GetSecret(req secretstores.GetSecretRequest) (secretstores.GetSecretResponse, error) {
+	c.calls++
+	return secretstores.GetSecretResponse{
+		Data: map[string]string{req.Name: "value"},
+	}, nil
+}
+
+func (c *countingSecretStore) BulkGetSecret(req secretstores.BulkGetSecretRequest) (secretstores.BulkGetSecretResponse, error) {
+	return secretstores.BulkGetSecretResponse{}, nil
+}
+
+func TestCachedSecretStore(t *testing.T) {
+	now := time.Now()
+	newStore := func(maxEntries int) (*CachedSecretStore, *countingSecretStore) {
+		inner := &countingSecretStore{}
+		store := NewCachedSecretStore(inner, time.Minute, maxEntries)
+		store.now = func() time.Time { return now }
+		return store, inner
+	}
+
+	t.Run("cached within ttl", func(t *testing.T) {
+		store, inner := newStore(0)
+		req := secretstores.GetSecretRequest{Name: "secret1"}
+
+		resp, err := store.GetSecret(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "value", resp.Data["secret1"])
+		_, err = store.GetSecret(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, inner.calls)
+	})
+
+	t.Run("fetched again after ttl", func(t *testing.T) {
+		store, inner := newStore(0)
+		req := secretstores.GetSecretRequest{Name: "secret1"}
+
+		store.GetSecret(req)
+		now = now.Add(time.Minute)
+		store.GetSecret(req)
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("metadata is part of the cache key", func(t *testing.T) {
+		store, inner := newStore(0)
+
+		store.GetSecret(secretstores.GetSecretRequest{Name: "secret1", Metadata: map[string]string{"namespace": "a"}})
+		store.GetSecret(secretstores.GetSecretRequest{Name: "secret1", Metadata: map[string]string{"namespace": "b"}})
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("invalidate cache", func(t *testing.T) {
+		store, inner := newStore(0)
+		req := secretstores.GetSecretRequest{Name: "secret1"}
+
+		store.GetSecret(req)
+		store.InvalidateCache()
+		store.GetSecret(req)
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("max entries", func(t *testing.T) {
+		store, _ := newStore(2)
+
+		store.GetSecret(secretstores.GetSecretRequest{Name: "secret1"})
+		now = now.Add(time.Second)
+		store.GetSecret(secretstores.GetSecretRequest{Name: "secret2"})
+		now = now.Add(time.Second)
+		store.GetSecret(secretstores.GetSecretRequest{Name: "secret3"})
+
+		assert.Len(t, store.entries, 2)
+		assert.NotContains(t, store.entries, "secret1")
+	})
+}
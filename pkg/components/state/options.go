@@ -0,0 +1,34 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package state
+
+import commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
+
+// ConsistencyToString returns the state store consistency value of a protobuf state option,
+// or an empty string if it is unspecified.
+func ConsistencyToString(c commonv1pb.StateOptions_StateConsistency) string {
+	switch c {
+	case commonv1pb.StateOptions_CONSISTENCY_EVENTUAL:
+		return "eventual"
+	case commonv1pb.StateOptions_CONSISTENCY_STRONG:
+		return "strong"
+	}
+
+	return ""
+}
+
+// ConcurrencyToString returns the state store concurrency value of a protobuf state option,
+// or an empty string if it is unspecified.
+func ConcurrencyToString(c commonv1pb.StateOptions_StateConcurrency) string {
+	switch c {
+	case commonv1pb.StateOptions_CONCURRENCY_FIRST_WRITE:
+		return "first-write"
+	case commonv1pb.StateOptions_CONCURRENCY_LAST_WRITE:
+		return "last-write"
+	}
+
+	return ""
+}
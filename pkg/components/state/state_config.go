@@ -21,6 +21,10 @@ const (
 	strategyDefault   = strategyAppid
 
 	daprSeparator = "||"
+
+	// reservedKeyPrefix starts the keys daprd keeps for itself in the state stores of an app, e.g. the
+	// transaction outbox. The state API rejects the keys with it, so that apps can't read or overwrite them.
+	reservedKeyPrefix = "_dapr"
 )
 
 var statesConfiguration = map[string]*StoreConfiguration{}
@@ -49,6 +53,9 @@ func GetModifiedStateKey(key, storeName, appID string) (string, error) {
 	if err := checkKeyIllegal(key); err != nil {
 		return "", err
 	}
+	if strings.HasPrefix(key, reservedKeyPrefix) {
+		return "", errors.Errorf("input key '%s' can't start with the reserved prefix '%s'", key, reservedKeyPrefix)
+	}
 	stateConfiguration := getStateConfiguration(storeName)
 	switch stateConfiguration.keyPrefixStrategy {
 	case strategyNone:
@@ -65,6 +72,12 @@ func GetModifiedStateKey(key, storeName, appID string) (string, error) {
 	}
 }
 
+// GetReservedStateKey returns a key daprd keeps for itself in the state stores of an app. It starts with
+// the app id and the reserved key prefix, which the state API rejects.
+func GetReservedStateKey(appID string, parts ...string) string {
+	return strings.Join(append([]string{appID, reservedKeyPrefix}, parts...), daprSeparator)
+}
+
 func GetOriginalStateKey(modifiedStateKey string) string {
 	splits := strings.Split(modifiedStateKey, daprSeparator)
 	if len(splits) <= 1 {
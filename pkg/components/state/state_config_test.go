@@ -139,3 +139,12 @@ func TestPrefix_StoreNotInitial(t *testing.T) {
 	originalStateKey := GetOriginalStateKey(modifiedStateKey)
 	require.Equal(t, key, originalStateKey)
 }
+
+func TestReservedStateKey(t *testing.T) {
+	_, err := GetModifiedStateKey("_dapr", "store2", "app1")
+	require.Error(t, err)
+	_, err = GetModifiedStateKey("_dapr||outbox", "store1", "app1")
+	require.Error(t, err)
+
+	require.Equal(t, "app1||_dapr||outbox||1", GetReservedStateKey("app1", "outbox", "1"))
+}
@@ -0,0 +1,69 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package components
+
+import (
+	"sync"
+	"time"
+)
+
+// InitStatus is the initialization status of a component.
+type InitStatus string
+
+const (
+	InitStatusInitializing InitStatus = "INITIALIZING"
+	InitStatusSucceeded    InitStatus = "SUCCEEDED"
+	InitStatusFailed       InitStatus = "FAILED"
+)
+
+// Status describes the outcome of the last initialization of a component.
+type Status struct {
+	Name         string
+	Type         string
+	Version      string
+	InitStatus   InitStatus
+	Error        string
+	InitDuration time.Duration
+}
+
+// StatusStore keeps the initialization status of components, in the order they were first seen.
+type StatusStore struct {
+	statuses map[string]Status
+	order    []string
+	lock     *sync.RWMutex
+}
+
+// NewStatusStore returns an empty StatusStore.
+func NewStatusStore() *StatusStore {
+	return &StatusStore{
+		statuses: map[string]Status{},
+		lock:     &sync.RWMutex{},
+	}
+}
+
+// Set records the status of a component, replacing any previous status for the same component.
+func (s *StatusStore) Set(status Status) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := status.Type + "/" + status.Name
+	if _, ok := s.statuses[key]; !ok {
+		s.order = append(s.order, key)
+	}
+	s.statuses[key] = status
+}
+
+// List returns the status of every component.
+func (s *StatusStore) List() []Status {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	list := make([]Status, 0, len(s.order))
+	for _, key := range s.order {
+		list = append(list, s.statuses[key])
+	}
+	return list
+}
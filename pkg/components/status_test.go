@@ -0,0 +1,28 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusStore(t *testing.T) {
+	s := NewStatusStore()
+	assert.Empty(t, s.List())
+
+	s.Set(Status{Name: "a", Type: "state.redis", InitStatus: InitStatusInitializing})
+	s.Set(Status{Name: "b", Type: "pubsub.redis", InitStatus: InitStatusFailed, Error: "boom"})
+	s.Set(Status{Name: "a", Type: "state.redis", InitStatus: InitStatusSucceeded})
+
+	list := s.List()
+	assert.Len(t, list, 2)
+	assert.Equal(t, "a", list[0].Name)
+	assert.Equal(t, InitStatusSucceeded, list[0].InitStatus)
+	assert.Equal(t, "b", list[1].Name)
+	assert.Equal(t, "boom", list[1].Error)
+}
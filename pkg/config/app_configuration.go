@@ -15,4 +15,15 @@ type ApplicationConfig struct {
 	// Duration. example: "30s"
 	DrainOngoingCallTimeout string `json:"drainOngoingCallTimeout"`
 	DrainRebalancedActors   bool   `json:"drainRebalancedActors"`
+	// EntitiesConfig overrides the drain settings above for the given actor types.
+	EntitiesConfig []EntityConfig `json:"entitiesConfig"`
+}
+
+// EntityConfig overrides the drain settings of ApplicationConfig for a set of actor types.
+// Settings that are left empty fall back to the values of ApplicationConfig.
+type EntityConfig struct {
+	Entities []string `json:"entities"`
+	// Duration. example: "30s"
+	DrainOngoingCallTimeout string `json:"drainOngoingCallTimeout"`
+	DrainRebalancedActors   *bool  `json:"drainRebalancedActors"`
 }
@@ -7,6 +7,7 @@ package config
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/asn1"
 	"encoding/json"
 	"fmt"
@@ -21,6 +22,7 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
 	yaml "gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 
@@ -43,6 +45,11 @@ const (
 	SpiffeIDPrefix      = "spiffe://"
 	HTTPProtocol        = "http"
 	GRPCProtocol        = "grpc"
+
+	ActorPlacementSticky           = "sticky"
+	ActorPlacementRebalanceEagerly = "rebalance-eagerly"
+
+	defaultProfilingInterval = time.Second * 10
 )
 
 // Configuration is an internal (and duplicate) representation of Dapr's Configuration CRD.
@@ -59,6 +66,7 @@ type AccessControlList struct {
 	DefaultAction string
 	TrustDomain   string
 	PolicySpec    map[string]AccessControlListPolicySpec
+	JWTValidator  *JWTValidator
 }
 
 // AccessControlListPolicySpec is an in-memory access control list config per app for fast lookup
@@ -75,16 +83,24 @@ type AccessControlListOperationAction struct {
 	VerbAction       map[string]string
 	OperationPostFix string
 	OperationAction  string
+	Claims           map[string]string
 }
 
 type ConfigurationSpec struct {
-	HTTPPipelineSpec   PipelineSpec       `json:"httpPipeline,omitempty" yaml:"httpPipeline,omitempty"`
-	TracingSpec        TracingSpec        `json:"tracing,omitempty" yaml:"tracing,omitempty"`
-	MTLSSpec           MTLSSpec           `json:"mtls,omitempty"`
-	MetricSpec         MetricSpec         `json:"metric,omitempty" yaml:"metric,omitempty"`
-	Secrets            SecretsSpec        `json:"secrets,omitempty" yaml:"secrets,omitempty"`
-	AccessControlSpec  AccessControlSpec  `json:"accessControl,omitempty" yaml:"accessControl,omitempty"`
-	NameResolutionSpec NameResolutionSpec `json:"nameResolution,omitempty" yaml:"nameResolution,omitempty"`
+	HTTPPipelineSpec   PipelineSpec          `json:"httpPipeline,omitempty" yaml:"httpPipeline,omitempty"`
+	GRPCPipelineSpec   PipelineSpec          `json:"grpcPipeline,omitempty" yaml:"grpcPipeline,omitempty"`
+	TracingSpec        TracingSpec           `json:"tracing,omitempty" yaml:"tracing,omitempty"`
+	MTLSSpec           MTLSSpec              `json:"mtls,omitempty"`
+	MetricSpec         MetricSpec            `json:"metric,omitempty" yaml:"metric,omitempty"`
+	Secrets            SecretsSpec           `json:"secrets,omitempty" yaml:"secrets,omitempty"`
+	AccessControlSpec  AccessControlSpec     `json:"accessControl,omitempty" yaml:"accessControl,omitempty"`
+	NameResolutionSpec NameResolutionSpec    `json:"nameResolution,omitempty" yaml:"nameResolution,omitempty"`
+	ServiceInvocation  ServiceInvocationSpec `json:"serviceInvocation,omitempty" yaml:"serviceInvocation,omitempty"`
+	Actors             ActorsSpec            `json:"actors,omitempty" yaml:"actors,omitempty"`
+	MaxBodySize        MaxBodySizeSpec       `json:"maxBodySize,omitempty" yaml:"maxBodySize,omitempty"`
+	Idempotency        IdempotencySpec       `json:"idempotency,omitempty" yaml:"idempotency,omitempty"`
+	Profiling          ProfilingSpec         `json:"profiling,omitempty" yaml:"profiling,omitempty"`
+	GC                 GCSpec                `json:"gc,omitempty" yaml:"gc,omitempty"`
 }
 
 type SecretsSpec struct {
@@ -93,14 +109,45 @@ type SecretsSpec struct {
 
 // SecretsScope defines the scope for secrets
 type SecretsScope struct {
-	DefaultAccess  string   `json:"defaultAccess,omitempty" yaml:"defaultAccess,omitempty"`
-	StoreName      string   `json:"storeName" yaml:"storeName"`
-	AllowedSecrets []string `json:"allowedSecrets,omitempty" yaml:"allowedSecrets,omitempty"`
-	DeniedSecrets  []string `json:"deniedSecrets,omitempty" yaml:"deniedSecrets,omitempty"`
+	DefaultAccess  string           `json:"defaultAccess,omitempty" yaml:"defaultAccess,omitempty"`
+	StoreName      string           `json:"storeName" yaml:"storeName"`
+	AllowedSecrets []string         `json:"allowedSecrets,omitempty" yaml:"allowedSecrets,omitempty"`
+	DeniedSecrets  []string         `json:"deniedSecrets,omitempty" yaml:"deniedSecrets,omitempty"`
+	Cache          SecretsCacheSpec `json:"cache,omitempty" yaml:"cache,omitempty"`
+}
+
+// SecretsCacheSpec configures the caching of secrets read from a secret store.
+// Secrets are not cached when the TTL is empty.
+type SecretsCacheSpec struct {
+	TTL        string `json:"ttl" yaml:"ttl"`
+	MaxEntries int    `json:"maxEntries,omitempty" yaml:"maxEntries,omitempty"`
+}
+
+// GetTTL returns how long secrets are cached.
+func (c SecretsCacheSpec) GetTTL() (time.Duration, error) {
+	if c.TTL == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(c.TTL)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid secrets cache ttl")
+	}
+	if ttl < 0 {
+		return 0, errors.New("secrets cache ttl must not be negative")
+	}
+	return ttl, nil
 }
 
 type PipelineSpec struct {
 	Handlers []HandlerSpec `json:"handlers" yaml:"handlers"`
+	// Outbound middlewares are applied to the service invocation requests of the HTTP and gRPC APIs sent to other apps.
+	Outbound    []HandlerSpec   `json:"outbound,omitempty" yaml:"outbound,omitempty"`
+	DecisionLog DecisionLogSpec `json:"decisionLog,omitempty" yaml:"decisionLog,omitempty"`
+}
+
+// DecisionLogSpec configures the sink receiving the allow/deny decisions of the middlewares in the pipeline.
+type DecisionLogSpec struct {
+	Sink string `json:"sink" yaml:"sink"`
 }
 
 type HandlerSpec struct {
@@ -120,9 +167,20 @@ type SelectorField struct {
 }
 
 type TracingSpec struct {
-	SamplingRate string     `json:"samplingRate" yaml:"samplingRate"`
-	Stdout       bool       `json:"stdout" yaml:"stdout"`
-	Zipkin       ZipkinSpec `json:"zipkin" yaml:"zipkin"`
+	SamplingRate   string     `json:"samplingRate" yaml:"samplingRate"`
+	Stdout         bool       `json:"stdout" yaml:"stdout"`
+	Zipkin         ZipkinSpec `json:"zipkin" yaml:"zipkin"`
+	MaxBaggageSize int        `json:"maxBaggageSize,omitempty" yaml:"maxBaggageSize,omitempty"`
+	// APISamplingRates overrides SamplingRate for individual API types
+	APISamplingRates APISamplingRatesSpec `json:"apiSamplingRates,omitempty" yaml:"apiSamplingRates,omitempty"`
+}
+
+// APISamplingRatesSpec defines the sampling rate of each API type. Empty rates use the global sampling rate.
+type APISamplingRatesSpec struct {
+	Invocation string `json:"invocation,omitempty" yaml:"invocation,omitempty"`
+	PubSub     string `json:"pubsub,omitempty" yaml:"pubsub,omitempty"`
+	State      string `json:"state,omitempty" yaml:"state,omitempty"`
+	Actors     string `json:"actors,omitempty" yaml:"actors,omitempty"`
 }
 
 // ZipkinSpec defines Zipkin trace configurations
@@ -132,7 +190,18 @@ type ZipkinSpec struct {
 
 // MetricSpec configuration for metrics
 type MetricSpec struct {
-	Enabled bool `json:"enabled" yaml:"enabled"`
+	Enabled bool           `json:"enabled" yaml:"enabled"`
+	HTTP    MetricHTTPSpec `json:"http,omitempty" yaml:"http,omitempty"`
+}
+
+// MetricHTTPSpec configures the labels recorded on HTTP server metrics.
+// Paths matching one of PathTemplates are recorded as the template, where a {name}
+// segment matches any single segment and a trailing * matches the rest of the path.
+// When CollapseUnmatchedPaths is set, other paths are recorded as "_unmatched".
+type MetricHTTPSpec struct {
+	ExcludedLabels         []string `json:"excludedLabels,omitempty" yaml:"excludedLabels,omitempty"`
+	PathTemplates          []string `json:"pathTemplates,omitempty" yaml:"pathTemplates,omitempty"`
+	CollapseUnmatchedPaths bool     `json:"collapseUnmatchedPaths,omitempty" yaml:"collapseUnmatchedPaths,omitempty"`
 }
 
 // AppPolicySpec defines the policy data structure for each app
@@ -146,9 +215,10 @@ type AppPolicySpec struct {
 
 // AppOperation defines the data structure for each app operation
 type AppOperation struct {
-	Operation string   `json:"name" yaml:"name"`
-	HTTPVerb  []string `json:"httpVerb" yaml:"httpVerb"`
-	Action    string   `json:"action" yaml:"action"`
+	Operation string            `json:"name" yaml:"name"`
+	HTTPVerb  []string          `json:"httpVerb" yaml:"httpVerb"`
+	Action    string            `json:"action" yaml:"action"`
+	Claims    map[string]string `json:"claims,omitempty" yaml:"claims,omitempty"`
 }
 
 // AccessControlSpec is the spec object in ConfigurationSpec
@@ -156,6 +226,14 @@ type AccessControlSpec struct {
 	DefaultAction string          `json:"defaultAction" yaml:"defaultAction"`
 	TrustDomain   string          `json:"trustDomain" yaml:"trustDomain"`
 	AppPolicies   []AppPolicySpec `json:"policies" yaml:"policies"`
+	JWT           JWTSpec         `json:"jwt,omitempty" yaml:"jwt,omitempty"`
+}
+
+// JWTSpec defines how bearer tokens presented on service invocation calls are validated
+type JWTSpec struct {
+	Issuer     string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	Audience   string `json:"audience,omitempty" yaml:"audience,omitempty"`
+	PublicKeys string `json:"publicKeys,omitempty" yaml:"publicKeys,omitempty"`
 }
 
 type NameResolutionSpec struct {
@@ -165,9 +243,187 @@ type NameResolutionSpec struct {
 }
 
 type MTLSSpec struct {
-	Enabled          bool   `json:"enabled"`
-	WorkloadCertTTL  string `json:"workloadCertTTL"`
-	AllowedClockSkew string `json:"allowedClockSkew"`
+	Enabled            bool     `json:"enabled"`
+	WorkloadCertTTL    string   `json:"workloadCertTTL"`
+	AllowedClockSkew   string   `json:"allowedClockSkew"`
+	SPIFFEPathTemplate string   `json:"spiffePathTemplate,omitempty"`
+	ExtraURISANs       []string `json:"extraURISANs,omitempty"`
+}
+
+// ServiceInvocationSpec configures how the sidecar handles service invocation calls to its app
+type ServiceInvocationSpec struct {
+	ResponseHeaders HeaderFilterSpec `json:"responseHeaders,omitempty" yaml:"responseHeaders,omitempty"`
+}
+
+// MaxBodySizeSpec lowers the maximum request body size in MB of the HTTP API for groups of endpoints.
+// Groups without an override use the global maximum request body size, which also bounds the overrides.
+type MaxBodySizeSpec struct {
+	State      int `json:"state,omitempty" yaml:"state,omitempty"`
+	PubSub     int `json:"pubsub,omitempty" yaml:"pubsub,omitempty"`
+	Invocation int `json:"invocation,omitempty" yaml:"invocation,omitempty"`
+	Bindings   int `json:"bindings,omitempty" yaml:"bindings,omitempty"`
+}
+
+// IdempotencySpec configures the deduplication of publish and output binding requests
+// with an Idempotency-Key header. Deduplication is disabled when the window is empty.
+// The keys are kept in memory, or in the state store named by Store to share them between replicas.
+// MaxKeys bounds the number of keys kept in memory.
+type IdempotencySpec struct {
+	Window  string `json:"window,omitempty" yaml:"window,omitempty"`
+	Store   string `json:"store,omitempty" yaml:"store,omitempty"`
+	MaxKeys int    `json:"maxKeys,omitempty" yaml:"maxKeys,omitempty"`
+}
+
+// GetWindow returns how long the response of a request with an idempotency key is kept.
+func (i IdempotencySpec) GetWindow() (time.Duration, error) {
+	if i.Window == "" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(i.Window)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid idempotency window")
+	}
+	if window < 0 {
+		return 0, errors.New("idempotency window must not be negative")
+	}
+	return window, nil
+}
+
+// ProfilingSpec configures the continuous profiling, which periodically pushes pprof profiles
+// to a Pyroscope compatible endpoint. Continuous profiling is disabled when the endpoint is empty.
+type ProfilingSpec struct {
+	Endpoint     string            `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	Interval     string            `json:"interval,omitempty" yaml:"interval,omitempty"`
+	ProfileTypes []string          `json:"profileTypes,omitempty" yaml:"profileTypes,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// GetInterval returns how often the profiles are pushed, 10s by default.
+func (p ProfilingSpec) GetInterval() (time.Duration, error) {
+	if p.Interval == "" {
+		return defaultProfilingInterval, nil
+	}
+	interval, err := time.ParseDuration(p.Interval)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid profiling interval")
+	}
+	if interval <= 0 {
+		return 0, errors.New("profiling interval must be positive")
+	}
+	return interval, nil
+}
+
+// GCSpec tunes the garbage collector of the runtime. Percent is the equivalent of GOGC. MemoryLimit
+// is a soft limit, e.g. 512Mi, the heap is kept under by collecting more often as it approaches it.
+type GCSpec struct {
+	Percent     int    `json:"percent,omitempty" yaml:"percent,omitempty"`
+	MemoryLimit string `json:"memoryLimit,omitempty" yaml:"memoryLimit,omitempty"`
+}
+
+// GetMemoryLimit returns the soft memory limit in bytes, or 0 if it is not set.
+func (g GCSpec) GetMemoryLimit() (uint64, error) {
+	if g.MemoryLimit == "" {
+		return 0, nil
+	}
+	return ParseMemoryLimit(g.MemoryLimit)
+}
+
+// ParseMemoryLimit parses a quantity such as 512Mi into a number of bytes.
+func ParseMemoryLimit(limit string) (uint64, error) {
+	q, err := resource.ParseQuantity(limit)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid memory limit")
+	}
+	if q.Sign() <= 0 {
+		return 0, errors.New("memory limit must be positive")
+	}
+	return uint64(q.Value()), nil
+}
+
+// ActorsSpec configures the actor runtime
+type ActorsSpec struct {
+	DeactivationWarnings []ActorDeactivationWarning `json:"deactivationWarnings,omitempty" yaml:"deactivationWarnings,omitempty"`
+	PlacementHints       []ActorPlacementHint       `json:"placementHints,omitempty" yaml:"placementHints,omitempty"`
+}
+
+// ActorPlacementHint defines how the placement service rebalances the actors of the given type when the hosts change.
+// With sticky, the changes of the table of the actor type are held back for a while, so that the actors are not
+// moved when a host leaves and returns. With rebalance-eagerly, the table is disseminated without waiting for
+// the membership to settle.
+type ActorPlacementHint struct {
+	ActorType string `json:"actorType" yaml:"actorType"`
+	Mode      string `json:"mode" yaml:"mode"`
+}
+
+// ActorDeactivationWarning defines how long before an idle actor of the given type is deactivated the app is warned
+type ActorDeactivationWarning struct {
+	ActorType string `json:"actorType" yaml:"actorType"`
+	Window    string `json:"window" yaml:"window"`
+}
+
+// GetPlacementHints returns the placement hint mode of each actor type.
+func (a ActorsSpec) GetPlacementHints() (map[string]string, error) {
+	hints := map[string]string{}
+	for _, h := range a.PlacementHints {
+		if h.Mode != ActorPlacementSticky && h.Mode != ActorPlacementRebalanceEagerly {
+			return nil, errors.Errorf("invalid placement hint %s for actor type %s: must be %s or %s",
+				h.Mode, h.ActorType, ActorPlacementSticky, ActorPlacementRebalanceEagerly)
+		}
+		hints[h.ActorType] = h.Mode
+	}
+	return hints, nil
+}
+
+// GetDeactivationWarningWindows returns the deactivation warning window of each actor type.
+func (a ActorsSpec) GetDeactivationWarningWindows() (map[string]time.Duration, error) {
+	windows := map[string]time.Duration{}
+	for _, w := range a.DeactivationWarnings {
+		window, err := time.ParseDuration(w.Window)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid deactivation warning window for actor type %s", w.ActorType)
+		}
+		if window <= 0 {
+			return nil, errors.Errorf("deactivation warning window for actor type %s must be positive", w.ActorType)
+		}
+		windows[w.ActorType] = window
+	}
+	return windows, nil
+}
+
+// HeaderFilterSpec defines which headers are forwarded. Names are case-insensitive and
+// may end with * to match a prefix. Denied headers are never forwarded, and when an
+// allow list is set only the headers it matches are forwarded.
+type HeaderFilterSpec struct {
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+}
+
+// IsEmpty returns true if the filter forwards every header.
+func (h HeaderFilterSpec) IsEmpty() bool {
+	return len(h.Allow) == 0 && len(h.Deny) == 0
+}
+
+// IsAllowed returns true if the header with the given name is forwarded.
+func (h HeaderFilterSpec) IsAllowed(name string) bool {
+	if matchesHeader(h.Deny, name) {
+		return false
+	}
+	return len(h.Allow) == 0 || matchesHeader(h.Allow, name)
+}
+
+func matchesHeader(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(name, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+		} else if p == name {
+			return true
+		}
+	}
+	return false
 }
 
 // SpiffeID represents the separated fields in a spiffe id
@@ -318,6 +574,14 @@ func ParseAccessControlSpec(accessControlSpec AccessControlSpec, protocol string
 	accessControlList.PolicySpec = make(map[string]AccessControlListPolicySpec)
 	accessControlList.DefaultAction = strings.ToLower(accessControlSpec.DefaultAction)
 
+	if accessControlSpec.JWT.PublicKeys != "" {
+		validator, err := NewJWTValidator(accessControlSpec.JWT)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid access control spec")
+		}
+		accessControlList.JWTValidator = validator
+	}
+
 	accessControlList.TrustDomain = accessControlSpec.TrustDomain
 	if accessControlSpec.TrustDomain == "" {
 		accessControlList.TrustDomain = DefaultTrustDomain
@@ -379,6 +643,7 @@ func ParseAccessControlSpec(accessControlSpec AccessControlSpec, protocol string
 			operationActions := AccessControlListOperationAction{
 				OperationPostFix: operationPostfix,
 				VerbAction:       make(map[string]string),
+				Claims:           appPolicy.Claims,
 			}
 
 			// Iterate over all the http verbs and create a map and set the action for fast lookup
@@ -426,6 +691,18 @@ func GetAndParseSpiffeID(ctx context.Context) (*SpiffeID, error) {
 	return id, err
 }
 
+// GetAndParseSpiffeIDFromCerts parses the first SPIFFE Id found in the URI SANs of the certs
+func GetAndParseSpiffeIDFromCerts(certs []*x509.Certificate) (*SpiffeID, error) {
+	for _, crt := range certs {
+		for _, uri := range crt.URIs {
+			if spiffeID := uri.String(); strings.HasPrefix(spiffeID, SpiffeIDPrefix) {
+				return parseSpiffeID(spiffeID)
+			}
+		}
+	}
+	return nil, errors.New("no spiffe id found in the certificates")
+}
+
 func parseSpiffeID(spiffeID string) (*SpiffeID, error) {
 	if spiffeID == "" {
 		return nil, errors.New("input spiffe id string is empty")
@@ -501,8 +778,10 @@ func getSpiffeID(ctx context.Context) (string, error) {
 	return "", nil
 }
 
-// IsOperationAllowedByAccessControlPolicy determines if access control policies allow the operation on the target app
-func IsOperationAllowedByAccessControlPolicy(spiffeID *SpiffeID, srcAppID string, inputOperation string, httpVerb common.HTTPExtension_Verb, appProtocol string, accessControlList *AccessControlList) (bool, string) {
+// IsOperationAllowedByAccessControlPolicy determines if access control policies allow the operation on the target app.
+// claims are the claims of the validated bearer token of the call, if any. An operation policy that requires claims
+// only applies when all of them are present, otherwise the default action of the app is applied.
+func IsOperationAllowedByAccessControlPolicy(spiffeID *SpiffeID, srcAppID string, inputOperation string, httpVerb common.HTTPExtension_Verb, appProtocol string, claims map[string]interface{}, accessControlList *AccessControlList) (bool, string) {
 	if accessControlList == nil {
 		// No access control list is provided. Do nothing
 		return isActionAllowed(AllowAccess), ""
@@ -577,6 +856,10 @@ func IsOperationAllowedByAccessControlPolicy(spiffeID *SpiffeID, srcAppID string
 			}
 		}
 
+		if len(operationPolicy.Claims) > 0 && !claimsMatch(claims, operationPolicy.Claims) {
+			return isActionAllowed(action), actionPolicy
+		}
+
 		// Operation prefix and postfix match. Now check the operation specific policy
 		if appProtocol == HTTPProtocol {
 			if httpVerb != common.HTTPExtension_NONE {
@@ -9,6 +9,7 @@ import (
 	"os"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/dapr/dapr/pkg/proto/common/v1"
 	"github.com/stretchr/testify/assert"
@@ -642,7 +643,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, nil)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, nil, nil)
 		// Action = Allow the operation since no ACL is defined
 		assert.True(t, isAllowed)
 	})
@@ -655,7 +656,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, nil, accessControlList)
 		// Action = Default global action
 		assert.False(t, isAllowed)
 	})
@@ -668,7 +669,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, nil, accessControlList)
 		// Action = Ignore policy and apply global default action
 		assert.False(t, isAllowed)
 	})
@@ -681,7 +682,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "abcd",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, nil, accessControlList)
 		// Action = Ignore policy and apply global default action
 		assert.False(t, isAllowed)
 	})
@@ -689,7 +690,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 	t.Run("test when spiffe id is nil", func(t *testing.T) {
 		srcAppID := app1
 		accessControlList, _ := initializeAccessControlList(HTTPProtocol)
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(nil, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(nil, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, nil, accessControlList)
 		// Action = Default global action
 		assert.False(t, isAllowed)
 	})
@@ -697,7 +698,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 	t.Run("test when src app id is empty", func(t *testing.T) {
 		srcAppID := ""
 		accessControlList, _ := initializeAccessControlList(HTTPProtocol)
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(nil, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(nil, srcAppID, "op1", common.HTTPExtension_POST, HTTPProtocol, nil, accessControlList)
 		// Action = Default global action
 		assert.False(t, isAllowed)
 	})
@@ -710,7 +711,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "opX", common.HTTPExtension_POST, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "opX", common.HTTPExtension_POST, HTTPProtocol, nil, accessControlList)
 		// Action = Ignore policy and apply default action for app
 		assert.True(t, isAllowed)
 	})
@@ -723,7 +724,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "Op2", common.HTTPExtension_POST, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "Op2", common.HTTPExtension_POST, HTTPProtocol, nil, accessControlList)
 		// Action = Ignore policy and apply default action for app
 		assert.False(t, isAllowed)
 	})
@@ -736,7 +737,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_PUT, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_PUT, HTTPProtocol, nil, accessControlList)
 		// Action = Default action for the specific app
 		assert.False(t, isAllowed)
 	})
@@ -749,7 +750,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op5", common.HTTPExtension_PUT, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op5", common.HTTPExtension_PUT, HTTPProtocol, nil, accessControlList)
 		// Action = Global Default action
 		assert.False(t, isAllowed)
 	})
@@ -762,7 +763,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns1",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op2", common.HTTPExtension_PUT, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op2", common.HTTPExtension_PUT, HTTPProtocol, nil, accessControlList)
 		// Action = Default action for the specific verb
 		assert.False(t, isAllowed)
 	})
@@ -775,7 +776,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_POST, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_POST, HTTPProtocol, nil, accessControlList)
 		// Action = Default action for the specific verb
 		assert.True(t, isAllowed)
 	})
@@ -788,7 +789,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op4", common.HTTPExtension_POST, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op4", common.HTTPExtension_POST, HTTPProtocol, nil, accessControlList)
 		// Action = Default action for the specific verb
 		assert.True(t, isAllowed)
 	})
@@ -801,7 +802,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_NONE, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_NONE, HTTPProtocol, nil, accessControlList)
 		// Action = Default action for the app
 		assert.False(t, isAllowed)
 	})
@@ -814,7 +815,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/a", common.HTTPExtension_PUT, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/a", common.HTTPExtension_PUT, HTTPProtocol, nil, accessControlList)
 		// Action = Default action for the specific verb
 		assert.True(t, isAllowed)
 	})
@@ -827,7 +828,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/OP4", common.HTTPExtension_NONE, GRPCProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/OP4", common.HTTPExtension_NONE, GRPCProtocol, nil, accessControlList)
 		// Action = Default action for the specific verb
 		assert.False(t, isAllowed)
 	})
@@ -840,7 +841,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/b/b", common.HTTPExtension_PUT, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/b/b", common.HTTPExtension_PUT, HTTPProtocol, nil, accessControlList)
 		// Action = Default action for the app
 		assert.False(t, isAllowed)
 	})
@@ -853,7 +854,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/a/b", common.HTTPExtension_PUT, HTTPProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "/op3/a/b", common.HTTPExtension_PUT, HTTPProtocol, nil, accessControlList)
 		// Action = Default action for the app
 		assert.True(t, isAllowed)
 	})
@@ -866,7 +867,7 @@ func TestIsOperationAllowedByAccessControlPolicy(t *testing.T) {
 			Namespace:   "ns2",
 			AppID:       srcAppID,
 		}
-		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_NONE, GRPCProtocol, accessControlList)
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, srcAppID, "op4", common.HTTPExtension_NONE, GRPCProtocol, nil, accessControlList)
 		// Action = Default action for the app
 		assert.True(t, isAllowed)
 	})
@@ -901,3 +902,204 @@ func TestGetOperationPrefixAndPostfix(t *testing.T) {
 		assert.Equal(t, "/a/b/*", postfix)
 	})
 }
+
+func TestIsOperationAllowedByAccessControlPolicyWithClaims(t *testing.T) {
+	accessControlList, err := ParseAccessControlSpec(AccessControlSpec{
+		DefaultAction: DenyAccess,
+		TrustDomain:   "public",
+		AppPolicies: []AppPolicySpec{
+			{
+				AppName:       app1,
+				DefaultAction: DenyAccess,
+				TrustDomain:   "public",
+				Namespace:     "ns1",
+				AppOperationActions: []AppOperation{
+					{
+						Action:    AllowAccess,
+						HTTPVerb:  []string{"POST"},
+						Operation: "/orders",
+						Claims:    map[string]string{"role": "admin"},
+					},
+				},
+			},
+		},
+	}, HTTPProtocol)
+	assert.NoError(t, err)
+
+	spiffeID := SpiffeID{
+		TrustDomain: "public",
+		Namespace:   "ns1",
+		AppID:       app1,
+	}
+
+	t.Run("claims match", func(t *testing.T) {
+		claims := map[string]interface{}{"role": []interface{}{"reader", "admin"}}
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "orders", common.HTTPExtension_POST, HTTPProtocol, claims, accessControlList)
+		assert.True(t, isAllowed)
+	})
+
+	t.Run("claims do not match", func(t *testing.T) {
+		claims := map[string]interface{}{"role": "reader"}
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "orders", common.HTTPExtension_POST, HTTPProtocol, claims, accessControlList)
+		// Action = Default app action
+		assert.False(t, isAllowed)
+	})
+
+	t.Run("no token", func(t *testing.T) {
+		isAllowed, _ := IsOperationAllowedByAccessControlPolicy(&spiffeID, app1, "orders", common.HTTPExtension_POST, HTTPProtocol, nil, accessControlList)
+		assert.False(t, isAllowed)
+	})
+}
+
+func TestHeaderFilterSpec(t *testing.T) {
+	t.Run("empty filter allows everything", func(t *testing.T) {
+		f := HeaderFilterSpec{}
+		assert.True(t, f.IsEmpty())
+		assert.True(t, f.IsAllowed("X-Internal-Debug"))
+	})
+
+	t.Run("deny list", func(t *testing.T) {
+		f := HeaderFilterSpec{Deny: []string{"x-internal-*", "Server"}}
+		assert.False(t, f.IsAllowed("X-Internal-Debug"))
+		assert.False(t, f.IsAllowed("server"))
+		assert.True(t, f.IsAllowed("X-Request-Id"))
+	})
+
+	t.Run("allow list", func(t *testing.T) {
+		f := HeaderFilterSpec{Allow: []string{"X-Request-Id", "x-trace-*"}}
+		assert.True(t, f.IsAllowed("x-request-id"))
+		assert.True(t, f.IsAllowed("X-Trace-Span"))
+		assert.False(t, f.IsAllowed("Set-Cookie"))
+	})
+
+	t.Run("deny takes precedence over allow", func(t *testing.T) {
+		f := HeaderFilterSpec{Allow: []string{"x-*"}, Deny: []string{"x-internal-*"}}
+		assert.True(t, f.IsAllowed("X-Request-Id"))
+		assert.False(t, f.IsAllowed("X-Internal-Debug"))
+	})
+}
+
+func TestGetDeactivationWarningWindows(t *testing.T) {
+	t.Run("valid windows", func(t *testing.T) {
+		spec := ActorsSpec{
+			DeactivationWarnings: []ActorDeactivationWarning{
+				{ActorType: "cart", Window: "30s"},
+				{ActorType: "session", Window: "2m"},
+			},
+		}
+		windows, err := spec.GetDeactivationWarningWindows()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]time.Duration{"cart": time.Second * 30, "session": time.Minute * 2}, windows)
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		spec := ActorsSpec{
+			DeactivationWarnings: []ActorDeactivationWarning{{ActorType: "cart", Window: "soon"}},
+		}
+		_, err := spec.GetDeactivationWarningWindows()
+		assert.Error(t, err)
+	})
+
+	t.Run("non positive window", func(t *testing.T) {
+		spec := ActorsSpec{
+			DeactivationWarnings: []ActorDeactivationWarning{{ActorType: "cart", Window: "0s"}},
+		}
+		_, err := spec.GetDeactivationWarningWindows()
+		assert.Error(t, err)
+	})
+}
+
+func TestGetPlacementHints(t *testing.T) {
+	t.Run("valid hints", func(t *testing.T) {
+		spec := ActorsSpec{
+			PlacementHints: []ActorPlacementHint{
+				{ActorType: "cart", Mode: ActorPlacementSticky},
+				{ActorType: "stateless", Mode: ActorPlacementRebalanceEagerly},
+			},
+		}
+		hints, err := spec.GetPlacementHints()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"cart": "sticky", "stateless": "rebalance-eagerly"}, hints)
+	})
+
+	t.Run("invalid hint", func(t *testing.T) {
+		spec := ActorsSpec{
+			PlacementHints: []ActorPlacementHint{{ActorType: "cart", Mode: "never"}},
+		}
+		_, err := spec.GetPlacementHints()
+		assert.Error(t, err)
+	})
+}
+
+func TestIdempotencySpecGetWindow(t *testing.T) {
+	t.Run("empty window disables deduplication", func(t *testing.T) {
+		window, err := IdempotencySpec{}.GetWindow()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), window)
+	})
+
+	t.Run("valid window", func(t *testing.T) {
+		window, err := IdempotencySpec{Window: "10m"}.GetWindow()
+		assert.NoError(t, err)
+		assert.Equal(t, 10*time.Minute, window)
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		_, err := IdempotencySpec{Window: "ten minutes"}.GetWindow()
+		assert.Error(t, err)
+	})
+
+	t.Run("negative window", func(t *testing.T) {
+		_, err := IdempotencySpec{Window: "-1m"}.GetWindow()
+		assert.Error(t, err)
+	})
+}
+
+func TestProfilingSpecGetInterval(t *testing.T) {
+	interval, err := ProfilingSpec{}.GetInterval()
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, interval)
+
+	interval, err = ProfilingSpec{Interval: "1m"}.GetInterval()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, interval)
+
+	_, err = ProfilingSpec{Interval: "one minute"}.GetInterval()
+	assert.Error(t, err)
+
+	_, err = ProfilingSpec{Interval: "0s"}.GetInterval()
+	assert.Error(t, err)
+}
+
+func TestGCSpecGetMemoryLimit(t *testing.T) {
+	limit, err := GCSpec{}.GetMemoryLimit()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), limit)
+
+	limit, err = GCSpec{MemoryLimit: "512Mi"}.GetMemoryLimit()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(512*1024*1024), limit)
+
+	limit, err = GCSpec{MemoryLimit: "1G"}.GetMemoryLimit()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1000*1000*1000), limit)
+
+	_, err = GCSpec{MemoryLimit: "lots"}.GetMemoryLimit()
+	assert.Error(t, err)
+
+	_, err = GCSpec{MemoryLimit: "0"}.GetMemoryLimit()
+	assert.Error(t, err)
+}
+
+func TestSecretsCacheSpecGetTTL(t *testing.T) {
+	ttl, err := SecretsCacheSpec{}.GetTTL()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	ttl, err = SecretsCacheSpec{TTL: "30s"}.GetTTL()
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, ttl)
+
+	_, err = SecretsCacheSpec{TTL: "thirty seconds"}.GetTTL()
+	assert.Error(t, err)
+}
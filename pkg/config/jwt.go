@@ -0,0 +1,221 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package config
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const bearerPrefix = "bearer "
+
+// JWTValidator validates bearer tokens presented on service invocation calls and returns their claims.
+type JWTValidator struct {
+	issuer    string
+	audience  string
+	keys      []crypto.PublicKey
+	clockSkew time.Duration
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+// jwtAlgorithm is a supported JWT signature algorithm. ECDSA algorithms are bound to a curve, RSA ones have none.
+type jwtAlgorithm struct {
+	hash  crypto.Hash
+	curve elliptic.Curve
+}
+
+// jwtAlgorithms are the supported JWT signature algorithms, from RFC 7518. A token is only verified
+// with the keys of the type, and for ECDSA the curve, its algorithm is bound to.
+var jwtAlgorithms = map[string]jwtAlgorithm{
+	"RS256": {hash: crypto.SHA256},
+	"RS384": {hash: crypto.SHA384},
+	"RS512": {hash: crypto.SHA512},
+	"ES256": {hash: crypto.SHA256, curve: elliptic.P256()},
+	"ES384": {hash: crypto.SHA384, curve: elliptic.P384()},
+	"ES512": {hash: crypto.SHA512, curve: elliptic.P521()},
+}
+
+// NewJWTValidator returns a JWTValidator for the given spec.
+// Tokens must be signed with one of the PEM encoded RSA or ECDSA public keys of the spec.
+func NewJWTValidator(spec JWTSpec) (*JWTValidator, error) {
+	v := &JWTValidator{
+		issuer:    spec.Issuer,
+		audience:  spec.Audience,
+		clockSkew: time.Minute,
+	}
+
+	rest := []byte(spec.PublicKeys)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		var key interface{}
+		var err error
+		if block.Type == "CERTIFICATE" {
+			var cert *x509.Certificate
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				key = cert.PublicKey
+			}
+		} else {
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing jwt public key")
+		}
+
+		switch key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+			v.keys = append(v.keys, key)
+		default:
+			return nil, errors.Errorf("unsupported jwt public key type %T", key)
+		}
+	}
+
+	if len(v.keys) == 0 {
+		return nil, errors.New("no jwt public keys found")
+	}
+	return v, nil
+}
+
+// ValidateBearerToken validates the token of an Authorization header value and returns its claims.
+func (v *JWTValidator) ValidateBearerToken(authorization string) (map[string]interface{}, error) {
+	if len(authorization) <= len(bearerPrefix) || !strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+		return nil, errors.New("authorization is not a bearer token")
+	}
+	return v.Validate(strings.TrimSpace(authorization[len(bearerPrefix):]))
+}
+
+// Validate verifies the signature, issuer, audience and validity period of a compact serialized JWT and returns its claims.
+func (v *JWTValidator) Validate(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed jwt")
+	}
+
+	var header jwtHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, errors.Wrap(err, "malformed jwt header")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed jwt signature")
+	}
+
+	if err = v.verifySignature(header.Alg, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	claims := map[string]interface{}{}
+	if err = decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, errors.Wrap(err, "malformed jwt claims")
+	}
+
+	now := time.Now()
+	if exp, ok := claims["exp"].(float64); ok && now.After(time.Unix(int64(exp), 0).Add(v.clockSkew)) {
+		return nil, errors.New("jwt is expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(v.clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return nil, errors.New("jwt is not valid yet")
+	}
+	if v.issuer != "" && claims["iss"] != v.issuer {
+		return nil, errors.Errorf("unexpected jwt issuer %v", claims["iss"])
+	}
+	if v.audience != "" && !claimContains(claims["aud"], v.audience) {
+		return nil, errors.Errorf("jwt audience does not include %s", v.audience)
+	}
+
+	return claims, nil
+}
+
+func (v *JWTValidator) verifySignature(alg, signed string, sig []byte) error {
+	algorithm, ok := jwtAlgorithms[alg]
+	if !ok {
+		return errors.Errorf("unsupported jwt algorithm %s", alg)
+	}
+
+	h := algorithm.hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	for _, key := range v.keys {
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			if algorithm.curve == nil && rsa.VerifyPKCS1v15(k, algorithm.hash, digest, sig) == nil {
+				return nil
+			}
+		case *ecdsa.PublicKey:
+			if algorithm.curve == nil || k.Curve.Params().Name != algorithm.curve.Params().Name {
+				continue
+			}
+			size := (k.Curve.Params().BitSize + 7) / 8
+			if len(sig) == 2*size {
+				r := new(big.Int).SetBytes(sig[:size])
+				s := new(big.Int).SetBytes(sig[size:])
+				if ecdsa.Verify(k, digest, r, s) {
+					return nil
+				}
+			}
+		}
+	}
+	return errors.New("invalid jwt signature")
+}
+
+func decodeJWTSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// claimContains returns true if the claim is the expected string, or an array containing it.
+func claimContains(claim interface{}, expected string) bool {
+	switch c := claim.(type) {
+	case string:
+		return c == expected
+	case []interface{}:
+		for _, item := range c {
+			if fmt.Sprint(item) == expected {
+				return true
+			}
+		}
+	case nil:
+		return false
+	default:
+		return fmt.Sprint(c) == expected
+	}
+	return false
+}
+
+// claimsMatch returns true if every required claim is present in the token claims.
+func claimsMatch(claims map[string]interface{}, required map[string]string) bool {
+	for name, value := range required {
+		if !claimContains(claims[name], value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package config
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodePublicKey(t *testing.T, key interface{}) string {
+	b, err := x509.MarshalPKIXPublicKey(key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}))
+}
+
+func signToken(t *testing.T, alg string, key crypto.Signer, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hash := crypto.SHA256
+	if algorithm, ok := jwtAlgorithms[alg]; ok {
+		hash = algorithm.hash
+	}
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, hash, digest)
+		assert.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		assert.NoError(t, err)
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig = make([]byte, 2*size)
+		r.FillBytes(sig[:size])
+		s.FillBytes(sig[size:])
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWTValidator(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	v, err := NewJWTValidator(JWTSpec{
+		Issuer:     "https://issuer",
+		Audience:   "orders",
+		PublicKeys: encodePublicKey(t, &rsaKey.PublicKey) + encodePublicKey(t, &ecKey.PublicKey),
+	})
+	assert.NoError(t, err)
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":  "https://issuer",
+			"aud":  []string{"orders", "payments"},
+			"exp":  time.Now().Add(time.Hour).Unix(),
+			"role": "admin",
+		}
+	}
+
+	t.Run("valid rsa token", func(t *testing.T) {
+		claims, err := v.ValidateBearerToken("Bearer " + signToken(t, "RS256", rsaKey, validClaims()))
+		assert.NoError(t, err)
+		assert.Equal(t, "admin", claims["role"])
+	})
+
+	t.Run("valid ecdsa token", func(t *testing.T) {
+		_, err := v.Validate(signToken(t, "ES256", ecKey, validClaims()))
+		assert.NoError(t, err)
+	})
+
+	t.Run("ecdsa algorithm of another curve", func(t *testing.T) {
+		_, err := v.Validate(signToken(t, "ES384", ecKey, validClaims()))
+		assert.Error(t, err)
+	})
+
+	t.Run("ecdsa algorithm with rsa key", func(t *testing.T) {
+		_, err := v.Validate(signToken(t, "ES256", rsaKey, validClaims()))
+		assert.Error(t, err)
+	})
+
+	t.Run("rsa algorithm with ecdsa key", func(t *testing.T) {
+		_, err := v.Validate(signToken(t, "RS256", ecKey, validClaims()))
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown signing key", func(t *testing.T) {
+		_, err := v.Validate(signToken(t, "RS256", otherKey, validClaims()))
+		assert.Error(t, err)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		c := validClaims()
+		c["exp"] = time.Now().Add(-time.Hour).Unix()
+		_, err := v.Validate(signToken(t, "RS256", rsaKey, c))
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong issuer", func(t *testing.T) {
+		c := validClaims()
+		c["iss"] = "https://other"
+		_, err := v.Validate(signToken(t, "RS256", rsaKey, c))
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		c := validClaims()
+		c["aud"] = "payments"
+		_, err := v.Validate(signToken(t, "RS256", rsaKey, c))
+		assert.Error(t, err)
+	})
+
+	t.Run("unsigned token", func(t *testing.T) {
+		parts := strings.Split(signToken(t, "RS256", rsaKey, validClaims()), ".")
+		parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+		_, err := v.Validate(strings.Join(parts, "."))
+		assert.Error(t, err)
+	})
+
+	t.Run("not a bearer token", func(t *testing.T) {
+		_, err := v.ValidateBearerToken("Basic dXNlcjpwYXNz")
+		assert.Error(t, err)
+	})
+
+	t.Run("no public keys", func(t *testing.T) {
+		_, err := NewJWTValidator(JWTSpec{PublicKeys: "not a key"})
+		assert.Error(t, err)
+	})
+}
@@ -1,7 +1,12 @@
 package credentials
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -11,6 +16,8 @@ const (
 	IssuerCertFilename = "issuer.crt"
 	// IssuerKeyFilename is the filename that holds the issuer key
 	IssuerKeyFilename = "issuer.key"
+	// RevokedSerialsFilename is the filename that holds the serial numbers of revoked certificates
+	RevokedSerialsFilename = "revoked-serials"
 )
 
 // CertChain holds the certificate trust chain PEM values
@@ -40,3 +47,16 @@ func LoadFromDisk(rootCertPath, issuerCertPath, issuerKeyPath string) (*CertChai
 		Key:    key,
 	}, nil
 }
+
+// CertExpiry returns the time the certificate of the chain expires
+func (c *CertChain) CertExpiry() (time.Time, error) {
+	block, _ := pem.Decode(c.Cert)
+	if block == nil {
+		return time.Time{}, errors.New("failed to decode certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to parse certificate")
+	}
+	return cert.NotAfter, nil
+}
@@ -0,0 +1,23 @@
+package credentials
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertExpiry(t *testing.T) {
+	t.Run("valid cert", func(t *testing.T) {
+		chain := &CertChain{Cert: []byte(TestCert)}
+		expiry, err := chain.CertExpiry()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2021, time.February, 10, 0, 45, 58, 0, time.UTC), expiry.UTC())
+	})
+
+	t.Run("invalid cert", func(t *testing.T) {
+		chain := &CertChain{Cert: []byte("not a cert")}
+		_, err := chain.CertExpiry()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,30 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package diagnostics
+
+import (
+	"github.com/dapr/dapr/pkg/config"
+)
+
+const (
+	// BaggageHeader is the W3C Baggage header
+	BaggageHeader = "baggage"
+	// DefaultMaxBaggageSize is the maximum baggage size in bytes recommended by the W3C Baggage specification
+	DefaultMaxBaggageSize = 8192
+)
+
+// LimitBaggage returns the baggage if it does not exceed the maximum size of the tracing spec.
+// Baggage over the limit is dropped as a whole, so no partial entries are propagated.
+func LimitBaggage(baggage string, spec config.TracingSpec) string {
+	maxSize := spec.MaxBaggageSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxBaggageSize
+	}
+	if len(baggage) > maxSize {
+		return ""
+	}
+	return baggage
+}
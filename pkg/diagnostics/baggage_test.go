@@ -0,0 +1,32 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package diagnostics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitBaggage(t *testing.T) {
+	t.Run("baggage within the default limit", func(t *testing.T) {
+		baggage := "userId=alice,serverNode=DF%2028,isProduction=false"
+		assert.Equal(t, baggage, LimitBaggage(baggage, config.TracingSpec{}))
+	})
+
+	t.Run("baggage over the default limit", func(t *testing.T) {
+		baggage := "key=" + strings.Repeat("a", DefaultMaxBaggageSize)
+		assert.Empty(t, LimitBaggage(baggage, config.TracingSpec{}))
+	})
+
+	t.Run("baggage over the configured limit", func(t *testing.T) {
+		baggage := "userId=alice"
+		assert.Empty(t, LimitBaggage(baggage, config.TracingSpec{MaxBaggageSize: 5}))
+		assert.Equal(t, baggage, LimitBaggage(baggage, config.TracingSpec{MaxBaggageSize: 12}))
+	})
+}
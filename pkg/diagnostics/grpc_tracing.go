@@ -31,7 +31,7 @@ func GRPCTraceUnaryServerInterceptor(appID string, spec config.TracingSpec) grpc
 		spanName := info.FullMethod
 
 		sc, _ := SpanContextFromIncomingGRPCMetadata(ctx)
-		sampler := diag_utils.TraceSampler(spec.SamplingRate)
+		sampler := diag_utils.TraceSampler(samplingRate(spec, apiTypeFromGRPCMethod(info.FullMethod)))
 
 		var spanKind trace.StartOption
 
@@ -11,7 +11,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dapr/dapr/pkg/config"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+	"github.com/pkg/errors"
 	"github.com/valyala/fasthttp"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
@@ -28,10 +30,15 @@ var (
 	httpMethodKey     = tag.MustNewKey("method")
 )
 
+// unmatchedPathLabel is the path label of requests not matching any path template
+const unmatchedPathLabel = "_unmatched"
+
 // Default distributions
 var (
 	defaultSizeDistribution    = view.Distribution(1024, 2048, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216, 67108864, 268435456, 1073741824, 4294967296)
 	defaultLatencyDistribution = view.Distribution(1, 2, 3, 4, 5, 6, 8, 10, 13, 16, 20, 25, 30, 40, 50, 65, 80, 100, 130, 160, 200, 250, 300, 400, 500, 650, 800, 1000, 2000, 5000, 10000, 20000, 50000, 100000)
+	// microsecondLatencyDistribution is the distribution of the latencies recorded in microseconds, from 100ns to 10ms.
+	microsecondLatencyDistribution = view.Distribution(0.1, 0.25, 0.5, 1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000)
 )
 
 type httpMetrics struct {
@@ -48,6 +55,11 @@ type httpMetrics struct {
 
 	appID   string
 	enabled bool
+
+	// serverExcludedKeys are the tag keys not recorded on HTTP server metrics.
+	serverExcludedKeys     map[tag.Key]bool
+	pathTemplates          [][]string
+	collapseUnmatchedPaths bool
 }
 
 func newHTTPMetrics() *httpMetrics {
@@ -150,17 +162,21 @@ func (h *httpMetrics) ClientRequestCompleted(ctx context.Context, method, path,
 	}
 }
 
-func (h *httpMetrics) Init(appID string) error {
+func (h *httpMetrics) Init(appID string, spec config.MetricHTTPSpec) error {
+	if err := h.configure(spec); err != nil {
+		return err
+	}
+
 	h.appID = appID
 	h.enabled = true
 
 	tags := []tag.Key{appIDKey}
 	return view.Register(
-		diag_utils.NewMeasureView(h.serverRequestCount, []tag.Key{appIDKey, httpPathKey, httpMethodKey}, view.Count()),
-		diag_utils.NewMeasureView(h.serverRequestBytes, tags, defaultSizeDistribution),
-		diag_utils.NewMeasureView(h.serverResponseBytes, tags, defaultSizeDistribution),
-		diag_utils.NewMeasureView(h.serverLatency, []tag.Key{appIDKey, httpMethodKey, httpPathKey, httpStatusCodeKey}, defaultSizeDistribution),
-		diag_utils.NewMeasureView(h.serverResponseCount, []tag.Key{appIDKey, httpMethodKey, httpPathKey, httpStatusCodeKey}, view.Count()),
+		diag_utils.NewMeasureView(h.serverRequestCount, h.serverTagKeys(appIDKey, httpPathKey, httpMethodKey), view.Count()),
+		diag_utils.NewMeasureView(h.serverRequestBytes, h.serverTagKeys(appIDKey), defaultSizeDistribution),
+		diag_utils.NewMeasureView(h.serverResponseBytes, h.serverTagKeys(appIDKey), defaultSizeDistribution),
+		diag_utils.NewMeasureView(h.serverLatency, h.serverTagKeys(appIDKey, httpMethodKey, httpPathKey, httpStatusCodeKey), defaultSizeDistribution),
+		diag_utils.NewMeasureView(h.serverResponseCount, h.serverTagKeys(appIDKey, httpMethodKey, httpPathKey, httpStatusCodeKey), view.Count()),
 		diag_utils.NewMeasureView(h.clientSentBytes, []tag.Key{appIDKey, httpMethodKey, httpPathKey, httpStatusCodeKey}, defaultSizeDistribution),
 		diag_utils.NewMeasureView(h.clientReceivedBytes, tags, defaultSizeDistribution),
 		diag_utils.NewMeasureView(h.clientRoundtripLatency, []tag.Key{appIDKey, httpMethodKey, httpPathKey, httpStatusCodeKey}, defaultSizeDistribution),
@@ -168,6 +184,72 @@ func (h *httpMetrics) Init(appID string) error {
 	)
 }
 
+// configure applies the label configuration of HTTP server metrics.
+func (h *httpMetrics) configure(spec config.MetricHTTPSpec) error {
+	h.serverExcludedKeys = map[tag.Key]bool{}
+	for _, label := range spec.ExcludedLabels {
+		found := false
+		for _, k := range []tag.Key{appIDKey, httpMethodKey, httpPathKey, httpStatusCodeKey} {
+			if k.Name() == label {
+				h.serverExcludedKeys[k] = true
+				found = true
+			}
+		}
+		if !found {
+			return errors.Errorf("unknown http metrics label %s", label)
+		}
+	}
+
+	h.pathTemplates = nil
+	for _, t := range spec.PathTemplates {
+		h.pathTemplates = append(h.pathTemplates, strings.Split(strings.TrimPrefix(t, "/"), "/"))
+	}
+	h.collapseUnmatchedPaths = spec.CollapseUnmatchedPaths
+	return nil
+}
+
+// serverTagKeys returns the keys that are not excluded from HTTP server metrics.
+func (h *httpMetrics) serverTagKeys(keys ...tag.Key) []tag.Key {
+	filtered := make([]tag.Key, 0, len(keys))
+	for _, k := range keys {
+		if !h.serverExcludedKeys[k] {
+			filtered = append(filtered, k)
+		}
+	}
+	return filtered
+}
+
+// serverPathLabel returns the path label of HTTP server metrics, applying the path templates.
+func (h *httpMetrics) serverPathLabel(path string) string {
+	if len(h.pathTemplates) > 0 {
+		segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+		for _, t := range h.pathTemplates {
+			if matchPathTemplate(t, segments) {
+				return "/" + strings.Join(t, "/")
+			}
+		}
+	}
+	if h.collapseUnmatchedPaths {
+		return unmatchedPathLabel
+	}
+	return h.convertPathToMetricLabel(path)
+}
+
+func matchPathTemplate(template, segments []string) bool {
+	for i, t := range template {
+		if t == "*" && i == len(template)-1 {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if t != segments[i] && !(strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}")) {
+			return false
+		}
+	}
+	return len(template) == len(segments)
+}
+
 // FastHTTPMiddleware is the middleware to track http server-side requests
 func (h *httpMetrics) FastHTTPMiddleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
@@ -177,7 +259,7 @@ func (h *httpMetrics) FastHTTPMiddleware(next fasthttp.RequestHandler) fasthttp.
 		}
 
 		method := string(ctx.Method())
-		path := h.convertPathToMetricLabel(string(ctx.Path()))
+		path := h.serverPathLabel(string(ctx.Path()))
 
 		h.ServerRequestReceived(ctx, method, path, int64(reqContentSize))
 
@@ -5,9 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dapr/dapr/pkg/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
 )
 
 func TestFastHTTPMiddleware(t *testing.T) {
@@ -23,7 +25,7 @@ func TestFastHTTPMiddleware(t *testing.T) {
 
 	// create test httpMetrics
 	testHTTP := newHTTPMetrics()
-	testHTTP.Init("fakeID")
+	testHTTP.Init("fakeID", config.MetricHTTPSpec{})
 
 	handler := testHTTP.FastHTTPMiddleware(fakeHandler)
 
@@ -74,7 +76,7 @@ func TestFastHTTPMiddlewareWhenMetricsDisabled(t *testing.T) {
 	testHTTP := newHTTPMetrics()
 	testHTTP.enabled = false
 
-	testHTTP.Init("fakeID")
+	testHTTP.Init("fakeID", config.MetricHTTPSpec{})
 	v := view.Find("http/server/request_count")
 	views := []*view.View{v}
 	view.Unregister(views...)
@@ -121,6 +123,48 @@ func TestConvertPathToMethodName(t *testing.T) {
 	}
 }
 
+func TestHTTPServerMetricsLabels(t *testing.T) {
+	t.Run("excluded labels are not recorded", func(t *testing.T) {
+		testHTTP := newHTTPMetrics()
+		err := testHTTP.configure(config.MetricHTTPSpec{ExcludedLabels: []string{"path", "app_id"}})
+		assert.NoError(t, err)
+
+		keys := testHTTP.serverTagKeys(appIDKey, httpMethodKey, httpPathKey, httpStatusCodeKey)
+		assert.Equal(t, []tag.Key{httpMethodKey, httpStatusCodeKey}, keys)
+	})
+
+	t.Run("unknown label", func(t *testing.T) {
+		testHTTP := newHTTPMetrics()
+		err := testHTTP.configure(config.MetricHTTPSpec{ExcludedLabels: []string{"host"}})
+		assert.Error(t, err)
+	})
+
+	var pathTests = []struct {
+		collapse bool
+		in       string
+		out      string
+	}{
+		{false, "/v1.0/invoke/app1/method/orders/1", "/v1.0/invoke/{app}/method/*"},
+		{false, "/v1.0/state/statestore/key", "/v1.0/state/{store}/{key}"},
+		{false, "/v1.0/state/statestore", "/v1.0/state/statestore"},
+		{false, "/v1/secrets/keyvault/name", "/v1/secrets/keyvault"},
+		{true, "/v1.0/invoke/app1/method/orders", "/v1.0/invoke/{app}/method/*"},
+		{true, "/v1.0/publish/pubsub/topic", unmatchedPathLabel},
+	}
+
+	for _, tt := range pathTests {
+		t.Run(tt.in, func(t *testing.T) {
+			testHTTP := newHTTPMetrics()
+			err := testHTTP.configure(config.MetricHTTPSpec{
+				PathTemplates:          []string{"/v1.0/invoke/{app}/method/*", "/v1.0/state/{store}/{key}"},
+				CollapseUnmatchedPaths: tt.collapse,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.out, testHTTP.serverPathLabel(tt.in))
+		})
+	}
+}
+
 func fakeFastHTTPRequestCtx(expectedBody string) *fasthttp.RequestCtx {
 	expectedMethod := fasthttp.MethodPost
 	expectedRequestURI := "/invoke/method/testmethod"
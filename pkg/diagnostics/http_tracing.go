@@ -86,7 +86,7 @@ func userDefinedHTTPHeaders(reqCtx *fasthttp.RequestCtx) map[string]string {
 
 func startTracingClientSpanFromHTTPContext(ctx *fasthttp.RequestCtx, spanName string, spec config.TracingSpec) (*fasthttp.RequestCtx, *trace.Span) {
 	sc, _ := SpanContextFromRequest(&ctx.Request)
-	probSamplerOption := diag_utils.TraceSampler(spec.SamplingRate)
+	probSamplerOption := diag_utils.TraceSampler(samplingRate(spec, apiTypeFromHTTPPath(spanName)))
 	kindOption := trace.WithSpanKind(trace.SpanKindClient)
 
 	_, span := trace.StartSpanWithRemoteParent(ctx, spanName, sc, kindOption, probSamplerOption)
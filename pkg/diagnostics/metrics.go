@@ -8,6 +8,7 @@ package diagnostics
 import (
 	"time"
 
+	"github.com/dapr/dapr/pkg/config"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
 )
@@ -30,7 +31,7 @@ var (
 )
 
 // InitMetrics initializes metrics
-func InitMetrics(appID string) error {
+func InitMetrics(appID string, metricSpec config.MetricSpec) error {
 	if err := DefaultMonitoring.Init(appID); err != nil {
 		return err
 	}
@@ -39,7 +40,7 @@ func InitMetrics(appID string) error {
 		return err
 	}
 
-	if err := DefaultHTTPMonitoring.Init(appID); err != nil {
+	if err := DefaultHTTPMonitoring.Init(appID, metricSpec.HTTP); err != nil {
 		return err
 	}
 
@@ -0,0 +1,102 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package diagnostics
+
+import (
+	"strings"
+
+	"github.com/dapr/dapr/pkg/config"
+	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+)
+
+// API types with a configurable sampling rate
+const (
+	invocationAPIType = "invocation"
+	pubsubAPIType     = "pubsub"
+	stateAPIType      = "state"
+	actorsAPIType     = "actors"
+)
+
+// IsTracingEnabled returns false if the global sampling rate and every per-API sampling rate are explicitly set to 0.
+func IsTracingEnabled(spec config.TracingSpec) bool {
+	if diag_utils.IsTracingEnabled(spec.SamplingRate) {
+		return true
+	}
+
+	rates := spec.APISamplingRates
+	for _, rate := range []string{rates.Invocation, rates.PubSub, rates.State, rates.Actors} {
+		if rate != "" && diag_utils.IsTracingEnabled(rate) {
+			return true
+		}
+	}
+	return false
+}
+
+// samplingRate returns the sampling rate of the given API type, or the global sampling rate if none is set.
+func samplingRate(spec config.TracingSpec, apiType string) string {
+	var rate string
+	switch apiType {
+	case invocationAPIType:
+		rate = spec.APISamplingRates.Invocation
+	case pubsubAPIType:
+		rate = spec.APISamplingRates.PubSub
+	case stateAPIType:
+		rate = spec.APISamplingRates.State
+	case actorsAPIType:
+		rate = spec.APISamplingRates.Actors
+	}
+
+	if rate == "" {
+		return spec.SamplingRate
+	}
+	return rate
+}
+
+// apiTypeFromHTTPPath returns the API type of a Dapr HTTP API path such as /v1.0/state/statestore.
+func apiTypeFromHTTPPath(path string) string {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+
+	switch parts[1] {
+	case "invoke":
+		return invocationAPIType
+	case "publish":
+		return pubsubAPIType
+	case "state":
+		return stateAPIType
+	case "actors":
+		return actorsAPIType
+	}
+	return ""
+}
+
+// apiTypeFromGRPCMethod returns the API type of a Dapr gRPC API or internal method.
+func apiTypeFromGRPCMethod(fullMethod string) string {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	switch method {
+	case "InvokeService", "CallLocal":
+		return invocationAPIType
+	case "PublishEvent":
+		return pubsubAPIType
+	case "GetState", "GetBulkState", "SaveState", "DeleteState", "DeleteBulkState", "ExecuteStateTransaction":
+		return stateAPIType
+	}
+
+	if strings.Contains(method, "Actor") {
+		return actorsAPIType
+	}
+	return ""
+}
+
+// apiTypeFromCallbackSpanName returns the API type of an internal callback span such as pubsub/topic.
+func apiTypeFromCallbackSpanName(spanName string) string {
+	if strings.HasPrefix(spanName, "pubsub/") {
+		return pubsubAPIType
+	}
+	return ""
+}
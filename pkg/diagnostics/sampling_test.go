@@ -0,0 +1,68 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTracingEnabled(t *testing.T) {
+	assert.True(t, IsTracingEnabled(config.TracingSpec{SamplingRate: "1"}))
+	assert.True(t, IsTracingEnabled(config.TracingSpec{}))
+	assert.False(t, IsTracingEnabled(config.TracingSpec{SamplingRate: "0"}))
+	assert.False(t, IsTracingEnabled(config.TracingSpec{
+		SamplingRate:     "0",
+		APISamplingRates: config.APISamplingRatesSpec{State: "0"},
+	}))
+	assert.True(t, IsTracingEnabled(config.TracingSpec{
+		SamplingRate:     "0",
+		APISamplingRates: config.APISamplingRatesSpec{Invocation: "1"},
+	}))
+}
+
+func TestSamplingRate(t *testing.T) {
+	spec := config.TracingSpec{
+		SamplingRate: "0.5",
+		APISamplingRates: config.APISamplingRatesSpec{
+			Invocation: "1",
+			State:      "0.01",
+		},
+	}
+
+	var tests = []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{"http invocation", apiTypeFromHTTPPath("/v1.0/invoke/app/method/orders"), "1"},
+		{"http state", apiTypeFromHTTPPath("/v1.0/state/statestore/key"), "0.01"},
+		{"http pubsub", apiTypeFromHTTPPath("/v1.0/publish/pubsub/topic"), "0.5"},
+		{"http bindings", apiTypeFromHTTPPath("/v1.0/bindings/kafka"), "0.5"},
+		{"grpc invocation", apiTypeFromGRPCMethod("/dapr.proto.runtime.v1.Dapr/InvokeService"), "1"},
+		{"internal invocation", apiTypeFromGRPCMethod("/dapr.proto.internals.v1.ServiceInvocation/CallLocal"), "1"},
+		{"grpc state", apiTypeFromGRPCMethod("/dapr.proto.runtime.v1.Dapr/ExecuteStateTransaction"), "0.01"},
+		{"grpc actor state", apiTypeFromGRPCMethod("/dapr.proto.runtime.v1.Dapr/GetActorState"), "0.5"},
+		{"subscription", apiTypeFromCallbackSpanName("pubsub/topic"), "0.5"},
+		{"input binding", apiTypeFromCallbackSpanName("bindings/kafka"), "0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.out, samplingRate(spec, tt.in))
+		})
+	}
+
+	t.Run("api types", func(t *testing.T) {
+		assert.Equal(t, actorsAPIType, apiTypeFromHTTPPath("/v1.0/actors/DemoActor/1/method/foo"))
+		assert.Equal(t, actorsAPIType, apiTypeFromGRPCMethod("/dapr.proto.internals.v1.ServiceInvocation/CallActor"))
+		assert.Equal(t, pubsubAPIType, apiTypeFromGRPCMethod("/dapr.proto.runtime.v1.Dapr/PublishEvent"))
+		assert.Equal(t, pubsubAPIType, apiTypeFromCallbackSpanName("pubsub/topic"))
+		assert.Equal(t, "", apiTypeFromHTTPPath("/healthz"))
+	})
+}
@@ -2,6 +2,7 @@ package diagnostics
 
 import (
 	"context"
+	"time"
 
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	"go.opencensus.io/stats"
@@ -15,9 +16,13 @@ var (
 	failReasonKey   = tag.MustNewKey("reason")
 	operationKey    = tag.MustNewKey("operation")
 	actorTypeKey    = tag.MustNewKey("actor_type")
+	triggerTypeKey  = tag.MustNewKey("trigger_type")
+	topicKey        = tag.MustNewKey("topic")
 	trustDomainKey  = tag.MustNewKey("trustDomain")
 	namespaceKey    = tag.MustNewKey("namespace")
 	policyActionKey = tag.MustNewKey("policyAction")
+	phaseKey        = tag.MustNewKey("phase")
+	cacheKey        = tag.MustNewKey("cache")
 )
 
 // serviceMetrics holds dapr runtime metric monitoring methods
@@ -32,6 +37,7 @@ type serviceMetrics struct {
 	mtlsInitFailed                *stats.Int64Measure
 	mtlsWorkloadCertRotated       *stats.Int64Measure
 	mtlsWorkloadCertRotatedFailed *stats.Int64Measure
+	mtlsWorkloadCertExpiry        *stats.Int64Measure
 
 	// Actor metrics
 	actorStatusReportTotal       *stats.Int64Measure
@@ -41,6 +47,14 @@ type serviceMetrics struct {
 	actorDeactivationTotal       *stats.Int64Measure
 	actorDeactivationFailedTotal *stats.Int64Measure
 	actorPendingCalls            *stats.Int64Measure
+	actorTriggerDelay            *stats.Float64Measure
+	actorLookupLatency           *stats.Float64Measure
+
+	// Pub/sub metrics
+	pubsubEventExpiredTotal *stats.Int64Measure
+
+	// Shutdown metrics
+	shutdownPhaseDuration *stats.Float64Measure
 
 	// Access Control Lists for Service Invocation metrics
 	appPolicyActionAllowed    *stats.Int64Measure
@@ -87,6 +101,10 @@ func newServiceMetrics() *serviceMetrics {
 			"runtime/mtls/workload_cert_rotated_fail_total",
 			"The number of the failed workload certificate rotations.",
 			stats.UnitDimensionless),
+		mtlsWorkloadCertExpiry: stats.Int64(
+			"runtime/mtls/workload_cert_expiry_remaining_seconds",
+			"The number of seconds until the workload certificate expires.",
+			stats.UnitDimensionless),
 
 		// Actor
 		actorStatusReportTotal: stats.Int64(
@@ -117,6 +135,26 @@ func newServiceMetrics() *serviceMetrics {
 			"runtime/actor/pending_actor_calls",
 			"The number of pending actor calls waiting to acquire the per-actor lock.",
 			stats.UnitDimensionless),
+		actorTriggerDelay: stats.Float64(
+			"runtime/actor/trigger_delay",
+			"The delay between the scheduled and the actual fire time of actor reminders and timers.",
+			stats.UnitMilliseconds),
+		actorLookupLatency: stats.Float64(
+			"runtime/actor/lookup_latency",
+			"The time taken to resolve the address of an actor in the placement tables, in microseconds, sampled on one lookup out of 100.",
+			"us"),
+
+		// Pub/sub
+		pubsubEventExpiredTotal: stats.Int64(
+			"runtime/pubsub/event_expired_total",
+			"The number of pub/sub events dropped before delivery to the app because they expired.",
+			stats.UnitDimensionless),
+
+		// Shutdown
+		shutdownPhaseDuration: stats.Float64(
+			"runtime/shutdown/phase_duration",
+			"The time taken by each phase of the runtime shutdown.",
+			stats.UnitMilliseconds),
 
 		// Access Control Lists for service invocation
 		appPolicyActionAllowed: stats.Int64(
@@ -155,6 +193,7 @@ func (s *serviceMetrics) Init(appID string) error {
 		diag_utils.NewMeasureView(s.mtlsInitFailed, []tag.Key{appIDKey, failReasonKey}, view.Count()),
 		diag_utils.NewMeasureView(s.mtlsWorkloadCertRotated, []tag.Key{appIDKey}, view.Count()),
 		diag_utils.NewMeasureView(s.mtlsWorkloadCertRotatedFailed, []tag.Key{appIDKey, failReasonKey}, view.Count()),
+		diag_utils.NewMeasureView(s.mtlsWorkloadCertExpiry, []tag.Key{appIDKey}, view.LastValue()),
 
 		diag_utils.NewMeasureView(s.actorStatusReportTotal, []tag.Key{appIDKey, actorTypeKey, operationKey}, view.Count()),
 		diag_utils.NewMeasureView(s.actorStatusReportFailedTotal, []tag.Key{appIDKey, actorTypeKey, operationKey, failReasonKey}, view.Count()),
@@ -163,6 +202,12 @@ func (s *serviceMetrics) Init(appID string) error {
 		diag_utils.NewMeasureView(s.actorDeactivationTotal, []tag.Key{appIDKey, actorTypeKey}, view.Count()),
 		diag_utils.NewMeasureView(s.actorDeactivationFailedTotal, []tag.Key{appIDKey, actorTypeKey}, view.Count()),
 		diag_utils.NewMeasureView(s.actorPendingCalls, []tag.Key{appIDKey, actorTypeKey}, view.LastValue()),
+		diag_utils.NewMeasureView(s.actorTriggerDelay, []tag.Key{appIDKey, actorTypeKey, triggerTypeKey}, defaultLatencyDistribution),
+		diag_utils.NewMeasureView(s.actorLookupLatency, []tag.Key{appIDKey, actorTypeKey, cacheKey}, microsecondLatencyDistribution),
+
+		diag_utils.NewMeasureView(s.pubsubEventExpiredTotal, []tag.Key{appIDKey, componentKey, topicKey}, view.Count()),
+
+		diag_utils.NewMeasureView(s.shutdownPhaseDuration, []tag.Key{appIDKey, phaseKey}, defaultLatencyDistribution),
 
 		diag_utils.NewMeasureView(s.appPolicyActionAllowed, []tag.Key{appIDKey, trustDomainKey, namespaceKey, operationKey, httpMethodKey, policyActionKey}, view.LastValue()),
 		diag_utils.NewMeasureView(s.globalPolicyActionAllowed, []tag.Key{appIDKey, trustDomainKey, namespaceKey, operationKey, httpMethodKey, policyActionKey}, view.LastValue()),
@@ -230,6 +275,15 @@ func (s *serviceMetrics) MTLSWorkLoadCertRotationFailed(reason string) {
 	}
 }
 
+// MTLSWorkloadCertExpiry records the time remaining until the workload certificate expires
+func (s *serviceMetrics) MTLSWorkloadCertExpiry(expiry time.Time) {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx, diag_utils.WithTags(appIDKey, s.appID),
+			s.mtlsWorkloadCertExpiry.M(int64(time.Until(expiry).Seconds())))
+	}
+}
+
 // ActorStatusReported records metrics when status is reported to placement service.
 func (s *serviceMetrics) ActorStatusReported(operation string) {
 	if s.enabled {
@@ -297,6 +351,51 @@ func (s *serviceMetrics) ReportActorPendingCalls(actorType string, pendingLocks
 	}
 }
 
+// ActorTriggerDelay records the delay between the scheduled and the actual fire time of an actor reminder or timer.
+func (s *serviceMetrics) ActorTriggerDelay(actorType, triggerType string, delay time.Duration) {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, actorTypeKey, actorType, triggerTypeKey, triggerType),
+			s.actorTriggerDelay.M(float64(delay/time.Millisecond)))
+	}
+}
+
+// ActorLookupCompleted records the time taken to resolve the address of an actor, and whether it was cached.
+// The lookups are sampled by the caller, as they are on the path of every actor call.
+func (s *serviceMetrics) ActorLookupCompleted(actorType string, cached bool, elapsed time.Duration) {
+	if s.enabled {
+		cache := "miss"
+		if cached {
+			cache = "hit"
+		}
+		stats.RecordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, actorTypeKey, actorType, cacheKey, cache),
+			s.actorLookupLatency.M(float64(elapsed)/float64(time.Microsecond)))
+	}
+}
+
+// PubsubEventExpired records metric when an expired pub/sub event is dropped.
+func (s *serviceMetrics) PubsubEventExpired(pubsubName, topic string) {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, componentKey, pubsubName, topicKey, topic),
+			s.pubsubEventExpiredTotal.M(1))
+	}
+}
+
+// ShutdownPhaseCompleted records the time taken by a phase of the runtime shutdown.
+func (s *serviceMetrics) ShutdownPhaseCompleted(phase string, elapsed time.Duration) {
+	if s.enabled {
+		stats.RecordWithTags(
+			s.ctx,
+			diag_utils.WithTags(appIDKey, s.appID, phaseKey, phase),
+			s.shutdownPhaseDuration.M(float64(elapsed/time.Millisecond)))
+	}
+}
+
 // RequestAllowedByAppAction records the requests allowed due to a match with the action specified in the access control policy for the app
 func (s *serviceMetrics) RequestAllowedByAppAction(appID, trustDomain, namespace, operation, httpverb string, policyAction bool) {
 	if s.enabled {
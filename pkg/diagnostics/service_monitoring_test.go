@@ -0,0 +1,61 @@
+package diagnostics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats/view"
+)
+
+func TestActorTriggerDelay(t *testing.T) {
+	testService := newServiceMetrics()
+	testService.Init("fakeID")
+
+	testService.ActorTriggerDelay("cat", "reminder", 250*time.Millisecond)
+
+	rows, err := view.RetrieveData("runtime/actor/trigger_delay")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "actor_type", rows[0].Tags[0].Key.Name())
+	assert.Equal(t, "cat", rows[0].Tags[0].Value)
+	assert.Equal(t, "app_id", rows[0].Tags[1].Key.Name())
+	assert.Equal(t, "fakeID", rows[0].Tags[1].Value)
+	assert.Equal(t, "trigger_type", rows[0].Tags[2].Key.Name())
+	assert.Equal(t, "reminder", rows[0].Tags[2].Value)
+	assert.Equal(t, 250.0, (rows[0].Data).(*view.DistributionData).Min)
+}
+
+func TestShutdownPhaseCompleted(t *testing.T) {
+	testService := newServiceMetrics()
+	testService.Init("fakeID")
+
+	testService.ShutdownPhaseCompleted("drain", 1500*time.Millisecond)
+
+	rows, err := view.RetrieveData("runtime/shutdown/phase_duration")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "app_id", rows[0].Tags[0].Key.Name())
+	assert.Equal(t, "fakeID", rows[0].Tags[0].Value)
+	assert.Equal(t, "phase", rows[0].Tags[1].Key.Name())
+	assert.Equal(t, "drain", rows[0].Tags[1].Value)
+	assert.Equal(t, 1500.0, (rows[0].Data).(*view.DistributionData).Min)
+}
+
+func TestActorLookupCompleted(t *testing.T) {
+	testService := newServiceMetrics()
+	testService.Init("fakeID")
+
+	testService.ActorLookupCompleted("cat", true, 250*time.Microsecond)
+
+	rows, err := view.RetrieveData("runtime/actor/lookup_latency")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "actor_type", rows[0].Tags[0].Key.Name())
+	assert.Equal(t, "cat", rows[0].Tags[0].Value)
+	assert.Equal(t, "app_id", rows[0].Tags[1].Key.Name())
+	assert.Equal(t, "fakeID", rows[0].Tags[1].Value)
+	assert.Equal(t, "cache", rows[0].Tags[2].Key.Name())
+	assert.Equal(t, "hit", rows[0].Tags[2].Value)
+	assert.Equal(t, 250.0, (rows[0].Data).(*view.DistributionData).Min)
+}
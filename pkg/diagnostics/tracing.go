@@ -222,11 +222,12 @@ func ConstructSubscriptionSpanAttributes(topic string) map[string]string {
 
 // StartInternalCallbackSpan starts trace span for internal callback such as input bindings and pubsub subscription.
 func StartInternalCallbackSpan(ctx context.Context, spanName string, parent trace.SpanContext, spec config.TracingSpec) (context.Context, *trace.Span) {
-	traceEnabled := diag_utils.IsTracingEnabled(spec.SamplingRate)
+	rate := samplingRate(spec, apiTypeFromCallbackSpanName(spanName))
+	traceEnabled := diag_utils.IsTracingEnabled(rate)
 	if !traceEnabled {
 		return ctx, nil
 	}
 
-	sampler := diag_utils.TraceSampler(spec.SamplingRate)
+	sampler := diag_utils.TraceSampler(rate)
 	return trace.StartSpanWithRemoteParent(ctx, spanName, parent, sampler, trace.WithSpanKind(trace.SpanKindServer))
 }
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/PuerkitoBio/purell"
@@ -28,12 +29,14 @@ import (
 	"github.com/dapr/dapr/pkg/messages"
 	"github.com/dapr/dapr/pkg/messaging"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
 	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
 	"github.com/golang/protobuf/ptypes/empty"
 	jsoniter "github.com/json-iterator/go"
+	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -43,8 +46,12 @@ import (
 
 const (
 	daprHTTPStatusHeader = "dapr-http-status"
+	authorizationHeader  = "authorization"
 )
 
+// etagMismatchViolationType is the type of the PreconditionFailure violation of a key whose ETag doesn't match.
+const etagMismatchViolationType = "ETAG_MISMATCH"
+
 // API is the gRPC interface for the Dapr gRPC API. It implements both the internal and external proto definitions.
 type API interface {
 	// DaprInternal Service methods
@@ -94,9 +101,11 @@ type api struct {
 	sendToOutputBindingFn    func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error)
 	tracingSpec              config.TracingSpec
 	accessControlList        *config.AccessControlList
+	responseHeaderFilter     config.HeaderFilterSpec
 	appProtocol              string
 	extendedMetadata         sync.Map
 	components               []components_v1alpha.Component
+	outboundPipeline         http_middleware.Pipeline
 	shutdown                 func()
 }
 
@@ -112,8 +121,10 @@ func NewAPI(
 	sendToOutputBindingFn func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error),
 	tracingSpec config.TracingSpec,
 	accessControlList *config.AccessControlList,
+	responseHeaderFilter config.HeaderFilterSpec,
 	appProtocol string,
 	getComponentsFn func() []components_v1alpha.Component,
+	outboundPipeline http_middleware.Pipeline,
 	shutdown func()) API {
 	transactionalStateStores := map[string]state.TransactionalStore{}
 	for key, store := range stateStores {
@@ -135,7 +146,9 @@ func NewAPI(
 		sendToOutputBindingFn:    sendToOutputBindingFn,
 		tracingSpec:              tracingSpec,
 		accessControlList:        accessControlList,
+		responseHeaderFilter:     responseHeaderFilter,
 		appProtocol:              appProtocol,
+		outboundPipeline:         outboundPipeline,
 		shutdown:                 shutdown,
 	}
 }
@@ -162,7 +175,8 @@ func (a *api) CallLocal(ctx context.Context, in *internalv1pb.InternalInvokeRequ
 				httpVerb = httpExt.GetVerb()
 			}
 		}
-		callAllowed, errMsg := a.applyAccessControlPolicies(ctx, operation, httpVerb, a.appProtocol)
+		claims := a.getBearerTokenClaims(req.Metadata())
+		callAllowed, errMsg := a.applyAccessControlPolicies(ctx, operation, httpVerb, a.appProtocol, claims)
 
 		if !callAllowed {
 			return nil, status.Errorf(codes.PermissionDenied, errMsg)
@@ -175,6 +189,7 @@ func (a *api) CallLocal(ctx context.Context, in *internalv1pb.InternalInvokeRequ
 		err = status.Errorf(codes.Internal, messages.ErrChannelInvoke, err)
 		return nil, err
 	}
+	messaging.FilterResponseHeaders(resp.Headers(), a.responseHeaderFilter)
 	return resp.Proto(), err
 }
 
@@ -186,7 +201,30 @@ func normalizeOperation(operation string) (string, error) {
 	return s, nil
 }
 
-func (a *api) applyAccessControlPolicies(ctx context.Context, operation string, httpVerb commonv1pb.HTTPExtension_Verb, appProtocol string) (bool, string) {
+// getBearerTokenClaims returns the claims of the bearer token forwarded with the call, if the access control
+// policies are configured to validate tokens and the token is valid.
+func (a *api) getBearerTokenClaims(md invokev1.DaprInternalMetadata) map[string]interface{} {
+	if a.accessControlList.JWTValidator == nil {
+		return nil
+	}
+
+	// Headers forwarded from HTTP callers keep their casing, gRPC metadata keys are lower case.
+	for k, v := range md {
+		if !strings.EqualFold(k, authorizationHeader) || len(v.GetValues()) == 0 {
+			continue
+		}
+
+		claims, err := a.accessControlList.JWTValidator.ValidateBearerToken(v.GetValues()[0])
+		if err != nil {
+			apiServerLogger.Debugf("ignoring bearer token: %s", err)
+			return nil
+		}
+		return claims
+	}
+	return nil
+}
+
+func (a *api) applyAccessControlPolicies(ctx context.Context, operation string, httpVerb commonv1pb.HTTPExtension_Verb, appProtocol string, claims map[string]interface{}) (bool, string) {
 	// Apply access control list filter
 	spiffeID, err := config.GetAndParseSpiffeID(ctx)
 	if err != nil {
@@ -209,7 +247,7 @@ func (a *api) applyAccessControlPolicies(ctx context.Context, operation string,
 		return false, errMessage
 	}
 
-	action, actionPolicy := config.IsOperationAllowedByAccessControlPolicy(spiffeID, appID, operation, httpVerb, appProtocol, a.accessControlList)
+	action, actionPolicy := config.IsOperationAllowedByAccessControlPolicy(spiffeID, appID, operation, httpVerb, appProtocol, claims, a.accessControlList)
 	emitACLMetrics(actionPolicy, appID, trustDomain, namespace, operation, httpVerb.String(), action)
 
 	if !action {
@@ -278,6 +316,7 @@ func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequ
 		DataContentType: in.DataContentType,
 		Data:            body,
 		TraceID:         corID,
+		Baggage:         a.getBaggage(ctx),
 		Pubsub:          in.PubsubName,
 	})
 	if err != nil {
@@ -319,10 +358,27 @@ func (a *api) PublishEvent(ctx context.Context, in *runtimev1pb.PublishEventRequ
 	return &emptypb.Empty{}, nil
 }
 
+// getBaggage returns the W3C baggage of the incoming call, or an empty string if it exceeds the configured size.
+func (a *api) getBaggage(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(diag.BaggageHeader)
+	if len(values) == 0 {
+		return ""
+	}
+	return diag.LimitBaggage(strings.Join(values, ","), a.tracingSpec)
+}
+
 func (a *api) InvokeService(ctx context.Context, in *runtimev1pb.InvokeServiceRequest) (*commonv1pb.InvokeResponse, error) {
 	req := invokev1.FromInvokeRequestMessage(in.GetMessage())
 
 	if incomingMD, ok := metadata.FromIncomingContext(ctx); ok {
+		if len(incomingMD.Get(diag.BaggageHeader)) > 0 && a.getBaggage(ctx) == "" {
+			incomingMD = incomingMD.Copy()
+			incomingMD.Delete(diag.BaggageHeader)
+		}
 		req.WithMetadata(incomingMD)
 	}
 
@@ -330,7 +386,13 @@ func (a *api) InvokeService(ctx context.Context, in *runtimev1pb.InvokeServiceRe
 		return nil, status.Errorf(codes.Internal, messages.ErrDirectInvokeNotReady)
 	}
 
-	resp, err := a.directMessaging.Invoke(ctx, in.Id, req)
+	var resp *invokev1.InvokeMethodResponse
+	var err error
+	if len(a.outboundPipeline.Handlers) > 0 {
+		resp, err = a.invokeThroughOutboundPipeline(ctx, in.Id, req)
+	} else {
+		resp, err = a.directMessaging.Invoke(ctx, in.Id, req)
+	}
 	if err != nil {
 		err = status.Errorf(codes.Internal, messages.ErrDirectInvoke, in.Id, err)
 		return nil, err
@@ -476,7 +538,7 @@ func (a *api) GetState(ctx context.Context, in *runtimev1pb.GetStateRequest) (*r
 		Key:      key,
 		Metadata: in.Metadata,
 		Options: state.GetStateOption{
-			Consistency: stateConsistencyToString(in.Consistency),
+			Consistency: state_loader.ConsistencyToString(in.Consistency),
 		},
 	}
 
@@ -504,6 +566,7 @@ func (a *api) SaveState(ctx context.Context, in *runtimev1pb.SaveStateRequest) (
 	}
 
 	reqs := []state.SetRequest{}
+	keys := make([]string, 0, len(in.States))
 	for _, s := range in.States {
 		key, err1 := state_loader.GetModifiedStateKey(s.Key, in.StoreName, a.id)
 		if err1 != nil {
@@ -519,22 +582,72 @@ func (a *api) SaveState(ctx context.Context, in *runtimev1pb.SaveStateRequest) (
 		}
 		if s.Options != nil {
 			req.Options = state.SetStateOption{
-				Consistency: stateConsistencyToString(s.Options.Consistency),
-				Concurrency: stateConcurrencyToString(s.Options.Concurrency),
+				Consistency: state_loader.ConsistencyToString(s.Options.Consistency),
+				Concurrency: state_loader.ConcurrencyToString(s.Options.Concurrency),
 			}
 		}
 		reqs = append(reqs, req)
+		keys = append(keys, s.Key)
 	}
 
-	err = store.BulkSet(reqs)
-	if err != nil {
+	if stateItemsHaveETags(in.States) {
+		err = a.executeStateOperationsWithETags(keys, func(i int) error {
+			return store.Set(&reqs[i])
+		}, func(err error) error {
+			return a.stateErrorResponse(err, messages.ErrStateSave, in.StoreName, err.Error())
+		})
+	} else if err = store.BulkSet(reqs); err != nil {
 		err = a.stateErrorResponse(err, messages.ErrStateSave, in.StoreName, err.Error())
+	}
+	if err != nil {
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
 	}
 	return &emptypb.Empty{}, nil
 }
 
+// executeStateOperationsWithETags runs op for each item of a bulk state request with ETags, so that
+// an ETag mismatch fails only its item. The first other error is returned converted by toError.
+// Otherwise, the keys of the conflicting items are returned in the PreconditionFailure details of
+// an Aborted error, so that the client can retry only those keys.
+func (a *api) executeStateOperationsWithETags(keys []string, op func(i int) error, toError func(err error) error) error {
+	errs := make([]error, len(keys))
+	limiter := concurrency.NewLimiter(concurrency.DefaultLimit)
+	for i := range keys {
+		fn := func(param interface{}) {
+			i := param.(int)
+			errs[i] = op(i)
+		}
+		limiter.Execute(fn, i)
+	}
+	limiter.Wait()
+
+	conflicts := &epb.PreconditionFailure{}
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if e, ok := err.(*state.ETagError); ok && e.Kind() == state.ETagMismatch {
+			conflicts.Violations = append(conflicts.Violations, &epb.PreconditionFailure_Violation{
+				Type:        etagMismatchViolationType,
+				Subject:     keys[i],
+				Description: err.Error(),
+			})
+			continue
+		}
+		return toError(err)
+	}
+	if len(conflicts.Violations) == 0 {
+		return nil
+	}
+
+	s := status.Newf(codes.Aborted, messages.ErrStateETagConflicts, len(conflicts.Violations))
+	if withDetails, err := s.WithDetails(conflicts); err == nil {
+		s = withDetails
+	}
+	return s.Err()
+}
+
 // stateErrorResponse takes a state store error, format and args and returns a status code encoded gRPC error
 func (a *api) stateErrorResponse(err error, format string, args ...interface{}) error {
 	e, ok := err.(*state.ETagError)
@@ -571,8 +684,8 @@ func (a *api) DeleteState(ctx context.Context, in *runtimev1pb.DeleteStateReques
 	}
 	if in.Options != nil {
 		req.Options = state.DeleteStateOption{
-			Concurrency: stateConcurrencyToString(in.Options.Concurrency),
-			Consistency: stateConsistencyToString(in.Options.Consistency),
+			Concurrency: state_loader.ConcurrencyToString(in.Options.Concurrency),
+			Consistency: state_loader.ConsistencyToString(in.Options.Consistency),
 		}
 	}
 
@@ -593,6 +706,7 @@ func (a *api) DeleteBulkState(ctx context.Context, in *runtimev1pb.DeleteBulkSta
 	}
 
 	reqs := make([]state.DeleteRequest, 0, len(in.States))
+	keys := make([]string, 0, len(in.States))
 	for _, item := range in.States {
 		key, err1 := state_loader.GetModifiedStateKey(item.Key, in.StoreName, a.id)
 		if err1 != nil {
@@ -607,13 +721,22 @@ func (a *api) DeleteBulkState(ctx context.Context, in *runtimev1pb.DeleteBulkSta
 		}
 		if item.Options != nil {
 			req.Options = state.DeleteStateOption{
-				Concurrency: stateConcurrencyToString(item.Options.Concurrency),
-				Consistency: stateConsistencyToString(item.Options.Consistency),
+				Concurrency: state_loader.ConcurrencyToString(item.Options.Concurrency),
+				Consistency: state_loader.ConsistencyToString(item.Options.Consistency),
 			}
 		}
 		reqs = append(reqs, req)
+		keys = append(keys, item.Key)
+	}
+	if stateItemsHaveETags(in.States) {
+		err = a.executeStateOperationsWithETags(keys, func(i int) error {
+			return store.Delete(&reqs[i])
+		}, func(err error) error {
+			return err
+		})
+	} else {
+		err = store.BulkDelete(reqs)
 	}
-	err = store.BulkDelete(reqs)
 	if err != nil {
 		apiServerLogger.Debug(err)
 		return &emptypb.Empty{}, err
@@ -715,6 +838,16 @@ func extractEtag(req *commonv1pb.StateItem) (bool, string) {
 	return false, ""
 }
 
+// getTransactionalStateStore returns the current instance of the transactional state store, so that
+// the instance replacing a reloaded state store serves the transactions.
+func (a *api) getTransactionalStateStore(storeName string) (state.TransactionalStore, bool) {
+	if _, ok := a.transactionalStateStores[storeName]; !ok {
+		return nil, false
+	}
+	transactionalStore, ok := a.stateStores[storeName].(state.TransactionalStore)
+	return transactionalStore, ok
+}
+
 func (a *api) ExecuteStateTransaction(ctx context.Context, in *runtimev1pb.ExecuteStateTransactionRequest) (*emptypb.Empty, error) {
 	if a.stateStores == nil || len(a.stateStores) == 0 {
 		err := status.Error(codes.FailedPrecondition, messages.ErrStateStoresNotConfigured)
@@ -730,7 +863,7 @@ func (a *api) ExecuteStateTransaction(ctx context.Context, in *runtimev1pb.Execu
 		return &emptypb.Empty{}, err
 	}
 
-	transactionalStore, ok := a.transactionalStateStores[storeName]
+	transactionalStore, ok := a.getTransactionalStateStore(storeName)
 	if !ok {
 		err := status.Errorf(codes.Unimplemented, messages.ErrStateStoreNotSupported, storeName)
 		apiServerLogger.Debug(err)
@@ -763,8 +896,8 @@ func (a *api) ExecuteStateTransaction(ctx context.Context, in *runtimev1pb.Execu
 			}
 			if req.Options != nil {
 				setReq.Options = state.SetStateOption{
-					Concurrency: stateConcurrencyToString(req.Options.Concurrency),
-					Consistency: stateConsistencyToString(req.Options.Consistency),
+					Concurrency: state_loader.ConcurrencyToString(req.Options.Concurrency),
+					Consistency: state_loader.ConsistencyToString(req.Options.Consistency),
 				}
 			}
 
@@ -784,8 +917,8 @@ func (a *api) ExecuteStateTransaction(ctx context.Context, in *runtimev1pb.Execu
 			}
 			if req.Options != nil {
 				delReq.Options = state.DeleteStateOption{
-					Concurrency: stateConcurrencyToString(req.Options.Concurrency),
-					Consistency: stateConsistencyToString(req.Options.Consistency),
+					Concurrency: state_loader.ConcurrencyToString(req.Options.Concurrency),
+					Consistency: state_loader.ConsistencyToString(req.Options.Consistency),
 				}
 			}
 
@@ -935,6 +1068,9 @@ func (a *api) GetActorState(ctx context.Context, in *runtimev1pb.GetActorStateRe
 	}, nil
 }
 
+// ExecuteActorStateTransaction saves the state of an actor in a transaction. The TransactionalActorStateOperation
+// message has no metadata field, so unlike the HTTP API the upserts can't set a ttlInSeconds: the TTL of the actor
+// state is only available over HTTP until the message gets one.
 func (a *api) ExecuteActorStateTransaction(ctx context.Context, in *runtimev1pb.ExecuteActorStateTransactionRequest) (*emptypb.Empty, error) {
 	if a.actor == nil {
 		err := status.Errorf(codes.Internal, messages.ErrActorRuntimeNotFound)
@@ -1017,6 +1153,9 @@ func (a *api) InvokeActor(ctx context.Context, in *runtimev1pb.InvokeActorReques
 	req := invokev1.NewInvokeMethodRequest(in.Method)
 	req.WithActor(in.ActorType, in.ActorId)
 	req.WithRawData(in.Data, "")
+	if baggage := a.getBaggage(ctx); baggage != "" {
+		req.WithMetadata(map[string][]string{diag.BaggageHeader: {baggage}})
+	}
 
 	resp, err := a.actor.Call(context.TODO(), req)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/phayes/freeport"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/valyala/fasthttp"
 	"go.opencensus.io/trace"
 	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
@@ -40,10 +42,12 @@ import (
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	"github.com/dapr/dapr/pkg/messages"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
 	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
 	daprt "github.com/dapr/dapr/pkg/testing"
 	testtrace "github.com/dapr/dapr/pkg/testing/trace"
 	"github.com/dapr/kit/logger"
@@ -185,7 +189,7 @@ func startDaprAPIServer(port int, testAPIServer *api, token string) *grpc.Server
 	opts := []grpc.ServerOption{}
 	if token != "" {
 		opts = append(opts,
-			grpc.UnaryInterceptor(setAPIAuthenticationMiddlewareUnary(token, "dapr-api-token")),
+			grpc.UnaryInterceptor(setAPIAuthenticationMiddlewareUnary(auth.NewToken(token), "dapr-api-token")),
 		)
 	}
 
@@ -309,6 +313,42 @@ func TestCallLocal(t *testing.T) {
 		_, err := client.CallLocal(context.Background(), request)
 		assert.Equal(t, codes.Internal, status.Code(err))
 	})
+
+	t.Run("response headers are filtered", func(t *testing.T) {
+		port, _ := freeport.GetFreePort()
+
+		fakeResp := invokev1.NewInvokeMethodResponse(200, "OK", nil).WithHeaders(metadata.MD{
+			"Content-Type":     []string{"application/json"},
+			"X-Internal-Debug": []string{"true"},
+			"X-Request-Id":     []string{"1"},
+			"Set-Cookie":       []string{"a=b"},
+		})
+		mockAppChannel := new(channelt.MockAppChannel)
+		mockAppChannel.On("InvokeMethod", mock.AnythingOfType("*context.valueCtx"), mock.AnythingOfType("*v1.InvokeMethodRequest")).Return(fakeResp, nil)
+		fakeAPI := &api{
+			id:         "fakeAPI",
+			appChannel: mockAppChannel,
+			responseHeaderFilter: config.HeaderFilterSpec{
+				Allow: []string{"x-*"},
+				Deny:  []string{"X-Internal-*"},
+			},
+		}
+		server := startInternalServer(port, fakeAPI)
+		defer server.Stop()
+		clientConn := createTestClient(port)
+		defer clientConn.Close()
+
+		client := internalv1pb.NewServiceInvocationClient(clientConn)
+		request := invokev1.NewInvokeMethodRequest("method").Proto()
+
+		resp, err := client.CallLocal(context.Background(), request)
+		assert.NoError(t, err)
+		headers := resp.GetHeaders()
+		assert.Contains(t, headers, "Content-Type")
+		assert.Contains(t, headers, "X-Request-Id")
+		assert.NotContains(t, headers, "X-Internal-Debug")
+		assert.NotContains(t, headers, "Set-Cookie")
+	})
 }
 
 func mustMarshalAny(msg proto.Message) *anypb.Any {
@@ -562,6 +602,79 @@ func TestInvokeServiceFromHTTPResponse(t *testing.T) {
 	}
 }
 
+func TestInvokeServiceWithOutboundPipeline(t *testing.T) {
+	mockDirectMessaging := new(daprt.MockDirectMessaging)
+
+	pipeline := http_middleware.Pipeline{
+		Handlers: []http_middleware.Middleware{
+			func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+				return func(ctx *fasthttp.RequestCtx) {
+					if len(ctx.Request.Header.Peek("x-forbidden")) > 0 {
+						ctx.Response.SetStatusCode(fasthttp.StatusForbidden)
+						ctx.Response.SetBodyString("forbidden")
+						return
+					}
+					ctx.Request.Header.Set("X-Api-Key", "fakeKey")
+					ctx.Request.SetRequestURI(strings.Replace(string(ctx.RequestURI()), "/method/", "/method/v2/", 1))
+					h(ctx)
+				}
+			},
+		},
+	}
+
+	fakeAPI := &api{
+		id:               "fakeAPI",
+		directMessaging:  mockDirectMessaging,
+		outboundPipeline: pipeline,
+	}
+
+	t.Run("middleware rewrites the invocation", func(t *testing.T) {
+		fakeResp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		fakeResp.WithRawData([]byte("fakeDirectMessageResponse"), "application/json")
+
+		mockDirectMessaging.Calls = nil // reset call count
+		mockDirectMessaging.On("Invoke",
+			mock.AnythingOfType("*context.valueCtx"),
+			"fakeAppID",
+			mock.AnythingOfType("*v1.InvokeMethodRequest")).Return(fakeResp, nil).Once()
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-trace", "fakeTrace"))
+		resp, err := fakeAPI.InvokeService(ctx, &runtimev1pb.InvokeServiceRequest{
+			Id: "fakeAppID",
+			Message: &commonv1pb.InvokeRequest{
+				Method: "fakeMethod",
+				Data:   &anypb.Any{Value: []byte("testData")},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("fakeDirectMessageResponse"), resp.Data.Value)
+		mockDirectMessaging.AssertNumberOfCalls(t, "Invoke", 1)
+		req := mockDirectMessaging.Calls[0].Arguments.Get(2).(*invokev1.InvokeMethodRequest)
+		assert.Equal(t, "v2/fakeMethod", req.Message().Method)
+		assert.Equal(t, []string{"fakeKey"}, req.Metadata()["x-api-key"].GetValues())
+		assert.Equal(t, []string{"fakeTrace"}, req.Metadata()["x-trace"].GetValues())
+		assert.Equal(t, []byte("testData"), req.Message().Data.Value)
+	})
+
+	t.Run("middleware responds to the invocation", func(t *testing.T) {
+		mockDirectMessaging.Calls = nil // reset call count
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-forbidden", "true"))
+		_, err := fakeAPI.InvokeService(ctx, &runtimev1pb.InvokeServiceRequest{
+			Id: "fakeAppID",
+			Message: &commonv1pb.InvokeRequest{
+				Method: "fakeMethod",
+			},
+		})
+
+		mockDirectMessaging.AssertNumberOfCalls(t, "Invoke", 0)
+		s, ok := status.FromError(err)
+		assert.True(t, ok)
+		assert.Equal(t, codes.PermissionDenied, s.Code())
+	})
+}
+
 func TestInvokeServiceFromGRPCResponse(t *testing.T) {
 	mockDirectMessaging := new(daprt.MockDirectMessaging)
 
@@ -938,6 +1051,97 @@ func TestSaveState(t *testing.T) {
 	}
 }
 
+func TestBulkStateWithETags(t *testing.T) {
+	fakeStore := &daprt.MockStateStore{}
+	fakeStore.On("Set", mock.MatchedBy(func(req *state.SetRequest) bool {
+		return req.Key == "fakeAPI||good-key"
+	})).Return(nil)
+	fakeStore.On("Set", mock.MatchedBy(func(req *state.SetRequest) bool {
+		return req.Key == "fakeAPI||conflict-key"
+	})).Return(state.NewETagError(state.ETagMismatch, errors.New("etag mismatch")))
+	fakeStore.On("Set", mock.MatchedBy(func(req *state.SetRequest) bool {
+		return req.Key == "fakeAPI||error-key"
+	})).Return(errors.New("failed to save state with error-key"))
+	fakeStore.On("Delete", mock.MatchedBy(func(req *state.DeleteRequest) bool {
+		return req.Key == "fakeAPI||good-key"
+	})).Return(nil)
+	fakeStore.On("Delete", mock.MatchedBy(func(req *state.DeleteRequest) bool {
+		return req.Key == "fakeAPI||conflict-key"
+	})).Return(state.NewETagError(state.ETagMismatch, errors.New("etag mismatch")))
+
+	fakeAPI := &api{
+		id:          "fakeAPI",
+		stateStores: map[string]state.Store{"store1": fakeStore},
+	}
+	port, _ := freeport.GetFreePort()
+	server := startDaprAPIServer(port, fakeAPI, "")
+	defer server.Stop()
+
+	clientConn := createTestClient(port)
+	defer clientConn.Close()
+
+	client := runtimev1pb.NewDaprClient(clientConn)
+
+	conflictingKeys := func(err error) []string {
+		keys := []string{}
+		for _, detail := range status.Convert(err).Details() {
+			if failure, ok := detail.(*epb.PreconditionFailure); ok {
+				for _, v := range failure.Violations {
+					keys = append(keys, v.Subject)
+				}
+			}
+		}
+		return keys
+	}
+
+	t.Run("save state with matching etags", func(t *testing.T) {
+		_, err := client.SaveState(context.Background(), &runtimev1pb.SaveStateRequest{
+			StoreName: "store1",
+			States: []*commonv1pb.StateItem{
+				{Key: "good-key", Etag: &commonv1pb.Etag{Value: "1"}},
+			},
+		})
+		assert.NoError(t, err)
+		fakeStore.AssertNotCalled(t, "BulkSet", mock.Anything)
+	})
+
+	t.Run("save state with a conflicting etag", func(t *testing.T) {
+		_, err := client.SaveState(context.Background(), &runtimev1pb.SaveStateRequest{
+			StoreName: "store1",
+			States: []*commonv1pb.StateItem{
+				{Key: "good-key", Etag: &commonv1pb.Etag{Value: "1"}},
+				{Key: "conflict-key", Etag: &commonv1pb.Etag{Value: "1"}},
+			},
+		})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+		assert.Equal(t, []string{"conflict-key"}, conflictingKeys(err))
+	})
+
+	t.Run("save state with an error and a conflicting etag", func(t *testing.T) {
+		_, err := client.SaveState(context.Background(), &runtimev1pb.SaveStateRequest{
+			StoreName: "store1",
+			States: []*commonv1pb.StateItem{
+				{Key: "conflict-key", Etag: &commonv1pb.Etag{Value: "1"}},
+				{Key: "error-key", Etag: &commonv1pb.Etag{Value: "1"}},
+			},
+		})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("delete bulk state with a conflicting etag", func(t *testing.T) {
+		_, err := client.DeleteBulkState(context.Background(), &runtimev1pb.DeleteBulkStateRequest{
+			StoreName: "store1",
+			States: []*commonv1pb.StateItem{
+				{Key: "good-key", Etag: &commonv1pb.Etag{Value: "1"}},
+				{Key: "conflict-key", Etag: &commonv1pb.Etag{Value: "1"}},
+			},
+		})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+		assert.Equal(t, []string{"conflict-key"}, conflictingKeys(err))
+		fakeStore.AssertNotCalled(t, "BulkDelete", mock.Anything)
+	})
+}
+
 func TestGetState(t *testing.T) {
 	fakeStore := &daprt.MockStateStore{}
 	fakeStore.On("Get", mock.MatchedBy(func(req *state.GetRequest) bool {
@@ -5,11 +5,12 @@ import (
 	"net/http"
 
 	v1 "github.com/dapr/dapr/pkg/messaging/v1"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
-func setAPIAuthenticationMiddlewareUnary(apiToken, authHeader string) grpc.UnaryServerInterceptor {
+func setAPIAuthenticationMiddlewareUnary(apiToken *auth.Token, authHeader string) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
@@ -23,7 +24,7 @@ func setAPIAuthenticationMiddlewareUnary(apiToken, authHeader string) grpc.Unary
 			return nil, err
 		}
 
-		if token[0] != apiToken {
+		if !apiToken.Accepts(token[0]) {
 			err := v1.ErrorFromHTTPResponseCode(http.StatusUnauthorized, "authentication error: api token mismatch")
 			return nil, err
 		}
@@ -53,19 +53,29 @@ func (g *Manager) SetAuthenticator(auth security.Authenticator) {
 }
 
 // CreateLocalChannel creates a new gRPC AppChannel
-func (g *Manager) CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool) (channel.AppChannel, error) {
-	conn, err := g.GetGRPCConnection(fmt.Sprintf("127.0.0.1:%v", port), "", "", true, false, sslEnabled)
+func (g *Manager) CreateLocalChannel(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, tlsConfig *tls.Config) (channel.AppChannel, error) {
+	conn, err := g.getGRPCConnection(fmt.Sprintf("127.0.0.1:%v", port), "", "", true, false, sslEnabled, tlsConfig)
 	if err != nil {
 		return nil, errors.Errorf("error establishing connection to app grpc on port %v: %s", port, err)
 	}
 
+	appToken, err := security.LoadAppToken()
+	if err != nil {
+		return nil, err
+	}
+
 	g.AppClient = conn
-	ch := grpc_channel.CreateLocalChannel(port, maxConcurrency, conn, spec)
+	ch := grpc_channel.CreateLocalChannel(port, maxConcurrency, conn, spec, appToken)
 	return ch, nil
 }
 
 // GetGRPCConnection returns a new grpc connection for a given address and inits one if doesn't exist
 func (g *Manager) GetGRPCConnection(address, id string, namespace string, skipTLS, recreateIfExists, sslEnabled bool) (*grpc.ClientConn, error) {
+	return g.getGRPCConnection(address, id, namespace, skipTLS, recreateIfExists, sslEnabled, nil)
+}
+
+// getGRPCConnection returns a grpc connection that uses the given TLS config when SSL is enabled.
+func (g *Manager) getGRPCConnection(address, id string, namespace string, skipTLS, recreateIfExists, sslEnabled bool, sslConfig *tls.Config) (*grpc.ClientConn, error) {
 	g.lock.RLock()
 	if val, ok := g.connectionPool[address]; ok && !recreateIfExists {
 		g.lock.RUnlock()
@@ -103,9 +113,10 @@ func (g *Manager) GetGRPCConnection(address, id string, namespace string, skipTL
 
 		// nolint:gosec
 		ta := credentials.NewTLS(&tls.Config{
-			ServerName:   serverName,
-			Certificates: []tls.Certificate{cert},
-			RootCAs:      signedCert.TrustChain,
+			ServerName:            serverName,
+			Certificates:          []tls.Certificate{cert},
+			RootCAs:               signedCert.TrustChain,
+			VerifyPeerCertificate: security.DefaultRevocationChecker.VerifyPeerCertificate,
 		})
 		opts = append(opts, grpc.WithTransportCredentials(ta))
 		transportCredentialsAdded = true
@@ -116,10 +127,13 @@ func (g *Manager) GetGRPCConnection(address, id string, namespace string, skipTL
 
 	dialPrefix := GetDialAddressPrefix(g.mode)
 	if sslEnabled {
-		// nolint:gosec
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
-			InsecureSkipVerify: true,
-		})))
+		if sslConfig == nil {
+			// nolint:gosec
+			sslConfig = &tls.Config{
+				InsecureSkipVerify: true,
+			}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(sslConfig)))
 		transportCredentialsAdded = true
 	}
 
@@ -0,0 +1,91 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package grpc
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	grpc_go "google.golang.org/grpc"
+
+	"github.com/dapr/components-contrib/middleware"
+	"github.com/dapr/dapr/pkg/components"
+)
+
+type (
+	// Middleware is a gRPC middleware component definition. gRPC middlewares are unary server
+	// interceptors, so they only apply to the unary calls of the Dapr gRPC API, not to streams.
+	Middleware struct {
+		Name          string
+		FactoryMethod func(metadata middleware.Metadata) (grpc_go.UnaryServerInterceptor, error)
+	}
+
+	// MiddlewareRegistry is the interface for callers to get registered gRPC middleware.
+	MiddlewareRegistry interface {
+		Register(components ...Middleware)
+		Create(name, version string, metadata middleware.Metadata) (grpc_go.UnaryServerInterceptor, error)
+	}
+
+	// Pipeline defines the interceptors plugged into the Dapr gRPC API, in order.
+	Pipeline struct {
+		Interceptors []grpc_go.UnaryServerInterceptor
+	}
+
+	grpcMiddlewareRegistry struct {
+		middleware map[string]func(middleware.Metadata) (grpc_go.UnaryServerInterceptor, error)
+	}
+)
+
+// NewMiddleware creates a Middleware.
+func NewMiddleware(name string, factoryMethod func(metadata middleware.Metadata) (grpc_go.UnaryServerInterceptor, error)) Middleware {
+	return Middleware{
+		Name:          name,
+		FactoryMethod: factoryMethod,
+	}
+}
+
+// NewMiddlewareRegistry returns a new gRPC middleware registry.
+func NewMiddlewareRegistry() MiddlewareRegistry {
+	return &grpcMiddlewareRegistry{
+		middleware: map[string]func(middleware.Metadata) (grpc_go.UnaryServerInterceptor, error){},
+	}
+}
+
+// Register registers one or more new gRPC middlewares.
+func (p *grpcMiddlewareRegistry) Register(components ...Middleware) {
+	for _, component := range components {
+		p.middleware[createMiddlewareFullName(component.Name)] = component.FactoryMethod
+	}
+}
+
+// Create instantiates a gRPC middleware based on `name`.
+func (p *grpcMiddlewareRegistry) Create(name, version string, metadata middleware.Metadata) (grpc_go.UnaryServerInterceptor, error) {
+	if method, ok := p.getMiddleware(name, version); ok {
+		interceptor, err := method(metadata)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating gRPC middleware %s/%s", name, version)
+		}
+		return interceptor, nil
+	}
+	return nil, errors.Errorf("gRPC middleware %s/%s has not been registered", name, version)
+}
+
+func (p *grpcMiddlewareRegistry) getMiddleware(name, version string) (func(middleware.Metadata) (grpc_go.UnaryServerInterceptor, error), bool) {
+	nameLower := strings.ToLower(name)
+	versionLower := strings.ToLower(version)
+	middlewareFn, ok := p.middleware[nameLower+"/"+versionLower]
+	if ok {
+		return middlewareFn, true
+	}
+	if components.IsInitialVersion(versionLower) {
+		middlewareFn, ok = p.middleware[nameLower]
+	}
+	return middlewareFn, ok
+}
+
+func createMiddlewareFullName(name string) string {
+	return strings.ToLower("middleware.grpc." + name)
+}
@@ -0,0 +1,72 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	grpc_go "google.golang.org/grpc"
+
+	"github.com/dapr/components-contrib/middleware"
+)
+
+func TestMiddlewareRegistry(t *testing.T) {
+	testRegistry := NewMiddlewareRegistry()
+
+	interceptor := func(name string) grpc_go.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpc_go.UnaryServerInfo, handler grpc_go.UnaryHandler) (interface{}, error) {
+			return name, nil
+		}
+	}
+	invoke := func(i grpc_go.UnaryServerInterceptor) interface{} {
+		resp, _ := i(context.Background(), nil, &grpc_go.UnaryServerInfo{}, nil)
+		return resp
+	}
+
+	t.Run("middleware is registered", func(t *testing.T) {
+		const componentName = "middleware.grpc.mockMiddleware"
+
+		testRegistry.Register(NewMiddleware("mockMiddleware", func(middleware.Metadata) (grpc_go.UnaryServerInterceptor, error) {
+			return interceptor("v1"), nil
+		}))
+		testRegistry.Register(NewMiddleware("mockMiddleware/v2", func(middleware.Metadata) (grpc_go.UnaryServerInterceptor, error) {
+			return interceptor("v2"), nil
+		}))
+
+		i, err := testRegistry.Create(componentName, "v1", middleware.Metadata{})
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", invoke(i))
+
+		i, err = testRegistry.Create(componentName, "v2", middleware.Metadata{})
+		assert.NoError(t, err)
+		assert.Equal(t, "v2", invoke(i))
+
+		// check case-insensitivity
+		i, err = testRegistry.Create(strings.ToUpper(componentName), "V2", middleware.Metadata{})
+		assert.NoError(t, err)
+		assert.Equal(t, "v2", invoke(i))
+	})
+
+	t.Run("middleware fails to be created", func(t *testing.T) {
+		testRegistry.Register(NewMiddleware("badMiddleware", func(middleware.Metadata) (grpc_go.UnaryServerInterceptor, error) {
+			return nil, errors.New("invalid metadata")
+		}))
+
+		i, err := testRegistry.Create("middleware.grpc.badMiddleware", "v1", middleware.Metadata{})
+		assert.Nil(t, i)
+		assert.EqualError(t, err, "error creating gRPC middleware middleware.grpc.badMiddleware/v1: invalid metadata")
+	})
+
+	t.Run("middleware is not registered", func(t *testing.T) {
+		i, err := testRegistry.Create("middleware.grpc.fakeMiddleware", "v1", middleware.Metadata{})
+		assert.Nil(t, i)
+		assert.EqualError(t, err, "gRPC middleware middleware.grpc.fakeMiddleware/v1 has not been registered")
+	})
+}
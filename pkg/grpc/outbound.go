@@ -0,0 +1,142 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
+)
+
+// outboundInvokePathPrefix is the path prefix of the service invocations presented to the outbound pipeline,
+// the same as the one of the invoke endpoint of the HTTP API.
+const outboundInvokePathPrefix = "/v1.0/invoke/"
+
+// invokeThroughOutboundPipeline invokes a service through the outbound HTTP pipeline, so that its middlewares
+// apply to the service invocations of the gRPC API as they do to those of the HTTP API. The invocation is
+// presented to the middlewares as a request of the invoke endpoint of the HTTP API. They may rewrite its
+// target app, method, query string, headers and data, or respond to it without invoking the target.
+func (a *api) invokeThroughOutboundPipeline(ctx context.Context, targetID string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
+	reqCtx := &fasthttp.RequestCtx{}
+	msg := req.Message()
+	uri := fmt.Sprintf("%s%s/method/%s", outboundInvokePathPrefix, targetID, msg.Method)
+	verb := fasthttp.MethodPost
+	if ext := msg.GetHttpExtension(); ext != nil {
+		verb = ext.Verb.String()
+		if ext.Querystring != "" {
+			uri += "?" + ext.Querystring
+		}
+	}
+	reqCtx.Request.Header.SetMethod(verb)
+	reqCtx.Request.SetRequestURI(uri)
+	for k, v := range req.Metadata() {
+		for _, val := range v.Values {
+			reqCtx.Request.Header.Add(k, val)
+		}
+	}
+	contentType, data := req.RawData()
+	reqCtx.Request.Header.SetContentType(contentType)
+	reqCtx.Request.SetBody(data)
+	headers := outboundRequestHeaders(&reqCtx.Request.Header)
+
+	var resp *invokev1.InvokeMethodResponse
+	var err error
+	invoked := false
+	a.outboundPipeline.Apply(func(reqCtx *fasthttp.RequestCtx) {
+		invoked = true
+		targetID = applyOutboundRequest(reqCtx, targetID, req, headers, contentType, data)
+		resp, err = a.directMessaging.Invoke(ctx, targetID, req)
+	})(reqCtx)
+	if invoked {
+		return resp, err
+	}
+
+	// a middleware responded to the request
+	resp = invokev1.NewInvokeMethodResponse(int32(reqCtx.Response.StatusCode()), "", nil)
+	resp.WithFastHTTPHeaders(&reqCtx.Response.Header)
+	resp.WithRawData(reqCtx.Response.Body(), string(reqCtx.Response.Header.ContentType()))
+	return resp, nil
+}
+
+// applyOutboundRequest applies the changes the middlewares made to the request to the service invocation,
+// and returns its target app. Only the changed headers and data are applied, so that the metadata and
+// data of a gRPC invocation are otherwise kept as they are.
+func applyOutboundRequest(reqCtx *fasthttp.RequestCtx, targetID string, req *invokev1.InvokeMethodRequest,
+	headers map[string][]string, contentType string, data []byte) string {
+	path := string(reqCtx.Path())
+	if strings.HasPrefix(path, outboundInvokePathPrefix) {
+		target := strings.SplitN(strings.TrimPrefix(path, outboundInvokePathPrefix), "/method/", 2)
+		if len(target) == 2 && target[0] != "" && target[1] != "" {
+			targetID = target[0]
+			req.Message().Method = target[1]
+		}
+	}
+
+	if ext := req.Message().GetHttpExtension(); ext != nil {
+		req.WithHTTPExtension(string(reqCtx.Method()), reqCtx.QueryArgs().String())
+	}
+
+	newContentType := string(reqCtx.Request.Header.ContentType())
+	if newData := reqCtx.Request.Body(); newContentType != contentType || !bytes.Equal(newData, data) {
+		req.WithRawData(append([]byte(nil), newData...), newContentType)
+	}
+
+	newHeaders := outboundRequestHeaders(&reqCtx.Request.Header)
+	md := req.Proto().Metadata
+	if md == nil {
+		md = invokev1.DaprInternalMetadata{}
+		req.Proto().Metadata = md
+	}
+	for k, v := range newHeaders {
+		if !equalStrings(headers[k], v) {
+			deleteMetadata(md, k)
+			md[k] = &internalv1pb.ListStringValue{Values: v}
+		}
+	}
+	for k := range headers {
+		if _, ok := newHeaders[k]; !ok {
+			deleteMetadata(md, k)
+		}
+	}
+	return targetID
+}
+
+// outboundRequestHeaders returns the headers of a request with lowercase names, as gRPC metadata.
+func outboundRequestHeaders(header *fasthttp.RequestHeader) map[string][]string {
+	headers := map[string][]string{}
+	header.VisitAll(func(key []byte, value []byte) {
+		k := strings.ToLower(string(key))
+		headers[k] = append(headers[k], string(value))
+	})
+	return headers
+}
+
+// deleteMetadata deletes a key of the metadata regardless of its case.
+func deleteMetadata(md invokev1.DaprInternalMetadata, key string) {
+	for k := range md {
+		if strings.EqualFold(k, key) {
+			delete(md, k)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -6,6 +6,7 @@
 package grpc
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"net"
@@ -14,13 +15,14 @@ import (
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/pkg/errors"
+	"go.uber.org/atomic"
 	grpc_go "google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
 
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
-	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+	"github.com/dapr/dapr/pkg/logsampler"
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 	auth "github.com/dapr/dapr/pkg/runtime/security"
@@ -38,6 +40,11 @@ const (
 // Server is an interface for the dapr gRPC server
 type Server interface {
 	StartNonBlocking() error
+	RotateWorkloadCert() error
+	// StopAccepting makes the server refuse new calls. The calls in progress complete.
+	StopAccepting()
+	// InflightRequests returns the number of calls in progress.
+	InflightRequests() int32
 }
 
 type server struct {
@@ -55,14 +62,25 @@ type server struct {
 	kind               string
 	logger             logger.Logger
 	maxConnectionAge   *time.Duration
-	authToken          string
+	authToken          *auth.Token
+	pipeline           Pipeline
+	inflight           atomic.Int32
 }
 
-var apiServerLogger = logger.NewLogger("dapr.runtime.grpc.api")
-var internalServerLogger = logger.NewLogger("dapr.runtime.grpc.internal")
+var apiServerLogger = logsampler.NewLogger(logger.NewLogger("dapr.runtime.grpc.api"))
+var internalServerLogger = logsampler.NewLogger(logger.NewLogger("dapr.runtime.grpc.internal"))
 
 // NewAPIServer returns a new user facing gRPC API server
-func NewAPIServer(api API, config ServerConfig, tracingSpec config.TracingSpec, metricSpec config.MetricSpec) Server {
+func NewAPIServer(api API, config ServerConfig, tracingSpec config.TracingSpec, metricSpec config.MetricSpec, pipeline Pipeline) (Server, error) {
+	var authToken *auth.Token
+	if auth.APITokenConfigured() {
+		var err error
+		authToken, err = auth.LoadAPIToken()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load api token")
+		}
+	}
+
 	return &server{
 		api:         api,
 		config:      config,
@@ -70,8 +88,9 @@ func NewAPIServer(api API, config ServerConfig, tracingSpec config.TracingSpec,
 		metricSpec:  metricSpec,
 		kind:        apiServer,
 		logger:      apiServerLogger,
-		authToken:   auth.GetAPIToken(),
-	}
+		authToken:   authToken,
+		pipeline:    pipeline,
+	}, nil
 }
 
 // NewInternalServer returns a new gRPC server for Dapr to Dapr communications
@@ -142,14 +161,16 @@ func (s *server) generateWorkloadCert() error {
 
 func (s *server) getMiddlewareOptions() []grpc_go.ServerOption {
 	opts := []grpc_go.ServerOption{}
-	intr := []grpc_go.UnaryServerInterceptor{}
+	intr := []grpc_go.UnaryServerInterceptor{s.countInflightUnary}
 
-	if s.authToken != "" {
+	// the token authentication is enabled when the token is configured, even before a token file
+	// holds a token, so that the token is checked once it is written.
+	if s.authToken != nil {
 		s.logger.Info("enabled token authentication on gRPC server")
 		intr = append(intr, setAPIAuthenticationMiddlewareUnary(s.authToken, auth.APITokenHeader))
 	}
 
-	if diag_utils.IsTracingEnabled(s.tracingSpec.SamplingRate) {
+	if diag.IsTracingEnabled(s.tracingSpec) {
 		s.logger.Info("enabled gRPC tracing middleware")
 		intr = append(intr, diag.GRPCTraceUnaryServerInterceptor(s.config.AppID, s.tracingSpec))
 	}
@@ -159,6 +180,11 @@ func (s *server) getMiddlewareOptions() []grpc_go.ServerOption {
 		intr = append(intr, diag.DefaultGRPCMonitoring.UnaryServerInterceptor())
 	}
 
+	if len(s.pipeline.Interceptors) > 0 {
+		s.logger.Infof("enabled %d gRPC pipeline middlewares", len(s.pipeline.Interceptors))
+		intr = append(intr, s.pipeline.Interceptors...)
+	}
+
 	chain := grpc_middleware.ChainUnaryServer(
 		intr...,
 	)
@@ -169,6 +195,28 @@ func (s *server) getMiddlewareOptions() []grpc_go.ServerOption {
 	return opts
 }
 
+// countInflightUnary counts the calls in progress, which the shutdown waits for.
+func (s *server) countInflightUnary(ctx context.Context, req interface{}, info *grpc_go.UnaryServerInfo, handler grpc_go.UnaryHandler) (interface{}, error) {
+	s.inflight.Inc()
+	defer s.inflight.Dec()
+	return handler(ctx, req)
+}
+
+// StopAccepting stops the server gracefully: the listener is closed and the new calls are refused,
+// and the calls in progress complete.
+func (s *server) StopAccepting() {
+	if s.srv == nil {
+		return
+	}
+	s.logger.Infof("stopping the %s", s.kind)
+	go s.srv.GracefulStop()
+}
+
+// InflightRequests returns the number of calls in progress.
+func (s *server) InflightRequests() int32 {
+	return s.inflight.Load()
+}
+
 func (s *server) getGRPCServer() (*grpc_go.Server, error) {
 	opts := s.getMiddlewareOptions()
 	if s.maxConnectionAge != nil {
@@ -188,6 +236,7 @@ func (s *server) getGRPCServer() (*grpc_go.Server, error) {
 			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
 				return &s.tlsCert, nil
 			},
+			VerifyPeerCertificate: auth.DefaultRevocationChecker.VerifyPeerCertificate,
 		}
 		ta := credentials.NewTLS(&tlsConfig)
 
@@ -210,17 +259,46 @@ func (s *server) startWorkloadCertRotation() {
 		renew := shouldRenewCert(s.signedCert.Expiry, s.signedCertDuration)
 		if renew {
 			s.logger.Info("renewing certificate: requesting new cert and restarting gRPC server")
-
-			err := s.generateWorkloadCert()
-			if err != nil {
-				s.logger.Errorf("error starting server: %s", err)
-			}
-			diag.DefaultMonitoring.MTLSWorkLoadCertRotationCompleted()
+			s.renewWorkloadCert() // nolint:errcheck
 		}
+		diag.DefaultMonitoring.MTLSWorkloadCertExpiry(s.signedCert.Expiry)
 		s.renewMutex.Unlock()
 	}
 }
 
+// RotateWorkloadCert immediately requests a new workload cert from Sentry, regardless of the expiry of the current one.
+func (s *server) RotateWorkloadCert() error {
+	if s.authenticator == nil {
+		return errors.New("mTLS is not enabled")
+	}
+
+	s.renewMutex.Lock()
+	defer s.renewMutex.Unlock()
+
+	if s.signedCert == nil {
+		return errors.New("the server is not started")
+	}
+
+	s.logger.Info("forced renewal of the workload certificate")
+	err := s.renewWorkloadCert()
+	if err == nil {
+		diag.DefaultMonitoring.MTLSWorkloadCertExpiry(s.signedCert.Expiry)
+	}
+	return err
+}
+
+// renewWorkloadCert replaces the workload cert. renewMutex must be held by the caller.
+func (s *server) renewWorkloadCert() error {
+	err := s.generateWorkloadCert()
+	if err != nil {
+		s.logger.Errorf("error renewing workload certificate: %s", err)
+		diag.DefaultMonitoring.MTLSWorkLoadCertRotationFailed("cert_sign")
+		return err
+	}
+	diag.DefaultMonitoring.MTLSWorkLoadCertRotationCompleted()
+	return nil
+}
+
 func shouldRenewCert(certExpiryDate time.Time, certDuration time.Duration) bool {
 	expiresIn := certExpiryDate.Sub(time.Now().UTC())
 	expiresInSeconds := expiresIn.Seconds()
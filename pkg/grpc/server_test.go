@@ -1,11 +1,13 @@
 package grpc
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	grpc_go "google.golang.org/grpc"
 
 	"github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/kit/logger"
@@ -30,6 +32,16 @@ func TestCertRenewal(t *testing.T) {
 	})
 }
 
+func TestRotateWorkloadCert(t *testing.T) {
+	t.Run("mTLS disabled", func(t *testing.T) {
+		fakeServer := &server{
+			renewMutex: &sync.Mutex{},
+		}
+
+		assert.Error(t, fakeServer.RotateWorkloadCert())
+	})
+}
+
 func TestGetMiddlewareOptions(t *testing.T) {
 	t.Run("should enable unary interceptor if tracing and metrics are enabled", func(t *testing.T) {
 		fakeServer := &server{
@@ -60,4 +72,36 @@ func TestGetMiddlewareOptions(t *testing.T) {
 
 		assert.Equal(t, 1, len(serverOption))
 	})
+
+	t.Run("should enable unary interceptor for the pipeline", func(t *testing.T) {
+		fakeServer := &server{
+			config:     ServerConfig{},
+			renewMutex: &sync.Mutex{},
+			logger:     logger.NewLogger("dapr.runtime.grpc.test"),
+			pipeline: Pipeline{
+				Interceptors: []grpc_go.UnaryServerInterceptor{
+					func(ctx context.Context, req interface{}, info *grpc_go.UnaryServerInfo, handler grpc_go.UnaryHandler) (interface{}, error) {
+						return handler(ctx, req)
+					},
+				},
+			},
+		}
+
+		serverOption := fakeServer.getMiddlewareOptions()
+
+		assert.Equal(t, 1, len(serverOption))
+	})
+}
+
+func TestCountInflightUnary(t *testing.T) {
+	fakeServer := &server{}
+	var inflight int32
+	_, err := fakeServer.countInflightUnary(context.Background(), nil, &grpc_go.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		inflight = fakeServer.InflightRequests()
+		return nil, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), inflight)
+	assert.Equal(t, int32(0), fakeServer.InflightRequests())
 }
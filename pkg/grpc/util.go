@@ -7,24 +7,12 @@ package grpc
 
 import commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
 
-func stateConsistencyToString(c commonv1pb.StateOptions_StateConsistency) string {
-	switch c {
-	case commonv1pb.StateOptions_CONSISTENCY_EVENTUAL:
-		return "eventual"
-	case commonv1pb.StateOptions_CONSISTENCY_STRONG:
-		return "strong"
+// stateItemsHaveETags returns true if any of the state items carries an ETag.
+func stateItemsHaveETags(items []*commonv1pb.StateItem) bool {
+	for _, item := range items {
+		if item.Etag != nil {
+			return true
+		}
 	}
-
-	return ""
-}
-
-func stateConcurrencyToString(c commonv1pb.StateOptions_StateConcurrency) string {
-	switch c {
-	case commonv1pb.StateOptions_CONCURRENCY_FIRST_WRITE:
-		return "first-write"
-	case commonv1pb.StateOptions_CONCURRENCY_LAST_WRITE:
-		return "last-write"
-	}
-
-	return ""
+	return false
 }
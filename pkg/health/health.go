@@ -6,9 +6,15 @@
 package health
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
@@ -17,6 +23,13 @@ const (
 	requestTimeout    = time.Second * 2
 	interval          = time.Second * 5
 	successStatusCode = 200
+
+	// ProbeModeHTTP probes the endpoint with a HTTP GET request.
+	ProbeModeHTTP = "http"
+	// ProbeModeGRPC probes the endpoint with the gRPC health checking protocol (grpc.health.v1.Health).
+	ProbeModeGRPC = "grpc"
+	// ProbeModeTCP probes the endpoint by opening a TCP connection.
+	ProbeModeTCP = "tcp"
 )
 
 // Option is an a function that applies a health check option
@@ -30,38 +43,111 @@ type healthCheckOptions struct {
 	successStatusCode int
 }
 
+// IsValidProbeMode returns true if mode is a supported probe mode.
+func IsValidProbeMode(mode string) bool {
+	switch mode {
+	case ProbeModeHTTP, ProbeModeGRPC, ProbeModeTCP:
+		return true
+	}
+	return false
+}
+
+// StartAppHealthCheck starts a health check of the app listening on baseAddress using the probe mode.
+// The http probe requests the /healthz endpoint of the app.
+func StartAppHealthCheck(probeMode, baseAddress string, opts ...Option) chan bool {
+	switch probeMode {
+	case ProbeModeGRPC:
+		return StartGRPCHealthCheck(hostAddress(baseAddress), opts...)
+	case ProbeModeTCP:
+		return StartTCPHealthCheck(hostAddress(baseAddress), opts...)
+	default:
+		return StartEndpointHealthCheck(fmt.Sprintf("%s/healthz", baseAddress), opts...)
+	}
+}
+
+// hostAddress returns the host:port part of the base address of an app channel.
+func hostAddress(baseAddress string) string {
+	if i := strings.Index(baseAddress, "://"); i >= 0 {
+		return baseAddress[i+3:]
+	}
+	return baseAddress
+}
+
 // StartEndpointHealthCheck starts a health check on the specified address with the given options.
 // It returns a channel that will emit true if the endpoint is healthy and false if the failure conditions
 // Have been met.
 func StartEndpointHealthCheck(endpointAddress string, opts ...Option) chan bool {
-	options := &healthCheckOptions{}
-	applyDefaults(options)
+	options := newOptions(opts)
 
-	for _, o := range opts {
-		o(options)
+	client := &fasthttp.Client{
+		MaxConnsPerHost:           5, // Limit Keep-Alive connections
+		ReadTimeout:               options.requestTimeout,
+		MaxIdemponentCallAttempts: 1,
+	}
+
+	req := fasthttp.AcquireRequest()
+	req.SetRequestURI(endpointAddress)
+	req.Header.SetMethod(fasthttp.MethodGet)
+
+	return startHealthCheck(options, func() bool {
+		resp := fasthttp.AcquireResponse()
+		defer fasthttp.ReleaseResponse(resp)
+
+		err := client.DoTimeout(req, resp, options.requestTimeout)
+		return err == nil && resp.StatusCode() == options.successStatusCode
+	})
+}
+
+// StartGRPCHealthCheck starts a health check of the gRPC server on the specified address (host:port)
+// using the gRPC health checking protocol. The server is healthy if it reports the SERVING status.
+func StartGRPCHealthCheck(address string, opts ...Option) chan bool {
+	options := newOptions(opts)
+
+	// Dial doesn't block, the connection is established by the first probe
+	// and re-established by grpc if the server restarts.
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return startHealthCheck(options, func() bool { return false })
 	}
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	return startHealthCheck(options, func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), options.requestTimeout)
+		defer cancel()
+
+		resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+		return err == nil && resp.GetStatus() == grpc_health_v1.HealthCheckResponse_SERVING
+	})
+}
+
+// StartTCPHealthCheck starts a health check on the specified address (host:port).
+// The endpoint is healthy if a TCP connection can be opened.
+func StartTCPHealthCheck(address string, opts ...Option) chan bool {
+	options := newOptions(opts)
+
+	return startHealthCheck(options, func() bool {
+		conn, err := net.DialTimeout("tcp", address, options.requestTimeout)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	})
+}
+
+// startHealthCheck runs probe every interval after the initial delay.
+// It returns a channel that will emit true when the probe succeeds and false
+// when the probe has failed failureThreshold times in a row.
+func startHealthCheck(options *healthCheckOptions, probe func() bool) chan bool {
 	signalChan := make(chan bool, 1)
 
-	go func(ch chan<- bool, endpointAddress string, options *healthCheckOptions) {
+	go func(ch chan<- bool, options *healthCheckOptions) {
 		ticker := time.NewTicker(options.interval)
 		failureCount := 0
 		time.Sleep(options.initialDelay)
 
-		client := &fasthttp.Client{
-			MaxConnsPerHost:           5, // Limit Keep-Alive connections
-			ReadTimeout:               options.requestTimeout,
-			MaxIdemponentCallAttempts: 1,
-		}
-
-		req := fasthttp.AcquireRequest()
-		req.SetRequestURI(endpointAddress)
-		req.Header.SetMethod(fasthttp.MethodGet)
-		defer fasthttp.ReleaseRequest(req)
-
 		for range ticker.C {
-			resp := fasthttp.AcquireResponse()
-			err := client.DoTimeout(req, resp, options.requestTimeout)
-			if err != nil || resp.StatusCode() != options.successStatusCode {
+			if !probe() {
 				failureCount++
 				if failureCount == options.failureThreshold {
 					failureCount--
@@ -71,12 +157,21 @@ func StartEndpointHealthCheck(endpointAddress string, opts ...Option) chan bool
 				ch <- true
 				failureCount = 0
 			}
-			fasthttp.ReleaseResponse(resp)
 		}
-	}(signalChan, endpointAddress, options)
+	}(signalChan, options)
 	return signalChan
 }
 
+func newOptions(opts []Option) *healthCheckOptions {
+	options := &healthCheckOptions{}
+	applyDefaults(options)
+
+	for _, o := range opts {
+		o(options)
+	}
+	return options
+}
+
 func applyDefaults(o *healthCheckOptions) {
 	o.failureThreshold = failureThreshold
 	o.initialDelay = initialDelay
@@ -6,12 +6,17 @@
 package health
 
 import (
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpc_health "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func TestHealthCheck(t *testing.T) {
@@ -165,3 +170,63 @@ func TestResponses(t *testing.T) {
 		}
 	})
 }
+
+func TestIsValidProbeMode(t *testing.T) {
+	assert.True(t, IsValidProbeMode(ProbeModeHTTP))
+	assert.True(t, IsValidProbeMode(ProbeModeGRPC))
+	assert.True(t, IsValidProbeMode(ProbeModeTCP))
+	assert.False(t, IsValidProbeMode("exec"))
+}
+
+func TestHostAddress(t *testing.T) {
+	assert.Equal(t, "127.0.0.1:3000", hostAddress("http://127.0.0.1:3000"))
+	assert.Equal(t, "127.0.0.1:3000", hostAddress("https://127.0.0.1:3000"))
+	assert.Equal(t, "127.0.0.1:3000", hostAddress("127.0.0.1:3000"))
+}
+
+func TestTCPHealthCheck(t *testing.T) {
+	t.Run("listening endpoint", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer lis.Close()
+
+		ch := StartTCPHealthCheck(lis.Addr().String(), WithInterval(time.Second*1), WithFailureThreshold(1))
+		assert.True(t, <-ch)
+	})
+
+	t.Run("closed endpoint", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		address := lis.Addr().String()
+		lis.Close()
+
+		ch := StartTCPHealthCheck(address, WithInterval(time.Second*1), WithFailureThreshold(1))
+		assert.False(t, <-ch)
+	})
+}
+
+func TestGRPCHealthCheck(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	healthServer := grpc_health.NewServer()
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	ch := StartGRPCHealthCheck(lis.Addr().String(), WithInterval(time.Second*1), WithFailureThreshold(1))
+
+	t.Run("serving", func(t *testing.T) {
+		assert.True(t, <-ch)
+	})
+
+	t.Run("not serving", func(t *testing.T) {
+		healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+		for healthy := range ch {
+			if !healthy {
+				return
+			}
+		}
+	})
+}
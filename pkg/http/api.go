@@ -12,6 +12,7 @@ import (
 	"sync"
 
 	"github.com/dapr/components-contrib/bindings"
+	contrib_contenttype "github.com/dapr/components-contrib/contenttype"
 	"github.com/dapr/components-contrib/pubsub"
 	"github.com/dapr/components-contrib/secretstores"
 	"github.com/dapr/components-contrib/state"
@@ -19,6 +20,8 @@ import (
 	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	"github.com/dapr/dapr/pkg/channel"
 	"github.com/dapr/dapr/pkg/channel/http"
+	"github.com/dapr/dapr/pkg/components"
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
 	state_loader "github.com/dapr/dapr/pkg/components/state"
 	"github.com/dapr/dapr/pkg/concurrency"
 	"github.com/dapr/dapr/pkg/config"
@@ -27,7 +30,10 @@ import (
 	"github.com/dapr/dapr/pkg/messages"
 	"github.com/dapr/dapr/pkg/messaging"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/fasthttp/router"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/mitchellh/mapstructure"
@@ -35,6 +41,7 @@ import (
 	"github.com/valyala/fasthttp"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // API returns a list of HTTP endpoints for Dapr
@@ -44,6 +51,10 @@ type API interface {
 	SetAppChannel(appChannel channel.AppChannel)
 	SetDirectMessaging(directMessaging messaging.DirectMessaging)
 	SetActorRuntime(actor actors.Actors)
+	SetWorkloadCertRotator(rotateFn func() error)
+	SetProfilingToggler(setProfilingEnabledFn func(enabled bool) error)
+	SetAppHealthChecker(appHealthFn func() bool)
+	StartTransactionOutboxRecovery(stopCh <-chan struct{})
 }
 
 type api struct {
@@ -51,6 +62,7 @@ type api struct {
 	directMessaging          messaging.DirectMessaging
 	appChannel               channel.AppChannel
 	getComponentsFn          func() []components_v1alpha1.Component
+	getComponentStatusFn     func() []components.Status
 	stateStores              map[string]state.Store
 	transactionalStateStores map[string]state.TransactionalStore
 	secretStores             map[string]secretstores.SecretStore
@@ -59,17 +71,37 @@ type api struct {
 	actor                    actors.Actors
 	pubsubAdapter            runtime_pubsub.Adapter
 	sendToOutputBindingFn    func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error)
+	setInputBindingPausedFn  func(name string, paused bool) error
+	refreshSubscriptionsFn   func() error
+	outboundPipeline         http_middleware.Pipeline
 	id                       string
 	extendedMetadata         sync.Map
 	readyStatus              bool
 	tracingSpec              config.TracingSpec
 	shutdown                 func()
+	rotateWorkloadCertFn     func() error
+	setProfilingEnabledFn    func(enabled bool) error
+	appHealthFn              func() bool
 }
 
 type registeredComponent struct {
-	Name    string `json:"name"`
-	Type    string `json:"type"`
-	Version string `json:"version"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Version      string `json:"version"`
+	Status       string `json:"status,omitempty"`
+	Error        string `json:"error,omitempty"`
+	InitDuration string `json:"initDuration,omitempty"`
+}
+
+type healthzSubsystem struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type detailedHealthz struct {
+	Status     string             `json:"status"`
+	Subsystems []healthzSubsystem `json:"subsystems"`
 }
 
 type metadata struct {
@@ -81,6 +113,7 @@ type metadata struct {
 
 const (
 	apiVersionV1         = "v1.0"
+	apiVersionV1alpha1   = "v1.0-alpha1"
 	idParam              = "id"
 	methodParam          = "method"
 	topicParam           = "topic"
@@ -92,10 +125,26 @@ const (
 	secretNameParam      = "key"
 	nameParam            = "name"
 	consistencyParam     = "consistency"
+	fieldsParam          = "fields"
 	concurrencyParam     = "concurrency"
 	pubsubnameparam      = "pubsubname"
+	detailedParam        = "detailed"
 	traceparentHeader    = "traceparent"
 	tracestateHeader     = "tracestate"
+
+	cloudEventsBatchContentType = "application/cloudevents-batch+json"
+
+	bindingEnvelopeHeader      = "dapr-binding-envelope"
+	bindingContentTypeMetadata = "contentType"
+	octetStreamContentType     = "application/octet-stream"
+
+	healthStatusReady    = "READY"
+	healthStatusNotReady = "NOT_READY"
+	healthStatusDisabled = "DISABLED"
+
+	broadcastScopeParam  = "scope"
+	broadcastScopeLocal  = "local"
+	broadcastScopeGlobal = "global"
 )
 
 // NewAPI returns a new API
@@ -104,12 +153,16 @@ func NewAPI(
 	appChannel channel.AppChannel,
 	directMessaging messaging.DirectMessaging,
 	getComponentsFn func() []components_v1alpha1.Component,
+	getComponentStatusFn func() []components.Status,
 	stateStores map[string]state.Store,
 	secretStores map[string]secretstores.SecretStore,
 	secretsConfiguration map[string]config.SecretsScope,
 	pubsubAdapter runtime_pubsub.Adapter,
 	actor actors.Actors,
 	sendToOutputBindingFn func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error),
+	setInputBindingPausedFn func(name string, paused bool) error,
+	refreshSubscriptionsFn func() error,
+	outboundPipeline http_middleware.Pipeline,
 	tracingSpec config.TracingSpec,
 	shutdown func()) API {
 	transactionalStateStores := map[string]state.TransactionalStore{}
@@ -121,6 +174,7 @@ func NewAPI(
 	api := &api{
 		appChannel:               appChannel,
 		getComponentsFn:          getComponentsFn,
+		getComponentStatusFn:     getComponentStatusFn,
 		directMessaging:          directMessaging,
 		stateStores:              stateStores,
 		transactionalStateStores: transactionalStateStores,
@@ -130,6 +184,9 @@ func NewAPI(
 		actor:                    actor,
 		pubsubAdapter:            pubsubAdapter,
 		sendToOutputBindingFn:    sendToOutputBindingFn,
+		setInputBindingPausedFn:  setInputBindingPausedFn,
+		refreshSubscriptionsFn:   refreshSubscriptionsFn,
+		outboundPipeline:         outboundPipeline,
 		id:                       appID,
 		tracingSpec:              tracingSpec,
 		shutdown:                 shutdown,
@@ -142,6 +199,9 @@ func NewAPI(
 	api.endpoints = append(api.endpoints, api.constructDirectMessagingEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructMetadataEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructShutdownEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructMTLSEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructProfilingEndpoints()...)
+	api.endpoints = append(api.endpoints, api.constructSubscriptionsEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructBindingsEndpoints()...)
 	api.endpoints = append(api.endpoints, api.constructHealthzEndpoints()...)
 
@@ -172,6 +232,12 @@ func (a *api) constructStateEndpoints() []Endpoint {
 			Version: apiVersionV1,
 			Handler: a.onPostState,
 		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "state/{storeName}/bulk/delete",
+			Version: apiVersionV1,
+			Handler: a.onBulkDeleteState,
+		},
 		{
 			Methods: []string{fasthttp.MethodDelete},
 			Route:   "state/{storeName}/{key}",
@@ -184,6 +250,12 @@ func (a *api) constructStateEndpoints() []Endpoint {
 			Version: apiVersionV1,
 			Handler: a.onBulkGetState,
 		},
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "state/{storeName}/bulk/set",
+			Version: apiVersionV1,
+			Handler: a.onBulkSetState,
+		},
 		{
 			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
 			Route:   "state/{storeName}/transaction",
@@ -207,6 +279,12 @@ func (a *api) constructSecretEndpoints() []Endpoint {
 			Version: apiVersionV1,
 			Handler: a.onGetSecret,
 		},
+		{
+			Methods: []string{fasthttp.MethodDelete},
+			Route:   "secrets/{secretStoreName}/cache",
+			Version: apiVersionV1,
+			Handler: adminOnly(a.onInvalidateSecretCache),
+		},
 	}
 }
 
@@ -229,16 +307,33 @@ func (a *api) constructBindingsEndpoints() []Endpoint {
 			Version: apiVersionV1,
 			Handler: a.onOutputBindingMessage,
 		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "bindings/{name}/pause",
+			Version: apiVersionV1alpha1,
+			Handler: adminOnly(a.onPauseInputBinding),
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "bindings/{name}/resume",
+			Version: apiVersionV1alpha1,
+			Handler: adminOnly(a.onResumeInputBinding),
+		},
 	}
 }
 
 func (a *api) constructDirectMessagingEndpoints() []Endpoint {
+	handler := a.onDirectMessage
+	if len(a.outboundPipeline.Handlers) > 0 {
+		handler = a.outboundPipeline.Apply(a.onOutboundDirectMessage)
+	}
+
 	return []Endpoint{
 		{
 			Methods: []string{router.MethodWild},
 			Route:   "invoke/{id}/method/{method:*}",
 			Version: apiVersionV1,
-			Handler: a.onDirectMessage,
+			Handler: handler,
 		},
 	}
 }
@@ -293,6 +388,25 @@ func (a *api) constructActorEndpoints() []Endpoint {
 			Version: apiVersionV1,
 			Handler: a.onGetActorReminder,
 		},
+		{
+			// Broadcasts have their own prefix, so that they can't be mistaken for a call to an actor with ID broadcast.
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodPut},
+			Route:   "actors-broadcast/{actorType}/{method}",
+			Version: apiVersionV1,
+			Handler: a.onActorBroadcast,
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "actors/drain",
+			Version: apiVersionV1,
+			Handler: adminOnly(a.onDrainActors),
+		},
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "actors/undrain",
+			Version: apiVersionV1,
+			Handler: adminOnly(a.onUndrainActors),
+		},
 	}
 }
 
@@ -324,6 +438,39 @@ func (a *api) constructShutdownEndpoints() []Endpoint {
 	}
 }
 
+func (a *api) constructSubscriptionsEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "subscriptions/refresh",
+			Version: apiVersionV1,
+			Handler: adminOnly(a.onRefreshSubscriptions),
+		},
+	}
+}
+
+func (a *api) constructMTLSEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Route:   "mtls/rotate",
+			Version: apiVersionV1,
+			Handler: adminOnly(a.onRotateWorkloadCert),
+		},
+	}
+}
+
+func (a *api) constructProfilingEndpoints() []Endpoint {
+	return []Endpoint{
+		{
+			Methods: []string{fasthttp.MethodPut},
+			Route:   "profiling",
+			Version: apiVersionV1,
+			Handler: adminOnly(a.onPutProfiling),
+		},
+	}
+}
+
 func (a *api) constructHealthzEndpoints() []Endpoint {
 	return []Endpoint{
 		{
@@ -335,6 +482,80 @@ func (a *api) constructHealthzEndpoints() []Endpoint {
 	}
 }
 
+// adminOnly makes an endpoint an admin operation. Admin operations are only available when the Dapr APIs
+// are protected by an api token, so that only the holders of the token can use them.
+func adminOnly(handler fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(reqCtx *fasthttp.RequestCtx) {
+		if !auth.APITokenConfigured() {
+			msg := NewErrorResponse("ERR_API_TOKEN_REQUIRED", messages.ErrAPITokenRequired)
+			respondWithError(reqCtx, fasthttp.StatusForbidden, msg)
+			log.Debug(msg)
+			return
+		}
+		handler(reqCtx)
+	}
+}
+
+// onRotateWorkloadCert forces the renewal of the workload cert. It is an admin operation, with no gRPC
+// API equivalent.
+func (a *api) onRotateWorkloadCert(reqCtx *fasthttp.RequestCtx) {
+	if a.rotateWorkloadCertFn == nil {
+		msg := NewErrorResponse("ERR_MTLS_NOT_ENABLED", messages.ErrMTLSNotEnabled)
+		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+		log.Debug(msg)
+		return
+	}
+
+	if err := a.rotateWorkloadCertFn(); err != nil {
+		msg := NewErrorResponse("ERR_MTLS_ROTATE_CERT", fmt.Sprintf(messages.ErrRotateWorkloadCert, err))
+		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+		log.Debug(msg)
+		return
+	}
+	respondEmpty(reqCtx)
+}
+
+// onRefreshSubscriptions reloads the subscriptions of the app. It is an admin operation.
+func (a *api) onRefreshSubscriptions(reqCtx *fasthttp.RequestCtx) {
+	if a.refreshSubscriptionsFn == nil {
+		msg := NewErrorResponse("ERR_PUBSUB_NOT_CONFIGURED", messages.ErrPubsubNotConfigured)
+		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+		log.Debug(msg)
+		return
+	}
+
+	if err := a.refreshSubscriptionsFn(); err != nil {
+		msg := NewErrorResponse("ERR_REFRESH_SUBSCRIPTIONS", fmt.Sprintf(messages.ErrRefreshSubscriptions, err))
+		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+		log.Debug(msg)
+		return
+	}
+	respondEmpty(reqCtx)
+}
+
+// onPauseInputBinding stops the delivery of the events of an input binding. It is an admin operation.
+func (a *api) onPauseInputBinding(reqCtx *fasthttp.RequestCtx) {
+	a.setInputBindingPaused(reqCtx, true)
+}
+
+// onResumeInputBinding resumes the delivery of the events of a paused input binding. It is an admin operation.
+func (a *api) onResumeInputBinding(reqCtx *fasthttp.RequestCtx) {
+	a.setInputBindingPaused(reqCtx, false)
+}
+
+func (a *api) setInputBindingPaused(reqCtx *fasthttp.RequestCtx, paused bool) {
+	name := reqCtx.UserValue(nameParam).(string)
+
+	if a.setInputBindingPausedFn == nil || a.setInputBindingPausedFn(name, paused) != nil {
+		msg := NewErrorResponse("ERR_INPUT_BINDING_NOT_FOUND", fmt.Sprintf(messages.ErrInputBindingNotFound, name))
+		respondWithError(reqCtx, fasthttp.StatusNotFound, msg)
+		log.Debug(msg)
+		return
+	}
+
+	respondEmpty(reqCtx)
+}
+
 func (a *api) onOutputBindingMessage(reqCtx *fasthttp.RequestCtx) {
 	name := reqCtx.UserValue(nameParam).(string)
 	body := reqCtx.PostBody()
@@ -356,20 +577,8 @@ func (a *api) onOutputBindingMessage(reqCtx *fasthttp.RequestCtx) {
 		return
 	}
 
-	// pass the trace context to output binding in metadata
-	if span := diag_utils.SpanFromContext(reqCtx); span != nil {
-		sc := span.SpanContext()
-		if req.Metadata == nil {
-			req.Metadata = map[string]string{}
-		}
-		req.Metadata[traceparentHeader] = diag.SpanContextToW3CString(sc)
-		if sc.Tracestate != nil {
-			req.Metadata[tracestateHeader] = diag.TraceStateToW3CString(sc)
-		}
-	}
-
 	resp, err := a.sendToOutputBindingFn(name, &bindings.InvokeRequest{
-		Metadata:  req.Metadata,
+		Metadata:  withTraceMetadata(reqCtx, req.Metadata),
 		Data:      b,
 		Operation: bindings.OperationKind(req.Operation),
 	})
@@ -379,13 +588,52 @@ func (a *api) onOutputBindingMessage(reqCtx *fasthttp.RequestCtx) {
 		log.Debug(msg)
 		return
 	}
-	if resp == nil {
+	if string(reqCtx.Request.Header.Peek(bindingEnvelopeHeader)) == "true" {
+		envelope := OutputBindingResponseEnvelope{}
+		if resp != nil {
+			envelope.Data = resp.Data
+			envelope.ContentType = bindingResponseContentType(resp)
+			envelope.Metadata = resp.Metadata
+		}
+		b, _ := a.json.Marshal(&envelope)
+		respondWithJSON(reqCtx, fasthttp.StatusOK, b)
+	} else if resp == nil {
 		respondEmpty(reqCtx)
 	} else {
 		respondWithJSON(reqCtx, fasthttp.StatusOK, resp.Data)
 	}
 }
 
+// withTraceMetadata adds the trace context of the request to the output binding metadata.
+func withTraceMetadata(reqCtx *fasthttp.RequestCtx, metadata map[string]string) map[string]string {
+	if span := diag_utils.SpanFromContext(reqCtx); span != nil {
+		sc := span.SpanContext()
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+		metadata[traceparentHeader] = diag.SpanContextToW3CString(sc)
+		if sc.Tracestate != nil {
+			metadata[tracestateHeader] = diag.TraceStateToW3CString(sc)
+		}
+	}
+	return metadata
+}
+
+// bindingResponseContentType returns the content type reported by the binding in the response
+// metadata, or guesses it from the data.
+func bindingResponseContentType(resp *bindings.InvokeResponse) string {
+	if contentType := resp.Metadata[bindingContentTypeMetadata]; contentType != "" {
+		return contentType
+	}
+	if len(resp.Data) == 0 {
+		return ""
+	}
+	if jsoniter.Valid(resp.Data) {
+		return jsonContentTypeHeader
+	}
+	return octetStreamContentType
+}
+
 func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 	store, storeName, err := a.getStateStoreWithRequestValidation(reqCtx)
 	if err != nil {
@@ -394,7 +642,14 @@ func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 	}
 
 	var req BulkGetRequest
-	err = a.json.Unmarshal(reqCtx.PostBody(), &req)
+	if isProtobufRequest(reqCtx) {
+		var in runtimev1pb.GetBulkStateRequest
+		if err = proto.Unmarshal(reqCtx.PostBody(), &in); err == nil {
+			req = BulkGetRequest{Metadata: in.Metadata, Keys: in.Keys, Parallelism: int(in.Parallelism)}
+		}
+	} else {
+		err = a.json.Unmarshal(reqCtx.PostBody(), &req)
+	}
 	if err != nil {
 		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
 		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
@@ -406,8 +661,7 @@ func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 
 	bulkResp := make([]BulkGetResponse, len(req.Keys))
 	if len(req.Keys) == 0 {
-		b, _ := a.json.Marshal(bulkResp)
-		respondWithJSON(reqCtx, fasthttp.StatusOK, b)
+		a.respondWithBulkGetResponse(reqCtx, bulkResp)
 		return
 	}
 
@@ -444,7 +698,7 @@ func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 				log.Debugf("bulk get: error getting key %s: %s", bulkResp[i].Key, responses[i].Error)
 				bulkResp[i].Error = responses[i].Error
 			} else {
-				bulkResp[i].Data = jsoniter.RawMessage(responses[i].Data)
+				bulkResp[i].Data = jsoniter.RawMessage(projectJSONFields(responses[i].Data, req.Fields))
 				bulkResp[i].ETag = responses[i].ETag
 			}
 		}
@@ -473,7 +727,7 @@ func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 					log.Debugf("bulk get: error getting key %s: %s", r.Key, err)
 					r.Error = err.Error()
 				} else if resp != nil {
-					r.Data = jsoniter.RawMessage(resp.Data)
+					r.Data = jsoniter.RawMessage(projectJSONFields(resp.Data, req.Fields))
 					r.ETag = resp.ETag
 				}
 			}
@@ -483,6 +737,135 @@ func (a *api) onBulkGetState(reqCtx *fasthttp.RequestCtx) {
 		limiter.Wait()
 	}
 
+	a.respondWithBulkGetResponse(reqCtx, bulkResp)
+}
+
+// onBulkSetState saves the given keys with the native BulkSet of the store. If it fails, the keys are
+// saved one by one so that the error of each key is reported. Keys with ETags are always saved one by one,
+// so that an ETag mismatch fails only its key and the others aren't saved twice.
+func (a *api) onBulkSetState(reqCtx *fasthttp.RequestCtx) {
+	store, storeName, err := a.getStateStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	reqs := []state.SetRequest{}
+	err = a.json.Unmarshal(reqCtx.PostBody(), &reqs)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+		log.Debug(msg)
+		return
+	}
+
+	bulkResp := make([]BulkStateOperationResponse, len(reqs))
+	for i, r := range reqs {
+		bulkResp[i].Key = r.Key
+		reqs[i].Key, err = state_loader.GetModifiedStateKey(r.Key, storeName, a.id)
+		if err != nil {
+			msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+			respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+			log.Debug(err)
+			return
+		}
+	}
+
+	setEach := func(i int) error {
+		return store.Set(&reqs[i])
+	}
+	hasETags := false
+	for _, r := range reqs {
+		hasETags = hasETags || r.ETag != nil
+	}
+	if hasETags {
+		executeBulkStateOperation(bulkResp, setEach)
+	} else if len(reqs) > 0 {
+		if err = store.BulkSet(reqs); err != nil {
+			log.Debugf("bulk set: falling back to saving the keys one by one: %s", err)
+			executeBulkStateOperation(bulkResp, setEach)
+		}
+	}
+
+	b, _ := a.json.Marshal(bulkResp)
+	respondWithJSON(reqCtx, fasthttp.StatusOK, b)
+}
+
+// onBulkDeleteState deletes the given keys with the native BulkDelete of the store. If it fails, the keys are
+// deleted one by one so that the error of each key is reported. Like for onBulkSetState, keys with ETags are
+// always deleted one by one.
+func (a *api) onBulkDeleteState(reqCtx *fasthttp.RequestCtx) {
+	store, storeName, err := a.getStateStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	reqs := []state.DeleteRequest{}
+	err = a.json.Unmarshal(reqCtx.PostBody(), &reqs)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+		log.Debug(msg)
+		return
+	}
+
+	bulkResp := make([]BulkStateOperationResponse, len(reqs))
+	for i, r := range reqs {
+		bulkResp[i].Key = r.Key
+		reqs[i].Key, err = state_loader.GetModifiedStateKey(r.Key, storeName, a.id)
+		if err != nil {
+			msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+			respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+			log.Debug(err)
+			return
+		}
+	}
+
+	deleteEach := func(i int) error {
+		return store.Delete(&reqs[i])
+	}
+	hasETags := false
+	for _, r := range reqs {
+		hasETags = hasETags || r.ETag != nil
+	}
+	if hasETags {
+		executeBulkStateOperation(bulkResp, deleteEach)
+	} else if len(reqs) > 0 {
+		if err = store.BulkDelete(reqs); err != nil {
+			log.Debugf("bulk delete: falling back to deleting the keys one by one: %s", err)
+			executeBulkStateOperation(bulkResp, deleteEach)
+		}
+	}
+
+	b, _ := a.json.Marshal(bulkResp)
+	respondWithJSON(reqCtx, fasthttp.StatusOK, b)
+}
+
+// executeBulkStateOperation runs op concurrently for each item of a bulk operation and records its error.
+func executeBulkStateOperation(bulkResp []BulkStateOperationResponse, op func(i int) error) {
+	limiter := concurrency.NewLimiter(concurrency.DefaultLimit)
+	for i := range bulkResp {
+		fn := func(param interface{}) {
+			i := param.(int)
+			if err := op(i); err != nil {
+				log.Debugf("bulk operation: error on key %s: %s", bulkResp[i].Key, err)
+				bulkResp[i].Error = err.Error()
+				if e, ok := err.(*state.ETagError); ok && e.Kind() == state.ETagMismatch {
+					bulkResp[i].Conflict = true
+				}
+			}
+		}
+		limiter.Execute(fn, i)
+	}
+	limiter.Wait()
+}
+
+func (a *api) respondWithBulkGetResponse(reqCtx *fasthttp.RequestCtx, bulkResp []BulkGetResponse) {
+	if wantsProtobuf(reqCtx) {
+		respondWithProto(reqCtx, fasthttp.StatusOK, bulkGetResponseToProto(bulkResp))
+		return
+	}
 	b, _ := a.json.Marshal(bulkResp)
 	respondWithJSON(reqCtx, fasthttp.StatusOK, b)
 }
@@ -544,7 +927,16 @@ func (a *api) onGetState(reqCtx *fasthttp.RequestCtx) {
 		respondEmpty(reqCtx)
 		return
 	}
-	respondWithETaggedJSON(reqCtx, fasthttp.StatusOK, resp.Data, resp.ETag)
+	fields := getFieldsFromRequest(string(reqCtx.QueryArgs().Peek(fieldsParam)))
+	if wantsProtobuf(reqCtx) {
+		respondWithProto(reqCtx, fasthttp.StatusOK, &runtimev1pb.GetStateResponse{
+			Data:     projectJSONFields(resp.Data, fields),
+			Etag:     stringValueOrEmpty(resp.ETag),
+			Metadata: resp.Metadata,
+		})
+		return
+	}
+	respondWithETaggedJSON(reqCtx, fasthttp.StatusOK, projectJSONFields(resp.Data, fields), resp.ETag)
 }
 
 func extractEtag(reqCtx *fasthttp.RequestCtx) (bool, string) {
@@ -687,6 +1079,26 @@ func (a *api) onBulkGetSecret(reqCtx *fasthttp.RequestCtx) {
 	respondWithJSON(reqCtx, fasthttp.StatusOK, respBytes)
 }
 
+// onInvalidateSecretCache clears the cached secrets of a secret store. It is an admin operation.
+func (a *api) onInvalidateSecretCache(reqCtx *fasthttp.RequestCtx) {
+	store, secretStoreName, err := a.getSecretStoreWithRequestValidation(reqCtx)
+	if err != nil {
+		log.Debug(err)
+		return
+	}
+
+	cache, ok := store.(secretstores_loader.CacheInvalidator)
+	if !ok {
+		msg := NewErrorResponse("ERR_SECRET_CACHE_NOT_ENABLED", fmt.Sprintf(messages.ErrSecretCacheNotEnabled, secretStoreName))
+		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+		log.Debug(msg)
+		return
+	}
+
+	cache.InvalidateCache()
+	respondEmpty(reqCtx)
+}
+
 func (a *api) getSecretStoreWithRequestValidation(reqCtx *fasthttp.RequestCtx) (secretstores.SecretStore, string, error) {
 	if a.secretStores == nil || len(a.secretStores) == 0 {
 		msg := NewErrorResponse("ERR_SECRET_STORES_NOT_CONFIGURED", messages.ErrSecretStoreNotConfigured)
@@ -712,7 +1124,14 @@ func (a *api) onPostState(reqCtx *fasthttp.RequestCtx) {
 	}
 
 	reqs := []state.SetRequest{}
-	err = a.json.Unmarshal(reqCtx.PostBody(), &reqs)
+	if isProtobufRequest(reqCtx) {
+		var in runtimev1pb.SaveStateRequest
+		if err = proto.Unmarshal(reqCtx.PostBody(), &in); err == nil {
+			reqs = stateSetRequestsFromProto(in.States)
+		}
+	} else {
+		err = a.json.Unmarshal(reqCtx.PostBody(), &reqs)
+	}
 	if err != nil {
 		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", err.Error())
 		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
@@ -803,6 +1222,7 @@ func (a *api) onDirectMessage(reqCtx *fasthttp.RequestCtx) {
 	req := invokev1.NewInvokeMethodRequest(invokeMethodName).WithHTTPExtension(verb, reqCtx.QueryArgs().String())
 	req.WithRawData(reqCtx.Request.Body(), string(reqCtx.Request.Header.ContentType()))
 	// Save headers to internal metadata
+	a.limitBaggageHeader(reqCtx)
 	req.WithFastHTTPHeaders(&reqCtx.Request.Header)
 
 	resp, err := a.directMessaging.Invoke(reqCtx, targetID, req)
@@ -835,7 +1255,23 @@ func (a *api) onDirectMessage(reqCtx *fasthttp.RequestCtx) {
 			}
 		}
 	}
-	respond(reqCtx, statusCode, body)
+	// The body is owned by resp, so it is written without another copy.
+	respondWithRawBody(reqCtx, statusCode, body)
+}
+
+// onOutboundDirectMessage invokes the target of a request that went through the outbound pipeline,
+// whose middlewares may have rewritten the target app or method in the path.
+func (a *api) onOutboundDirectMessage(reqCtx *fasthttp.RequestCtx) {
+	prefix := fmt.Sprintf("/%s/invoke/", apiVersionV1)
+	path := string(reqCtx.Path())
+	if strings.HasPrefix(path, prefix) {
+		target := strings.SplitN(strings.TrimPrefix(path, prefix), "/method/", 2)
+		if len(target) == 2 && target[0] != "" && target[1] != "" {
+			reqCtx.SetUserValue(idParam, target[0])
+			reqCtx.SetUserValue(methodParam, target[1])
+		}
+	}
+	a.onDirectMessage(reqCtx)
 }
 
 func (a *api) onCreateActorReminder(reqCtx *fasthttp.RequestCtx) {
@@ -872,6 +1308,42 @@ func (a *api) onCreateActorReminder(reqCtx *fasthttp.RequestCtx) {
 	}
 }
 
+// onDrainActors moves the actors of the runtime to other hosts. It is an admin operation.
+func (a *api) onDrainActors(reqCtx *fasthttp.RequestCtx) {
+	if a.actor == nil {
+		msg := NewErrorResponse("ERR_ACTOR_RUNTIME_NOT_FOUND", messages.ErrActorRuntimeNotFound)
+		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+		log.Debug(msg)
+		return
+	}
+
+	a.actor.Drain()
+
+	drainStatus := a.actor.GetDrainStatus(reqCtx)
+	b, _ := a.json.Marshal(drainStatus)
+	if drainStatus.Completed {
+		respondWithJSON(reqCtx, fasthttp.StatusOK, b)
+	} else {
+		respondWithJSON(reqCtx, fasthttp.StatusAccepted, b)
+	}
+}
+
+// onUndrainActors stops draining the actors of the runtime, so that it hosts actors again. It is an
+// admin operation.
+func (a *api) onUndrainActors(reqCtx *fasthttp.RequestCtx) {
+	if a.actor == nil {
+		msg := NewErrorResponse("ERR_ACTOR_RUNTIME_NOT_FOUND", messages.ErrActorRuntimeNotFound)
+		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+		log.Debug(msg)
+		return
+	}
+
+	a.actor.Undrain()
+
+	b, _ := a.json.Marshal(a.actor.GetDrainStatus(reqCtx))
+	respondWithJSON(reqCtx, fasthttp.StatusOK, b)
+}
+
 func (a *api) onCreateActorTimer(reqCtx *fasthttp.RequestCtx) {
 	if a.actor == nil {
 		msg := NewErrorResponse("ERR_ACTOR_RUNTIME_NOT_FOUND", messages.ErrActorRuntimeNotFound)
@@ -1065,6 +1537,7 @@ func (a *api) onDirectActorMessage(reqCtx *fasthttp.RequestCtx) {
 	req.WithRawData(body, string(reqCtx.Request.Header.ContentType()))
 
 	// Save headers to metadata
+	a.limitBaggageHeader(reqCtx)
 	metadata := map[string][]string{}
 	reqCtx.Request.Header.VisitAll(func(key []byte, value []byte) {
 		metadata[string(key)] = []string{string(value)}
@@ -1091,6 +1564,47 @@ func (a *api) onDirectActorMessage(reqCtx *fasthttp.RequestCtx) {
 	respond(reqCtx, statusCode, body)
 }
 
+func (a *api) onActorBroadcast(reqCtx *fasthttp.RequestCtx) {
+	if a.actor == nil {
+		msg := NewErrorResponse("ERR_ACTOR_RUNTIME_NOT_FOUND", messages.ErrActorRuntimeNotFound)
+		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+		log.Debug(msg)
+		return
+	}
+
+	scope := string(reqCtx.QueryArgs().Peek(broadcastScopeParam))
+	if scope != "" && scope != broadcastScopeLocal && scope != broadcastScopeGlobal {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrActorBroadcastScope, scope))
+		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+		log.Debug(msg)
+		return
+	}
+
+	a.limitBaggageHeader(reqCtx)
+	metadata := map[string][]string{}
+	reqCtx.Request.Header.VisitAll(func(key []byte, value []byte) {
+		metadata[string(key)] = []string{string(value)}
+	})
+
+	resp, err := a.actor.Broadcast(reqCtx, &actors.BroadcastRequest{
+		ActorType:   reqCtx.UserValue(actorTypeParam).(string),
+		Method:      reqCtx.UserValue(methodParam).(string),
+		Data:        reqCtx.PostBody(),
+		ContentType: string(reqCtx.Request.Header.ContentType()),
+		Metadata:    metadata,
+		Global:      scope == broadcastScopeGlobal,
+	})
+	if err != nil {
+		msg := NewErrorResponse("ERR_ACTOR_BROADCAST", fmt.Sprintf(messages.ErrActorBroadcast, err))
+		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+		log.Debug(msg)
+		return
+	}
+
+	b, _ := a.json.Marshal(resp)
+	respondWithJSON(reqCtx, fasthttp.StatusOK, b)
+}
+
 func (a *api) onGetActorState(reqCtx *fasthttp.RequestCtx) {
 	if a.actor == nil {
 		msg := NewErrorResponse("ERR_ACTOR_RUNTIME_NOT_FOUND", messages.ErrActorRuntimeNotFound)
@@ -1149,18 +1663,40 @@ func (a *api) onGetMetadata(reqCtx *fasthttp.RequestCtx) {
 		activeActorsCount = a.actor.GetActiveActorsCount(reqCtx)
 	}
 
-	components := a.getComponentsFn()
-	registeredComponents := make([]registeredComponent, 0, len(components))
+	comps := a.getComponentsFn()
+	registeredComponents := make([]registeredComponent, 0, len(comps))
+	registeredIndex := make(map[string]int, len(comps))
 
-	for _, comp := range components {
+	for _, comp := range comps {
 		registeredComp := registeredComponent{
 			Name:    comp.Name,
 			Version: comp.Spec.Version,
 			Type:    comp.Spec.Type,
 		}
+		registeredIndex[comp.Spec.Type+"/"+comp.Name] = len(registeredComponents)
 		registeredComponents = append(registeredComponents, registeredComp)
 	}
 
+	// Components that failed or are still initializing are not registered, list them with their status.
+	if a.getComponentStatusFn != nil {
+		for _, s := range a.getComponentStatusFn() {
+			i, ok := registeredIndex[s.Type+"/"+s.Name]
+			if !ok {
+				i = len(registeredComponents)
+				registeredComponents = append(registeredComponents, registeredComponent{
+					Name:    s.Name,
+					Version: s.Version,
+					Type:    s.Type,
+				})
+			}
+			registeredComponents[i].Status = string(s.InitStatus)
+			registeredComponents[i].Error = s.Error
+			if s.InitDuration > 0 {
+				registeredComponents[i].InitDuration = s.InitDuration.String()
+			}
+		}
+	}
+
 	mtd := metadata{
 		ID:                   a.id,
 		ActiveActorsCount:    activeActorsCount,
@@ -1185,6 +1721,33 @@ func (a *api) onPutMetadata(reqCtx *fasthttp.RequestCtx) {
 	respondEmpty(reqCtx)
 }
 
+// onPutProfiling starts or stops the profiling server, as set by the "true" or "false" body. It is an
+// admin operation.
+func (a *api) onPutProfiling(reqCtx *fasthttp.RequestCtx) {
+
+	enabled, err := strconv.ParseBool(strings.TrimSpace(string(reqCtx.PostBody())))
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+		log.Debug(msg)
+		return
+	}
+	if a.setProfilingEnabledFn == nil {
+		msg := NewErrorResponse("ERR_PROFILING_NOT_AVAILABLE", messages.ErrProfilingNotAvailable)
+		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+		log.Debug(msg)
+		return
+	}
+
+	if err := a.setProfilingEnabledFn(enabled); err != nil {
+		msg := NewErrorResponse("ERR_PROFILING_TOGGLE", fmt.Sprintf(messages.ErrProfilingToggle, err))
+		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+		log.Debug(msg)
+		return
+	}
+	respondEmpty(reqCtx)
+}
+
 func (a *api) onShutdown(reqCtx *fasthttp.RequestCtx) {
 	if !reqCtx.IsPost() {
 		log.Warn("Please use POST method when invoking shutdown API")
@@ -1237,12 +1800,18 @@ func (a *api) onPublish(reqCtx *fasthttp.RequestCtx) {
 	// Populate W3C traceparent to cloudevent envelope
 	corID := diag.SpanContextToW3CString(span.SpanContext())
 
+	if contentType == cloudEventsBatchContentType {
+		a.onPublishBatch(reqCtx, thepubsub, pubsubName, topic, body, metadata, corID)
+		return
+	}
+
 	envelope, err := runtime_pubsub.NewCloudEvent(&runtime_pubsub.CloudEvent{
 		ID:              a.id,
 		Topic:           topic,
 		DataContentType: contentType,
 		Data:            body,
 		TraceID:         corID,
+		Baggage:         diag.LimitBaggage(string(reqCtx.Request.Header.Peek(diag.BaggageHeader)), a.tracingSpec),
 		Pubsub:          pubsubName,
 	})
 	if err != nil {
@@ -1295,6 +1864,148 @@ func (a *api) onPublish(reqCtx *fasthttp.RequestCtx) {
 	}
 }
 
+const (
+	bulkPublishEntrySuccess = "SUCCESS"
+	bulkPublishEntryFailed  = "FAILED"
+)
+
+// BulkPublishResponse is the response of a batch publish, with the result of every entry of the batch.
+type BulkPublishResponse struct {
+	Entries   []BulkPublishResponseEntry `json:"entries"`
+	ErrorCode string                     `json:"errorCode,omitempty"`
+}
+
+// BulkPublishResponseEntry is the result of publishing an entry of a batch.
+type BulkPublishResponseEntry struct {
+	EntryID string `json:"entryId"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// onPublishBatch splits a CloudEvents JSON batch and publishes every event to the topic.
+// Events are published independently and the result of each is reported in a BulkPublishResponse:
+// 204 if all the events were published, 207 if some failed and 500 if all failed. The batch is rejected
+// as a whole only when the topic is forbidden or the pubsub is not found before any event was published.
+func (a *api) onPublishBatch(reqCtx *fasthttp.RequestCtx, thepubsub pubsub.PubSub, pubsubName, topic string, body []byte, metadata map[string]string, corID string) {
+	var entries []jsoniter.RawMessage
+	if err := a.json.Unmarshal(body, &entries); err != nil || len(entries) == 0 {
+		if err == nil {
+			err = errors.New("batch is empty")
+		}
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err))
+		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+		log.Debug(msg)
+		return
+	}
+
+	features := thepubsub.Features()
+	baggage := diag.LimitBaggage(string(reqCtx.Request.Header.Peek(diag.BaggageHeader)), a.tracingSpec)
+	res := BulkPublishResponse{Entries: make([]BulkPublishResponseEntry, 0, len(entries))}
+	published, failed := 0, 0
+	fail := func(entryID, err string) {
+		res.Entries = append(res.Entries, BulkPublishResponseEntry{
+			EntryID: entryID,
+			Status:  bulkPublishEntryFailed,
+			Error:   err,
+		})
+		failed++
+	}
+	for i, entry := range entries {
+		entryID, err := a.validateBatchEntry(entry)
+		if entryID == "" {
+			entryID = strconv.Itoa(i)
+		}
+		if err != nil {
+			fail(entryID, fmt.Sprintf(messages.ErrPubsubBatchEntryInvalid, err))
+			continue
+		}
+
+		envelope, err := runtime_pubsub.NewCloudEvent(&runtime_pubsub.CloudEvent{
+			ID:              a.id,
+			Topic:           topic,
+			DataContentType: contrib_contenttype.CloudEventContentType,
+			Data:            entry,
+			TraceID:         corID,
+			Baggage:         baggage,
+			Pubsub:          pubsubName,
+		})
+		if err != nil {
+			fail(entryID, fmt.Sprintf(messages.ErrPubsubCloudEventCreation, err.Error()))
+			continue
+		}
+
+		pubsub.ApplyMetadata(envelope, features, metadata)
+		b, err := a.json.Marshal(envelope)
+		if err != nil {
+			fail(entryID, fmt.Sprintf(messages.ErrPubsubCloudEventsSer, topic, pubsubName, err.Error()))
+			continue
+		}
+
+		err = a.pubsubAdapter.Publish(&pubsub.PublishRequest{
+			PubsubName: pubsubName,
+			Topic:      topic,
+			Data:       b,
+			Metadata:   metadata,
+		})
+		if err != nil {
+			// Access and lookup errors apply to the whole batch, and reject it as long as no entry
+			// was published. Once some were, they are reported per entry like any other error.
+			if published == 0 {
+				if errors.As(err, &runtime_pubsub.NotAllowedError{}) {
+					msg := NewErrorResponse("ERR_PUBSUB_FORBIDDEN", err.Error())
+					respondWithError(reqCtx, fasthttp.StatusForbidden, msg)
+					log.Debug(msg)
+					return
+				}
+				if errors.As(err, &runtime_pubsub.NotFoundError{}) {
+					msg := NewErrorResponse("ERR_PUBSUB_NOT_FOUND", err.Error())
+					respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+					log.Debug(msg)
+					return
+				}
+			}
+			fail(entryID, fmt.Sprintf(messages.ErrPubsubPublishMessage, topic, pubsubName, err.Error()))
+			continue
+		}
+		published++
+		res.Entries = append(res.Entries, BulkPublishResponseEntry{
+			EntryID: entryID,
+			Status:  bulkPublishEntrySuccess,
+		})
+	}
+
+	if failed == 0 {
+		respondEmpty(reqCtx)
+		return
+	}
+
+	status := fasthttp.StatusMultiStatus
+	if failed == len(entries) {
+		status = fasthttp.StatusInternalServerError
+	}
+	res.ErrorCode = "ERR_PUBSUB_PUBLISH_MESSAGE"
+	b, _ := a.json.Marshal(&res)
+	respondWithJSON(reqCtx, status, b)
+	log.Debugf("failed to publish %d of %d events in batch to topic %s in pubsub %s", failed, len(entries), topic, pubsubName)
+}
+
+// validateBatchEntry checks that an entry of a CloudEvents batch is a structured event with the required attributes.
+// It returns the event id when one is set.
+func (a *api) validateBatchEntry(entry []byte) (string, error) {
+	var event map[string]interface{}
+	if err := a.json.Unmarshal(entry, &event); err != nil {
+		return "", err
+	}
+
+	id, _ := event[pubsub.IDField].(string)
+	for _, attr := range []string{pubsub.IDField, pubsub.SourceField, pubsub.TypeField, pubsub.SpecVersionField} {
+		if v, _ := event[attr].(string); v == "" {
+			return id, errors.Errorf("required attribute %s is missing", attr)
+		}
+	}
+	return id, nil
+}
+
 // GetStatusCodeFromMetadata extracts the http status code from the metadata if it exists
 func GetStatusCodeFromMetadata(metadata map[string]string) int {
 	code := metadata[http.HTTPStatusCode]
@@ -1308,6 +2019,11 @@ func GetStatusCodeFromMetadata(metadata map[string]string) int {
 }
 
 func (a *api) onGetHealthz(reqCtx *fasthttp.RequestCtx) {
+	if string(reqCtx.QueryArgs().Peek(detailedParam)) == "true" {
+		a.onGetDetailedHealthz(reqCtx)
+		return
+	}
+
 	if !a.readyStatus {
 		msg := NewErrorResponse("ERR_HEALTH_NOT_READY", messages.ErrHealthNotReady)
 		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
@@ -1317,6 +2033,114 @@ func (a *api) onGetHealthz(reqCtx *fasthttp.RequestCtx) {
 	}
 }
 
+func (a *api) onGetDetailedHealthz(reqCtx *fasthttp.RequestCtx) {
+	subsystems := []healthzSubsystem{
+		a.actorRuntimeHealth(),
+		a.placementHealth(),
+		a.componentsHealth(),
+		a.appChannelHealth(),
+	}
+
+	health := detailedHealthz{
+		Status:     healthStatusReady,
+		Subsystems: subsystems,
+	}
+	statusCode := fasthttp.StatusOK
+	if !a.readyStatus {
+		health.Status = healthStatusNotReady
+	}
+	for _, s := range subsystems {
+		if s.Status == healthStatusNotReady {
+			health.Status = healthStatusNotReady
+		}
+	}
+	if health.Status != healthStatusReady {
+		statusCode = fasthttp.StatusInternalServerError
+	}
+
+	b, err := a.json.Marshal(health)
+	if err != nil {
+		msg := NewErrorResponse("ERR_HEALTH_NOT_READY", err.Error())
+		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+		log.Debug(msg)
+		return
+	}
+	respondWithJSON(reqCtx, statusCode, b)
+}
+
+// actorRuntimeHealth is ready when actors can be located, which requires the connection to placement
+// and placement tables that are not being updated.
+func (a *api) actorRuntimeHealth() healthzSubsystem {
+	s := healthzSubsystem{Name: "actors", Status: healthStatusReady}
+	switch {
+	case a.actor == nil:
+		s.Status = healthStatusDisabled
+	case !a.actor.IsPlacementConnected():
+		s.Status = healthStatusNotReady
+		s.Error = "not connected to the placement service"
+	case !a.actor.IsPlacementTableReady():
+		s.Status = healthStatusNotReady
+		s.Error = "placement tables not received or being updated"
+	}
+	return s
+}
+
+func (a *api) placementHealth() healthzSubsystem {
+	s := healthzSubsystem{Name: "placement", Status: healthStatusReady}
+	if a.actor == nil {
+		s.Status = healthStatusDisabled
+	} else if !a.actor.IsPlacementConnected() {
+		s.Status = healthStatusNotReady
+		s.Error = "not connected to the placement service"
+	}
+	return s
+}
+
+func (a *api) componentsHealth() healthzSubsystem {
+	s := healthzSubsystem{Name: "components", Status: healthStatusReady}
+	if a.getComponentStatusFn == nil {
+		return s
+	}
+
+	var errs []string
+	for _, c := range a.getComponentStatusFn() {
+		// The initialization errors are left out since the endpoint is not authenticated, they are
+		// returned by the metadata endpoint.
+		switch c.InitStatus {
+		case components.InitStatusFailed:
+			errs = append(errs, fmt.Sprintf("component %s (%s) failed to initialize", c.Name, c.Type))
+		case components.InitStatusInitializing:
+			errs = append(errs, fmt.Sprintf("component %s (%s) is initializing", c.Name, c.Type))
+		}
+	}
+	if len(errs) > 0 {
+		s.Status = healthStatusNotReady
+		s.Error = strings.Join(errs, "; ")
+	}
+	return s
+}
+
+// appChannelHealth is ready when the app health probe succeeds.
+func (a *api) appChannelHealth() healthzSubsystem {
+	s := healthzSubsystem{Name: "appChannel", Status: healthStatusReady}
+	switch {
+	case a.appChannel == nil:
+		s.Status = healthStatusDisabled
+	case a.appHealthFn != nil && !a.appHealthFn():
+		s.Status = healthStatusNotReady
+		s.Error = "app health probe failing"
+	}
+	return s
+}
+
+// limitBaggageHeader removes the W3C baggage header of the request if it exceeds the configured size.
+func (a *api) limitBaggageHeader(reqCtx *fasthttp.RequestCtx) {
+	baggage := string(reqCtx.Request.Header.Peek(diag.BaggageHeader))
+	if baggage != "" && diag.LimitBaggage(baggage, a.tracingSpec) == "" {
+		reqCtx.Request.Header.Del(diag.BaggageHeader)
+	}
+}
+
 func getMetadataFromRequest(reqCtx *fasthttp.RequestCtx) map[string]string {
 	metadata := map[string]string{}
 	const metadataPrefix string = "metadata."
@@ -1331,6 +2155,16 @@ func getMetadataFromRequest(reqCtx *fasthttp.RequestCtx) map[string]string {
 	return metadata
 }
 
+// getTransactionalStateStore returns the current instance of the transactional state store, so that
+// the instance replacing a reloaded state store serves the transactions.
+func (a *api) getTransactionalStateStore(storeName string) (state.TransactionalStore, bool) {
+	if _, ok := a.transactionalStateStores[storeName]; !ok {
+		return nil, false
+	}
+	transactionalStore, ok := a.stateStores[storeName].(state.TransactionalStore)
+	return transactionalStore, ok
+}
+
 func (a *api) onPostStateTransaction(reqCtx *fasthttp.RequestCtx) {
 	if a.stateStores == nil || len(a.stateStores) == 0 {
 		msg := NewErrorResponse("ERR_STATE_STORES_NOT_CONFIGURED", messages.ErrStateStoresNotConfigured)
@@ -1348,7 +2182,7 @@ func (a *api) onPostStateTransaction(reqCtx *fasthttp.RequestCtx) {
 		return
 	}
 
-	transactionalStore, ok := a.transactionalStateStores[storeName]
+	transactionalStore, ok := a.getTransactionalStateStore(storeName)
 	if !ok {
 		msg := NewErrorResponse("ERR_STATE_STORE_NOT_SUPPORTED", fmt.Sprintf(messages.ErrStateStoreNotSupported, storeName))
 		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
@@ -1357,18 +2191,59 @@ func (a *api) onPostStateTransaction(reqCtx *fasthttp.RequestCtx) {
 	}
 
 	body := reqCtx.PostBody()
-	var req state.TransactionalStateRequest
+	var req StateTransactionRequest
 	if err := a.json.Unmarshal(body, &req); err != nil {
 		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err.Error()))
 		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
 		log.Debug(msg)
 		return
 	}
-	if len(req.Operations) == 0 {
+	if len(req.Operations) == 0 && len(req.Bindings) == 0 {
 		respondEmpty(reqCtx)
 		return
 	}
 
+	// the binding invocations are saved in the outbox of the transaction, so that they
+	// are committed together with the operations and invoked once the transaction commits.
+	// The outbox is listed in a sharded index updated with ETags, so that the invocations
+	// that were not dispatched can be recovered.
+	if len(req.Bindings) > 0 && !state.FeatureETag.IsPresent(a.stateStores[storeName].Features()) {
+		msg := NewErrorResponse("ERR_TRANSACTION_BINDING_NOT_SUPPORTED", fmt.Sprintf(messages.ErrTransactionBindingNotSupported, storeName))
+		respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+		log.Debug(msg)
+		return
+	}
+	outboxKeys := make([]string, 0, len(req.Bindings))
+	outboxRecords := make([]transactionOutboxRecord, 0, len(req.Bindings))
+	for _, b := range req.Bindings {
+		if b.Name == "" {
+			msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, messages.ErrTransactionBindingName))
+			respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+			log.Debug(msg)
+			return
+		}
+		if !a.outputBindingExists(b.Name) {
+			msg := NewErrorResponse("ERR_TRANSACTION_BINDING_NOT_FOUND", fmt.Sprintf(messages.ErrTransactionBindingNotFound, b.Name))
+			respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
+			log.Debug(msg)
+			return
+		}
+		data, err := a.json.Marshal(b.Data)
+		if err != nil {
+			msg := NewErrorResponse("ERR_MALFORMED_REQUEST_DATA", fmt.Sprintf(messages.ErrMalformedRequestData, err))
+			respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+			log.Debug(msg)
+			return
+		}
+		outboxKeys = append(outboxKeys, a.transactionOutboxKey())
+		outboxRecords = append(outboxRecords, transactionOutboxRecord{
+			Name:      b.Name,
+			Operation: b.Operation,
+			Data:      data,
+			Metadata:  withTraceMetadata(reqCtx, b.Metadata),
+		})
+	}
+
 	operations := []state.TransactionalStateOperation{}
 	for _, o := range req.Operations {
 		switch o.Operation {
@@ -1382,11 +2257,11 @@ func (a *api) onPostStateTransaction(reqCtx *fasthttp.RequestCtx) {
 				log.Debug(msg)
 				return
 			}
-			upsertReq.Key, err = state_loader.GetModifiedStateKey(upsertReq.Key, storeName, a.id)
-			if err != nil {
-				msg := NewErrorResponse("ERR_MALFORMED_REQUEST", err.Error())
-				respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
-				log.Debug(err)
+			var errResp *ErrorResponse
+			upsertReq.Key, errResp = a.getTransactionStateKey(reqCtx, storeName, upsertReq.Key, o.Request)
+			if errResp != nil {
+				respondWithError(reqCtx, fasthttp.StatusBadRequest, *errResp)
+				log.Debug(*errResp)
 				return
 			}
 			operations = append(operations, state.TransactionalStateOperation{
@@ -1403,11 +2278,11 @@ func (a *api) onPostStateTransaction(reqCtx *fasthttp.RequestCtx) {
 				log.Debug(msg)
 				return
 			}
-			delReq.Key, err = state_loader.GetModifiedStateKey(delReq.Key, storeName, a.id)
-			if err != nil {
-				msg := NewErrorResponse("ERR_MALFORMED_REQUEST", err.Error())
-				respondWithError(reqCtx, fasthttp.StatusBadRequest, msg)
-				log.Debug(msg)
+			var errResp *ErrorResponse
+			delReq.Key, errResp = a.getTransactionStateKey(reqCtx, storeName, delReq.Key, o.Request)
+			if errResp != nil {
+				respondWithError(reqCtx, fasthttp.StatusBadRequest, *errResp)
+				log.Debug(*errResp)
 				return
 			}
 			operations = append(operations, state.TransactionalStateOperation{
@@ -1424,18 +2299,85 @@ func (a *api) onPostStateTransaction(reqCtx *fasthttp.RequestCtx) {
 		}
 	}
 
+	outboxEntries := newTransactionOutboxEntries(outboxKeys)
+	for i := range outboxRecords {
+		// the records are leased to this replica until they can be recovered, so that
+		// the recovery sweeps of the other replicas don't invoke them during the dispatch.
+		outboxRecords[i].LeaseUntil = outboxEntries[i].Created.Add(transactionOutboxRecoveryAge)
+		operations = append(operations, state.TransactionalStateOperation{
+			Request:   state.SetRequest{Key: outboxKeys[i], Value: outboxRecords[i]},
+			Operation: state.Upsert,
+		})
+	}
+
+	if len(outboxRecords) > 0 {
+		if err := a.addTransactionOutboxEntries(a.stateStores[storeName], outboxEntries); err != nil {
+			msg := NewErrorResponse("ERR_STATE_TRANSACTION", fmt.Sprintf(messages.ErrStateTransaction, err.Error()))
+			respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+			log.Debug(msg)
+			return
+		}
+	}
+
 	err := transactionalStore.Multi(&state.TransactionalStateRequest{
 		Operations: operations,
 		Metadata:   req.Metadata,
 	})
-
 	if err != nil {
+		if len(outboxRecords) > 0 {
+			a.removeTransactionOutboxEntries(a.stateStores[storeName], outboxEntries)
+		}
 		msg := NewErrorResponse("ERR_STATE_TRANSACTION", fmt.Sprintf(messages.ErrStateTransaction, err.Error()))
 		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
 		log.Debug(msg)
-	} else {
-		respondEmpty(reqCtx)
+		return
+	}
+
+	if len(outboxRecords) > 0 {
+		go a.dispatchTransactionOutbox(a.stateStores[storeName], outboxEntries, outboxRecords)
+	}
+	respondEmpty(reqCtx)
+}
+
+// getTransactionStateKey returns the key to save for a state transaction operation.
+// Operations with an actorType and actorId target the state of a locally hosted actor,
+// which is only allowed when the store is the actor state store.
+func (a *api) getTransactionStateKey(reqCtx *fasthttp.RequestCtx, storeName, key string, request interface{}) (string, *ErrorResponse) {
+	var scope TransactionActorScope
+	if err := mapstructure.Decode(request, &scope); err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", fmt.Sprintf(messages.ErrMalformedRequest, err.Error()))
+		return "", &msg
 	}
+
+	if scope.ActorType == "" && scope.ActorID == "" {
+		modifiedKey, err := state_loader.GetModifiedStateKey(key, storeName, a.id)
+		if err != nil {
+			msg := NewErrorResponse("ERR_MALFORMED_REQUEST", err.Error())
+			return "", &msg
+		}
+		return modifiedKey, nil
+	}
+
+	if a.actor == nil {
+		msg := NewErrorResponse("ERR_ACTOR_RUNTIME_NOT_FOUND", messages.ErrActorRuntimeNotFound)
+		return "", &msg
+	}
+
+	actorKey, err := a.actor.GetActorStateKey(storeName, scope.ActorType, scope.ActorID, key)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_REQUEST", err.Error())
+		return "", &msg
+	}
+
+	hosted := a.actor.IsActorHosted(reqCtx, &actors.ActorHostedRequest{
+		ActorType: scope.ActorType,
+		ActorID:   scope.ActorID,
+	})
+	if !hosted {
+		msg := NewErrorResponse("ERR_ACTOR_INSTANCE_MISSING", messages.ErrActorInstanceMissing)
+		return "", &msg
+	}
+	return actorKey, nil
 }
 
 func (a *api) isSecretAllowed(storeName, key string) bool {
@@ -1457,3 +2399,15 @@ func (a *api) SetDirectMessaging(directMessaging messaging.DirectMessaging) {
 func (a *api) SetActorRuntime(actor actors.Actors) {
 	a.actor = actor
 }
+
+func (a *api) SetWorkloadCertRotator(rotateFn func() error) {
+	a.rotateWorkloadCertFn = rotateFn
+}
+
+func (a *api) SetProfilingToggler(setProfilingEnabledFn func(enabled bool) error) {
+	a.setProfilingEnabledFn = setProfilingEnabledFn
+}
+
+func (a *api) SetAppHealthChecker(appHealthFn func() bool) {
+	a.appHealthFn = appHealthFn
+}
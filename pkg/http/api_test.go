@@ -30,6 +30,7 @@ import (
 	epb "google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,12 +43,18 @@ import (
 	"github.com/dapr/dapr/pkg/actors"
 	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	"github.com/dapr/dapr/pkg/channel/http"
+	channelt "github.com/dapr/dapr/pkg/channel/testing"
+	"github.com/dapr/dapr/pkg/components"
 	http_middleware_loader "github.com/dapr/dapr/pkg/components/middleware/http"
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
+	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
 	daprt "github.com/dapr/dapr/pkg/testing"
 	testtrace "github.com/dapr/dapr/pkg/testing/trace"
 	"github.com/dapr/kit/logger"
@@ -217,6 +224,178 @@ func TestPubSubEndpoints(t *testing.T) {
 	fakeServer.Shutdown()
 }
 
+func TestPubSubPublishBaggage(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	var published map[string]interface{}
+	testAPI := &api{
+		pubsubAdapter: &daprt.MockPubSubAdapter{
+			PublishFn: func(req *pubsub.PublishRequest) error {
+				published = nil
+				return json.Unmarshal(req.Data, &published)
+			},
+			GetPubSubFn: func(pubsubName string) pubsub.PubSub {
+				return &daprt.MockPubSub{}
+			},
+		},
+		json:        jsoniter.ConfigFastest,
+		tracingSpec: config.TracingSpec{MaxBaggageSize: 32},
+	}
+	fakeServer.StartServer(testAPI.constructPubSubEndpoints())
+
+	publish := func(baggage string) int {
+		url := fmt.Sprintf("http://localhost/%s/publish/pubsubname/topic", apiVersionV1)
+		r, _ := gohttp.NewRequest("POST", url, bytes.NewBuffer([]byte("{\"key\": \"value\"}")))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("baggage", baggage)
+		res, err := fakeServer.client.Do(r)
+		assert.NoError(t, err)
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	t.Run("baggage is added to the cloudevent", func(t *testing.T) {
+		assert.Equal(t, 204, publish("userId=alice"))
+		assert.Equal(t, "userId=alice", published[runtime_pubsub.BaggageField])
+	})
+
+	t.Run("baggage over the limit is dropped", func(t *testing.T) {
+		assert.Equal(t, 204, publish("userId="+strings.Repeat("a", 32)))
+		assert.NotContains(t, published, runtime_pubsub.BaggageField)
+	})
+
+	fakeServer.Shutdown()
+}
+
+func TestPubSubBatchPublish(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	published := []pubsub.PublishRequest{}
+	testAPI := &api{
+		pubsubAdapter: &daprt.MockPubSubAdapter{
+			PublishFn: func(req *pubsub.PublishRequest) error {
+				if req.PubsubName == "errnotallowed" || strings.Contains(string(req.Data), "forbidden") {
+					return runtime_pubsub.NotAllowedError{Topic: req.Topic, ID: "test"}
+				}
+				if strings.Contains(string(req.Data), "fail") {
+					return fmt.Errorf("Error from pubsub %s", req.PubsubName)
+				}
+				published = append(published, *req)
+				return nil
+			},
+			GetPubSubFn: func(pubsubName string) pubsub.PubSub {
+				return &daprt.MockPubSub{}
+			},
+		},
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructPubSubEndpoints())
+	defer fakeServer.Shutdown()
+
+	apiPath := fmt.Sprintf("%s/publish/pubsubname/topic", apiVersionV1)
+
+	t.Run("Publish batch successfully - 204 No Content", func(t *testing.T) {
+		published = published[:0]
+		body := []byte(`[
+			{"specversion":"1.0","id":"1","source":"app","type":"order.created","data":{"id":1}},
+			{"specversion":"1.0","id":"2","source":"app","type":"order.created","data":{"id":2}}
+		]`)
+		resp := fakeServer.DoRequestWithContentType("POST", apiPath, cloudEventsBatchContentType, body)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Len(t, published, 2)
+	})
+
+	t.Run("Publish batch with failed entries - 207 Multi-Status", func(t *testing.T) {
+		published = published[:0]
+		body := []byte(`[
+			{"specversion":"1.0","id":"1","source":"app","type":"order.created","data":"ok"},
+			{"specversion":"1.0","id":"b","source":"app","type":"order.created","data":"fail"},
+			{"specversion":"1.0","source":"app","type":"order.created","data":"ok"}
+		]`)
+		resp := fakeServer.DoRequestWithContentType("POST", apiPath, cloudEventsBatchContentType, body)
+		assert.Equal(t, 207, resp.StatusCode)
+		assert.Len(t, published, 1)
+
+		var res BulkPublishResponse
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &res))
+		assert.Equal(t, "ERR_PUBSUB_PUBLISH_MESSAGE", res.ErrorCode)
+		if assert.Len(t, res.Entries, 3) {
+			assert.Equal(t, BulkPublishResponseEntry{EntryID: "1", Status: "SUCCESS"}, res.Entries[0])
+			assert.Equal(t, "b", res.Entries[1].EntryID)
+			assert.Equal(t, "FAILED", res.Entries[1].Status)
+			// Entries without an id are reported by their index in the batch.
+			assert.Equal(t, "2", res.Entries[2].EntryID)
+			assert.Equal(t, "FAILED", res.Entries[2].Status)
+			assert.Contains(t, res.Entries[2].Error, "id")
+		}
+	})
+
+	t.Run("Publish batch with all entries failed - 500", func(t *testing.T) {
+		published = published[:0]
+		body := []byte(`[{"specversion":"1.0","id":"1","source":"app","type":"order.created","data":"fail"}]`)
+		resp := fakeServer.DoRequestWithContentType("POST", apiPath, cloudEventsBatchContentType, body)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Empty(t, published)
+
+		var res BulkPublishResponse
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &res))
+		assert.Len(t, res.Entries, 1)
+	})
+
+	t.Run("Publish batch propagates baggage to every entry", func(t *testing.T) {
+		published = published[:0]
+		body := []byte(`[
+			{"specversion":"1.0","id":"1","source":"app","type":"order.created","data":"ok"},
+			{"specversion":"1.0","id":"2","source":"app","type":"order.created","data":"ok"}
+		]`)
+		resp := fakeServer.DoRequestWithHeaders("POST", apiPath, body, map[string]string{
+			"Content-Type": cloudEventsBatchContentType,
+			"baggage":      "userId=alice",
+		})
+		assert.Equal(t, 204, resp.StatusCode)
+		if assert.Len(t, published, 2) {
+			for _, req := range published {
+				var envelope map[string]interface{}
+				assert.NoError(t, json.Unmarshal(req.Data, &envelope))
+				assert.Equal(t, "userId=alice", envelope[runtime_pubsub.BaggageField])
+			}
+		}
+	})
+
+	t.Run("Publish batch forbidden after some entries were published - 207 Multi-Status", func(t *testing.T) {
+		published = published[:0]
+		body := []byte(`[
+			{"specversion":"1.0","id":"1","source":"app","type":"order.created","data":"ok"},
+			{"specversion":"1.0","id":"2","source":"app","type":"order.created","data":"forbidden"},
+			{"specversion":"1.0","id":"3","source":"app","type":"order.created","data":"ok"}
+		]`)
+		resp := fakeServer.DoRequestWithContentType("POST", apiPath, cloudEventsBatchContentType, body)
+		assert.Equal(t, 207, resp.StatusCode)
+		assert.Len(t, published, 2)
+
+		var res BulkPublishResponse
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &res))
+		if assert.Len(t, res.Entries, 3) {
+			assert.Equal(t, "SUCCESS", res.Entries[0].Status)
+			assert.Equal(t, "FAILED", res.Entries[1].Status)
+			assert.Equal(t, "SUCCESS", res.Entries[2].Status)
+		}
+	})
+
+	t.Run("Publish malformed batch - 400", func(t *testing.T) {
+		for _, body := range []string{`{"id":"1"}`, `[]`} {
+			resp := fakeServer.DoRequestWithContentType("POST", apiPath, cloudEventsBatchContentType, []byte(body))
+			assert.Equal(t, 400, resp.StatusCode)
+			assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+		}
+	})
+
+	t.Run("Publish batch to forbidden topic - 403", func(t *testing.T) {
+		body := []byte(`[{"specversion":"1.0","id":"1","source":"app","type":"order.created"}]`)
+		resp := fakeServer.DoRequestWithContentType("POST", fmt.Sprintf("%s/publish/errnotallowed/topic", apiVersionV1), cloudEventsBatchContentType, body)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Equal(t, "ERR_PUBSUB_FORBIDDEN", resp.ErrorBody["errorCode"])
+	})
+}
+
 func TestShutdownEndpoints(t *testing.T) {
 	fakeServer := newFakeHTTPServer()
 
@@ -254,6 +433,166 @@ func TestShutdownEndpoints(t *testing.T) {
 	fakeServer.Shutdown()
 }
 
+func TestRotateWorkloadCertEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	rotated := 0
+	testAPI := &api{
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructMTLSEndpoints())
+	apiPath := fmt.Sprintf("%s/mtls/rotate", apiVersionV1)
+
+	t.Run("api token required - 403", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Equal(t, "ERR_API_TOKEN_REQUIRED", resp.ErrorBody["errorCode"])
+	})
+
+	os.Setenv("DAPR_API_TOKEN", "1234")
+	defer os.Unsetenv("DAPR_API_TOKEN")
+
+	t.Run("mTLS not enabled - 400", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MTLS_NOT_ENABLED", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("rotation failed - 500", func(t *testing.T) {
+		testAPI.SetWorkloadCertRotator(func() error {
+			return errors.New("sentry unavailable")
+		})
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_MTLS_ROTATE_CERT", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("rotated - 204", func(t *testing.T) {
+		testAPI.SetWorkloadCertRotator(func() error {
+			rotated++
+			return nil
+		})
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Equal(t, 1, rotated)
+	})
+
+	fakeServer.Shutdown()
+}
+
+func TestV1SubscriptionsRefreshEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructSubscriptionsEndpoints())
+	apiPath := fmt.Sprintf("%s/subscriptions/refresh", apiVersionV1)
+
+	t.Run("api token required - 403", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Equal(t, "ERR_API_TOKEN_REQUIRED", resp.ErrorBody["errorCode"])
+	})
+
+	os.Setenv("DAPR_API_TOKEN", "1234")
+	defer os.Unsetenv("DAPR_API_TOKEN")
+
+	t.Run("pubsub not configured - 400", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_PUBSUB_NOT_CONFIGURED", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("refresh failed - 500", func(t *testing.T) {
+		testAPI.refreshSubscriptionsFn = func() error {
+			return errors.New("app channel not initialized")
+		}
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_REFRESH_SUBSCRIPTIONS", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("refreshed - 204", func(t *testing.T) {
+		refreshed := 0
+		testAPI.refreshSubscriptionsFn = func() error {
+			refreshed++
+			return nil
+		}
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Equal(t, 1, refreshed)
+	})
+
+	fakeServer.Shutdown()
+}
+
+func TestV1ProfilingEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(append(testAPI.constructMetadataEndpoints(), testAPI.constructProfilingEndpoints()...))
+	apiPath := fmt.Sprintf("%s/profiling", apiVersionV1)
+
+	t.Run("api token required - 403", func(t *testing.T) {
+		resp := fakeServer.DoRequest("PUT", apiPath, []byte("true"), nil)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Equal(t, "ERR_API_TOKEN_REQUIRED", resp.ErrorBody["errorCode"])
+	})
+
+	os.Setenv("DAPR_API_TOKEN", "1234")
+	defer os.Unsetenv("DAPR_API_TOKEN")
+
+	t.Run("invalid body - 400", func(t *testing.T) {
+		resp := fakeServer.DoRequest("PUT", apiPath, []byte("on"), nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("profiling not available - 400", func(t *testing.T) {
+		resp := fakeServer.DoRequest("PUT", apiPath, []byte("true"), nil)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_PROFILING_NOT_AVAILABLE", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("toggle failed - 500", func(t *testing.T) {
+		testAPI.SetProfilingToggler(func(enabled bool) error {
+			return errors.New("address already in use")
+		})
+		resp := fakeServer.DoRequest("PUT", apiPath, []byte("true"), nil)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_PROFILING_TOGGLE", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("enabled and disabled - 204", func(t *testing.T) {
+		var toggles []bool
+		testAPI.SetProfilingToggler(func(enabled bool) error {
+			toggles = append(toggles, enabled)
+			return nil
+		})
+		resp := fakeServer.DoRequest("PUT", apiPath, []byte("true"), nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		resp = fakeServer.DoRequest("PUT", apiPath, []byte("false"), nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.Equal(t, []bool{true, false}, toggles)
+	})
+
+	t.Run("metadata key named profiling - 204", func(t *testing.T) {
+		toggled := false
+		testAPI.SetProfilingToggler(func(enabled bool) error {
+			toggled = true
+			return nil
+		})
+		resp := fakeServer.DoRequest("PUT", fmt.Sprintf("%s/metadata/profiling", apiVersionV1), []byte("on"), nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.False(t, toggled)
+		value, ok := testAPI.extendedMetadata.Load("profiling")
+		assert.True(t, ok)
+		assert.Equal(t, "on", value)
+	})
+
+	fakeServer.Shutdown()
+}
+
 func TestGetStatusCodeFromMetadata(t *testing.T) {
 	t.Run("status code present", func(t *testing.T) {
 		res := GetStatusCodeFromMetadata(map[string]string{
@@ -346,6 +685,46 @@ func TestV1OutputBindingsEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("Invoke output bindings - 200 OK envelope", func(t *testing.T) {
+		binaryData := []byte{0xff, 0x00, 0xfe}
+		testAPI.sendToOutputBindingFn = func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+			switch name {
+			case "binary":
+				return &bindings.InvokeResponse{Data: binaryData, Metadata: map[string]string{"key": "value"}}, nil
+			case "typed":
+				return &bindings.InvokeResponse{Data: []byte("<a/>"), Metadata: map[string]string{"contentType": "application/xml"}}, nil
+			case "json":
+				return &bindings.InvokeResponse{Data: []byte(`{"a":1}`)}, nil
+			}
+			return nil, nil
+		}
+
+		b, _ := json.Marshal(&OutputBindingRequest{Data: "fake output"})
+		headers := map[string]string{bindingEnvelopeHeader: "true"}
+
+		testCases := []struct {
+			binding     string
+			data        []byte
+			contentType string
+			metadata    map[string]string
+		}{
+			{"binary", binaryData, "application/octet-stream", map[string]string{"key": "value"}},
+			{"typed", []byte("<a/>"), "application/xml", map[string]string{"contentType": "application/xml"}},
+			{"json", []byte(`{"a":1}`), "application/json", nil},
+			{"empty", nil, "", nil},
+		}
+		for _, tc := range testCases {
+			resp := fakeServer.DoRequestWithHeaders("POST", fmt.Sprintf("%s/bindings/%s", apiVersionV1, tc.binding), b, headers)
+
+			assert.Equal(t, 200, resp.StatusCode, tc.binding)
+			var envelope OutputBindingResponseEnvelope
+			assert.NoError(t, json.Unmarshal(resp.RawBody, &envelope), tc.binding)
+			assert.Equal(t, tc.data, envelope.Data, tc.binding)
+			assert.Equal(t, tc.contentType, envelope.ContentType, tc.binding)
+			assert.Equal(t, tc.metadata, envelope.Metadata, tc.binding)
+		}
+	})
+
 	t.Run("Invoke output bindings - 500 InternalError", func(t *testing.T) {
 		apiPath := fmt.Sprintf("%s/bindings/notfound", apiVersionV1)
 		req := OutputBindingRequest{
@@ -371,6 +750,50 @@ func TestV1OutputBindingsEndpoints(t *testing.T) {
 	fakeServer.Shutdown()
 }
 
+func TestV1InputBindingsPauseEndpoints(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	paused := map[string]bool{}
+	testAPI := &api{
+		setInputBindingPausedFn: func(name string, p bool) error {
+			if name != "testbinding" {
+				return errors.New("input binding not found")
+			}
+			paused[name] = p
+			return nil
+		},
+		json: jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructBindingsEndpoints())
+
+	t.Run("Pause input binding - 403 api token required", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", fmt.Sprintf("%s/bindings/testbinding/pause", apiVersionV1alpha1), nil, nil)
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Equal(t, "ERR_API_TOKEN_REQUIRED", resp.ErrorBody["errorCode"])
+		assert.False(t, paused["testbinding"])
+	})
+
+	os.Setenv("DAPR_API_TOKEN", "1234")
+	defer os.Unsetenv("DAPR_API_TOKEN")
+
+	t.Run("Pause and resume input binding - 204 No Content", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", fmt.Sprintf("%s/bindings/testbinding/pause", apiVersionV1alpha1), nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.True(t, paused["testbinding"])
+
+		resp = fakeServer.DoRequest("POST", fmt.Sprintf("%s/bindings/testbinding/resume", apiVersionV1alpha1), nil, nil)
+		assert.Equal(t, 204, resp.StatusCode)
+		assert.False(t, paused["testbinding"])
+	})
+
+	t.Run("Pause input binding - 404 Not Found", func(t *testing.T) {
+		resp := fakeServer.DoRequest("POST", fmt.Sprintf("%s/bindings/notfound/pause", apiVersionV1alpha1), nil, nil)
+		assert.Equal(t, 404, resp.StatusCode)
+		assert.Equal(t, "ERR_INPUT_BINDING_NOT_FOUND", resp.ErrorBody["errorCode"])
+	})
+
+	fakeServer.Shutdown()
+}
+
 func TestV1OutputBindingsEndpointsWithTracer(t *testing.T) {
 	fakeServer := newFakeHTTPServer()
 	buffer := ""
@@ -691,6 +1114,58 @@ func TestV1DirectMessagingEndpoints(t *testing.T) {
 	fakeServer.Shutdown()
 }
 
+func TestV1DirectMessagingEndpointsWithOutboundPipeline(t *testing.T) {
+	fakeDirectMessageResponse := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+	fakeDirectMessageResponse.WithRawData([]byte("fakeDirectMessageResponse"), "application/json")
+
+	mockDirectMessaging := new(daprt.MockDirectMessaging)
+
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		directMessaging: mockDirectMessaging,
+		json:            jsoniter.ConfigFastest,
+		outboundPipeline: http_middleware.Pipeline{
+			Handlers: []http_middleware.Middleware{
+				func(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+					return func(ctx *fasthttp.RequestCtx) {
+						ctx.Request.Header.Set("X-Api-Key", "fakeKey")
+						ctx.Request.SetRequestURI(strings.Replace(string(ctx.Request.RequestURI()), "/method/", "/method/v2/", 1))
+						h(ctx)
+					}
+				},
+			},
+		},
+	}
+	fakeServer.StartServer(testAPI.constructDirectMessagingEndpoints())
+	defer fakeServer.Shutdown()
+
+	t.Run("Invoke direct messaging through the outbound pipeline - 200 OK", func(t *testing.T) {
+		apiPath := "v1.0/invoke/fakeAppID/method/fakeMethod?param1=val1"
+
+		mockDirectMessaging.Calls = nil // reset call count
+
+		mockDirectMessaging.On("Invoke",
+			mock.MatchedBy(func(a context.Context) bool {
+				return true
+			}), mock.MatchedBy(func(b string) bool {
+				return b == "fakeAppID"
+			}), mock.MatchedBy(func(c *invokev1.InvokeMethodRequest) bool {
+				apiKey, ok := c.Metadata()["X-Api-Key"]
+				return c.Message().Method == "v2/fakeMethod" &&
+					c.Message().HttpExtension.Querystring == "param1=val1" &&
+					ok && apiKey.Values[0] == "fakeKey"
+			})).Return(fakeDirectMessageResponse, nil).Once()
+
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, []byte("fakeData"), nil)
+
+		// assert
+		mockDirectMessaging.AssertNumberOfCalls(t, "Invoke", 1)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, []byte("fakeDirectMessageResponse"), resp.RawBody)
+	})
+}
+
 func TestV1DirectMessagingEndpointsWithTracer(t *testing.T) {
 	headerMetadata := map[string][]string{
 		"Accept-Encoding":  {"gzip"},
@@ -796,8 +1271,13 @@ func TestV1ActorEndpoints(t *testing.T) {
 			"v1.0/actors/fakeActorType/fakeActorID/reminders/reminder1": {"POST", "PUT", "GET", "DELETE"},
 			"v1.0/actors/fakeActorType/fakeActorID/method/method1":      {"POST", "PUT", "GET", "DELETE"},
 			"v1.0/actors/fakeActorType/fakeActorID/timers/timer1":       {"POST", "PUT", "DELETE"},
+			"v1.0/actors-broadcast/fakeActorType/method1":               {"POST", "PUT"},
+			"v1.0/actors/drain":   {"POST"},
+			"v1.0/actors/undrain": {"POST"},
 		}
 		testAPI.actor = nil
+		os.Setenv("DAPR_API_TOKEN", "1234")
+		defer os.Unsetenv("DAPR_API_TOKEN")
 
 		for apiPath, testMethods := range apisAndMethods {
 			for _, method := range testMethods {
@@ -984,6 +1464,44 @@ func TestV1ActorEndpoints(t *testing.T) {
 		mockActors.AssertNumberOfCalls(t, "IsActorHosted", 1)
 	})
 
+	t.Run("Transaction - actor with ID broadcast", func(t *testing.T) {
+		apiPath := "v1.0/actors/fakeActorType/broadcast/state"
+
+		testTransactionalOperations := []actors.TransactionalOperation{
+			{
+				Operation: actors.Delete,
+				Request: map[string]interface{}{
+					"key": "fakeKey1",
+				},
+			},
+		}
+
+		mockActors := new(daprt.MockActors)
+		mockActors.On("TransactionalStateOperation", &actors.TransactionalRequest{
+			ActorID:    "broadcast",
+			ActorType:  "fakeActorType",
+			Operations: testTransactionalOperations,
+		}).Return(nil)
+
+		mockActors.On("IsActorHosted", &actors.ActorHostedRequest{
+			ActorID:   "broadcast",
+			ActorType: "fakeActorType",
+		}).Return(true)
+
+		testAPI.actor = mockActors
+
+		// act
+		inputBodyBytes, err := json.Marshal(testTransactionalOperations)
+
+		assert.NoError(t, err)
+		resp := fakeServer.DoRequest("POST", apiPath, inputBodyBytes, nil)
+
+		// assert
+		assert.Equal(t, 204, resp.StatusCode)
+		mockActors.AssertNumberOfCalls(t, "TransactionalStateOperation", 1)
+		mockActors.AssertNumberOfCalls(t, "Broadcast", 0)
+	})
+
 	t.Run("Transaction - 400 when actor instance not present", func(t *testing.T) {
 		apiPath := "v1.0/actors/fakeActorType/fakeActorID/state"
 
@@ -1413,8 +1931,164 @@ func TestV1ActorEndpoints(t *testing.T) {
 		mockActors.AssertNumberOfCalls(t, "Call", 1)
 	})
 
-	fakeServer.Shutdown()
-}
+	t.Run("Drain actors - 403 api token required", func(t *testing.T) {
+		apiPath := "v1.0/actors/drain"
+		mockActors := new(daprt.MockActors)
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+
+		// assert
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Equal(t, "ERR_API_TOKEN_REQUIRED", resp.ErrorBody["errorCode"])
+		mockActors.AssertNotCalled(t, "Drain")
+	})
+
+	t.Run("Drain actors - 202 Accepted while draining", func(t *testing.T) {
+		os.Setenv("DAPR_API_TOKEN", "1234")
+		defer os.Unsetenv("DAPR_API_TOKEN")
+		apiPath := "v1.0/actors/drain"
+		mockActors := new(daprt.MockActors)
+		mockActors.On("Drain")
+		mockActors.On("GetDrainStatus", mock.Anything).Return(actors.DrainStatus{
+			Draining:        true,
+			RemainingActors: []actors.ActiveActorsCount{{Type: "fakeActorType", Count: 2}},
+		})
+
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+
+		// assert
+		assert.Equal(t, 202, resp.StatusCode)
+		var drainStatus actors.DrainStatus
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &drainStatus))
+		assert.True(t, drainStatus.Draining)
+		assert.False(t, drainStatus.Completed)
+		assert.Equal(t, 2, drainStatus.RemainingActors[0].Count)
+		mockActors.AssertNumberOfCalls(t, "Drain", 1)
+	})
+
+	t.Run("Drain actors - 200 OK when completed", func(t *testing.T) {
+		os.Setenv("DAPR_API_TOKEN", "1234")
+		defer os.Unsetenv("DAPR_API_TOKEN")
+		apiPath := "v1.0/actors/drain"
+		mockActors := new(daprt.MockActors)
+		mockActors.On("Drain")
+		mockActors.On("GetDrainStatus", mock.Anything).Return(actors.DrainStatus{
+			Draining:        true,
+			Completed:       true,
+			RemainingActors: []actors.ActiveActorsCount{},
+		})
+
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+		mockActors.AssertNumberOfCalls(t, "Drain", 1)
+	})
+
+	t.Run("Undrain actors - 200 OK", func(t *testing.T) {
+		os.Setenv("DAPR_API_TOKEN", "1234")
+		defer os.Unsetenv("DAPR_API_TOKEN")
+		apiPath := "v1.0/actors/undrain"
+		mockActors := new(daprt.MockActors)
+		mockActors.On("Undrain")
+		mockActors.On("GetDrainStatus", mock.Anything).Return(actors.DrainStatus{
+			RemainingActors: []actors.ActiveActorsCount{},
+		})
+
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, nil, nil)
+
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+		var drainStatus actors.DrainStatus
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &drainStatus))
+		assert.False(t, drainStatus.Draining)
+		mockActors.AssertNumberOfCalls(t, "Undrain", 1)
+	})
+
+	t.Run("Broadcast actor method - 200 OK", func(t *testing.T) {
+		apiPath := "v1.0/actors-broadcast/fakeActorType/method1"
+		mockActors := new(daprt.MockActors)
+		mockActors.On("Broadcast", mock.MatchedBy(func(req *actors.BroadcastRequest) bool {
+			return req.ActorType == "fakeActorType" && req.Method == "method1" && string(req.Data) == string(fakeData) && !req.Global
+		})).Return(&actors.BroadcastResponse{
+			Invoked: 2,
+			Failed:  []actors.BroadcastFailure{{ActorID: "fakeActorID", Error: "fake error"}},
+		}, nil)
+
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, fakeData, nil)
+
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+		var broadcastResp actors.BroadcastResponse
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &broadcastResp))
+		assert.Equal(t, 2, broadcastResp.Invoked)
+		assert.Equal(t, "fakeActorID", broadcastResp.Failed[0].ActorID)
+		mockActors.AssertNumberOfCalls(t, "Broadcast", 1)
+	})
+
+	t.Run("Broadcast actor method globally - 200 OK", func(t *testing.T) {
+		apiPath := "v1.0/actors-broadcast/fakeActorType/method1"
+		mockActors := new(daprt.MockActors)
+		mockActors.On("Broadcast", mock.MatchedBy(func(req *actors.BroadcastRequest) bool {
+			return req.Global
+		})).Return(&actors.BroadcastResponse{Invoked: 5}, nil)
+
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, fakeData, map[string]string{"scope": "global"})
+
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+		mockActors.AssertNumberOfCalls(t, "Broadcast", 1)
+	})
+
+	t.Run("Broadcast actor method - 400 on invalid scope", func(t *testing.T) {
+		apiPath := "v1.0/actors-broadcast/fakeActorType/method1"
+		mockActors := new(daprt.MockActors)
+
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, fakeData, map[string]string{"scope": "everywhere"})
+
+		// assert
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+		mockActors.AssertNumberOfCalls(t, "Broadcast", 0)
+	})
+
+	t.Run("Broadcast actor method - 500 on error", func(t *testing.T) {
+		apiPath := "v1.0/actors-broadcast/fakeActorType/method1"
+		mockActors := new(daprt.MockActors)
+		mockActors.On("Broadcast", mock.AnythingOfType("*actors.BroadcastRequest")).Return(nil, errors.New("UPSTREAM_ERROR"))
+
+		testAPI.actor = mockActors
+
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, fakeData, nil)
+
+		// assert
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_ACTOR_BROADCAST", resp.ErrorBody["errorCode"])
+	})
+
+	fakeServer.Shutdown()
+}
 
 func TestV1MetadataEndpoint(t *testing.T) {
 	fakeServer := newFakeHTTPServer()
@@ -1494,6 +2168,48 @@ func TestV1MetadataEndpoint(t *testing.T) {
 	fakeServer.Shutdown()
 }
 
+func TestV1MetadataEndpointComponentStatus(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+
+	testAPI := &api{
+		getComponentsFn: func() []components_v1alpha1.Component {
+			return []components_v1alpha1.Component{
+				{
+					ObjectMeta: meta_v1.ObjectMeta{
+						Name: "statestore",
+					},
+					Spec: components_v1alpha1.ComponentSpec{
+						Type:    "state.redis",
+						Version: "v1",
+					},
+				},
+			}
+		},
+		getComponentStatusFn: func() []components.Status {
+			return []components.Status{
+				{Name: "statestore", Type: "state.redis", Version: "v1", InitStatus: components.InitStatusSucceeded, InitDuration: time.Second},
+				{Name: "pubsub", Type: "pubsub.kafka", Version: "v1", InitStatus: components.InitStatusFailed, Error: "connection refused", InitDuration: 2 * time.Second},
+			}
+		},
+		json: jsoniter.ConfigFastest,
+	}
+
+	fakeServer.StartServer(testAPI.constructMetadataEndpoints())
+	defer fakeServer.Shutdown()
+
+	resp := fakeServer.DoRequest("GET", "v1.0/metadata", nil, nil)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var body struct {
+		RegisteredComponents []registeredComponent `json:"components"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.RawBody, &body))
+	assert.Equal(t, []registeredComponent{
+		{Name: "statestore", Type: "state.redis", Version: "v1", Status: "SUCCEEDED", InitDuration: "1s"},
+		{Name: "pubsub", Type: "pubsub.kafka", Version: "v1", Status: "FAILED", Error: "connection refused", InitDuration: "2s"},
+	}, body.RegisteredComponents)
+}
+
 func createExporters(buffer *string) {
 	exporter := testtrace.NewStringExporter(buffer, logger.NewLogger("fakeLogger"))
 	exporter.Register("fakeID")
@@ -2022,9 +2738,14 @@ func (f *fakeHTTPServer) StartServerWithTracing(spec config.TracingSpec, endpoin
 
 func (f *fakeHTTPServer) StartServerWithAPIToken(endpoints []Endpoint) {
 	router := f.getRouter(endpoints)
+	token, err := auth.LoadAPIToken()
+	if err != nil {
+		panic(fmt.Errorf("failed to load api token: %v", err))
+	}
+	srv := &server{apiToken: token}
 	f.ln = fasthttputil.NewInmemoryListener()
 	go func() {
-		if err := fasthttp.Serve(f.ln, useAPIAuthentication(router.Handler)); err != nil {
+		if err := fasthttp.Serve(f.ln, srv.useAPIAuthentication(router.Handler)); err != nil {
 			panic(fmt.Errorf("failed to serve: %v", err))
 		}
 	}()
@@ -2092,6 +2813,59 @@ func (f *fakeHTTPServer) DoRequestWithAPIToken(method, path, token string, body
 	return response
 }
 
+func (f *fakeHTTPServer) DoRequestWithContentType(method, path, contentType string, body []byte) fakeHTTPResponse {
+	url := fmt.Sprintf("http://localhost/%s", path)
+	r, _ := gohttp.NewRequest(method, url, bytes.NewBuffer(body))
+	r.Header.Set("Content-Type", contentType)
+	res, err := f.client.Do(r)
+	if err != nil {
+		panic(fmt.Errorf("failed to request: %v", err))
+	}
+
+	bodyBytes, _ := ioutil.ReadAll(res.Body)
+	defer res.Body.Close()
+	response := fakeHTTPResponse{
+		StatusCode:  res.StatusCode,
+		ContentType: res.Header.Get("Content-Type"),
+		RawHeader:   res.Header,
+		RawBody:     bodyBytes,
+	}
+
+	if response.ContentType == "application/json" && response.StatusCode >= 300 {
+		json.Unmarshal(bodyBytes, &response.ErrorBody)
+	}
+
+	return response
+}
+
+func (f *fakeHTTPServer) DoRequestWithHeaders(method, path string, body []byte, headers map[string]string) fakeHTTPResponse {
+	url := fmt.Sprintf("http://localhost/%s", path)
+	r, _ := gohttp.NewRequest(method, url, bytes.NewBuffer(body))
+	r.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	res, err := f.client.Do(r)
+	if err != nil {
+		panic(fmt.Errorf("failed to request: %v", err))
+	}
+
+	bodyBytes, _ := ioutil.ReadAll(res.Body)
+	defer res.Body.Close()
+	response := fakeHTTPResponse{
+		StatusCode:  res.StatusCode,
+		ContentType: res.Header.Get("Content-Type"),
+		RawHeader:   res.Header,
+		RawBody:     bodyBytes,
+	}
+
+	if response.ContentType == "application/json" && response.StatusCode >= 300 {
+		json.Unmarshal(bodyBytes, &response.ErrorBody)
+	}
+
+	return response
+}
+
 func (f *fakeHTTPServer) DoRequest(method, path string, body []byte, params map[string]string, headers ...string) fakeHTTPResponse {
 	url := fmt.Sprintf("http://localhost/%s", path)
 	if params != nil {
@@ -2154,6 +2928,8 @@ func TestV1StateEndpoints(t *testing.T) {
 			"v1.0/state/nonexistantStore/bad-key":     {"GET", "DELETE"},
 			"v1.0/state/nonexistantStore/":            {"POST", "PUT"},
 			"v1.0/state/nonexistantStore/bulk":        {"POST", "PUT"},
+			"v1.0/state/nonexistantStore/bulk/set":    {"POST", "PUT"},
+			"v1.0/state/nonexistantStore/bulk/delete": {"POST", "PUT"},
 			"v1.0/state/nonexistantStore/transaction": {"POST", "PUT"},
 		}
 
@@ -2179,6 +2955,8 @@ func TestV1StateEndpoints(t *testing.T) {
 		apiPaths := []string{
 			"v1.0/state/store1/",
 			"v1.0/state/store1/bulk",
+			"v1.0/state/store1/bulk/set",
+			"v1.0/state/store1/bulk/delete",
 			"v1.0/state/store1/transaction",
 		}
 
@@ -2319,6 +3097,15 @@ func TestV1StateEndpoints(t *testing.T) {
 		assert.Equal(t, 500, resp.StatusCode, "updating existing key with wrong etag should fail")
 	})
 
+	t.Run("Delete state - key named bulk", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/bulk", storeName)
+		// act
+		resp := fakeServer.DoRequest("DELETE", apiPath, nil, nil)
+		// assert
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_STATE_DELETE", resp.ErrorBody["errorCode"])
+	})
+
 	t.Run("Bulk state get - Empty request", func(t *testing.T) {
 		apiPath := fmt.Sprintf("v1.0/state/%s/bulk", storeName)
 		request := BulkGetRequest{}
@@ -2407,6 +3194,170 @@ func TestV1StateEndpoints(t *testing.T) {
 
 		assert.Equal(t, expectedResponses, responses, "Responses do not match")
 	})
+
+	t.Run("Bulk state set", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/bulk/set", storeName)
+		request := []state.SetRequest{{Key: "good-key"}, {Key: "good-key", ETag: &etag}}
+		body, _ := json.Marshal(request)
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, body, nil)
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var responses []BulkStateOperationResponse
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &responses), "Response should be valid JSON")
+		assert.Equal(t, []BulkStateOperationResponse{{Key: "good-key"}, {Key: "good-key"}}, responses)
+	})
+
+	t.Run("Bulk state set - one key returns error", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/bulk/set", storeName)
+		request := []state.SetRequest{{Key: "good-key"}, {Key: "error-key"}}
+		body, _ := json.Marshal(request)
+		// act
+		resp := fakeServer.DoRequest("PUT", apiPath, body, nil)
+		// assert
+		assert.Equal(t, 200, resp.StatusCode, "Bulk API should succeed even if a key fails")
+
+		var responses []BulkStateOperationResponse
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &responses), "Response should be valid JSON")
+		expectedResponses := []BulkStateOperationResponse{
+			{Key: "good-key"},
+			{Key: "error-key", Error: "NOT FOUND"},
+		}
+		assert.Equal(t, expectedResponses, responses)
+	})
+
+	t.Run("Bulk state delete - one key returns error", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/bulk/delete", storeName)
+		request := []state.DeleteRequest{{Key: "good-key", ETag: &etag}, {Key: "missing-key"}}
+		body, _ := json.Marshal(request)
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, body, nil)
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var responses []BulkStateOperationResponse
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &responses), "Response should be valid JSON")
+		expectedResponses := []BulkStateOperationResponse{
+			{Key: "good-key"},
+			{Key: "missing-key", Error: "NOT FOUND"},
+		}
+		assert.Equal(t, expectedResponses, responses)
+	})
+
+	t.Run("Bulk state set - conflicting etag", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/bulk/set", storeName)
+		request := []state.SetRequest{{Key: "good-key", ETag: &etag}, {Key: "conflict-key", ETag: &etag}, {Key: "error-key"}}
+		body, _ := json.Marshal(request)
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, body, nil)
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var responses []BulkStateOperationResponse
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &responses), "Response should be valid JSON")
+		assert.Len(t, responses, 3)
+		assert.Equal(t, BulkStateOperationResponse{Key: "good-key"}, responses[0])
+		assert.Equal(t, "conflict-key", responses[1].Key)
+		assert.True(t, responses[1].Conflict)
+		assert.NotEmpty(t, responses[1].Error)
+		assert.Equal(t, BulkStateOperationResponse{Key: "error-key", Error: "NOT FOUND"}, responses[2])
+	})
+
+	t.Run("Bulk state delete - conflicting etag", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/bulk/delete", storeName)
+		request := []state.DeleteRequest{{Key: "good-key", ETag: &etag}, {Key: "conflict-key", ETag: &etag}}
+		body, _ := json.Marshal(request)
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, body, nil)
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+
+		var responses []BulkStateOperationResponse
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &responses), "Response should be valid JSON")
+		assert.Len(t, responses, 2)
+		assert.False(t, responses[0].Conflict)
+		assert.True(t, responses[1].Conflict)
+	})
+
+	t.Run("Bulk state delete - empty request", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/bulk/delete", storeName)
+		// act
+		resp := fakeServer.DoRequest("POST", apiPath, []byte("[]"), nil)
+		// assert
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, []byte("[]"), resp.RawBody)
+	})
+}
+
+func TestV1StateEndpointsWithProtobuf(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	testAPI := &api{
+		stateStores: map[string]state.Store{"store1": fakeStateStore{}},
+		json:        jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructStateEndpoints())
+	protobufHeaders := map[string]string{"Content-Type": invokev1.ProtobufContentType}
+
+	t.Run("Get state", func(t *testing.T) {
+		headers := map[string]string{"Accept": invokev1.ProtobufContentType}
+		resp := fakeServer.DoRequestWithHeaders("GET", "v1.0/state/store1/good-key", nil, headers)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, invokev1.ProtobufContentType, resp.ContentType)
+
+		var out runtimev1pb.GetStateResponse
+		assert.NoError(t, proto.Unmarshal(resp.RawBody, &out))
+		assert.Equal(t, []byte("\"bGlmZSBpcyBnb29k\""), out.Data)
+		assert.Equal(t, "`~!@#$%^&*()_+-={}[]|\\:\";'<>?,./'", out.Etag)
+	})
+
+	t.Run("Get state - JSON without Accept header", func(t *testing.T) {
+		resp := fakeServer.DoRequest("GET", "v1.0/state/store1/good-key", nil, nil)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.ContentType)
+		assert.Equal(t, []byte("\"bGlmZSBpcyBnb29k\""), resp.RawBody)
+	})
+
+	t.Run("Save state", func(t *testing.T) {
+		b, _ := proto.Marshal(&runtimev1pb.SaveStateRequest{
+			States: []*commonv1pb.StateItem{{Key: "good-key", Value: []byte("data")}},
+		})
+		resp := fakeServer.DoRequestWithHeaders("POST", "v1.0/state/store1", b, protobufHeaders)
+		assert.Equal(t, 204, resp.StatusCode)
+	})
+
+	t.Run("Save state - state error", func(t *testing.T) {
+		b, _ := proto.Marshal(&runtimev1pb.SaveStateRequest{
+			States: []*commonv1pb.StateItem{{Key: "error-key", Etag: &commonv1pb.Etag{Value: ""}}},
+		})
+		resp := fakeServer.DoRequestWithHeaders("POST", "v1.0/state/store1", b, protobufHeaders)
+		assert.Equal(t, 500, resp.StatusCode)
+		assert.Equal(t, "ERR_STATE_SAVE", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Save state - malformed protobuf", func(t *testing.T) {
+		resp := fakeServer.DoRequestWithHeaders("POST", "v1.0/state/store1", invalidJSON, protobufHeaders)
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Bulk get state", func(t *testing.T) {
+		b, _ := proto.Marshal(&runtimev1pb.GetBulkStateRequest{
+			Keys: []string{"good-key", "missing-key"},
+		})
+		resp := fakeServer.DoRequestWithHeaders("POST", "v1.0/state/store1/bulk", b, protobufHeaders)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, invokev1.ProtobufContentType, resp.ContentType)
+
+		var out runtimev1pb.GetBulkStateResponse
+		assert.NoError(t, proto.Unmarshal(resp.RawBody, &out))
+		assert.Len(t, out.Items, 2)
+		assert.Equal(t, "good-key", out.Items[0].Key)
+		assert.Equal(t, []byte("\"bGlmZSBpcyBnb29k\""), out.Items[0].Data)
+		assert.NotEmpty(t, out.Items[0].Etag)
+		assert.Equal(t, "missing-key", out.Items[1].Key)
+		assert.Empty(t, out.Items[1].Data)
+	})
 }
 
 type fakeStateStore struct {
@@ -2444,6 +3395,9 @@ func (c fakeStateStore) Delete(req *state.DeleteRequest) error {
 		}
 		return nil
 	}
+	if req.Key == "conflict-key" {
+		return state.NewETagError(state.ETagMismatch, errors.New("ETag mismatch"))
+	}
 	return errors.New("NOT FOUND")
 }
 
@@ -2484,6 +3438,9 @@ func (c fakeStateStore) Set(req *state.SetRequest) error {
 		}
 		return nil
 	}
+	if req.Key == "conflict-key" {
+		return state.NewETagError(state.ETagMismatch, errors.New("ETag mismatch"))
+	}
 	return errors.New("NOT FOUND")
 }
 
@@ -2633,6 +3590,59 @@ func TestV1SecretEndpoints(t *testing.T) {
 	})
 }
 
+func TestV1SecretCacheEndpoint(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+	fakeStores := map[string]secretstores.SecretStore{
+		"cachedStore": secretstores_loader.NewCachedSecretStore(daprt.FakeSecretStore{}, time.Minute, 0),
+		"store":       daprt.FakeSecretStore{},
+	}
+	testAPI := &api{
+		secretStores: fakeStores,
+		json:         jsoniter.ConfigFastest,
+	}
+	fakeServer.StartServer(testAPI.constructSecretEndpoints())
+
+	t.Run("Invalidate cache - 403 api token required", func(t *testing.T) {
+		apiPath := "v1.0/secrets/cachedStore/cache"
+		// act
+		resp := fakeServer.DoRequest("DELETE", apiPath, nil, nil)
+		// assert
+		assert.Equal(t, 403, resp.StatusCode)
+		assert.Equal(t, "ERR_API_TOKEN_REQUIRED", resp.ErrorBody["errorCode"])
+	})
+
+	os.Setenv("DAPR_API_TOKEN", "1234")
+	defer os.Unsetenv("DAPR_API_TOKEN")
+
+	t.Run("Invalidate cache - 204 No Content", func(t *testing.T) {
+		apiPath := "v1.0/secrets/cachedStore/cache"
+		// act
+		resp := fakeServer.DoRequest("DELETE", apiPath, nil, nil)
+		// assert
+		assert.Equal(t, 204, resp.StatusCode)
+	})
+
+	t.Run("Invalidate cache without caching - 400 ERR_SECRET_CACHE_NOT_ENABLED", func(t *testing.T) {
+		apiPath := "v1.0/secrets/store/cache"
+		// act
+		resp := fakeServer.DoRequest("DELETE", apiPath, nil, nil)
+		// assert
+		assert.Equal(t, 400, resp.StatusCode)
+		assert.Equal(t, "ERR_SECRET_CACHE_NOT_ENABLED", resp.ErrorBody["errorCode"])
+	})
+
+	t.Run("Invalidate cache of missing store - 401 ERR_SECRET_STORE_NOT_FOUND", func(t *testing.T) {
+		apiPath := "v1.0/secrets/notexistStore/cache"
+		// act
+		resp := fakeServer.DoRequest("DELETE", apiPath, nil, nil)
+		// assert
+		assert.Equal(t, 401, resp.StatusCode)
+		assert.Equal(t, "ERR_SECRET_STORE_NOT_FOUND", resp.ErrorBody["errorCode"])
+	})
+
+	fakeServer.Shutdown()
+}
+
 func TestV1HealthzEndpoint(t *testing.T) {
 	fakeServer := newFakeHTTPServer()
 
@@ -2661,18 +3671,152 @@ func TestV1HealthzEndpoint(t *testing.T) {
 	fakeServer.Shutdown()
 }
 
+func TestV1HealthzEndpointDetailed(t *testing.T) {
+	fakeServer := newFakeHTTPServer()
+
+	statuses := []components.Status{
+		{Name: "statestore", Type: "state.redis", Version: "v1", InitStatus: components.InitStatusSucceeded},
+	}
+	testAPI := &api{
+		json:                 jsoniter.ConfigFastest,
+		getComponentStatusFn: func() []components.Status { return statuses },
+	}
+	testAPI.MarkStatusAsReady()
+
+	fakeServer.StartServer(testAPI.constructHealthzEndpoints())
+
+	getHealth := func(t *testing.T, expectedStatusCode int) detailedHealthz {
+		resp := fakeServer.DoRequest("GET", "v1.0/healthz?detailed=true", nil, nil)
+		assert.Equal(t, expectedStatusCode, resp.StatusCode)
+
+		var health detailedHealthz
+		assert.NoError(t, json.Unmarshal(resp.RawBody, &health))
+		return health
+	}
+
+	subsystem := func(health detailedHealthz, name string) healthzSubsystem {
+		for _, s := range health.Subsystems {
+			if s.Name == name {
+				return s
+			}
+		}
+		return healthzSubsystem{}
+	}
+
+	t.Run("all subsystems ready", func(t *testing.T) {
+		health := getHealth(t, 200)
+
+		assert.Equal(t, healthStatusReady, health.Status)
+		assert.Equal(t, healthStatusDisabled, subsystem(health, "actors").Status)
+		assert.Equal(t, healthStatusDisabled, subsystem(health, "placement").Status)
+		assert.Equal(t, healthStatusReady, subsystem(health, "components").Status)
+		assert.Equal(t, healthStatusDisabled, subsystem(health, "appChannel").Status)
+	})
+
+	t.Run("placement not connected", func(t *testing.T) {
+		mockActors := new(daprt.MockActors)
+		mockActors.On("IsPlacementConnected").Return(false)
+		testAPI.actor = mockActors
+		defer func() { testAPI.actor = nil }()
+
+		health := getHealth(t, 500)
+
+		assert.Equal(t, healthStatusNotReady, health.Status)
+		assert.Equal(t, healthStatusNotReady, subsystem(health, "actors").Status)
+		assert.Equal(t, healthStatusNotReady, subsystem(health, "placement").Status)
+		assert.NotEmpty(t, subsystem(health, "placement").Error)
+	})
+
+	t.Run("placement tables not ready", func(t *testing.T) {
+		mockActors := new(daprt.MockActors)
+		mockActors.On("IsPlacementConnected").Return(true)
+		mockActors.On("IsPlacementTableReady").Return(false)
+		testAPI.actor = mockActors
+		defer func() { testAPI.actor = nil }()
+
+		health := getHealth(t, 500)
+
+		assert.Equal(t, healthStatusNotReady, health.Status)
+		assert.Equal(t, healthStatusNotReady, subsystem(health, "actors").Status)
+		assert.NotEmpty(t, subsystem(health, "actors").Error)
+		assert.Equal(t, healthStatusReady, subsystem(health, "placement").Status)
+	})
+
+	t.Run("actors ready", func(t *testing.T) {
+		mockActors := new(daprt.MockActors)
+		mockActors.On("IsPlacementConnected").Return(true)
+		mockActors.On("IsPlacementTableReady").Return(true)
+		testAPI.actor = mockActors
+		defer func() { testAPI.actor = nil }()
+
+		health := getHealth(t, 200)
+
+		assert.Equal(t, healthStatusReady, subsystem(health, "actors").Status)
+		assert.Equal(t, healthStatusReady, subsystem(health, "placement").Status)
+	})
+
+	t.Run("component failed to initialize", func(t *testing.T) {
+		statuses = append(statuses, components.Status{
+			Name: "pubsub", Type: "pubsub.kafka", Version: "v1", InitStatus: components.InitStatusFailed, Error: "broker unreachable",
+		})
+		defer func() { statuses = statuses[:1] }()
+
+		health := getHealth(t, 500)
+
+		assert.Equal(t, healthStatusNotReady, health.Status)
+		assert.Equal(t, healthStatusNotReady, subsystem(health, "components").Status)
+		assert.Contains(t, subsystem(health, "components").Error, "pubsub (pubsub.kafka) failed to initialize")
+		assert.NotContains(t, subsystem(health, "components").Error, "broker unreachable")
+	})
+
+	t.Run("app health probe failing", func(t *testing.T) {
+		appHealthy := true
+		testAPI.appChannel = new(channelt.MockAppChannel)
+		testAPI.SetAppHealthChecker(func() bool { return appHealthy })
+		defer func() {
+			testAPI.appChannel = nil
+			testAPI.appHealthFn = nil
+		}()
+
+		health := getHealth(t, 200)
+		assert.Equal(t, healthStatusReady, subsystem(health, "appChannel").Status)
+
+		appHealthy = false
+		health = getHealth(t, 500)
+		assert.Equal(t, healthStatusNotReady, health.Status)
+		assert.Equal(t, healthStatusNotReady, subsystem(health, "appChannel").Status)
+		assert.NotEmpty(t, subsystem(health, "appChannel").Error)
+	})
+
+	t.Run("without detailed the response is empty", func(t *testing.T) {
+		resp := fakeServer.DoRequest("GET", "v1.0/healthz", nil, nil)
+
+		assert.Equal(t, 204, resp.StatusCode)
+	})
+
+	fakeServer.Shutdown()
+}
+
 func TestV1TransactionEndpoints(t *testing.T) {
 	fakeServer := newFakeHTTPServer()
 	var fakeStore state.Store = fakeStateStore{}
 	fakeStoreNonTransactional := new(daprt.MockStateStore)
+	fakeOutboxStore := daprt.NewFakeETagStateStore()
+	fakeNoETagStore := daprt.NewFakeETagStateStore()
+	fakeNoETagStore.NoETag = true
 	fakeStores := map[string]state.Store{
 		"store1":                fakeStore,
 		"storeNonTransactional": fakeStoreNonTransactional,
+		"storeOutbox":           fakeOutboxStore,
+		"storeNoETag":           fakeNoETagStore,
 	}
 	fakeTransactionalStores := map[string]state.TransactionalStore{
-		"store1": fakeStore.(state.TransactionalStore),
+		"store1":      fakeStore.(state.TransactionalStore),
+		"storeOutbox": fakeOutboxStore,
+		"storeNoETag": fakeNoETagStore,
 	}
 	testAPI := &api{
+		id:                       "fakeAppID",
 		stateStores:              fakeStores,
 		transactionalStateStores: fakeTransactionalStores,
 		json:                     jsoniter.ConfigFastest,
@@ -2852,6 +3996,249 @@ func TestV1TransactionEndpoints(t *testing.T) {
 		assert.Equal(t, 500, resp.StatusCode, "Dapr should return 500")
 		assert.Equal(t, "ERR_STATE_TRANSACTION", resp.ErrorBody["errorCode"], apiPath)
 	})
+	t.Run("Transaction with bindings", func(t *testing.T) {
+		apiPath := "v1.0/state/storeOutbox/transaction"
+		invoked := make(chan string, 10)
+		testAPI.sendToOutputBindingFn = func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+			invoked <- name + ":" + string(req.Operation) + ":" + string(req.Data)
+			return nil, nil
+		}
+		testAPI.getComponentsFn = func() []components_v1alpha1.Component {
+			return []components_v1alpha1.Component{
+				{ObjectMeta: meta_v1.ObjectMeta{Name: "queue1"}, Spec: components_v1alpha1.ComponentSpec{Type: "bindings.kafka"}},
+				{ObjectMeta: meta_v1.ObjectMeta{Name: "queue2"}, Spec: components_v1alpha1.ComponentSpec{Type: "bindings.kafka"}},
+				{ObjectMeta: meta_v1.ObjectMeta{Name: "pubsub1"}, Spec: components_v1alpha1.ComponentSpec{Type: "pubsub.kafka"}},
+			}
+		}
+		defer func() {
+			testAPI.sendToOutputBindingFn = nil
+			testAPI.getComponentsFn = nil
+		}()
+
+		testTransactionalOperations := []state.TransactionalStateOperation{
+			{
+				Operation: state.Upsert,
+				Request: map[string]interface{}{
+					"key":   "fakeKey1",
+					"value": fakeBodyObject,
+				},
+			},
+		}
+		testBinding := func(name string) TransactionBindingRequest {
+			return TransactionBindingRequest{
+				Name: name,
+				OutputBindingRequest: OutputBindingRequest{
+					Operation: "create",
+					Data:      "fakeData",
+				},
+			}
+		}
+
+		t.Run("invoked in order after commit - 204 No Content", func(t *testing.T) {
+			inputBodyBytes, err := json.Marshal(StateTransactionRequest{
+				Operations: testTransactionalOperations,
+				Bindings:   []TransactionBindingRequest{testBinding("queue1"), testBinding("queue2")},
+			})
+			assert.NoError(t, err)
+
+			// act
+			resp := fakeServer.DoRequest("POST", apiPath, inputBodyBytes, nil)
+
+			// assert
+			assert.Equal(t, 204, resp.StatusCode)
+			for _, expected := range []string{`queue1:create:"fakeData"`, `queue2:create:"fakeData"`} {
+				select {
+				case name := <-invoked:
+					assert.Equal(t, expected, name)
+				case <-time.After(5 * time.Second):
+					assert.Fail(t, "output binding not invoked", expected)
+				}
+			}
+			assert.Eventually(t, func() bool {
+				return len(allTransactionOutboxEntries(t, testAPI, fakeOutboxStore)) == 0
+			}, 5*time.Second, 10*time.Millisecond, "dispatched invocations are removed from the outbox index")
+		})
+
+		t.Run("not invoked if the transaction fails - 500 ERR_STATE_TRANSACTION", func(t *testing.T) {
+			inputBodyBytes, err := json.Marshal(StateTransactionRequest{
+				Operations: testTransactionalOperations,
+				Bindings:   []TransactionBindingRequest{testBinding("queue1")},
+			})
+			assert.NoError(t, err)
+			fakeOutboxStore.MultiErr = errors.New("Transaction error")
+			defer func() { fakeOutboxStore.MultiErr = nil }()
+
+			// act
+			resp := fakeServer.DoRequest("POST", apiPath, inputBodyBytes, nil)
+
+			// assert
+			assert.Equal(t, 500, resp.StatusCode)
+			assert.Equal(t, "ERR_STATE_TRANSACTION", resp.ErrorBody["errorCode"])
+			select {
+			case name := <-invoked:
+				assert.Fail(t, "output binding invoked", name)
+			case <-time.After(100 * time.Millisecond):
+			}
+			assert.Empty(t, allTransactionOutboxEntries(t, testAPI, fakeOutboxStore))
+		})
+
+		t.Run("state store without ETags - 400 ERR_TRANSACTION_BINDING_NOT_SUPPORTED", func(t *testing.T) {
+			inputBodyBytes, err := json.Marshal(StateTransactionRequest{
+				Operations: testTransactionalOperations,
+				Bindings:   []TransactionBindingRequest{testBinding("queue1")},
+			})
+			assert.NoError(t, err)
+
+			// act
+			resp := fakeServer.DoRequest("POST", "v1.0/state/storeNoETag/transaction", inputBodyBytes, nil)
+
+			// assert
+			assert.Equal(t, 400, resp.StatusCode)
+			assert.Equal(t, "ERR_TRANSACTION_BINDING_NOT_SUPPORTED", resp.ErrorBody["errorCode"])
+		})
+
+		t.Run("unknown binding - 400 ERR_TRANSACTION_BINDING_NOT_FOUND", func(t *testing.T) {
+			for _, name := range []string{"queue3", "pubsub1"} {
+				inputBodyBytes, err := json.Marshal(StateTransactionRequest{
+					Operations: testTransactionalOperations,
+					Bindings:   []TransactionBindingRequest{testBinding("queue1"), testBinding(name)},
+				})
+				assert.NoError(t, err)
+
+				// act
+				resp := fakeServer.DoRequest("POST", apiPath, inputBodyBytes, nil)
+
+				// assert
+				assert.Equal(t, 400, resp.StatusCode)
+				assert.Equal(t, "ERR_TRANSACTION_BINDING_NOT_FOUND", resp.ErrorBody["errorCode"])
+			}
+		})
+
+		t.Run("binding without name - 400 ERR_MALFORMED_REQUEST", func(t *testing.T) {
+			inputBodyBytes, err := json.Marshal(StateTransactionRequest{
+				Operations: testTransactionalOperations,
+				Bindings:   []TransactionBindingRequest{testBinding("")},
+			})
+			assert.NoError(t, err)
+
+			// act
+			resp := fakeServer.DoRequest("POST", apiPath, inputBodyBytes, nil)
+
+			// assert
+			assert.Equal(t, 400, resp.StatusCode)
+			assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"])
+		})
+	})
+
+	t.Run("Transaction with actor and app keys - 204 No Content", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/transaction", storeName)
+		mockActors := new(daprt.MockActors)
+		mockActors.On("GetActorStateKey", storeName, "fakeActorType", "fakeActorID", "fakeKey2").Return("fakeAppID||fakeActorType||fakeActorID||fakeKey2", nil)
+		mockActors.On("IsActorHosted", &actors.ActorHostedRequest{
+			ActorID:   "fakeActorID",
+			ActorType: "fakeActorType",
+		}).Return(true)
+		testAPI.actor = mockActors
+		defer func() { testAPI.actor = nil }()
+
+		testTransactionalOperations := []state.TransactionalStateOperation{
+			{
+				Operation: state.Upsert,
+				Request: map[string]interface{}{
+					"key":   "fakeKey1",
+					"value": fakeBodyObject,
+				},
+			},
+			{
+				Operation: state.Upsert,
+				Request: map[string]interface{}{
+					"key":       "fakeKey2",
+					"value":     fakeBodyObject,
+					"actorType": "fakeActorType",
+					"actorId":   "fakeActorID",
+				},
+			},
+		}
+
+		// act
+		inputBodyBytes, err := json.Marshal(state.TransactionalStateRequest{
+			Operations: testTransactionalOperations,
+		})
+
+		assert.NoError(t, err)
+		resp := fakeServer.DoRequest("POST", apiPath, inputBodyBytes, nil)
+
+		// assert
+		assert.Equal(t, 204, resp.StatusCode, "Dapr should return 204")
+		mockActors.AssertNumberOfCalls(t, "GetActorStateKey", 1)
+	})
+
+	t.Run("Transaction with actor key on non actor store - 400", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/transaction", storeName)
+		mockActors := new(daprt.MockActors)
+		mockActors.On("GetActorStateKey", storeName, "fakeActorType", "fakeActorID", "fakeKey2").Return("", errors.New("state store store1 is not the actor state store"))
+		testAPI.actor = mockActors
+		defer func() { testAPI.actor = nil }()
+
+		testTransactionalOperations := []state.TransactionalStateOperation{
+			{
+				Operation: state.Delete,
+				Request: map[string]interface{}{
+					"key":       "fakeKey2",
+					"actorType": "fakeActorType",
+					"actorId":   "fakeActorID",
+				},
+			},
+		}
+
+		// act
+		inputBodyBytes, err := json.Marshal(state.TransactionalStateRequest{
+			Operations: testTransactionalOperations,
+		})
+
+		assert.NoError(t, err)
+		resp := fakeServer.DoRequest("POST", apiPath, inputBodyBytes, nil)
+
+		// assert
+		assert.Equal(t, 400, resp.StatusCode, "Dapr should return 400")
+		assert.Equal(t, "ERR_MALFORMED_REQUEST", resp.ErrorBody["errorCode"], apiPath)
+	})
+
+	t.Run("Transaction with actor key for actor not hosted - 400", func(t *testing.T) {
+		apiPath := fmt.Sprintf("v1.0/state/%s/transaction", storeName)
+		mockActors := new(daprt.MockActors)
+		mockActors.On("GetActorStateKey", storeName, "fakeActorType", "fakeActorID", "fakeKey2").Return("fakeAppID||fakeActorType||fakeActorID||fakeKey2", nil)
+		mockActors.On("IsActorHosted", &actors.ActorHostedRequest{
+			ActorID:   "fakeActorID",
+			ActorType: "fakeActorType",
+		}).Return(false)
+		testAPI.actor = mockActors
+		defer func() { testAPI.actor = nil }()
+
+		testTransactionalOperations := []state.TransactionalStateOperation{
+			{
+				Operation: state.Delete,
+				Request: map[string]interface{}{
+					"key":       "fakeKey2",
+					"actorType": "fakeActorType",
+					"actorId":   "fakeActorID",
+				},
+			},
+		}
+
+		// act
+		inputBodyBytes, err := json.Marshal(state.TransactionalStateRequest{
+			Operations: testTransactionalOperations,
+		})
+
+		assert.NoError(t, err)
+		resp := fakeServer.DoRequest("POST", apiPath, inputBodyBytes, nil)
+
+		// assert
+		assert.Equal(t, 400, resp.StatusCode, "Dapr should return 400")
+		assert.Equal(t, "ERR_ACTOR_INSTANCE_MISSING", resp.ErrorBody["errorCode"], apiPath)
+	})
+
 	fakeServer.Shutdown()
 }
 
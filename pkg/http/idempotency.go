@@ -0,0 +1,274 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/state"
+	state_loader "github.com/dapr/dapr/pkg/components/state"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultIdempotencyMaxKeys is the number of keys the in-memory store holds when no bound is configured.
+	DefaultIdempotencyMaxKeys  = 100000
+	idempotencyJanitorInterval = time.Minute
+	idempotencyStateKeyPrefix  = "idempotency"
+	idempotencyTTLMetadata     = "ttlInSeconds"
+)
+
+var (
+	errIdempotencyStoreFull   = errors.New("too many idempotency keys in flight or within the idempotency window")
+	errIdempotencyKeyMismatch = errors.New("the idempotency key was used for a different request")
+)
+
+// IdempotentResponse is the response saved for a request with an idempotency key.
+type IdempotentResponse struct {
+	StatusCode  int    `json:"statusCode"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyStore saves the responses of requests with an idempotency key,
+// so retried requests get the original response. A key is bound to the hash of the request
+// that reserved it, a request with another hash can't use the key.
+type IdempotencyStore interface {
+	// Reserve marks the key as in flight for ttl, unless the key is already known. For a known key,
+	// it returns the saved response, or nil while the request holding the key is in flight. It returns
+	// errIdempotencyKeyMismatch when the key is known for another request hash.
+	Reserve(key, requestHash string, ttl time.Duration) (bool, *IdempotentResponse, error)
+	// Set saves the response of the request holding the key for ttl.
+	Set(key, requestHash string, resp *IdempotentResponse, ttl time.Duration) error
+	// Release removes the key of a request that failed, so that the request can be retried.
+	Release(key string) error
+}
+
+type memoryIdempotencyEntry struct {
+	requestHash string
+	// resp is nil while the request is in flight.
+	resp    *IdempotentResponse
+	expires time.Time
+}
+
+type memoryIdempotencyStore struct {
+	lock    sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+	maxKeys int
+	now     func() time.Time
+}
+
+// NewMemoryIdempotencyStore returns an IdempotencyStore that keeps responses in memory.
+// It holds up to maxKeys keys, expired keys are removed periodically.
+func NewMemoryIdempotencyStore(maxKeys int) IdempotencyStore {
+	s := newMemoryIdempotencyStore(maxKeys)
+	go s.runJanitor(idempotencyJanitorInterval)
+	return s
+}
+
+func newMemoryIdempotencyStore(maxKeys int) *memoryIdempotencyStore {
+	if maxKeys <= 0 {
+		maxKeys = DefaultIdempotencyMaxKeys
+	}
+	return &memoryIdempotencyStore{
+		entries: map[string]memoryIdempotencyEntry{},
+		maxKeys: maxKeys,
+		now:     time.Now,
+	}
+}
+
+func (s *memoryIdempotencyStore) Reserve(key, requestHash string, ttl time.Duration) (bool, *IdempotentResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expires) {
+		if entry.requestHash != requestHash {
+			return false, nil, errIdempotencyKeyMismatch
+		}
+		return false, entry.resp, nil
+	}
+	if len(s.entries) >= s.maxKeys {
+		s.removeExpired(now)
+		if len(s.entries) >= s.maxKeys {
+			return false, nil, errIdempotencyStoreFull
+		}
+	}
+	s.entries[key] = memoryIdempotencyEntry{requestHash: requestHash, expires: now.Add(ttl)}
+	return true, nil, nil
+}
+
+func (s *memoryIdempotencyStore) Set(key, requestHash string, resp *IdempotentResponse, ttl time.Duration) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.entries[key] = memoryIdempotencyEntry{
+		requestHash: requestHash,
+		resp:        resp,
+		expires:     s.now().Add(ttl),
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) runJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.lock.Lock()
+		s.removeExpired(s.now())
+		s.lock.Unlock()
+	}
+}
+
+func (s *memoryIdempotencyStore) removeExpired(now time.Time) {
+	for k, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, k)
+		}
+	}
+}
+
+type stateIdempotencyEntry struct {
+	// Token identifies the request that reserved the key.
+	Token       string              `json:"token,omitempty"`
+	RequestHash string              `json:"requestHash"`
+	Expires     time.Time           `json:"expires"`
+	Response    *IdempotentResponse `json:"response,omitempty"`
+}
+
+type stateIdempotencyStore struct {
+	store state.Store
+	appID string
+	now   func() time.Time
+}
+
+// NewStateIdempotencyStore returns an IdempotencyStore that keeps responses in a state store, so that
+// they are shared by the replicas of the app. Keys are reserved with first-write concurrency and read
+// back, so the state store must support ETags.
+func NewStateIdempotencyStore(store state.Store, appID string) (IdempotencyStore, error) {
+	if !state.FeatureETag.IsPresent(store.Features()) {
+		return nil, errors.New("the state store of the idempotency keys must support ETags")
+	}
+	return &stateIdempotencyStore{
+		store: store,
+		appID: appID,
+		now:   time.Now,
+	}, nil
+}
+
+// stateKey returns the key of the idempotency key in the state store, in the keys reserved by Dapr
+// so that it can't collide with the state of the app.
+func (s *stateIdempotencyStore) stateKey(key string) string {
+	return state_loader.GetReservedStateKey(s.appID, idempotencyStateKeyPrefix, key)
+}
+
+func (s *stateIdempotencyStore) get(stateKey string) (*stateIdempotencyEntry, *string, error) {
+	resp, err := s.store.Get(&state.GetRequest{Key: stateKey})
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp == nil || len(resp.Data) == 0 {
+		return nil, nil, nil
+	}
+
+	var entry stateIdempotencyEntry
+	if err = json.Unmarshal(resp.Data, &entry); err != nil {
+		return nil, nil, errors.Wrap(err, "error decoding idempotency key")
+	}
+	return &entry, resp.ETag, nil
+}
+
+func (s *stateIdempotencyStore) Reserve(key, requestHash string, ttl time.Duration) (bool, *IdempotentResponse, error) {
+	stateKey := s.stateKey(key)
+	entry, etag, err := s.get(stateKey)
+	if err != nil {
+		return false, nil, err
+	}
+	now := s.now()
+	if entry != nil && now.Before(entry.Expires) {
+		resp, err := entry.knownResponse(requestHash)
+		return false, resp, err
+	}
+
+	// an expired entry is replaced with its ETag, so that only one request replaces it
+	token := uuid.New().String()
+	err = s.store.Set(&state.SetRequest{
+		Key:      stateKey,
+		Value:    stateIdempotencyEntry{Token: token, RequestHash: requestHash, Expires: now.Add(ttl)},
+		ETag:     etag,
+		Metadata: idempotencyTTL(ttl),
+		Options: state.SetStateOption{
+			Concurrency: "first-write",
+		},
+	})
+	if err != nil {
+		if _, ok := err.(*state.ETagError); ok {
+			// another request reserved the key first
+			return false, nil, nil
+		}
+		return false, nil, err
+	}
+
+	// state stores that don't enforce first-write on new keys keep the last write, read back the winner
+	entry, _, err = s.get(stateKey)
+	if err != nil {
+		return false, nil, err
+	}
+	if entry == nil {
+		return false, nil, nil
+	}
+	if entry.Token != token {
+		resp, err := entry.knownResponse(requestHash)
+		return false, resp, err
+	}
+	return true, nil, nil
+}
+
+// knownResponse returns the saved response of the entry of a known key for a request with the hash.
+func (e *stateIdempotencyEntry) knownResponse(requestHash string) (*IdempotentResponse, error) {
+	if e.RequestHash != requestHash {
+		return nil, errIdempotencyKeyMismatch
+	}
+	return e.Response, nil
+}
+
+func (s *stateIdempotencyStore) Set(key, requestHash string, resp *IdempotentResponse, ttl time.Duration) error {
+	return s.store.Set(&state.SetRequest{
+		Key:      s.stateKey(key),
+		Value:    stateIdempotencyEntry{RequestHash: requestHash, Expires: s.now().Add(ttl), Response: resp},
+		Metadata: idempotencyTTL(ttl),
+	})
+}
+
+func (s *stateIdempotencyStore) Release(key string) error {
+	return s.store.Delete(&state.DeleteRequest{Key: s.stateKey(key)})
+}
+
+// idempotencyTTL returns the metadata expiring the key in the state stores that support TTLs.
+func idempotencyTTL(ttl time.Duration) map[string]string {
+	seconds := int64(ttl.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return map[string]string{idempotencyTTLMetadata: strconv.FormatInt(seconds, 10)}
+}
@@ -0,0 +1,161 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	daprt "github.com/dapr/dapr/pkg/testing"
+)
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	now := time.Now()
+	store := newMemoryIdempotencyStore(2)
+	store.now = func() time.Time { return now }
+	resp := &IdempotentResponse{StatusCode: 204}
+
+	t.Run("new key is reserved", func(t *testing.T) {
+		reserved, saved, err := store.Reserve("key1", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+		assert.Nil(t, saved)
+	})
+
+	t.Run("reserved key is in flight", func(t *testing.T) {
+		reserved, saved, err := store.Reserve("key1", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Nil(t, saved)
+	})
+
+	t.Run("saved response is returned within window", func(t *testing.T) {
+		assert.NoError(t, store.Set("key1", "hash1", resp, time.Minute))
+		reserved, saved, err := store.Reserve("key1", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, resp, saved)
+	})
+
+	t.Run("key used by another request is rejected", func(t *testing.T) {
+		reserved, saved, err := store.Reserve("key1", "hash2", time.Minute)
+		assert.Equal(t, errIdempotencyKeyMismatch, err)
+		assert.False(t, reserved)
+		assert.Nil(t, saved)
+	})
+
+	t.Run("released key can be reserved again", func(t *testing.T) {
+		reserved, _, _ := store.Reserve("key2", "hash1", time.Minute)
+		assert.True(t, reserved)
+		assert.NoError(t, store.Release("key2"))
+		reserved, _, err := store.Reserve("key2", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+	})
+
+	t.Run("full store rejects new keys", func(t *testing.T) {
+		_, _, err := store.Reserve("key3", "hash1", time.Minute)
+		assert.Equal(t, errIdempotencyStoreFull, err)
+	})
+
+	t.Run("expired keys are reserved again and make room", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		reserved, saved, err := store.Reserve("key1", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+		assert.Nil(t, saved)
+
+		reserved, _, err = store.Reserve("key3", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+		assert.NotContains(t, store.entries, "key2")
+	})
+}
+
+func TestStateIdempotencyStore(t *testing.T) {
+	now := time.Now()
+	fakeStore := daprt.NewFakeETagStateStore()
+	s, err := NewStateIdempotencyStore(fakeStore, "app1")
+	assert.NoError(t, err)
+	store := s.(*stateIdempotencyStore)
+	store.now = func() time.Time { return now }
+	resp := &IdempotentResponse{StatusCode: 204}
+
+	t.Run("state store without ETags is rejected", func(t *testing.T) {
+		_, err := NewStateIdempotencyStore(&daprt.FakeETagStateStore{NoETag: true}, "app1")
+		assert.Error(t, err)
+	})
+
+	t.Run("new key is reserved with a ttl", func(t *testing.T) {
+		reserved, saved, err := store.Reserve("key1", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+		assert.Nil(t, saved)
+		assert.Contains(t, fakeStore.Items, "app1||_dapr||idempotency||key1")
+		assert.Equal(t, "60", fakeStore.Metadata["app1||_dapr||idempotency||key1"][idempotencyTTLMetadata])
+	})
+
+	t.Run("reserved key is in flight", func(t *testing.T) {
+		reserved, saved, err := store.Reserve("key1", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Nil(t, saved)
+	})
+
+	t.Run("saved response is returned within window", func(t *testing.T) {
+		assert.NoError(t, store.Set("key1", "hash1", resp, time.Minute))
+		reserved, saved, err := store.Reserve("key1", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Equal(t, resp, saved)
+	})
+
+	t.Run("key used by another request is rejected", func(t *testing.T) {
+		reserved, saved, err := store.Reserve("key1", "hash2", time.Minute)
+		assert.Equal(t, errIdempotencyKeyMismatch, err)
+		assert.False(t, reserved)
+		assert.Nil(t, saved)
+	})
+
+	t.Run("released key can be reserved again", func(t *testing.T) {
+		reserved, _, _ := store.Reserve("key2", "hash1", time.Minute)
+		assert.True(t, reserved)
+		assert.NoError(t, store.Release("key2"))
+		reserved, _, err := store.Reserve("key2", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+	})
+
+	t.Run("expired key is reserved again", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		reserved, saved, err := store.Reserve("key1", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+		assert.Nil(t, saved)
+	})
+
+	t.Run("key reserved concurrently is in flight", func(t *testing.T) {
+		fakeStore.BeforeSet = func() {
+			fakeStore.BeforeSet = nil
+			_, _, err := store.Reserve("key3", "hash1", time.Minute)
+			assert.NoError(t, err)
+		}
+		reserved, saved, err := store.Reserve("key3", "hash1", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, reserved)
+		assert.Nil(t, saved)
+	})
+
+	t.Run("state store error", func(t *testing.T) {
+		fakeStore.Err = errors.New("UPSTREAM STATE ERROR")
+		defer func() { fakeStore.Err = nil }()
+		_, _, err := store.Reserve("key4", "hash1", time.Minute)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,64 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/pprofhandler"
+)
+
+// profilingServer serves the pprof endpoints on the profile port.
+// It can be started and stopped while the runtime is running.
+type profilingServer struct {
+	port   int
+	server *fasthttp.Server
+	lock   *sync.Mutex
+}
+
+func newProfilingServer(port int) *profilingServer {
+	return &profilingServer{
+		port: port,
+		lock: &sync.Mutex{},
+	}
+}
+
+// setEnabled starts or stops the profiling server. It is a no-op if the server is already in the requested state.
+func (p *profilingServer) setEnabled(enabled bool) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if enabled == (p.server != nil) {
+		return nil
+	}
+
+	if !enabled {
+		err := p.server.Shutdown()
+		p.server = nil
+		log.Infof("stopped profiling server on port %v", p.port)
+		return err
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%v", p.port))
+	if err != nil {
+		return err
+	}
+	server := &fasthttp.Server{
+		Handler: pprofhandler.PprofHandler,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Errorf("profiling server error: %s", err)
+		}
+	}()
+	p.server = server
+
+	log.Infof("starting profiling server on port %v", p.port)
+	return nil
+}
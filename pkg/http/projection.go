@@ -0,0 +1,89 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// projectJSONFields returns a JSON object with only the given dot-separated paths of the data.
+// Paths missing from the data are omitted. Data that is not a JSON object is returned unchanged.
+// The projected values are copied as they are stored, so numbers keep their precision.
+func projectJSONFields(data []byte, fields []string) []byte {
+	if len(fields) == 0 {
+		return data
+	}
+
+	var doc map[string]jsoniter.RawMessage
+	if err := jsoniter.ConfigFastest.Unmarshal(data, &doc); err != nil || doc == nil {
+		return data
+	}
+
+	projected := map[string]interface{}{}
+	for _, field := range fields {
+		path := strings.Split(field, ".")
+		if value, ok := lookupJSONPath(doc, path); ok {
+			setJSONPath(projected, path, value)
+		}
+	}
+
+	b, err := jsoniter.ConfigFastest.Marshal(projected)
+	if err != nil {
+		return data
+	}
+	return b
+}
+
+// lookupJSONPath only decodes the objects along the path, and returns the value at its end undecoded.
+func lookupJSONPath(doc map[string]jsoniter.RawMessage, path []string) (jsoniter.RawMessage, bool) {
+	for i, p := range path {
+		value, ok := doc[p]
+		if !ok {
+			return nil, false
+		}
+		if i == len(path)-1 {
+			return value, true
+		}
+		doc = nil
+		if err := jsoniter.ConfigFastest.Unmarshal(value, &doc); err != nil || doc == nil {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func setJSONPath(doc map[string]interface{}, path []string, value jsoniter.RawMessage) {
+	for _, p := range path[:len(path)-1] {
+		if _, ok := doc[p].(jsoniter.RawMessage); ok {
+			// The whole parent is already projected.
+			return
+		}
+		next, ok := doc[p].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			doc[p] = next
+		}
+		doc = next
+	}
+	doc[path[len(path)-1]] = value
+}
+
+// getFieldsFromRequest returns the comma-separated fields query parameter of the request.
+func getFieldsFromRequest(fields string) []string {
+	if fields == "" {
+		return nil
+	}
+
+	var result []string
+	for _, f := range strings.Split(fields, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectJSONFields(t *testing.T) {
+	data := []byte(`{"name":"dapr","address":{"city":"Redmond","zip":"98052"},"tags":["a","b"]}`)
+
+	t.Run("no fields returns data unchanged", func(t *testing.T) {
+		assert.Equal(t, data, projectJSONFields(data, nil))
+	})
+
+	t.Run("top level and nested fields", func(t *testing.T) {
+		result := projectJSONFields(data, []string{"name", "address.city"})
+		assert.JSONEq(t, `{"name":"dapr","address":{"city":"Redmond"}}`, string(result))
+	})
+
+	t.Run("missing fields are omitted", func(t *testing.T) {
+		result := projectJSONFields(data, []string{"name", "address.country", "missing"})
+		assert.JSONEq(t, `{"name":"dapr"}`, string(result))
+	})
+
+	t.Run("values are copied without loss of precision", func(t *testing.T) {
+		raw := []byte(`{"id":9007199254740993,"ratio":0.123456789012345678,"other":1}`)
+		result := projectJSONFields(raw, []string{"id", "ratio"})
+		assert.Contains(t, string(result), `"id":9007199254740993`)
+		assert.Contains(t, string(result), `"ratio":0.123456789012345678`)
+		assert.NotContains(t, string(result), "other")
+	})
+
+	t.Run("parent and nested field", func(t *testing.T) {
+		result := projectJSONFields(data, []string{"address", "address.city"})
+		assert.JSONEq(t, `{"address":{"city":"Redmond","zip":"98052"}}`, string(result))
+	})
+
+	t.Run("non-object value returned unchanged", func(t *testing.T) {
+		raw := []byte(`"just a string"`)
+		assert.Equal(t, raw, projectJSONFields(raw, []string{"name"}))
+	})
+
+	t.Run("invalid json returned unchanged", func(t *testing.T) {
+		raw := []byte(`not json`)
+		assert.Equal(t, raw, projectJSONFields(raw, []string{"name"}))
+	})
+}
+
+func TestGetFieldsFromRequest(t *testing.T) {
+	assert.Nil(t, getFieldsFromRequest(""))
+	assert.Equal(t, []string{"name", "address.city"}, getFieldsFromRequest("name, address.city,"))
+}
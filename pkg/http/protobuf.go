@@ -0,0 +1,91 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"strings"
+
+	"github.com/dapr/components-contrib/state"
+	state_loader "github.com/dapr/dapr/pkg/components/state"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
+	commonv1pb "github.com/dapr/dapr/pkg/proto/common/v1"
+	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/valyala/fasthttp"
+	"google.golang.org/protobuf/proto"
+)
+
+// isProtobufRequest returns true if the request body is a protobuf message of the runtime API.
+func isProtobufRequest(reqCtx *fasthttp.RequestCtx) bool {
+	return string(reqCtx.Request.Header.ContentType()) == invokev1.ProtobufContentType
+}
+
+// wantsProtobuf returns true if the response body should be a protobuf message of the runtime API,
+// which is the case when the request accepts it or is a protobuf message itself.
+func wantsProtobuf(reqCtx *fasthttp.RequestCtx) bool {
+	accept := string(reqCtx.Request.Header.Peek("Accept"))
+	return strings.Contains(accept, invokev1.ProtobufContentType) || isProtobufRequest(reqCtx)
+}
+
+func respondWithProto(reqCtx *fasthttp.RequestCtx, code int, m proto.Message) {
+	b, err := proto.Marshal(m)
+	if err != nil {
+		msg := NewErrorResponse("ERR_MALFORMED_RESPONSE", err.Error())
+		respondWithError(reqCtx, fasthttp.StatusInternalServerError, msg)
+		return
+	}
+	reqCtx.Response.Header.SetContentType(invokev1.ProtobufContentType)
+	respond(reqCtx, code, b)
+}
+
+// stateSetRequestsFromProto converts the state items of a SaveStateRequest to state store set requests.
+func stateSetRequestsFromProto(items []*commonv1pb.StateItem) []state.SetRequest {
+	reqs := make([]state.SetRequest, 0, len(items))
+	for _, s := range items {
+		req := state.SetRequest{
+			Key:      s.Key,
+			Metadata: s.Metadata,
+			Value:    s.Value,
+		}
+		if s.Etag != nil {
+			req.ETag = &s.Etag.Value
+		}
+		if s.Options != nil {
+			req.Options = state.SetStateOption{
+				Consistency: state_loader.ConsistencyToString(s.Options.Consistency),
+				Concurrency: state_loader.ConcurrencyToString(s.Options.Concurrency),
+			}
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+// bulkGetResponseToProto converts the response of a bulk get to a GetBulkStateResponse.
+func bulkGetResponseToProto(bulkResp []BulkGetResponse) *runtimev1pb.GetBulkStateResponse {
+	resp := &runtimev1pb.GetBulkStateResponse{
+		Items: make([]*runtimev1pb.BulkStateItem, len(bulkResp)),
+	}
+	for i, r := range bulkResp {
+		item := &runtimev1pb.BulkStateItem{
+			Key:   r.Key,
+			Data:  r.Data,
+			Error: r.Error,
+		}
+		if r.ETag != nil {
+			item.Etag = *r.ETag
+		}
+		resp.Items[i] = item
+	}
+	return resp
+}
+
+func stringValueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
@@ -5,6 +5,10 @@
 
 package http
 
+import (
+	"github.com/dapr/components-contrib/state"
+)
+
 // OutputBindingRequest is the request object to invoke an output binding
 type OutputBindingRequest struct {
 	Metadata  map[string]string `json:"metadata"`
@@ -12,9 +16,30 @@ type OutputBindingRequest struct {
 	Operation string            `json:"operation"`
 }
 
+// StateTransactionRequest is the request object to execute a state transaction
+type StateTransactionRequest struct {
+	Operations []state.TransactionalStateOperation `json:"operations"`
+	Metadata   map[string]string                   `json:"metadata,omitempty"`
+	// Bindings are saved with the operations and invoked in order once they are committed.
+	Bindings []TransactionBindingRequest `json:"bindings,omitempty"`
+}
+
+// TransactionBindingRequest is an output binding invocation of a state transaction
+type TransactionBindingRequest struct {
+	Name string `json:"name"`
+	OutputBindingRequest
+}
+
 // BulkGetRequest is the request object to get a list of values for multiple keys from a state store
 type BulkGetRequest struct {
 	Metadata    map[string]string `json:"metadata"`
 	Keys        []string          `json:"keys"`
 	Parallelism int               `json:"parallelism"`
+	Fields      []string          `json:"fields,omitempty"`
+}
+
+// TransactionActorScope scopes a state transaction operation to the state of an actor.
+type TransactionActorScope struct {
+	ActorType string `mapstructure:"actorType"`
+	ActorID   string `mapstructure:"actorId"`
 }
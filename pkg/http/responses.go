@@ -25,6 +25,22 @@ type BulkGetResponse struct {
 	Error string              `json:"error,omitempty"`
 }
 
+// BulkStateOperationResponse is the result of saving or deleting one key of a bulk state operation.
+// Conflict is set when the ETag of the key doesn't match, so that only those keys are retried.
+type BulkStateOperationResponse struct {
+	Key      string `json:"key"`
+	Error    string `json:"error,omitempty"`
+	Conflict bool   `json:"conflict,omitempty"`
+}
+
+// OutputBindingResponseEnvelope is the response object of an output binding invocation when the caller
+// opts in with the dapr-binding-envelope header. Data is base64 encoded so binary payloads survive.
+type OutputBindingResponseEnvelope struct {
+	Data        []byte            `json:"data"`
+	ContentType string            `json:"contentType"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
 // respondWithJSON overrides the content-type with application/json
 func respondWithJSON(ctx *fasthttp.RequestCtx, code int, obj []byte) {
 	respond(ctx, code, obj)
@@ -41,6 +57,17 @@ func respond(ctx *fasthttp.RequestCtx, code int, obj []byte) {
 	}
 }
 
+// respondWithRawBody is like respond, but the response body refers to obj instead of a copy of it,
+// so obj must not be modified until the response is written.
+func respondWithRawBody(ctx *fasthttp.RequestCtx, code int, obj []byte) {
+	ctx.Response.SetStatusCode(code)
+	ctx.Response.SetBodyRaw(obj)
+
+	if len(ctx.Response.Header.ContentType()) == 0 {
+		ctx.Response.Header.SetContentType(jsonContentTypeHeader)
+	}
+}
+
 // respondWithETaggedJSON overrides the content-type with application/json and etag header
 func respondWithETaggedJSON(ctx *fasthttp.RequestCtx, code int, obj []byte, etag *string) {
 	respond(ctx, code, obj)
@@ -6,82 +6,152 @@
 package http
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	cors "github.com/AdhityaRamadhanus/fasthttpcors"
 	"github.com/dapr/dapr/pkg/config"
 	cors_dapr "github.com/dapr/dapr/pkg/cors"
+	"github.com/dapr/dapr/pkg/logsampler"
 	"github.com/dapr/kit/logger"
 
 	diag "github.com/dapr/dapr/pkg/diagnostics"
-	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+	"github.com/dapr/dapr/pkg/messages"
 	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
 	auth "github.com/dapr/dapr/pkg/runtime/security"
 	routing "github.com/fasthttp/router"
+	"github.com/pkg/errors"
 	"github.com/valyala/fasthttp"
-	"github.com/valyala/fasthttp/pprofhandler"
+	"go.uber.org/atomic"
 )
 
-var log = logger.NewLogger("dapr.runtime.http")
+var log = logsampler.NewLogger(logger.NewLogger("dapr.runtime.http"))
 
 // Server is an interface for the Dapr HTTP server
 type Server interface {
 	StartNonBlocking()
+	SetProfilingEnabled(enabled bool) error
+	// StopAccepting closes the listener of the server. The requests in progress complete.
+	StopAccepting()
+	// InflightRequests returns the number of requests in progress.
+	InflightRequests() int32
 }
 
 type server struct {
-	config      ServerConfig
-	tracingSpec config.TracingSpec
-	metricSpec  config.MetricSpec
-	pipeline    http_middleware.Pipeline
-	api         API
+	config            ServerConfig
+	tracingSpec       config.TracingSpec
+	metricSpec        config.MetricSpec
+	maxBodySizeSpec   config.MaxBodySizeSpec
+	idempotencyStore  IdempotencyStore
+	idempotencyWindow time.Duration
+	pipeline          http_middleware.Pipeline
+	api               API
+	profiling         *profilingServer
+	srv               *fasthttp.Server
+	inflight          atomic.Int32
+	apiToken          *auth.Token
 }
 
 // NewServer returns a new HTTP server
-func NewServer(api API, config ServerConfig, tracingSpec config.TracingSpec, metricSpec config.MetricSpec, pipeline http_middleware.Pipeline) Server {
-	return &server{
-		api:         api,
-		config:      config,
-		tracingSpec: tracingSpec,
-		metricSpec:  metricSpec,
-		pipeline:    pipeline,
+func NewServer(api API, config ServerConfig, tracingSpec config.TracingSpec, metricSpec config.MetricSpec, maxBodySizeSpec config.MaxBodySizeSpec,
+	idempotencyStore IdempotencyStore, idempotencyWindow time.Duration, pipeline http_middleware.Pipeline) (Server, error) {
+	var apiToken *auth.Token
+	if auth.APITokenConfigured() {
+		var err error
+		apiToken, err = auth.LoadAPIToken()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load api token")
+		}
 	}
+
+	return &server{
+		api:               api,
+		config:            config,
+		tracingSpec:       tracingSpec,
+		metricSpec:        metricSpec,
+		maxBodySizeSpec:   maxBodySizeSpec,
+		idempotencyStore:  idempotencyStore,
+		idempotencyWindow: idempotencyWindow,
+		pipeline:          pipeline,
+		profiling:         newProfilingServer(config.ProfilePort),
+		apiToken:          apiToken,
+	}, nil
 }
 
 // StartNonBlocking starts a new server in a goroutine
 func (s *server) StartNonBlocking() {
 	handler :=
-		useAPIAuthentication(
+		s.useAPIAuthentication(
 			s.useCors(
-				s.useComponents(
-					s.useRouter())))
+				s.useMaxBodySize(
+					s.useComponents(
+						s.useRouter()))))
 
 	handler = s.useMetrics(handler)
 	handler = s.useTracing(handler)
+	handler = s.useInflight(handler)
 
 	customServer := &fasthttp.Server{
 		Handler:            handler,
 		MaxRequestBodySize: s.config.MaxRequestBodySize * 1024 * 1024,
 	}
+	s.srv = customServer
 
 	go func() {
-		log.Fatal(customServer.ListenAndServe(fmt.Sprintf(":%v", s.config.Port)))
+		// the server returns nil when it is shut down
+		if err := customServer.ListenAndServe(fmt.Sprintf(":%v", s.config.Port)); err != nil {
+			log.Fatal(err)
+		}
 	}()
 
 	if s.config.EnableProfiling {
-		go func() {
-			log.Infof("starting profiling server on port %v", s.config.ProfilePort)
-			log.Fatal(fasthttp.ListenAndServe(fmt.Sprintf(":%v", s.config.ProfilePort), pprofhandler.PprofHandler))
-		}()
+		if err := s.profiling.setEnabled(true); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// StopAccepting closes the listener of the server. The shutdown of the server then waits for
+// the open connections to be idle, so that the requests in progress complete.
+func (s *server) StopAccepting() {
+	if s.srv == nil {
+		return
 	}
+	log.Info("stopping the http server")
+	go func() {
+		if err := s.srv.Shutdown(); err != nil {
+			log.Warnf("error stopping the http server: %s", err)
+		}
+	}()
+}
+
+// InflightRequests returns the number of requests in progress.
+func (s *server) InflightRequests() int32 {
+	return s.inflight.Load()
+}
+
+// useInflight counts the requests in progress, which the shutdown waits for.
+func (s *server) useInflight(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		s.inflight.Inc()
+		defer s.inflight.Dec()
+		next(ctx)
+	}
+}
+
+// SetProfilingEnabled starts or stops the profiling server.
+func (s *server) SetProfilingEnabled(enabled bool) error {
+	return s.profiling.setEnabled(enabled)
 }
 
 func (s *server) useTracing(next fasthttp.RequestHandler) fasthttp.RequestHandler {
-	if diag_utils.IsTracingEnabled(s.tracingSpec.SamplingRate) {
+	if diag.IsTracingEnabled(s.tracingSpec) {
 		log.Infof("enabled tracing http middleware")
 		return diag.HTTPTraceMiddleware(next, s.config.AppID, s.tracingSpec)
 	}
@@ -117,16 +187,18 @@ func (s *server) useCors(next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return corsHandler.CorsMiddleware(next)
 }
 
-func useAPIAuthentication(next fasthttp.RequestHandler) fasthttp.RequestHandler {
-	token := auth.GetAPIToken()
-	if token == "" {
+// useAPIAuthentication checks the api token when it is configured, even before a token file
+// holds a token, so that the token is checked once it is written.
+func (s *server) useAPIAuthentication(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	token := s.apiToken
+	if token == nil {
 		return next
 	}
 	log.Info("enabled token authentication on http server")
 
 	return func(ctx *fasthttp.RequestCtx) {
 		v := ctx.Request.Header.Peek(auth.APITokenHeader)
-		if auth.ExcludedRoute(string(ctx.Request.URI().FullURI())) || string(v) == token {
+		if auth.ExcludedRoute(string(ctx.Request.URI().FullURI())) || token.Accepts(string(v)) {
 			ctx.Request.Header.Del(auth.APITokenHeader)
 			next(ctx)
 		} else {
@@ -175,14 +247,137 @@ func (s *server) getRouter(endpoints []Endpoint) *routing.Router {
 	parameterFinder, _ := regexp.Compile("/{.*}")
 	for _, e := range endpoints {
 		path := fmt.Sprintf("/%s/%s", e.Version, e.Route)
+		handler := s.useIdempotency(e, e.Handler)
 		for _, m := range e.Methods {
 			pathIncludesParameters := parameterFinder.MatchString(path)
 			if pathIncludesParameters {
-				router.Handle(m, path, s.unescapeRequestParametersHandler(e.Handler))
+				router.Handle(m, path, s.unescapeRequestParametersHandler(handler))
 			} else {
-				router.Handle(m, path, e.Handler)
+				router.Handle(m, path, handler)
 			}
 		}
 	}
 	return router
 }
+
+// useMaxBodySize rejects requests with a body larger than the maximum body size of their endpoint group,
+// before the middleware pipeline handles them. The server reads bodies up to the global maximum request
+// body size, so the group sizes can only lower it.
+func (s *server) useMaxBodySize(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	for group, size := range map[string]int{
+		"state":      s.maxBodySizeSpec.State,
+		"pubsub":     s.maxBodySizeSpec.PubSub,
+		"invocation": s.maxBodySizeSpec.Invocation,
+		"bindings":   s.maxBodySizeSpec.Bindings,
+	} {
+		if size > s.config.MaxRequestBodySize {
+			log.Warnf("the maximum body size of %d MB of the %s endpoints exceeds the maximum request body size of %d MB, which applies instead",
+				size, group, s.config.MaxRequestBodySize)
+		}
+	}
+
+	return func(ctx *fasthttp.RequestCtx) {
+		maxBodySize := s.routeMaxBodySize(string(ctx.Path())) * 1024 * 1024
+		bodySize := ctx.Request.Header.ContentLength()
+		if bodySize <= maxBodySize {
+			bodySize = len(ctx.Request.Body())
+		}
+		if bodySize > maxBodySize {
+			msg := NewErrorResponse("ERR_REQUEST_TOO_LARGE", fmt.Sprintf(messages.ErrRequestTooLarge, bodySize, maxBodySize))
+			respondWithError(ctx, fasthttp.StatusRequestEntityTooLarge, msg)
+			log.Debug(msg)
+			return
+		}
+		next(ctx)
+	}
+}
+
+// useIdempotency replays the response of publish and output binding requests that were already
+// handled with the same Idempotency-Key header within the idempotency window. The key is reserved
+// before the request is handled, so a retry received while the request is in flight is rejected.
+// The key is bound to the hash of the request body, a request reusing it with another body is rejected.
+func (s *server) useIdempotency(e Endpoint, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	if s.idempotencyStore == nil || s.idempotencyWindow <= 0 ||
+		!(strings.HasPrefix(e.Route, "publish/") || strings.HasPrefix(e.Route, "bindings/")) {
+		return next
+	}
+
+	return func(ctx *fasthttp.RequestCtx) {
+		idempotencyKey := string(ctx.Request.Header.Peek(idempotencyKeyHeader))
+		if idempotencyKey == "" {
+			next(ctx)
+			return
+		}
+
+		key := fmt.Sprintf("%s|%s|%s", ctx.Method(), ctx.Path(), idempotencyKey)
+		bodyHash := sha256.Sum256(ctx.PostBody())
+		requestHash := hex.EncodeToString(bodyHash[:])
+		reserved, resp, err := s.idempotencyStore.Reserve(key, requestHash, s.idempotencyWindow)
+		if err == errIdempotencyKeyMismatch {
+			msg := NewErrorResponse("ERR_IDEMPOTENCY_KEY_MISMATCH", messages.ErrIdempotencyKeyMismatch)
+			respondWithError(ctx, fasthttp.StatusUnprocessableEntity, msg)
+			log.Debug(msg)
+			return
+		}
+		if err != nil {
+			msg := NewErrorResponse("ERR_IDEMPOTENCY_STORE", fmt.Sprintf(messages.ErrIdempotencyStore, err))
+			respondWithError(ctx, fasthttp.StatusInternalServerError, msg)
+			log.Debug(msg)
+			return
+		}
+		if !reserved {
+			if resp == nil {
+				msg := NewErrorResponse("ERR_IDEMPOTENCY_KEY_IN_FLIGHT", messages.ErrIdempotencyKeyInFlight)
+				respondWithError(ctx, fasthttp.StatusConflict, msg)
+				log.Debug(msg)
+				return
+			}
+			if resp.ContentType != "" {
+				ctx.Response.Header.SetContentType(resp.ContentType)
+			}
+			ctx.Response.Header.Set(idempotentReplayedHeader, "true")
+			ctx.Response.SetStatusCode(resp.StatusCode)
+			ctx.Response.SetBody(resp.Body)
+			return
+		}
+
+		next(ctx)
+
+		// Only successful responses are saved so that failed requests can be retried.
+		if statusCode := ctx.Response.StatusCode(); statusCode >= 200 && statusCode < 300 {
+			err = s.idempotencyStore.Set(key, requestHash, &IdempotentResponse{
+				StatusCode:  statusCode,
+				ContentType: string(ctx.Response.Header.ContentType()),
+				Body:        append([]byte(nil), ctx.Response.Body()...),
+			}, s.idempotencyWindow)
+		} else {
+			err = s.idempotencyStore.Release(key)
+		}
+		if err != nil {
+			log.Warnf("error saving the idempotency key %s: %s", idempotencyKey, err)
+		}
+	}
+}
+
+// routeMaxBodySize returns the maximum body size in MB of the endpoint group a request path belongs to.
+// Paths are /<version>/<route>, and the group is the first segment of the route.
+func (s *server) routeMaxBodySize(path string) int {
+	var size int
+	segments := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(segments) > 1 {
+		switch segments[1] {
+		case "state":
+			size = s.maxBodySizeSpec.State
+		case "publish":
+			size = s.maxBodySizeSpec.PubSub
+		case "invoke":
+			size = s.maxBodySizeSpec.Invocation
+		case "bindings":
+			size = s.maxBodySizeSpec.Bindings
+		}
+	}
+	if size <= 0 || size > s.config.MaxRequestBodySize {
+		return s.config.MaxRequestBodySize
+	}
+	return size
+}
@@ -7,10 +7,14 @@ package http
 
 import (
 	"fmt"
+	"net"
 	"runtime"
 	"testing"
+	"time"
 
+	"github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/cors"
+	auth "github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 )
@@ -62,6 +66,59 @@ func TestCorsHandler(t *testing.T) {
 		assert.True(t, mh.hasCORS)
 	})
 }
+
+func TestInflightHandler(t *testing.T) {
+	srv := newServer()
+	var inflight int32
+	h := srv.useInflight(func(ctx *fasthttp.RequestCtx) {
+		inflight = srv.InflightRequests()
+	})
+	h(&fasthttp.RequestCtx{})
+
+	assert.Equal(t, int32(1), inflight)
+	assert.Equal(t, int32(0), srv.InflightRequests())
+}
+
+func TestAPIAuthenticationHandler(t *testing.T) {
+	called := false
+	next := func(ctx *fasthttp.RequestCtx) {
+		called = true
+	}
+	newRequest := func(token string) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI("/v1.0/state/store1")
+		if token != "" {
+			ctx.Request.Header.Set(auth.APITokenHeader, token)
+		}
+		return ctx
+	}
+
+	t.Run("no token configured", func(t *testing.T) {
+		called = false
+		srv := newServer()
+		srv.useAPIAuthentication(next)(newRequest(""))
+		assert.True(t, called)
+	})
+
+	t.Run("token file without a token rejects requests", func(t *testing.T) {
+		called = false
+		srv := newServer()
+		srv.apiToken = auth.NewToken("")
+		ctx := newRequest("")
+		srv.useAPIAuthentication(next)(ctx)
+		assert.False(t, called)
+		assert.Equal(t, fasthttp.StatusUnauthorized, ctx.Response.StatusCode())
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		called = false
+		srv := newServer()
+		srv.apiToken = auth.NewToken("token1")
+		srv.useAPIAuthentication(next)(newRequest("token1"))
+		assert.True(t, called)
+	})
+}
+
 func TestUnescapeRequestParametersHandler(t *testing.T) {
 	mh := func(reqCtx *fasthttp.RequestCtx) {
 		pc, _, _, ok := runtime.Caller(1)
@@ -219,3 +276,172 @@ func TestUnescapeRequestParametersHandler(t *testing.T) {
 		}
 	})
 }
+
+func TestMaxBodySize(t *testing.T) {
+	handler := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusOK)
+	}
+	srv := newServer()
+	srv.config.MaxRequestBodySize = 2
+	srv.maxBodySizeSpec = config.MaxBodySizeSpec{State: 1, Bindings: 4}
+	h := srv.useMaxBodySize(handler)
+
+	t.Run("endpoint group with override rejects larger bodies", func(t *testing.T) {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI("/v1.0/state/store1")
+		ctx.Request.SetBody(make([]byte, 1024*1024+1))
+		h(ctx)
+		assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, ctx.Response.StatusCode())
+		assert.Contains(t, string(ctx.Response.Body()), "ERR_REQUEST_TOO_LARGE")
+	})
+
+	t.Run("endpoint group with override rejects larger content length", func(t *testing.T) {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI("/v1.0/state/store1")
+		ctx.Request.Header.SetContentLength(1024*1024 + 1)
+		h(ctx)
+		assert.Equal(t, fasthttp.StatusRequestEntityTooLarge, ctx.Response.StatusCode())
+	})
+
+	t.Run("endpoint group with override accepts smaller bodies", func(t *testing.T) {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI("/v1.0/state/store1")
+		ctx.Request.SetBody(make([]byte, 1024))
+		h(ctx)
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	})
+
+	t.Run("endpoint group without override accepts bodies up to the global size", func(t *testing.T) {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI("/v1.0/publish/pubsub1/topic1")
+		ctx.Request.SetBody(make([]byte, 1024*1024+1))
+		h(ctx)
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	})
+
+	t.Run("override larger than the global size is bounded by it", func(t *testing.T) {
+		assert.Equal(t, 2, srv.routeMaxBodySize("/v1.0/bindings/binding1"))
+		assert.Equal(t, 1, srv.routeMaxBodySize("/v1.0/state/store1/transaction"))
+		assert.Equal(t, 2, srv.routeMaxBodySize("/v1.0-alpha1/state/store1/query"))
+		assert.Equal(t, 2, srv.routeMaxBodySize("/v1.0/actors/type1/id1/state"))
+	})
+}
+
+func TestIdempotency(t *testing.T) {
+	calls := 0
+	handler := func(ctx *fasthttp.RequestCtx) {
+		calls++
+		if string(ctx.Request.Header.Peek("fail")) == "true" {
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			return
+		}
+		ctx.SetStatusCode(fasthttp.StatusOK)
+		ctx.SetBodyString(fmt.Sprintf("call %d", calls))
+	}
+	srv := newServer()
+	srv.idempotencyStore = newMemoryIdempotencyStore(0)
+	srv.idempotencyWindow = time.Minute
+
+	newRequest := func(idempotencyKey string) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+		ctx.Request.SetRequestURI("/v1.0/bindings/mybinding")
+		if idempotencyKey != "" {
+			ctx.Request.Header.Set(idempotencyKeyHeader, idempotencyKey)
+		}
+		return ctx
+	}
+
+	h := srv.useIdempotency(Endpoint{Route: "bindings/{name}"}, handler)
+
+	t.Run("retried request gets the original response", func(t *testing.T) {
+		calls = 0
+		ctx := newRequest("key1")
+		h(ctx)
+		assert.Equal(t, "call 1", string(ctx.Response.Body()))
+
+		ctx = newRequest("key1")
+		h(ctx)
+		assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+		assert.Equal(t, "call 1", string(ctx.Response.Body()))
+		assert.Equal(t, "true", string(ctx.Response.Header.Peek(idempotentReplayedHeader)))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("key reused with another body is rejected", func(t *testing.T) {
+		calls = 0
+		ctx := newRequest("key5")
+		ctx.Request.SetBodyString(`{"data":"1"}`)
+		h(ctx)
+
+		ctx = newRequest("key5")
+		ctx.Request.SetBodyString(`{"data":"2"}`)
+		h(ctx)
+		assert.Equal(t, fasthttp.StatusUnprocessableEntity, ctx.Response.StatusCode())
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("requests without idempotency key are not deduplicated", func(t *testing.T) {
+		calls = 0
+		h(newRequest(""))
+		h(newRequest(""))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("failed responses are not saved", func(t *testing.T) {
+		calls = 0
+		ctx := newRequest("key2")
+		ctx.Request.Header.Set("fail", "true")
+		h(ctx)
+		h(newRequest("key2"))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("request with a key in flight is rejected", func(t *testing.T) {
+		calls = 0
+		inFlight := srv.useIdempotency(Endpoint{Route: "bindings/{name}"}, func(ctx *fasthttp.RequestCtx) {
+			retry := newRequest("key4")
+			h(retry)
+			assert.Equal(t, fasthttp.StatusConflict, retry.Response.StatusCode())
+			ctx.SetStatusCode(fasthttp.StatusOK)
+		})
+		inFlight(newRequest("key4"))
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("other endpoints are not deduplicated", func(t *testing.T) {
+		calls = 0
+		stateHandler := srv.useIdempotency(Endpoint{Route: "state/{storeName}"}, handler)
+		stateHandler(newRequest("key3"))
+		stateHandler(newRequest("key3"))
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestProfilingServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	p := newProfilingServer(port)
+	url := fmt.Sprintf("http://127.0.0.1:%d/debug/pprof/", port)
+
+	t.Run("enable", func(t *testing.T) {
+		assert.NoError(t, p.setEnabled(true))
+		// enabling a running server is a no-op
+		assert.NoError(t, p.setEnabled(true))
+
+		statusCode, _, err := fasthttp.Get(nil, url)
+		assert.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, statusCode)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		assert.NoError(t, p.setEnabled(false))
+		assert.NoError(t, p.setEnabled(false))
+
+		_, _, err := fasthttp.Get(nil, url)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,398 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/state"
+	state_loader "github.com/dapr/dapr/pkg/components/state"
+)
+
+const (
+	transactionOutboxKeyPrefix = "outbox"
+	// transactionOutboxMaxElapsedTime bounds the retries of an output binding invocation of the outbox.
+	transactionOutboxMaxElapsedTime = 5 * time.Minute
+	// transactionOutboxRecoveryInterval is how often the outboxes are swept for the invocations
+	// that were not dispatched, because the dispatch failed or daprd stopped.
+	transactionOutboxRecoveryInterval = time.Minute
+	// transactionOutboxRecoveryAge is how old an invocation must be to be recovered. The records of a transaction
+	// are leased to the replica that executed it for that long, which is longer than the retries of the dispatch.
+	transactionOutboxRecoveryAge = 2 * transactionOutboxMaxElapsedTime
+	// transactionOutboxLeaseDuration is how long a record is leased to the replica recovering it.
+	transactionOutboxLeaseDuration = transactionOutboxMaxElapsedTime
+	// transactionOutboxIndexShards is the number of keys the outbox index is split into, so that
+	// concurrent transactions seldom update the same key.
+	transactionOutboxIndexShards = 16
+	// transactionOutboxIndexAttempts bounds the concurrent updates of an outbox index shard a request retries.
+	transactionOutboxIndexAttempts = 10
+)
+
+var errTransactionOutboxIndexConflict = errors.New("too many concurrent updates of the transaction outbox index")
+
+// transactionOutboxRecord is an output binding invocation saved in the same state transaction
+// as the operations it belongs to. The record is leased to the replica invoking it, and the other
+// replicas skip it until the lease expires.
+type transactionOutboxRecord struct {
+	Name       string            `json:"name"`
+	Operation  string            `json:"operation"`
+	Data       []byte            `json:"data,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	LeaseUntil time.Time         `json:"leaseUntil"`
+}
+
+// transactionOutboxEntry lists an outbox record in the outbox index, as state stores can't list their keys.
+type transactionOutboxEntry struct {
+	Key         string    `json:"key"`
+	Transaction string    `json:"transaction"`
+	Created     time.Time `json:"created"`
+}
+
+// transactionOutboxIndex lists the outbox records of a shard of a state store, in the order of their transactions.
+type transactionOutboxIndex struct {
+	Entries []transactionOutboxEntry `json:"entries"`
+}
+
+// outputBindingExists returns whether a binding component with the name is loaded.
+func (a *api) outputBindingExists(name string) bool {
+	if a.getComponentsFn == nil {
+		return false
+	}
+	for _, c := range a.getComponentsFn() {
+		if c.Name == name && strings.HasPrefix(c.Spec.Type, "bindings.") {
+			return true
+		}
+	}
+	return false
+}
+
+// transactionOutboxKey returns the key of a new outbox record. The outbox keys are reserved keys,
+// which the state API rejects, so that apps can't overwrite them.
+func (a *api) transactionOutboxKey() string {
+	return state_loader.GetReservedStateKey(a.id, transactionOutboxKeyPrefix, uuid.New().String())
+}
+
+func (a *api) transactionOutboxIndexKey(shard int) string {
+	return state_loader.GetReservedStateKey(a.id, transactionOutboxKeyPrefix, "index", strconv.Itoa(shard))
+}
+
+// transactionOutboxShard returns the index shard listing the outbox records of a transaction.
+func transactionOutboxShard(transaction string) int {
+	h := fnv.New32a()
+	h.Write([]byte(transaction)) // nolint:errcheck
+	return int(h.Sum32() % transactionOutboxIndexShards)
+}
+
+// newTransactionOutboxEntries returns the index entries of the outbox records of a transaction.
+func newTransactionOutboxEntries(keys []string) []transactionOutboxEntry {
+	transaction := uuid.New().String()
+	created := time.Now().UTC()
+	entries := make([]transactionOutboxEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, transactionOutboxEntry{Key: key, Transaction: transaction, Created: created})
+	}
+	return entries
+}
+
+// shardTransactionOutboxEntries groups index entries by the shard of their transaction.
+func shardTransactionOutboxEntries(entries []transactionOutboxEntry) map[int][]transactionOutboxEntry {
+	shards := map[int][]transactionOutboxEntry{}
+	for _, e := range entries {
+		shard := transactionOutboxShard(e.Transaction)
+		shards[shard] = append(shards[shard], e)
+	}
+	return shards
+}
+
+func (a *api) getTransactionOutboxIndex(store state.Store, shard int) (*transactionOutboxIndex, *string, error) {
+	resp, err := store.Get(&state.GetRequest{Key: a.transactionOutboxIndexKey(shard)})
+	if err != nil {
+		return nil, nil, err
+	}
+	index := &transactionOutboxIndex{}
+	if resp == nil || len(resp.Data) == 0 {
+		return index, nil, nil
+	}
+	if err = json.Unmarshal(resp.Data, index); err != nil {
+		return nil, nil, errors.Wrap(err, "error decoding the transaction outbox index")
+	}
+	return index, resp.ETag, nil
+}
+
+// updateTransactionOutboxIndex applies update to a shard of the outbox index with first-write concurrency, and
+// retries when another request updated it first. The shard is read back, as state stores that don't enforce
+// first-write on new keys keep the last write.
+func (a *api) updateTransactionOutboxIndex(store state.Store, shard int, update func(index *transactionOutboxIndex), applied func(index *transactionOutboxIndex) bool) error {
+	for attempt := 0; attempt < transactionOutboxIndexAttempts; attempt++ {
+		index, etag, err := a.getTransactionOutboxIndex(store, shard)
+		if err != nil {
+			return err
+		}
+		update(index)
+		err = store.Set(&state.SetRequest{
+			Key:   a.transactionOutboxIndexKey(shard),
+			Value: index,
+			ETag:  etag,
+			Options: state.SetStateOption{
+				Concurrency: "first-write",
+			},
+		})
+		if err != nil {
+			if _, ok := err.(*state.ETagError); ok {
+				continue
+			}
+			return err
+		}
+
+		index, _, err = a.getTransactionOutboxIndex(store, shard)
+		if err != nil {
+			return err
+		}
+		if applied(index) {
+			return nil
+		}
+	}
+	return errTransactionOutboxIndexConflict
+}
+
+// addTransactionOutboxEntries lists the outbox records of a transaction in the outbox index before the
+// transaction is executed, so that they are recovered if the dispatch after the commit doesn't complete.
+func (a *api) addTransactionOutboxEntries(store state.Store, entries []transactionOutboxEntry) error {
+	for shard, shardEntries := range shardTransactionOutboxEntries(entries) {
+		shardEntries := shardEntries
+		err := a.updateTransactionOutboxIndex(store, shard, func(index *transactionOutboxIndex) {
+			index.Entries = append(index.Entries, shardEntries...)
+		}, func(index *transactionOutboxIndex) bool {
+			return containsTransactionOutboxEntries(index, shardEntries)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeTransactionOutboxEntries removes the entries of the records that were dispatched or never committed.
+func (a *api) removeTransactionOutboxEntries(store state.Store, entries []transactionOutboxEntry) {
+	for shard, shardEntries := range shardTransactionOutboxEntries(entries) {
+		removed := make(map[string]bool, len(shardEntries))
+		for _, e := range shardEntries {
+			removed[e.Key] = true
+		}
+		err := a.updateTransactionOutboxIndex(store, shard, func(index *transactionOutboxIndex) {
+			kept := index.Entries[:0]
+			for _, e := range index.Entries {
+				if !removed[e.Key] {
+					kept = append(kept, e)
+				}
+			}
+			index.Entries = kept
+		}, func(index *transactionOutboxIndex) bool {
+			for _, e := range index.Entries {
+				if removed[e.Key] {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			// the next recovery sweep removes them
+			log.Warnf("error removing %d entries from the transaction outbox index: %s", len(shardEntries), err)
+		}
+	}
+}
+
+func containsTransactionOutboxEntries(index *transactionOutboxIndex, entries []transactionOutboxEntry) bool {
+	keys := make(map[string]bool, len(index.Entries))
+	for _, e := range index.Entries {
+		keys[e.Key] = true
+	}
+	for _, e := range entries {
+		if !keys[e.Key] {
+			return false
+		}
+	}
+	return true
+}
+
+// dispatchTransactionOutbox invokes the output bindings of a committed state transaction in order,
+// and deletes each record once its binding is invoked. The records are leased to this replica until
+// they are old enough to be recovered. An invocation is retried with an exponential backoff. When it
+// keeps failing, it and the invocations after it are left in the outbox, so that they are never invoked
+// out of order, and they are retried by the recovery sweep.
+func (a *api) dispatchTransactionOutbox(store state.Store, entries []transactionOutboxEntry, records []transactionOutboxRecord) {
+	dispatched := make([]transactionOutboxEntry, 0, len(entries))
+	defer func() {
+		if len(dispatched) > 0 {
+			a.removeTransactionOutboxEntries(store, dispatched)
+		}
+	}()
+
+	for i, record := range records {
+		bo := backoff.NewExponentialBackOff()
+		bo.MaxElapsedTime = transactionOutboxMaxElapsedTime
+		err := backoff.Retry(func() error {
+			return a.invokeTransactionOutboxRecord(record)
+		}, bo)
+		if err != nil {
+			log.Errorf("error invoking output binding %s of a state transaction, %d invocations are left in the outbox for recovery: %s",
+				record.Name, len(records)-i, err)
+			return
+		}
+
+		if err = store.Delete(&state.DeleteRequest{Key: entries[i].Key}); err != nil {
+			log.Warnf("error deleting the outbox record %s of output binding %s: %s", entries[i].Key, record.Name, err)
+			continue
+		}
+		dispatched = append(dispatched, entries[i])
+	}
+}
+
+func (a *api) invokeTransactionOutboxRecord(record transactionOutboxRecord) error {
+	_, err := a.sendToOutputBindingFn(record.Name, &bindings.InvokeRequest{
+		Data:      record.Data,
+		Metadata:  record.Metadata,
+		Operation: bindings.OperationKind(record.Operation),
+	})
+	return err
+}
+
+// StartTransactionOutboxRecovery sweeps the outboxes of the transactional state stores now and then
+// periodically, and invokes the output bindings of the committed transactions that were not dispatched.
+// The sweeps stop when stopCh is closed.
+func (a *api) StartTransactionOutboxRecovery(stopCh <-chan struct{}) {
+	go func() {
+		a.recoverTransactionOutboxes()
+
+		ticker := time.NewTicker(transactionOutboxRecoveryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				a.recoverTransactionOutboxes()
+			}
+		}
+	}()
+}
+
+func (a *api) recoverTransactionOutboxes() {
+	for name, store := range a.stateStores {
+		if _, ok := a.transactionalStateStores[name]; !ok || !state.FeatureETag.IsPresent(store.Features()) {
+			continue
+		}
+		if err := a.recoverTransactionOutbox(store, time.Now().UTC()); err != nil {
+			log.Warnf("error recovering the transaction outbox of state store %s: %s", name, err)
+		}
+	}
+}
+
+// recoverTransactionOutbox invokes the output bindings of the outbox records older than the recovery age,
+// shard by shard of the outbox index.
+func (a *api) recoverTransactionOutbox(store state.Store, now time.Time) error {
+	for shard := 0; shard < transactionOutboxIndexShards; shard++ {
+		if err := a.recoverTransactionOutboxShard(store, shard, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recoverTransactionOutboxShard invokes the output bindings of the outbox records of a shard older than the
+// recovery age. Each record is leased to this replica before it is invoked, so that the replicas sweeping the
+// same outbox don't invoke it too. A record that is missing was either dispatched or belongs to a transaction
+// that didn't commit, and only its index entry is removed. The invocations of a transaction stop at the first
+// record that fails or is leased to another replica, and are retried by the next sweep. A binding is invoked
+// again only when the lease of the replica invoking it expired before it removed the record, so the delivery
+// is at least once.
+func (a *api) recoverTransactionOutboxShard(store state.Store, shard int, now time.Time) error {
+	index, _, err := a.getTransactionOutboxIndex(store, shard)
+	if err != nil {
+		return err
+	}
+
+	var done []transactionOutboxEntry
+	skipped := map[string]bool{}
+	for _, e := range index.Entries {
+		if skipped[e.Transaction] || now.Sub(e.Created) < transactionOutboxRecoveryAge {
+			continue
+		}
+
+		resp, err := store.Get(&state.GetRequest{Key: e.Key})
+		if err != nil {
+			skipped[e.Transaction] = true
+			log.Warnf("error reading the outbox record %s: %s", e.Key, err)
+			continue
+		}
+		if resp == nil || len(resp.Data) == 0 {
+			done = append(done, e)
+			continue
+		}
+
+		var record transactionOutboxRecord
+		if err = json.Unmarshal(resp.Data, &record); err != nil {
+			// a record that can't be decoded can never be invoked
+			log.Errorf("error decoding the outbox record %s, dropping it: %s", e.Key, err)
+		} else {
+			if !a.leaseTransactionOutboxRecord(store, e.Key, record, resp.ETag, now) {
+				skipped[e.Transaction] = true
+				continue
+			}
+			if err = a.invokeTransactionOutboxRecord(record); err != nil {
+				skipped[e.Transaction] = true
+				log.Warnf("error invoking output binding %s of the outbox record %s: %s", record.Name, e.Key, err)
+				continue
+			}
+		}
+
+		if err = store.Delete(&state.DeleteRequest{Key: e.Key}); err != nil {
+			log.Warnf("error deleting the outbox record %s: %s", e.Key, err)
+			continue
+		}
+		done = append(done, e)
+	}
+
+	if len(done) > 0 {
+		a.removeTransactionOutboxEntries(store, done)
+	}
+	return nil
+}
+
+// leaseTransactionOutboxRecord leases a record to this replica before it is invoked. The lease is saved with
+// the ETag the record was read with, so that a single replica gets it. It returns false when the record is
+// leased to another replica, or another replica leased it first.
+func (a *api) leaseTransactionOutboxRecord(store state.Store, key string, record transactionOutboxRecord, etag *string, now time.Time) bool {
+	if now.Before(record.LeaseUntil) {
+		return false
+	}
+
+	record.LeaseUntil = now.Add(transactionOutboxLeaseDuration)
+	err := store.Set(&state.SetRequest{
+		Key:   key,
+		Value: record,
+		ETag:  etag,
+		Options: state.SetStateOption{
+			Concurrency: "first-write",
+		},
+	})
+	if err != nil {
+		if _, ok := err.(*state.ETagError); !ok {
+			log.Warnf("error leasing the outbox record %s: %s", key, err)
+		}
+		return false
+	}
+	return true
+}
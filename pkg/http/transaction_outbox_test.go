@@ -0,0 +1,234 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/bindings"
+	"github.com/dapr/components-contrib/state"
+	daprt "github.com/dapr/dapr/pkg/testing"
+)
+
+// allTransactionOutboxEntries returns the entries of all the shards of the outbox index.
+func allTransactionOutboxEntries(t *testing.T, a *api, store state.Store) []transactionOutboxEntry {
+	var entries []transactionOutboxEntry
+	for shard := 0; shard < transactionOutboxIndexShards; shard++ {
+		index, _, err := a.getTransactionOutboxIndex(store, shard)
+		assert.NoError(t, err)
+		if index != nil {
+			entries = append(entries, index.Entries...)
+		}
+	}
+	return entries
+}
+
+func transactionOutboxEntryKeys(entries []transactionOutboxEntry) []string {
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
+
+func TestTransactionOutboxKeys(t *testing.T) {
+	testAPI := &api{id: "app1"}
+
+	assert.True(t, strings.HasPrefix(testAPI.transactionOutboxKey(), "app1||_dapr||outbox||"))
+	assert.Equal(t, "app1||_dapr||outbox||index||3", testAPI.transactionOutboxIndexKey(3))
+
+	shards := map[int]bool{}
+	for i := 0; i < 100; i++ {
+		shard := transactionOutboxShard(fmt.Sprintf("tx%d", i))
+		assert.True(t, shard >= 0 && shard < transactionOutboxIndexShards)
+		assert.Equal(t, shard, transactionOutboxShard(fmt.Sprintf("tx%d", i)))
+		shards[shard] = true
+	}
+	assert.Greater(t, len(shards), 1, "transactions are spread over the index shards")
+}
+
+func TestTransactionOutboxIndex(t *testing.T) {
+	fakeStore := daprt.NewFakeETagStateStore()
+	testAPI := &api{id: "app1"}
+	entries := newTransactionOutboxEntries([]string{"key1", "key2"})
+	shard := transactionOutboxShard(entries[0].Transaction)
+
+	t.Run("entries are added to the shard of their transaction", func(t *testing.T) {
+		assert.NoError(t, testAPI.addTransactionOutboxEntries(fakeStore, entries))
+
+		index, _, err := testAPI.getTransactionOutboxIndex(fakeStore, shard)
+		assert.NoError(t, err)
+		assert.Equal(t, entries, index.Entries)
+		assert.Equal(t, entries, allTransactionOutboxEntries(t, testAPI, fakeStore))
+	})
+
+	t.Run("concurrent update is retried", func(t *testing.T) {
+		// the entries of the same transaction are in the same shard
+		concurrent := []transactionOutboxEntry{{Key: "key3", Transaction: entries[0].Transaction, Created: entries[0].Created}}
+		fakeStore.BeforeSet = func() {
+			fakeStore.BeforeSet = nil
+			assert.NoError(t, testAPI.addTransactionOutboxEntries(fakeStore, concurrent))
+		}
+		more := []transactionOutboxEntry{{Key: "key4", Transaction: entries[0].Transaction, Created: entries[0].Created}}
+		assert.NoError(t, testAPI.addTransactionOutboxEntries(fakeStore, more))
+
+		index, _, err := testAPI.getTransactionOutboxIndex(fakeStore, shard)
+		assert.NoError(t, err)
+		assert.True(t, containsTransactionOutboxEntries(index, concurrent))
+		assert.True(t, containsTransactionOutboxEntries(index, more))
+	})
+
+	t.Run("entries are removed", func(t *testing.T) {
+		index, _, _ := testAPI.getTransactionOutboxIndex(fakeStore, shard)
+		testAPI.removeTransactionOutboxEntries(fakeStore, []transactionOutboxEntry{index.Entries[0], index.Entries[2]})
+
+		index, _, err := testAPI.getTransactionOutboxIndex(fakeStore, shard)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"key2", "key4"}, transactionOutboxEntryKeys(index.Entries))
+	})
+
+	t.Run("state store error", func(t *testing.T) {
+		fakeStore.Err = errors.New("UPSTREAM STATE ERROR")
+		defer func() { fakeStore.Err = nil }()
+		assert.Error(t, testAPI.addTransactionOutboxEntries(fakeStore, newTransactionOutboxEntries([]string{"key5"})))
+	})
+}
+
+func TestRecoverTransactionOutbox(t *testing.T) {
+	now := time.Now().UTC()
+	var invoked []string
+	failing := map[string]bool{}
+	testAPI := &api{
+		id: "app1",
+		sendToOutputBindingFn: func(name string, req *bindings.InvokeRequest) (*bindings.InvokeResponse, error) {
+			if failing[name] {
+				return nil, errors.New("binding error")
+			}
+			invoked = append(invoked, name)
+			return nil, nil
+		},
+	}
+
+	// newStore returns a store with the committed records of the transactions, and the entry of
+	// a transaction that didn't commit. The records are old enough to be recovered, except the recent one.
+	newStore := func(transactions map[string][]string) *daprt.FakeETagStateStore {
+		fakeStore := daprt.NewFakeETagStateStore()
+		old := now.Add(-2 * transactionOutboxRecoveryAge)
+		for tx, keys := range transactions {
+			created := old
+			if tx == "recent" {
+				created = now
+			}
+			var entries []transactionOutboxEntry
+			for _, key := range keys {
+				entries = append(entries, transactionOutboxEntry{Key: key, Transaction: tx, Created: created})
+				if key != "uncommitted" {
+					assert.NoError(t, fakeStore.Set(&state.SetRequest{
+						Key:   key,
+						Value: transactionOutboxRecord{Name: "binding-" + key, Operation: "create", LeaseUntil: created.Add(transactionOutboxRecoveryAge)},
+					}))
+				}
+			}
+			assert.NoError(t, testAPI.addTransactionOutboxEntries(fakeStore, entries))
+		}
+		return fakeStore
+	}
+	transactions := map[string][]string{
+		"tx1":    {"tx1-1", "tx1-2"},
+		"tx2":    {"tx2-1"},
+		"tx3":    {"uncommitted"},
+		"recent": {"recent"},
+	}
+
+	t.Run("old records are invoked in order", func(t *testing.T) {
+		invoked = nil
+		fakeStore := newStore(transactions)
+
+		assert.NoError(t, testAPI.recoverTransactionOutbox(fakeStore, now))
+
+		assert.ElementsMatch(t, []string{"binding-tx1-1", "binding-tx1-2", "binding-tx2-1"}, invoked)
+		assert.Less(t, indexOf(invoked, "binding-tx1-1"), indexOf(invoked, "binding-tx1-2"))
+		assert.False(t, fakeStore.Has("tx1-1"))
+		assert.True(t, fakeStore.Has("recent"))
+		assert.Equal(t, []string{"recent"}, transactionOutboxEntryKeys(allTransactionOutboxEntries(t, testAPI, fakeStore)))
+	})
+
+	t.Run("failure stops the invocations of the transaction", func(t *testing.T) {
+		invoked = nil
+		failing["binding-tx1-1"] = true
+		defer delete(failing, "binding-tx1-1")
+		fakeStore := newStore(transactions)
+
+		assert.NoError(t, testAPI.recoverTransactionOutbox(fakeStore, now))
+
+		assert.Equal(t, []string{"binding-tx2-1"}, invoked)
+		assert.True(t, fakeStore.Has("tx1-1"))
+		assert.True(t, fakeStore.Has("tx1-2"))
+		assert.ElementsMatch(t, []string{"tx1-1", "tx1-2", "recent"}, transactionOutboxEntryKeys(allTransactionOutboxEntries(t, testAPI, fakeStore)))
+
+		// the failed record stays leased to this replica until the lease expires
+		delete(failing, "binding-tx1-1")
+		invoked = nil
+		assert.NoError(t, testAPI.recoverTransactionOutbox(fakeStore, now))
+		assert.Empty(t, invoked)
+
+		assert.NoError(t, testAPI.recoverTransactionOutbox(fakeStore, now.Add(transactionOutboxLeaseDuration+time.Second)))
+		assert.Equal(t, []string{"binding-tx1-1", "binding-tx1-2"}, invoked)
+	})
+
+	t.Run("record leased to another replica is skipped", func(t *testing.T) {
+		invoked = nil
+		fakeStore := newStore(map[string][]string{"tx1": {"tx1-1", "tx1-2"}})
+		assert.NoError(t, fakeStore.Set(&state.SetRequest{
+			Key:   "tx1-1",
+			Value: transactionOutboxRecord{Name: "binding-tx1-1", Operation: "create", LeaseUntil: now.Add(time.Minute)},
+		}))
+
+		assert.NoError(t, testAPI.recoverTransactionOutbox(fakeStore, now))
+
+		assert.Empty(t, invoked)
+		assert.True(t, fakeStore.Has("tx1-1"))
+		assert.True(t, fakeStore.Has("tx1-2"))
+	})
+
+	t.Run("record leased by another replica first is skipped", func(t *testing.T) {
+		invoked = nil
+		fakeStore := newStore(map[string][]string{"tx1": {"tx1-1", "tx1-2"}})
+		fakeStore.BeforeSet = func() {
+			fakeStore.BeforeSet = nil
+			assert.NoError(t, fakeStore.Set(&state.SetRequest{
+				Key:   "tx1-1",
+				Value: transactionOutboxRecord{Name: "binding-tx1-1", Operation: "create", LeaseUntil: now.Add(transactionOutboxLeaseDuration)},
+			}))
+		}
+
+		assert.NoError(t, testAPI.recoverTransactionOutbox(fakeStore, now))
+
+		assert.Empty(t, invoked)
+		assert.True(t, fakeStore.Has("tx1-1"))
+	})
+
+	t.Run("state store error", func(t *testing.T) {
+		fakeStore := newStore(transactions)
+		fakeStore.Err = errors.New("UPSTREAM STATE ERROR")
+		assert.Error(t, testAPI.recoverTransactionOutbox(fakeStore, now))
+	})
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
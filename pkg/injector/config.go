@@ -5,7 +5,12 @@
 
 package injector
 
-import "github.com/kelseyhightower/envconfig"
+import (
+	"encoding/json"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+)
 
 // Config represents configuration options for the Dapr Sidecar Injector webhook server
 type Config struct {
@@ -14,6 +19,18 @@ type Config struct {
 	SidecarImage           string `envconfig:"SIDECAR_IMAGE" required:"true"`
 	SidecarImagePullPolicy string `envconfig:"SIDECAR_IMAGE_PULL_POLICY"`
 	Namespace              string `envconfig:"NAMESPACE" required:"true"`
+	SidecarResourcePresets string `envconfig:"SIDECAR_RESOURCE_PRESETS"`
+
+	ResourcePresets map[string]ResourcePreset `ignored:"true"`
+}
+
+// ResourcePreset is a named set of cpu and memory resources for the sidecar container,
+// selected by apps with the dapr.io/sidecar-resources-preset annotation.
+type ResourcePreset struct {
+	CPURequest    string `json:"cpuRequest,omitempty"`
+	CPULimit      string `json:"cpuLimit,omitempty"`
+	MemoryRequest string `json:"memoryRequest,omitempty"`
+	MemoryLimit   string `json:"memoryLimit,omitempty"`
 }
 
 // NewConfigWithDefaults returns a Config object with default values already
@@ -29,5 +46,46 @@ func NewConfigWithDefaults() Config {
 func GetConfigFromEnvironment() (Config, error) {
 	c := NewConfigWithDefaults()
 	err := envconfig.Process("", &c)
+	if err != nil {
+		return c, err
+	}
+	c.ResourcePresets, err = parseResourcePresets(c.SidecarResourcePresets)
 	return c, err
 }
+
+// parseResourcePresets parses a JSON object mapping preset names to sidecar resources.
+func parseResourcePresets(s string) (map[string]ResourcePreset, error) {
+	presets := map[string]ResourcePreset{}
+	if s == "" {
+		return presets, nil
+	}
+	if err := json.Unmarshal([]byte(s), &presets); err != nil {
+		return nil, errors.Wrap(err, "error parsing sidecar resource presets")
+	}
+	for name, preset := range presets {
+		if _, err := getResourceRequirements(map[string]string{daprResourcesPresetKey: name}, map[string]ResourcePreset{name: preset}); err != nil {
+			return nil, err
+		}
+	}
+	return presets, nil
+}
+
+// parseNamespaceDefaults parses the data of the namespace defaults ConfigMap, which maps namespaces to
+// a JSON object of the default sidecar annotations of their pods. The defaults of the "_all" key apply
+// to all namespaces.
+func parseNamespaceDefaults(data map[string]string) (map[string]map[string]string, error) {
+	defaults := make(map[string]map[string]string, len(data))
+	for namespace, s := range data {
+		annotations := map[string]string{}
+		if err := json.Unmarshal([]byte(s), &annotations); err != nil {
+			return nil, errors.Wrapf(err, "error parsing sidecar namespace defaults, namespace: %s", namespace)
+		}
+		for key := range annotations {
+			if key == daprEnabledKey || key == appIDKey {
+				return nil, errors.Errorf("annotation %s can't have a default value, namespace: %s", key, namespace)
+			}
+		}
+		defaults[namespace] = annotations
+	}
+	return defaults, nil
+}
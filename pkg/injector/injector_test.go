@@ -14,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 const (
@@ -187,7 +188,7 @@ func TestGetContainer(t *testing.T) {
 	annotations[daprConfigKey] = "config"
 	annotations[daprAppPortKey] = appPort
 
-	c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "")
+	c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "", nil)
 
 	assert.NotNil(t, c)
 	assert.Equal(t, "image", c.Image)
@@ -202,10 +203,11 @@ func TestSidecarResourceLimits(t *testing.T) {
 		annotations[daprCPULimitKey] = "100m"
 		annotations[daprMemoryLimitKey] = "1Gi"
 
-		c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "")
+		c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "", nil)
 		assert.NotNil(t, c)
 		assert.Equal(t, "100m", c.Resources.Limits.Cpu().String())
 		assert.Equal(t, "1Gi", c.Resources.Limits.Memory().String())
+		assert.Contains(t, c.Args, "--memory-soft-limit")
 	})
 
 	t.Run("with requests", func(t *testing.T) {
@@ -216,7 +218,7 @@ func TestSidecarResourceLimits(t *testing.T) {
 		annotations[daprCPURequestKey] = "100m"
 		annotations[daprMemoryRequestKey] = "1Gi"
 
-		c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "")
+		c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "", nil)
 		assert.NotNil(t, c)
 		assert.Equal(t, "100m", c.Resources.Requests.Cpu().String())
 		assert.Equal(t, "1Gi", c.Resources.Requests.Memory().String())
@@ -228,7 +230,7 @@ func TestSidecarResourceLimits(t *testing.T) {
 		annotations[daprAppPortKey] = appPort
 		annotations[daprLogAsJSON] = "true"
 
-		c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "")
+		c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "", nil)
 		assert.NotNil(t, c)
 		assert.Len(t, c.Resources.Limits, 0)
 	})
@@ -267,14 +269,14 @@ func TestGetAppIDFromRequest(t *testing.T) {
 
 func TestGetResourceRequirements(t *testing.T) {
 	t.Run("no resource requirements", func(t *testing.T) {
-		r, err := getResourceRequirements(nil)
+		r, err := getResourceRequirements(nil, nil)
 		assert.Nil(t, err)
 		assert.Nil(t, r)
 	})
 
 	t.Run("valid resource limits", func(t *testing.T) {
 		a := map[string]string{daprCPULimitKey: "100m", daprMemoryLimitKey: "1Gi"}
-		r, err := getResourceRequirements(a)
+		r, err := getResourceRequirements(a, nil)
 		assert.Nil(t, err)
 		assert.Equal(t, "100m", r.Limits.Cpu().String())
 		assert.Equal(t, "1Gi", r.Limits.Memory().String())
@@ -282,21 +284,21 @@ func TestGetResourceRequirements(t *testing.T) {
 
 	t.Run("invalid cpu limit", func(t *testing.T) {
 		a := map[string]string{daprCPULimitKey: "cpu", daprMemoryLimitKey: "1Gi"}
-		r, err := getResourceRequirements(a)
+		r, err := getResourceRequirements(a, nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, r)
 	})
 
 	t.Run("invalid memory limit", func(t *testing.T) {
 		a := map[string]string{daprCPULimitKey: "100m", daprMemoryLimitKey: "memory"}
-		r, err := getResourceRequirements(a)
+		r, err := getResourceRequirements(a, nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, r)
 	})
 
 	t.Run("valid resource requests", func(t *testing.T) {
 		a := map[string]string{daprCPURequestKey: "100m", daprMemoryRequestKey: "1Gi"}
-		r, err := getResourceRequirements(a)
+		r, err := getResourceRequirements(a, nil)
 		assert.Nil(t, err)
 		assert.Equal(t, "100m", r.Requests.Cpu().String())
 		assert.Equal(t, "1Gi", r.Requests.Memory().String())
@@ -304,19 +306,204 @@ func TestGetResourceRequirements(t *testing.T) {
 
 	t.Run("invalid cpu request", func(t *testing.T) {
 		a := map[string]string{daprCPURequestKey: "cpu", daprMemoryRequestKey: "1Gi"}
-		r, err := getResourceRequirements(a)
+		r, err := getResourceRequirements(a, nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, r)
 	})
 
 	t.Run("invalid memory request", func(t *testing.T) {
 		a := map[string]string{daprCPURequestKey: "100m", daprMemoryRequestKey: "memory"}
-		r, err := getResourceRequirements(a)
+		r, err := getResourceRequirements(a, nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, r)
 	})
 }
 
+func TestGetResourceRequirementsWithPreset(t *testing.T) {
+	presets := map[string]ResourcePreset{
+		"small": {CPURequest: "100m", CPULimit: "300m", MemoryRequest: "64Mi", MemoryLimit: "256Mi"},
+	}
+
+	t.Run("preset applied", func(t *testing.T) {
+		a := map[string]string{daprResourcesPresetKey: "small"}
+		r, err := getResourceRequirements(a, presets)
+		assert.Nil(t, err)
+		assert.Equal(t, "100m", r.Requests.Cpu().String())
+		assert.Equal(t, "300m", r.Limits.Cpu().String())
+		assert.Equal(t, "64Mi", r.Requests.Memory().String())
+		assert.Equal(t, "256Mi", r.Limits.Memory().String())
+	})
+
+	t.Run("annotations override preset", func(t *testing.T) {
+		a := map[string]string{daprResourcesPresetKey: "small", daprMemoryLimitKey: "1Gi"}
+		r, err := getResourceRequirements(a, presets)
+		assert.Nil(t, err)
+		assert.Equal(t, "1Gi", r.Limits.Memory().String())
+		assert.Equal(t, "300m", r.Limits.Cpu().String())
+	})
+
+	t.Run("unknown preset", func(t *testing.T) {
+		a := map[string]string{daprResourcesPresetKey: "huge"}
+		r, err := getResourceRequirements(a, presets)
+		assert.NotNil(t, err)
+		assert.Nil(t, r)
+	})
+}
+
+func TestGetGCArgs(t *testing.T) {
+	t.Run("no annotations and no memory limit", func(t *testing.T) {
+		args := getGCArgs(map[string]string{}, corev1.ResourceRequirements{})
+		assert.Len(t, args, 0)
+	})
+
+	t.Run("gc percent", func(t *testing.T) {
+		a := map[string]string{daprGCPercentKey: "50"}
+		args := getGCArgs(a, corev1.ResourceRequirements{})
+		assert.Equal(t, []string{"--gc-percent", "50"}, args)
+	})
+
+	t.Run("invalid gc percent", func(t *testing.T) {
+		a := map[string]string{daprGCPercentKey: "fifty"}
+		args := getGCArgs(a, corev1.ResourceRequirements{})
+		assert.Len(t, args, 0)
+	})
+
+	t.Run("memory soft limit", func(t *testing.T) {
+		a := map[string]string{daprMemorySoftLimitKey: "400Mi", daprMemoryLimitKey: "1Gi"}
+		r, err := getResourceRequirements(a, nil)
+		assert.Nil(t, err)
+		args := getGCArgs(a, *r)
+		assert.Equal(t, []string{"--memory-soft-limit", "400Mi"}, args)
+	})
+
+	t.Run("invalid memory soft limit", func(t *testing.T) {
+		a := map[string]string{daprMemorySoftLimitKey: "lots"}
+		args := getGCArgs(a, corev1.ResourceRequirements{})
+		assert.Len(t, args, 0)
+	})
+
+	t.Run("memory soft limit defaults to a share of the memory limit", func(t *testing.T) {
+		a := map[string]string{daprGCPercentKey: "200", daprMemoryLimitKey: "1000Mi"}
+		r, err := getResourceRequirements(a, nil)
+		assert.Nil(t, err)
+		args := getGCArgs(a, *r)
+		assert.Equal(t, []string{"--gc-percent", "200", "--memory-soft-limit", "943718400"}, args)
+	})
+}
+
+func TestParseResourcePresets(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		p, err := parseResourcePresets("")
+		assert.Nil(t, err)
+		assert.Len(t, p, 0)
+	})
+
+	t.Run("valid presets", func(t *testing.T) {
+		p, err := parseResourcePresets(`{"small":{"cpuRequest":"100m","memoryLimit":"256Mi"},"large":{"cpuLimit":"2"}}`)
+		assert.Nil(t, err)
+		assert.Len(t, p, 2)
+		assert.Equal(t, "100m", p["small"].CPURequest)
+		assert.Equal(t, "2", p["large"].CPULimit)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := parseResourcePresets(`{"small":`)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid quantity", func(t *testing.T) {
+		_, err := parseResourcePresets(`{"small":{"cpuLimit":"cpu"}}`)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestParseNamespaceDefaults(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		d, err := parseNamespaceDefaults(nil)
+		assert.Nil(t, err)
+		assert.Len(t, d, 0)
+	})
+
+	t.Run("valid defaults", func(t *testing.T) {
+		d, err := parseNamespaceDefaults(map[string]string{
+			"_all": `{"dapr.io/log-as-json":"true"}`,
+			"dev":  `{"dapr.io/log-level":"debug"}`,
+		})
+		assert.Nil(t, err)
+		assert.Len(t, d, 2)
+		assert.Equal(t, "debug", d["dev"][daprLogLevel])
+		assert.Equal(t, "true", d[namespaceDefaultsWildcard][daprLogAsJSON])
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := parseNamespaceDefaults(map[string]string{"dev": `{"dapr.io/log-level":`})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("enabled can't have a default", func(t *testing.T) {
+		_, err := parseNamespaceDefaults(map[string]string{"dev": `{"dapr.io/enabled":"true"}`})
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGetNamespaceDefaults(t *testing.T) {
+	t.Run("configmap doesn't exist", func(t *testing.T) {
+		d, err := getNamespaceDefaults(fake.NewSimpleClientset(), "dapr-system")
+		assert.Nil(t, err)
+		assert.Len(t, d, 0)
+	})
+
+	t.Run("configmap of the control plane namespace", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: namespaceDefaultsConfigMapName, Namespace: "dapr-system"},
+			Data:       map[string]string{"dev": `{"dapr.io/log-level":"debug"}`},
+		})
+
+		d, err := getNamespaceDefaults(kubeClient, "dapr-system")
+		assert.Nil(t, err)
+		assert.Equal(t, "debug", d["dev"][daprLogLevel])
+
+		d, err = getNamespaceDefaults(kubeClient, "other")
+		assert.Nil(t, err)
+		assert.Len(t, d, 0)
+	})
+
+	t.Run("invalid configmap", func(t *testing.T) {
+		kubeClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: namespaceDefaultsConfigMapName, Namespace: "dapr-system"},
+			Data:       map[string]string{"dev": `{"dapr.io/app-id":"app"}`},
+		})
+
+		_, err := getNamespaceDefaults(kubeClient, "dapr-system")
+		assert.NotNil(t, err)
+	})
+}
+
+func TestWithNamespaceDefaults(t *testing.T) {
+	defaults := map[string]map[string]string{
+		namespaceDefaultsWildcard: {daprLogAsJSON: "true", daprLogLevel: "info"},
+		"dev":                     {daprLogLevel: "debug", daprEnableProfilingKey: "true"},
+	}
+
+	t.Run("namespace defaults override the defaults of all namespaces", func(t *testing.T) {
+		a := withNamespaceDefaults(map[string]string{daprEnabledKey: "true"}, defaults, "dev")
+		assert.Equal(t, "debug", a[daprLogLevel])
+		assert.Equal(t, "true", a[daprLogAsJSON])
+		assert.Equal(t, "true", a[daprEnableProfilingKey])
+	})
+
+	t.Run("pod annotations are kept", func(t *testing.T) {
+		a := withNamespaceDefaults(map[string]string{daprLogLevel: "warn"}, defaults, "dev")
+		assert.Equal(t, "warn", a[daprLogLevel])
+	})
+
+	t.Run("other namespaces", func(t *testing.T) {
+		a := withNamespaceDefaults(nil, defaults, "prod")
+		assert.Equal(t, "info", a[daprLogLevel])
+		assert.Empty(t, a[daprEnableProfilingKey])
+	})
+}
+
 func TestAPITokenSecret(t *testing.T) {
 	t.Run("secret exists", func(t *testing.T) {
 		annotations := map[string]string{}
@@ -362,7 +549,7 @@ func TestAppSSL(t *testing.T) {
 		annotations := map[string]string{
 			daprAppSSLKey: "true",
 		}
-		c, _ := getSidecarContainer(annotations, "app", "image", "", "ns", "a", "b", nil, "", "", "", "", false, "")
+		c, _ := getSidecarContainer(annotations, "app", "image", "", "ns", "a", "b", nil, "", "", "", "", false, "", nil)
 		found := false
 		for _, a := range c.Args {
 			if a == "--app-ssl" {
@@ -377,7 +564,7 @@ func TestAppSSL(t *testing.T) {
 		annotations := map[string]string{
 			daprAppSSLKey: "false",
 		}
-		c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "")
+		c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "", nil)
 		for _, a := range c.Args {
 			if a == "--app-ssl" {
 				t.FailNow()
@@ -387,7 +574,7 @@ func TestAppSSL(t *testing.T) {
 
 	t.Run("get sidecar container not specified", func(t *testing.T) {
 		annotations := map[string]string{}
-		c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "")
+		c, _ := getSidecarContainer(annotations, "app", "image", "Always", "ns", "a", "b", nil, "", "", "", "", false, "", nil)
 		for _, a := range c.Args {
 			if a == "--app-ssl" {
 				t.FailNow()
@@ -22,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -50,6 +51,9 @@ const (
 	daprMemoryLimitKey                = "dapr.io/sidecar-memory-limit"
 	daprCPURequestKey                 = "dapr.io/sidecar-cpu-request"
 	daprMemoryRequestKey              = "dapr.io/sidecar-memory-request"
+	daprResourcesPresetKey            = "dapr.io/sidecar-resources-preset"
+	daprGCPercentKey                  = "dapr.io/sidecar-gc-percent"
+	daprMemorySoftLimitKey            = "dapr.io/sidecar-memory-soft-limit"
 	daprLivenessProbeDelayKey         = "dapr.io/sidecar-liveness-probe-delay-seconds"
 	daprLivenessProbeTimeoutKey       = "dapr.io/sidecar-liveness-probe-timeout-seconds"
 	daprLivenessProbePeriodKey        = "dapr.io/sidecar-liveness-probe-period-seconds"
@@ -60,7 +64,13 @@ const (
 	daprReadinessProbeThresholdKey    = "dapr.io/sidecar-readiness-probe-threshold"
 	daprMaxRequestBodySize            = "dapr.io/http-max-request-size"
 	daprAppSSLKey                     = "dapr.io/app-ssl"
+	daprAppMTLSKey                    = "dapr.io/app-mtls"
+	daprAppHealthProbeKey             = "dapr.io/app-health-probe"
+	daprAppHTTP2Key                   = "dapr.io/app-http2"
+	daprAppHTTPMaxConnsKey            = "dapr.io/app-http-max-conns"
+	daprNativeSidecarKey              = "dapr.io/native-sidecar"
 	containersPath                    = "/spec/containers"
+	initContainersPath                = "/spec/initContainers"
 	sidecarHTTPPort                   = 3500
 	sidecarAPIGRPCPort                = 50001
 	sidecarInternalGRPCPort           = 50002
@@ -77,6 +87,7 @@ const (
 	defaultLogLevel                   = "info"
 	defaultLogAsJSON                  = false
 	defaultAppSSL                     = false
+	defaultAppMTLS                    = false
 	kubernetesMountPath               = "/var/run/secrets/kubernetes.io/serviceaccount"
 	defaultConfig                     = "daprsystem"
 	defaultEnabledMetric              = true
@@ -91,8 +102,25 @@ const (
 	apiVersionV1                      = "v1.0"
 	defaultMtlsEnabled                = true
 	trueString                        = "true"
+	restartPolicyAlways               = "Always"
+	// namespaceDefaultsConfigMapName is the ConfigMap of the control plane namespace with the default
+	// annotations of the pods per namespace.
+	namespaceDefaultsConfigMapName = "dapr-sidecar-namespace-defaults"
+	// namespaceDefaultsWildcard is the key of the defaults of all namespaces, which can't be a namespace name.
+	namespaceDefaultsWildcard = "_all"
 )
 
+// memorySoftLimitRatio is the share of the sidecar memory limit used as the default soft memory limit.
+const memorySoftLimitRatio = 0.9
+
+// nativeSidecarContainer is a sidecar container declared as an init container that keeps running
+// alongside the app containers, supported by Kubernetes 1.29+.
+// The restartPolicy field is declared here as the vendored Kubernetes API predates it.
+type nativeSidecarContainer struct {
+	corev1.Container `json:",inline"`
+	RestartPolicy    string `json:"restartPolicy"`
+}
+
 func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 	namespace, image, imagePullPolicy string, kubeClient *kubernetes.Clientset, daprClient scheme.Interface) ([]PatchOperation, error) {
 	req := ar.Request
@@ -118,8 +146,15 @@ func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 		return nil, nil
 	}
 
+	namespaceDefaults, err := getNamespaceDefaults(kubeClient, namespace)
+	if err != nil {
+		// the pod annotations are still valid without the defaults
+		log.Errorf("failed to read the sidecar namespace defaults, injecting the sidecar without them: %s", err)
+	}
+	pod.Annotations = withNamespaceDefaults(pod.Annotations, namespaceDefaults, req.Namespace)
+
 	id := getAppID(pod)
-	err := validation.ValidateKubernetesAppID(id)
+	err = validation.ValidateKubernetesAppID(id)
 	if err != nil {
 		return nil, err
 	}
@@ -141,40 +176,82 @@ func (i *injector) getPodPatchOperations(ar *v1.AdmissionReview,
 	}
 
 	tokenMount := getTokenVolumeMount(pod)
-	sidecarContainer, err := getSidecarContainer(pod.Annotations, id, image, imagePullPolicy, req.Namespace, apiSrvAddress, placementAddress, tokenMount, trustAnchors, certChain, certKey, sentryAddress, mtlsEnabled, identity)
+	sidecarContainer, err := getSidecarContainer(pod.Annotations, id, image, imagePullPolicy, req.Namespace, apiSrvAddress, placementAddress, tokenMount, trustAnchors, certChain, certKey, sentryAddress, mtlsEnabled, identity, i.config.ResourcePresets)
 	if err != nil {
 		return nil, err
 	}
 
-	patchOps := []PatchOperation{}
-	envPatchOps := []PatchOperation{}
-	var path string
-	var value interface{}
-	if len(pod.Spec.Containers) == 0 {
-		path = containersPath
-		value = []corev1.Container{*sidecarContainer}
-	} else {
-		envPatchOps = addDaprEnvVarsToContainers(pod.Spec.Containers)
-		path = "/spec/containers/-"
-		value = sidecarContainer
+	patchOps := []PatchOperation{
+		getSidecarPatchOperation(&pod, sidecarContainer),
+	}
+	if len(pod.Spec.Containers) > 0 {
+		var appEnv []corev1.EnvVar
+		if appMTLSEnabled(pod.Annotations) && mtlsEnabled && trustAnchors != "" {
+			// the app authenticates the client certificate daprd presents on the app channel with the trust anchors
+			appEnv = append(appEnv, corev1.EnvVar{Name: certs.TrustAnchorsEnvVar, Value: trustAnchors})
+		}
+		patchOps = append(patchOps, addDaprEnvVarsToContainers(pod.Spec.Containers, appEnv...)...)
 	}
-
-	patchOps = append(
-		patchOps,
-		PatchOperation{
-			Op:    "add",
-			Path:  path,
-			Value: value,
-		},
-	)
-	patchOps = append(patchOps, envPatchOps...)
 
 	return patchOps, nil
 }
 
+// getNamespaceDefaults reads the default annotations of the pods per namespace from the namespace defaults
+// ConfigMap of the control plane namespace. It is read on each admission, so that its changes apply to the
+// next pods without restarting the injector. There are no defaults when the ConfigMap doesn't exist.
+func getNamespaceDefaults(kubeClient kubernetes.Interface, namespace string) (map[string]map[string]string, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), namespaceDefaultsConfigMapName, meta_v1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseNamespaceDefaults(cm.Data)
+}
+
+// withNamespaceDefaults returns the annotations of a pod, completed with the default annotations
+// of its namespace and then with those of all namespaces. Annotations of the pod are never overridden.
+func withNamespaceDefaults(annotations map[string]string, defaults map[string]map[string]string, namespace string) map[string]string {
+	merged := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		merged[k] = v
+	}
+	for _, ns := range []string{namespace, namespaceDefaultsWildcard} {
+		for k, v := range defaults[ns] {
+			if _, ok := merged[k]; !ok {
+				merged[k] = v
+			}
+		}
+	}
+	return merged
+}
+
+// getSidecarPatchOperation returns the operation adding the sidecar to the app containers or,
+// if enabled by annotation, as a native sidecar to the init containers so that it starts before
+// the app containers and terminates after them. The native sidecar is inserted first in the init
+// containers, so that the other init containers can reach the Dapr APIs.
+func getSidecarPatchOperation(pod *corev1.Pod, sidecarContainer *corev1.Container) PatchOperation {
+	if nativeSidecarEnabled(pod.Annotations) {
+		container := nativeSidecarContainer{
+			Container:     *sidecarContainer,
+			RestartPolicy: restartPolicyAlways,
+		}
+		if len(pod.Spec.InitContainers) == 0 {
+			return PatchOperation{Op: "add", Path: initContainersPath, Value: []nativeSidecarContainer{container}}
+		}
+		return PatchOperation{Op: "add", Path: initContainersPath + "/0", Value: container}
+	}
+
+	if len(pod.Spec.Containers) == 0 {
+		return PatchOperation{Op: "add", Path: containersPath, Value: []corev1.Container{*sidecarContainer}}
+	}
+	return PatchOperation{Op: "add", Path: containersPath + "/-", Value: sidecarContainer}
+}
+
 // This function add Dapr environment variables to all the containers in any Dapr enabled pod.
 // The containers can be injected or user defined.
-func addDaprEnvVarsToContainers(containers []corev1.Container) []PatchOperation {
+func addDaprEnvVarsToContainers(containers []corev1.Container, extraEnv ...corev1.EnvVar) []PatchOperation {
 	portEnv := []corev1.EnvVar{
 		{
 			Name:  userContainerDaprHTTPPortName,
@@ -185,6 +262,7 @@ func addDaprEnvVarsToContainers(containers []corev1.Container) []PatchOperation
 			Value: strconv.Itoa(sidecarAPIGRPCPort),
 		},
 	}
+	portEnv = append(portEnv, extraEnv...)
 	envPatchOps := make([]PatchOperation, 0, len(containers))
 	for i, container := range containers {
 		path := fmt.Sprintf("%s/%d/env", containersPath, i)
@@ -272,6 +350,11 @@ func podContainsSidecarContainer(pod *corev1.Pod) bool {
 			return true
 		}
 	}
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == sidecarContainerName {
+			return true
+		}
+	}
 	return false
 }
 
@@ -291,6 +374,10 @@ func getProtocol(annotations map[string]string) string {
 	return getStringAnnotationOrDefault(annotations, daprAppProtocolKey, "http")
 }
 
+func getAppHealthProbe(annotations map[string]string) string {
+	return getStringAnnotation(annotations, daprAppHealthProbeKey)
+}
+
 func getEnableMetrics(annotations map[string]string) bool {
 	return getBoolAnnotationOrDefault(annotations, daprEnableMetricsKey, defaultEnabledMetric)
 }
@@ -327,6 +414,22 @@ func appSSLEnabled(annotations map[string]string) bool {
 	return getBoolAnnotationOrDefault(annotations, daprAppSSLKey, defaultAppSSL)
 }
 
+func appMTLSEnabled(annotations map[string]string) bool {
+	return getBoolAnnotationOrDefault(annotations, daprAppMTLSKey, defaultAppMTLS)
+}
+
+func appHTTP2Enabled(annotations map[string]string) bool {
+	return getBoolAnnotationOrDefault(annotations, daprAppHTTP2Key, false)
+}
+
+func getAppHTTPMaxConns(annotations map[string]string) (int32, error) {
+	return getInt32Annotation(annotations, daprAppHTTPMaxConnsKey)
+}
+
+func nativeSidecarEnabled(annotations map[string]string) bool {
+	return getBoolAnnotationOrDefault(annotations, daprNativeSidecarKey, false)
+}
+
 func getAPITokenSecret(annotations map[string]string) string {
 	return getStringAnnotationOrDefault(annotations, daprAPITokenSecret, "")
 }
@@ -410,11 +513,39 @@ func appendQuantityToResourceList(quantity string, resourceName corev1.ResourceN
 	return &resourceList, nil
 }
 
-func getResourceRequirements(annotations map[string]string) (*corev1.ResourceRequirements, error) {
+// getResourceRequirements returns the resources of the sidecar container.
+// The preset selected with the resources preset annotation is applied first, and the
+// individual cpu and memory annotations override the values of the preset.
+func getResourceRequirements(annotations map[string]string, presets map[string]ResourcePreset) (*corev1.ResourceRequirements, error) {
 	r := corev1.ResourceRequirements{
 		Limits:   corev1.ResourceList{},
 		Requests: corev1.ResourceList{},
 	}
+	presetName, ok := annotations[daprResourcesPresetKey]
+	if ok {
+		preset, ok := presets[presetName]
+		if !ok {
+			return nil, errors.Errorf("unknown sidecar resources preset %s", presetName)
+		}
+		quantities := []struct {
+			value        string
+			resourceName corev1.ResourceName
+			resourceList corev1.ResourceList
+		}{
+			{preset.CPULimit, corev1.ResourceCPU, r.Limits},
+			{preset.MemoryLimit, corev1.ResourceMemory, r.Limits},
+			{preset.CPURequest, corev1.ResourceCPU, r.Requests},
+			{preset.MemoryRequest, corev1.ResourceMemory, r.Requests},
+		}
+		for _, q := range quantities {
+			if q.value == "" {
+				continue
+			}
+			if _, err := appendQuantityToResourceList(q.value, q.resourceName, q.resourceList); err != nil {
+				return nil, errors.Wrapf(err, "error parsing sidecar resources preset %s", presetName)
+			}
+		}
+	}
 	cpuLimit, ok := annotations[daprCPULimitKey]
 	if ok {
 		list, err := appendQuantityToResourceList(cpuLimit, corev1.ResourceCPU, r.Limits)
@@ -475,7 +606,7 @@ func getPullPolicy(pullPolicy string) corev1.PullPolicy {
 	}
 }
 
-func getSidecarContainer(annotations map[string]string, id, daprSidecarImage, imagePullPolicy, namespace, controlPlaneAddress, placementServiceAddress string, tokenVolumeMount *corev1.VolumeMount, trustAnchors, certChain, certKey, sentryAddress string, mtlsEnabled bool, identity string) (*corev1.Container, error) {
+func getSidecarContainer(annotations map[string]string, id, daprSidecarImage, imagePullPolicy, namespace, controlPlaneAddress, placementServiceAddress string, tokenVolumeMount *corev1.VolumeMount, trustAnchors, certChain, certKey, sentryAddress string, mtlsEnabled bool, identity string, resourcePresets map[string]ResourcePreset) (*corev1.Container, error) {
 	appPort, err := getAppPort(annotations)
 	if err != nil {
 		return nil, err
@@ -493,6 +624,7 @@ func getSidecarContainer(annotations map[string]string, id, daprSidecarImage, im
 	}
 
 	sslEnabled := appSSLEnabled(annotations)
+	appMTLS := appMTLSEnabled(annotations)
 
 	pullPolicy := getPullPolicy(imagePullPolicy)
 
@@ -639,6 +771,26 @@ func getSidecarContainer(annotations map[string]string, id, daprSidecarImage, im
 		c.Args = append(c.Args, "--app-ssl")
 	}
 
+	if appMTLS {
+		c.Args = append(c.Args, "--app-mtls")
+	}
+
+	if appHealthProbe := getAppHealthProbe(annotations); appHealthProbe != "" {
+		c.Args = append(c.Args, "--app-health-probe", appHealthProbe)
+	}
+
+	if appHTTP2Enabled(annotations) {
+		c.Args = append(c.Args, "--app-http2")
+	}
+
+	appHTTPMaxConns, err := getAppHTTPMaxConns(annotations)
+	if err != nil {
+		log.Warn(err)
+	}
+	if appHTTPMaxConns > 0 {
+		c.Args = append(c.Args, "--app-http-max-conns", fmt.Sprintf("%v", appHTTPMaxConns))
+	}
+
 	secret := getAPITokenSecret(annotations)
 	if secret != "" {
 		c.Env = append(c.Env, corev1.EnvVar{
@@ -669,12 +821,40 @@ func getSidecarContainer(annotations map[string]string, id, daprSidecarImage, im
 		})
 	}
 
-	resources, err := getResourceRequirements(annotations)
+	resources, err := getResourceRequirements(annotations, resourcePresets)
 	if err != nil {
 		log.Warnf("couldn't set container resource requirements: %s. using defaults", err)
 	}
 	if resources != nil {
 		c.Resources = *resources
 	}
+	c.Args = append(c.Args, getGCArgs(annotations, c.Resources)...)
 	return c, nil
 }
+
+// getGCArgs returns the garbage collection flags of the sidecar. Without a soft memory limit annotation,
+// the soft limit defaults to a share of the memory limit of the sidecar so that it collects garbage
+// before it is OOM killed.
+func getGCArgs(annotations map[string]string, resources corev1.ResourceRequirements) []string {
+	args := []string{}
+	gcPercent, err := getInt32Annotation(annotations, daprGCPercentKey)
+	if err != nil {
+		log.Warn(err)
+	}
+	if gcPercent > 0 {
+		args = append(args, "--gc-percent", fmt.Sprintf("%v", gcPercent))
+	}
+
+	if softLimit := getStringAnnotation(annotations, daprMemorySoftLimitKey); softLimit != "" {
+		if _, err := resource.ParseQuantity(softLimit); err != nil {
+			log.Warnf("error parsing sidecar memory soft limit: %s", err)
+			return args
+		}
+		return append(args, "--memory-soft-limit", softLimit)
+	}
+	if limit := resources.Limits.Memory(); !limit.IsZero() {
+		softLimit := int64(float64(limit.Value()) * memorySoftLimitRatio)
+		args = append(args, "--memory-soft-limit", fmt.Sprintf("%v", softLimit))
+	}
+	return args
+}
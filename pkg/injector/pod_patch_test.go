@@ -6,10 +6,13 @@
 package injector
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/dapr/dapr/pkg/sentry/certs"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -42,6 +45,47 @@ func TestLogAsJSONEnabled(t *testing.T) {
 	})
 }
 
+func TestAppMTLSEnabled(t *testing.T) {
+	t.Run("dapr.io/app-mtls is true", func(t *testing.T) {
+		fakeAnnotation := map[string]string{
+			daprAppMTLSKey: trueString,
+		}
+
+		assert.Equal(t, true, appMTLSEnabled(fakeAnnotation))
+	})
+
+	t.Run("dapr.io/app-mtls is not given", func(t *testing.T) {
+		fakeAnnotation := map[string]string{}
+
+		assert.Equal(t, false, appMTLSEnabled(fakeAnnotation))
+	})
+}
+
+func TestAppHTTPChannelArgs(t *testing.T) {
+	t.Run("not given", func(t *testing.T) {
+		annotations := map[string]string{daprConfigKey: "config", daprAppPortKey: "5000"}
+		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", true, "pod_identity", nil)
+
+		assert.NotContains(t, container.Args, "--app-http2")
+		assert.NotContains(t, container.Args, "--app-http-max-conns")
+	})
+
+	t.Run("http2 and max conns", func(t *testing.T) {
+		annotations := map[string]string{daprConfigKey: "config", daprAppPortKey: "5000", daprAppHTTP2Key: trueString, daprAppHTTPMaxConnsKey: "64"}
+		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", true, "pod_identity", nil)
+
+		assert.Contains(t, container.Args, "--app-http2")
+		assert.Subset(t, container.Args, []string{"--app-http-max-conns", "64"})
+	})
+
+	t.Run("invalid max conns", func(t *testing.T) {
+		annotations := map[string]string{daprConfigKey: "config", daprAppPortKey: "5000", daprAppHTTPMaxConnsKey: "many"}
+		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", true, "pod_identity", nil)
+
+		assert.NotContains(t, container.Args, "--app-http-max-conns")
+	})
+}
+
 func TestFormatProbePath(t *testing.T) {
 	testCases := []struct {
 		given    []string
@@ -95,7 +139,7 @@ func TestGetSideCarContainer(t *testing.T) {
 		annotations[daprLogAsJSON] = trueString
 		annotations[daprAPITokenSecret] = "secret"
 		annotations[daprAppTokenSecret] = "appsecret"
-		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", true, "pod_identity")
+		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", true, "pod_identity", nil)
 
 		expectedArgs := []string{
 			"--mode", "kubernetes",
@@ -136,7 +180,7 @@ func TestGetSideCarContainer(t *testing.T) {
 		annotations[daprAppTokenSecret] = "appsecret"
 		annotations[daprEnableDebugKey] = trueString
 		annotations[daprDebugPortKey] = "55555"
-		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", true, "pod_identity")
+		container, _ := getSidecarContainer(annotations, "app_id", "darpio/dapr", "Always", "dapr-system", "controlplane:9000", "placement:50000", nil, "", "", "", "sentry:50000", true, "pod_identity", nil)
 
 		expectedArgs := []string{
 			"--listen=:55555",
@@ -215,6 +259,53 @@ func TestImagePullPolicy(t *testing.T) {
 	}
 }
 
+func TestGetSidecarPatchOperation(t *testing.T) {
+	sidecar := &corev1.Container{Name: sidecarContainerName}
+
+	t.Run("sidecar is added to the containers", func(t *testing.T) {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+
+		op := getSidecarPatchOperation(pod, sidecar)
+
+		assert.Equal(t, "/spec/containers/-", op.Path)
+		assert.Equal(t, sidecar, op.Value)
+	})
+
+	t.Run("native sidecar is added to empty init containers", func(t *testing.T) {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+		pod.Annotations = map[string]string{daprNativeSidecarKey: trueString}
+
+		op := getSidecarPatchOperation(pod, sidecar)
+
+		assert.Equal(t, initContainersPath, op.Path)
+		containers, ok := op.Value.([]nativeSidecarContainer)
+		assert.True(t, ok)
+		assert.Len(t, containers, 1)
+		assert.Equal(t, restartPolicyAlways, containers[0].RestartPolicy)
+	})
+
+	t.Run("native sidecar is inserted first in init containers", func(t *testing.T) {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: "init"}}}}
+		pod.Annotations = map[string]string{daprNativeSidecarKey: trueString}
+
+		op := getSidecarPatchOperation(pod, sidecar)
+
+		assert.Equal(t, "/spec/initContainers/0", op.Path)
+		b, err := json.Marshal(op.Value)
+		assert.NoError(t, err)
+		var container map[string]interface{}
+		assert.NoError(t, json.Unmarshal(b, &container))
+		assert.Equal(t, sidecarContainerName, container["name"])
+		assert.Equal(t, restartPolicyAlways, container["restartPolicy"])
+	})
+
+	t.Run("native sidecar is detected", func(t *testing.T) {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{InitContainers: []corev1.Container{*sidecar}}}
+
+		assert.True(t, podContainsSidecarContainer(pod))
+	})
+}
+
 func TestAddDaprEnvVarsToContainers(t *testing.T) {
 	testCases := []struct {
 		testName      string
@@ -332,4 +423,12 @@ func TestAddDaprEnvVarsToContainers(t *testing.T) {
 			assert.Equal(t, tc.expOps, patchEnv)
 		})
 	}
+
+	t.Run("trust anchors for app mTLS", func(t *testing.T) {
+		trustAnchors := corev1.EnvVar{Name: certs.TrustAnchorsEnvVar, Value: "anchors"}
+		patchEnv := addDaprEnvVarsToContainers([]corev1.Container{{Name: "Mock Container"}}, trustAnchors)
+
+		assert.Equal(t, 1, len(patchEnv))
+		assert.Contains(t, patchEnv[0].Value, trustAnchors)
+	})
 }
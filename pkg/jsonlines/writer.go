@@ -0,0 +1,46 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package jsonlines
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// StdoutSink is the name of the sink writing the records to stdout.
+const StdoutSink = "stdout"
+
+// Writer writes records as JSON lines, one record per line. It is safe for concurrent use.
+type Writer struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+}
+
+// NewWriter returns a writer of JSON lines to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{encoder: json.NewEncoder(w)}
+}
+
+// NewSinkWriter returns the writer of JSON lines to the sink with the given name.
+// It returns false if the sink is not supported.
+func NewSinkWriter(name string) (*Writer, bool) {
+	switch strings.ToLower(name) {
+	case StdoutSink:
+		return NewWriter(os.Stdout), true
+	}
+	return nil, false
+}
+
+// Write writes the record as a JSON line.
+func (w *Writer) Write(record interface{}) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.encoder.Encode(record)
+}
@@ -0,0 +1,32 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package jsonlines
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	assert.NoError(t, w.Write(map[string]string{"id": "1"}))
+	assert.NoError(t, w.Write(map[string]string{"id": "2"}))
+	assert.Equal(t, "{\"id\":\"1\"}\n{\"id\":\"2\"}\n", buf.String())
+	assert.Error(t, w.Write(func() {}))
+}
+
+func TestNewSinkWriter(t *testing.T) {
+	w, ok := NewSinkWriter("STDOUT")
+	assert.True(t, ok)
+	assert.NotNil(t, w)
+
+	_, ok = NewSinkWriter("kafka")
+	assert.False(t, ok)
+}
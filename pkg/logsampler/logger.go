@@ -0,0 +1,208 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package logsampler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dapr/kit/logger"
+)
+
+// maxTrackedMessages bounds the number of distinct messages tracked by a logger.
+const maxTrackedMessages = 1000
+
+var (
+	loggers     []*Logger
+	loggersLock sync.Mutex
+)
+
+// Logger wraps a logger and limits how often identical warnings and errors are emitted.
+// Once a message exceeds its budget for the interval it is dropped, and the number of
+// dropped duplicates is logged when the interval is over.
+type Logger struct {
+	logger.Logger
+
+	lock      sync.Mutex
+	opts      Options
+	entries   map[string]*entry
+	now       func() time.Time
+	stopFlush chan struct{}
+}
+
+type entry struct {
+	level       string
+	msg         string
+	windowStart time.Time
+	count       int
+	suppressed  int
+}
+
+// NewLogger returns a sampling logger wrapping l. Sampling is disabled until options are applied.
+func NewLogger(l logger.Logger) *Logger {
+	s := &Logger{
+		Logger:  l,
+		opts:    DefaultOptions(),
+		entries: map[string]*entry{},
+		now:     time.Now,
+	}
+
+	loggersLock.Lock()
+	loggers = append(loggers, s)
+	loggersLock.Unlock()
+	return s
+}
+
+// ApplyOptionsToLoggers applies the options to all sampling loggers.
+func ApplyOptionsToLoggers(opts Options) {
+	loggersLock.Lock()
+	defer loggersLock.Unlock()
+
+	for _, l := range loggers {
+		l.SetOptions(opts)
+	}
+}
+
+// SetOptions sets the sampling options and resets the sampling state.
+// While sampling is enabled, the suppressed duplicates are reported every interval.
+func (l *Logger) SetOptions(opts Options) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.stopFlush != nil {
+		close(l.stopFlush)
+		l.stopFlush = nil
+	}
+	l.opts = opts
+	l.entries = map[string]*entry{}
+	if opts.MaxPerInterval > 0 && opts.Interval > 0 {
+		l.stopFlush = make(chan struct{})
+		go l.flushEvery(opts.Interval, l.stopFlush)
+	}
+}
+
+// Warn logs a message at level Warn, unless it is sampled out.
+func (l *Logger) Warn(args ...interface{}) {
+	msg := fmt.Sprint(args...)
+	if l.sample("warn", msg) {
+		l.Logger.Warn(args...)
+	}
+}
+
+// Warnf logs a message at level Warn, unless it is sampled out.
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if l.sample("warn", msg) {
+		l.Logger.Warn(msg)
+	}
+}
+
+// Error logs a message at level Error, unless it is sampled out.
+func (l *Logger) Error(args ...interface{}) {
+	msg := fmt.Sprint(args...)
+	if l.sample("error", msg) {
+		l.Logger.Error(args...)
+	}
+}
+
+// Errorf logs a message at level Error, unless it is sampled out.
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if l.sample("error", msg) {
+		l.Logger.Error(msg)
+	}
+}
+
+// sample returns true if the message should be emitted. When a new interval starts for a message
+// that had duplicates dropped, the number of dropped duplicates is reported first.
+func (l *Logger) sample(level, msg string) bool {
+	l.lock.Lock()
+	if l.opts.MaxPerInterval <= 0 {
+		l.lock.Unlock()
+		return true
+	}
+
+	now := l.now()
+	key := level + "|" + msg
+	e, ok := l.entries[key]
+	if ok && now.Sub(e.windowStart) < l.opts.Interval {
+		e.count++
+		allowed := e.count <= l.opts.MaxPerInterval
+		if !allowed {
+			e.suppressed++
+		}
+		l.lock.Unlock()
+		return allowed
+	}
+
+	var ended []*entry
+	if ok {
+		ended = append(ended, e)
+	} else if len(l.entries) >= maxTrackedMessages {
+		ended = l.prune(now)
+		if len(l.entries) >= maxTrackedMessages {
+			l.lock.Unlock()
+			l.report(ended)
+			return true
+		}
+	}
+	l.entries[key] = &entry{level: level, msg: msg, windowStart: now, count: 1}
+	l.lock.Unlock()
+
+	l.report(ended)
+	return true
+}
+
+func (l *Logger) flushEvery(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			l.flush()
+		}
+	}
+}
+
+// flush reports the suppressed duplicates of the messages whose interval is over,
+// so that the count of a message that doesn't recur isn't lost.
+func (l *Logger) flush() {
+	l.lock.Lock()
+	ended := l.prune(l.now())
+	l.lock.Unlock()
+
+	l.report(ended)
+}
+
+// prune removes the messages whose interval is over and returns them. It must be called with the lock held.
+func (l *Logger) prune(now time.Time) []*entry {
+	var ended []*entry
+	for key, e := range l.entries {
+		if now.Sub(e.windowStart) >= l.opts.Interval {
+			ended = append(ended, e)
+			delete(l.entries, key)
+		}
+	}
+	return ended
+}
+
+// report logs the number of suppressed duplicates of the entries. It must be called without the lock held.
+func (l *Logger) report(entries []*entry) {
+	for _, e := range entries {
+		if e.suppressed == 0 {
+			continue
+		}
+		logf := l.Logger.Warnf
+		if e.level == "error" {
+			logf = l.Logger.Errorf
+		}
+		logf("suppressed %d duplicates of: %s", e.suppressed, e.msg)
+	}
+}
@@ -0,0 +1,117 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package logsampler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dapr/kit/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLogger struct {
+	logger.Logger
+	warnings []string
+	errors   []string
+}
+
+func (r *recordingLogger) Warn(args ...interface{}) {
+	r.warnings = append(r.warnings, fmt.Sprint(args...))
+}
+
+func (r *recordingLogger) Warnf(format string, args ...interface{}) {
+	r.warnings = append(r.warnings, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingLogger) Error(args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func (r *recordingLogger) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newTestLogger(opts Options) (*Logger, *recordingLogger, *time.Time) {
+	rec := &recordingLogger{Logger: logger.NewLogger("dapr.test.logsampler")}
+	now := time.Now()
+	l := NewLogger(rec)
+	l.SetOptions(opts)
+	l.now = func() time.Time { return now }
+	return l, rec, &now
+}
+
+func TestLogSampling(t *testing.T) {
+	t.Run("sampling disabled by default", func(t *testing.T) {
+		l, rec, _ := newTestLogger(DefaultOptions())
+		for i := 0; i < 10; i++ {
+			l.Warnf("component %s failed", "redis")
+		}
+		assert.Len(t, rec.warnings, 10)
+	})
+
+	t.Run("identical messages are limited per interval", func(t *testing.T) {
+		l, rec, _ := newTestLogger(Options{MaxPerInterval: 2, Interval: time.Minute})
+		for i := 0; i < 10; i++ {
+			l.Errorf("component %s failed", "redis")
+		}
+		l.Error("other error")
+
+		assert.Equal(t, []string{"component redis failed", "component redis failed", "other error"}, rec.errors)
+	})
+
+	t.Run("suppressed duplicates are reported in the next interval", func(t *testing.T) {
+		l, rec, now := newTestLogger(Options{MaxPerInterval: 1, Interval: time.Minute})
+		for i := 0; i < 4; i++ {
+			l.Warn("connection lost")
+		}
+		*now = now.Add(time.Minute)
+		l.Warn("connection lost")
+
+		assert.Equal(t, []string{
+			"connection lost",
+			"suppressed 3 duplicates of: connection lost",
+			"connection lost",
+		}, rec.warnings)
+	})
+
+	t.Run("suppressed duplicates are flushed at the end of the interval", func(t *testing.T) {
+		l, rec, now := newTestLogger(Options{MaxPerInterval: 1, Interval: time.Minute})
+		for i := 0; i < 3; i++ {
+			l.Error("connection lost")
+		}
+		l.flush()
+		assert.Equal(t, []string{"connection lost"}, rec.errors)
+
+		*now = now.Add(time.Minute)
+		l.flush()
+		assert.Equal(t, []string{
+			"connection lost",
+			"suppressed 2 duplicates of: connection lost",
+		}, rec.errors)
+		assert.Empty(t, l.entries)
+	})
+
+	t.Run("levels are sampled separately", func(t *testing.T) {
+		l, rec, _ := newTestLogger(Options{MaxPerInterval: 1, Interval: time.Minute})
+		l.Warn("connection lost")
+		l.Error("connection lost")
+
+		assert.Len(t, rec.warnings, 1)
+		assert.Len(t, rec.errors, 1)
+	})
+
+	t.Run("apply options to all loggers", func(t *testing.T) {
+		l, rec, _ := newTestLogger(DefaultOptions())
+		ApplyOptionsToLoggers(Options{MaxPerInterval: 1, Interval: time.Minute})
+		defer ApplyOptionsToLoggers(DefaultOptions())
+
+		l.Warn("connection lost")
+		l.Warn("connection lost")
+		assert.Len(t, rec.warnings, 1)
+	})
+}
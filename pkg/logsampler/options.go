@@ -0,0 +1,47 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package logsampler
+
+import "time"
+
+const (
+	defaultMaxPerInterval = 0
+	defaultInterval       = time.Minute
+)
+
+// Options defines the sets of options for log sampling
+type Options struct {
+	// MaxPerInterval is the number of identical warnings or errors emitted per interval.
+	// Sampling is disabled when it is 0.
+	MaxPerInterval int
+
+	// Interval is the window in which identical messages are counted.
+	Interval time.Duration
+}
+
+// DefaultOptions returns the default options, with sampling disabled.
+func DefaultOptions() Options {
+	return Options{
+		MaxPerInterval: defaultMaxPerInterval,
+		Interval:       defaultInterval,
+	}
+}
+
+// AttachCmdFlags attaches log sampling options to command flags
+func (o *Options) AttachCmdFlags(
+	intVar func(p *int, name string, value int, usage string),
+	durationVar func(p *time.Duration, name string, value time.Duration, usage string)) {
+	intVar(
+		&o.MaxPerInterval,
+		"log-sample-max",
+		defaultMaxPerInterval,
+		"Maximum number of identical warnings or errors logged per sampling interval. 0 disables log sampling")
+	durationVar(
+		&o.Interval,
+		"log-sample-interval",
+		defaultInterval,
+		"Sampling interval for identical warnings and errors")
+}
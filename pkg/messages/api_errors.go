@@ -4,6 +4,12 @@ const (
 	// Http
 	ErrMalformedRequest     = "failed deserializing HTTP body: %s"
 	ErrMalformedRequestData = "can't serialize request data field: %s"
+	ErrRequestTooLarge      = "request body size %d exceeds the maximum of %d bytes for this endpoint"
+
+	// Idempotency
+	ErrIdempotencyStore       = "failed reserving the idempotency key: %s"
+	ErrIdempotencyKeyInFlight = "a request with the same idempotency key is in progress"
+	ErrIdempotencyKeyMismatch = "the idempotency key was used for a request with a different body"
 
 	// State
 	ErrStateStoresNotConfigured = "state store is not configured"
@@ -11,14 +17,19 @@ const (
 	ErrStateGet                 = "fail to get %s from state store %s: %s"
 	ErrStateDelete              = "failed deleting state with key %s: %s"
 	ErrStateSave                = "failed saving state in state store %s: %s"
+	ErrStateETagConflicts       = "etag mismatch for %d keys"
 
 	// StateTransaction
-	ErrStateStoreNotSupported     = "state store %s doesn't support transaction"
-	ErrNotSupportedStateOperation = "operation type %s not supported"
-	ErrStateTransaction           = "error while executing state transaction: %s"
+	ErrStateStoreNotSupported         = "state store %s doesn't support transaction"
+	ErrNotSupportedStateOperation     = "operation type %s not supported"
+	ErrStateTransaction               = "error while executing state transaction: %s"
+	ErrTransactionBindingNotFound     = "output binding %s not found"
+	ErrTransactionBindingName         = "output binding name is empty"
+	ErrTransactionBindingNotSupported = "state store %s doesn't support ETags, which the output bindings of a transaction require"
 
 	// Binding
-	ErrInvokeOutputBinding = "error when invoke output binding %s: %s"
+	ErrInvokeOutputBinding  = "error when invoke output binding %s: %s"
+	ErrInputBindingNotFound = "input binding %s not found"
 
 	// PubSub
 	ErrPubsubNotConfigured      = "no pubsub is configured"
@@ -29,6 +40,8 @@ const (
 	ErrPubsubPublishMessage     = "error when publish to topic %s in pubsub %s: %s"
 	ErrPubsubForbidden          = "topic %s is not allowed for app id %s"
 	ErrPubsubCloudEventCreation = "cannot create cloudevent: %s"
+	ErrPubsubBatchEntryInvalid  = "invalid cloudevent in batch: %s"
+	ErrRefreshSubscriptions     = "failed refreshing the subscriptions: %s"
 
 	// AppChannel
 	ErrChannelNotFound       = "app channel is not initialized"
@@ -39,6 +52,8 @@ const (
 	ErrActorRuntimeNotFound      = "actor runtime is not configured"
 	ErrActorInstanceMissing      = "actor instance is missing"
 	ErrActorInvoke               = "error invoke actor method: %s"
+	ErrActorBroadcast            = "error broadcasting actor method: %s"
+	ErrActorBroadcastScope       = "invalid broadcast scope %s: must be local or global"
 	ErrActorReminderCreate       = "error creating actor reminder: %s"
 	ErrActorReminderGet          = "error getting actor reminder: %s"
 	ErrActorReminderDelete       = "error deleting actor reminder: %s"
@@ -53,6 +68,7 @@ const (
 	ErrPermissionDenied         = "access denied by policy to get %q from %q"
 	ErrSecretGet                = "failed getting secret with key %s from secret store %s: %s"
 	ErrBulkSecretGet            = "failed getting secrets from secret store %s: %s"
+	ErrSecretCacheNotEnabled    = "secrets caching is not enabled for secret store %s"
 
 	// DirectMessaging
 	ErrDirectInvoke         = "fail to invoke, id: %s, err: %s"
@@ -60,8 +76,15 @@ const (
 	ErrDirectInvokeNotReady = "invoke API is not ready"
 
 	// Metadata
-	ErrMetadataGet = "failed deserializing metadata: %s"
+	ErrMetadataGet           = "failed deserializing metadata: %s"
+	ErrProfilingNotAvailable = "profiling is not available"
+	ErrProfilingToggle       = "failed toggling the profiling server: %s"
 
 	// Healthz
 	ErrHealthNotReady = "dapr is not ready"
+
+	// mTLS
+	ErrAPITokenRequired   = "this operation requires api token authentication to be enabled"
+	ErrMTLSNotEnabled     = "mTLS is not enabled"
+	ErrRotateWorkloadCert = "failed rotating the workload certificate: %s"
 )
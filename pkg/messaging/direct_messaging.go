@@ -22,6 +22,7 @@ import (
 	"github.com/dapr/dapr/pkg/config"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
+	"github.com/dapr/dapr/pkg/logsampler"
 	"github.com/dapr/dapr/pkg/modes"
 	"github.com/dapr/dapr/pkg/retry"
 	"github.com/dapr/dapr/utils"
@@ -31,7 +32,7 @@ import (
 	internalv1pb "github.com/dapr/dapr/pkg/proto/internals/v1"
 )
 
-var log = logger.NewLogger("dapr.runtime.direct_messaging")
+var log = logsampler.NewLogger(logger.NewLogger("dapr.runtime.direct_messaging"))
 
 // messageClientConnection is the function type to connect to the other
 // applications to send the message using service invocation.
@@ -54,6 +55,8 @@ type directMessaging struct {
 	hostAddress         string
 	hostName            string
 	maxRequestBodySize  int
+	// responseHeaderFilter filters the headers of the responses of the local app.
+	responseHeaderFilter config.HeaderFilterSpec
 }
 
 type remoteApp struct {
@@ -69,21 +72,23 @@ func NewDirectMessaging(
 	appChannel channel.AppChannel,
 	clientConnFn messageClientConnection,
 	resolver nr.Resolver,
-	tracingSpec config.TracingSpec, maxRequestBodySize int) DirectMessaging {
+	tracingSpec config.TracingSpec, maxRequestBodySize int,
+	responseHeaderFilter config.HeaderFilterSpec) DirectMessaging {
 	hAddr, _ := utils.GetHostAddress()
 	hName, _ := os.Hostname()
 	return &directMessaging{
-		appChannel:          appChannel,
-		connectionCreatorFn: clientConnFn,
-		appID:               appID,
-		mode:                mode,
-		grpcPort:            port,
-		namespace:           namespace,
-		resolver:            resolver,
-		tracingSpec:         tracingSpec,
-		hostAddress:         hAddr,
-		hostName:            hName,
-		maxRequestBodySize:  maxRequestBodySize,
+		appChannel:           appChannel,
+		connectionCreatorFn:  clientConnFn,
+		appID:                appID,
+		mode:                 mode,
+		grpcPort:             port,
+		namespace:            namespace,
+		resolver:             resolver,
+		tracingSpec:          tracingSpec,
+		hostAddress:          hAddr,
+		hostName:             hName,
+		maxRequestBodySize:   maxRequestBodySize,
+		responseHeaderFilter: responseHeaderFilter,
 	}
 }
 
@@ -149,7 +154,24 @@ func (d *directMessaging) invokeLocal(ctx context.Context, req *invokev1.InvokeM
 		return nil, errors.New("cannot invoke local endpoint: app channel not initialized")
 	}
 
-	return d.appChannel.InvokeMethod(ctx, req)
+	resp, err := d.appChannel.InvokeMethod(ctx, req)
+	if err == nil && resp != nil {
+		FilterResponseHeaders(resp.Headers(), d.responseHeaderFilter)
+	}
+	return resp, err
+}
+
+// FilterResponseHeaders removes the app response headers that must not be forwarded to the caller.
+// The content type is always forwarded since the caller needs it to read the response.
+func FilterResponseHeaders(headers invokev1.DaprInternalMetadata, filter config.HeaderFilterSpec) {
+	if filter.IsEmpty() {
+		return
+	}
+	for k := range headers {
+		if !strings.EqualFold(k, fasthttp.HeaderContentType) && !filter.IsAllowed(k) {
+			delete(headers, k)
+		}
+	}
 }
 
 func (d *directMessaging) invokeRemote(ctx context.Context, appID, namespace, appAddress string, req *invokev1.InvokeMethodRequest) (*invokev1.InvokeMethodResponse, error) {
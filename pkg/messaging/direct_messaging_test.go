@@ -6,11 +6,16 @@
 package messaging
 
 import (
+	"context"
 	"testing"
 
+	channelt "github.com/dapr/dapr/pkg/channel/testing"
+	"github.com/dapr/dapr/pkg/config"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc/metadata"
 )
 
 func newDirectMessaging() *directMessaging {
@@ -111,3 +116,29 @@ func TestKubernetesNamespace(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestInvokeLocalResponseHeaders(t *testing.T) {
+	fakeResp := invokev1.NewInvokeMethodResponse(200, "OK", nil).WithHeaders(metadata.MD{
+		"Content-Type":     []string{"application/json"},
+		"X-Internal-Debug": []string{"true"},
+		"X-Request-Id":     []string{"1"},
+		"Set-Cookie":       []string{"a=b"},
+	})
+	mockAppChannel := new(channelt.MockAppChannel)
+	mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).Return(fakeResp, nil)
+
+	dm := newDirectMessaging()
+	dm.appChannel = mockAppChannel
+	dm.responseHeaderFilter = config.HeaderFilterSpec{
+		Allow: []string{"x-*"},
+		Deny:  []string{"X-Internal-*"},
+	}
+
+	resp, err := dm.invokeLocal(context.Background(), invokev1.NewInvokeMethodRequest("method"))
+	assert.NoError(t, err)
+	headers := resp.Headers()
+	assert.Contains(t, headers, "Content-Type")
+	assert.Contains(t, headers, "X-Request-Id")
+	assert.NotContains(t, headers, "X-Internal-Debug")
+	assert.NotContains(t, headers, "Set-Cookie")
+}
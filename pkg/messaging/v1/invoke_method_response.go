@@ -59,6 +59,19 @@ func (imr *InvokeMethodResponse) WithRawData(data []byte, contentType string) *I
 	return imr
 }
 
+// WithRawDataNoCopy sets Message using byte data and content type without copying data,
+// so the caller must hand over the ownership of data and must not modify it afterwards.
+func (imr *InvokeMethodResponse) WithRawDataNoCopy(data []byte, contentType string) *InvokeMethodResponse {
+	if contentType == "" {
+		contentType = JSONContentType
+	}
+
+	imr.r.Message.ContentType = contentType
+	imr.r.Message.Data = &anypb.Any{Value: data}
+
+	return imr
+}
+
 // WithHeaders sets gRPC response header metadata
 func (imr *InvokeMethodResponse) WithHeaders(headers metadata.MD) *InvokeMethodResponse {
 	imr.r.Headers = MetadataToInternalMetadata(headers)
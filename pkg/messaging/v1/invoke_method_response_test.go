@@ -72,6 +72,16 @@ func TestResponseData(t *testing.T) {
 		assert.Equal(t, []byte("test"), bData)
 	})
 
+	t.Run("data is not copied", func(t *testing.T) {
+		data := []byte("test")
+		resp := NewInvokeMethodResponse(0, "OK", nil)
+		resp.WithRawDataNoCopy(data, "")
+		data[0] = 'b'
+		contentType, bData := resp.RawData()
+		assert.Equal(t, "application/json", contentType)
+		assert.Equal(t, []byte("best"), bData)
+	})
+
 	t.Run("typeurl is set but content_type is unset", func(t *testing.T) {
 		s := &commonv1pb.StateItem{Key: "custom_key"}
 		b, err := anypb.New(s)
@@ -0,0 +1,87 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package grpc
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	grpc_go "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dapr/components-contrib/middleware"
+)
+
+const (
+	// RateLimitName is the name of the middleware limiting the requests per second of each gRPC method.
+	RateLimitName = "ratelimit"
+	// RateLimitMaxRequestsPerSecondKey is the metadata key of the limit of each method.
+	RateLimitMaxRequestsPerSecondKey = "maxRequestsPerSecond"
+)
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type rateLimiter struct {
+	limit   float64
+	lock    *sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+// NewRateLimitMiddleware returns an interceptor limiting the requests per second of each method
+// of the Dapr gRPC API. Requests over the limit fail with ResourceExhausted.
+func NewRateLimitMiddleware(metadata middleware.Metadata) (grpc_go.UnaryServerInterceptor, error) {
+	val := metadata.Properties[RateLimitMaxRequestsPerSecondKey]
+	limit, err := strconv.ParseFloat(val, 64)
+	if err != nil || limit <= 0 {
+		return nil, errors.Errorf("invalid %s: %s", RateLimitMaxRequestsPerSecondKey, val)
+	}
+
+	limiter := &rateLimiter{
+		limit:   limit,
+		lock:    &sync.Mutex{},
+		buckets: map[string]*tokenBucket{},
+		now:     time.Now,
+	}
+	return limiter.intercept, nil
+}
+
+func (l *rateLimiter) intercept(ctx context.Context, req interface{}, info *grpc_go.UnaryServerInfo, handler grpc_go.UnaryHandler) (interface{}, error) {
+	if !l.take(info.FullMethod) {
+		return nil, status.Errorf(codes.ResourceExhausted, "rate limit of %s exceeded", info.FullMethod)
+	}
+	return handler(ctx, req)
+}
+
+// take removes a token from the bucket of the method, refilled at limit tokens per second up to limit tokens.
+func (l *rateLimiter) take(method string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	bucket, ok := l.buckets[method]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.limit}
+		l.buckets[method] = bucket
+	} else if elapsed := now.Sub(bucket.updated).Seconds(); elapsed > 0 {
+		bucket.tokens = math.Min(l.limit, bucket.tokens+elapsed*l.limit)
+	}
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
@@ -0,0 +1,70 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	grpc_go "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dapr/components-contrib/middleware"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	t.Run("invalid limit", func(t *testing.T) {
+		for _, val := range []string{"", "abc", "0", "-1"} {
+			_, err := NewRateLimitMiddleware(middleware.Metadata{Properties: map[string]string{
+				RateLimitMaxRequestsPerSecondKey: val,
+			}})
+			assert.Error(t, err, val)
+		}
+	})
+
+	t.Run("valid limit", func(t *testing.T) {
+		interceptor, err := NewRateLimitMiddleware(middleware.Metadata{Properties: map[string]string{
+			RateLimitMaxRequestsPerSecondKey: "10",
+		}})
+		assert.NoError(t, err)
+		assert.NotNil(t, interceptor)
+	})
+
+	t.Run("requests over the limit are rejected", func(t *testing.T) {
+		now := time.Now()
+		limiter := &rateLimiter{
+			limit:   2,
+			lock:    &sync.Mutex{},
+			buckets: map[string]*tokenBucket{},
+			now:     func() time.Time { return now },
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "ok", nil
+		}
+		invoke := func(method string) error {
+			_, err := limiter.intercept(context.Background(), nil, &grpc_go.UnaryServerInfo{FullMethod: method}, handler)
+			return err
+		}
+
+		assert.NoError(t, invoke("/dapr.proto.runtime.v1.Dapr/GetState"))
+		assert.NoError(t, invoke("/dapr.proto.runtime.v1.Dapr/GetState"))
+		err := invoke("/dapr.proto.runtime.v1.Dapr/GetState")
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		// methods have their own limits
+		assert.NoError(t, invoke("/dapr.proto.runtime.v1.Dapr/SaveState"))
+
+		// tokens are refilled over time
+		now = now.Add(500 * time.Millisecond)
+		assert.NoError(t, invoke("/dapr.proto.runtime.v1.Dapr/GetState"))
+		err = invoke("/dapr.proto.runtime.v1.Dapr/GetState")
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
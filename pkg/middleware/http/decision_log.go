@@ -0,0 +1,93 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/dapr/pkg/jsonlines"
+)
+
+// StdoutDecisionSink is the decision log sink writing the decisions to stdout as JSON lines.
+const StdoutDecisionSink = jsonlines.StdoutSink
+
+const decisionUserValuePrefix = "dapr-middleware-decision-"
+
+// Decision is the outcome of a middleware of the pipeline for a request.
+type Decision struct {
+	Time       time.Time `json:"time"`
+	Middleware string    `json:"middleware"`
+	Type       string    `json:"type"`
+	Allowed    bool      `json:"allowed"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	RemoteIP   string    `json:"remoteIP"`
+}
+
+// DecisionSink receives the decisions of the middlewares of the pipeline.
+type DecisionSink interface {
+	WriteDecision(decision Decision)
+}
+
+type jsonDecisionSink struct {
+	writer *jsonlines.Writer
+}
+
+// NewDecisionSink returns the decision log sink with the given name.
+func NewDecisionSink(name string) (DecisionSink, error) {
+	writer, ok := jsonlines.NewSinkWriter(name)
+	if !ok {
+		return nil, errors.Errorf("decision log sink %s is not supported", name)
+	}
+	return &jsonDecisionSink{writer: writer}, nil
+}
+
+// NewJSONDecisionSink returns a decision log sink writing the decisions to w as JSON lines.
+func NewJSONDecisionSink(w io.Writer) DecisionSink {
+	return &jsonDecisionSink{writer: jsonlines.NewWriter(w)}
+}
+
+func (s *jsonDecisionSink) WriteDecision(decision Decision) {
+	// decisions are best effort and never fail the request
+	_ = s.writer.Write(decision)
+}
+
+// WithDecisionLog returns a middleware writing to sink whether middleware let each request through.
+// A request is denied when the middleware responds without calling the next handler of the pipeline.
+func WithDecisionLog(name, middlewareType string, middleware Middleware, sink DecisionSink) Middleware {
+	key := decisionUserValuePrefix + name
+
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		handler := middleware(func(ctx *fasthttp.RequestCtx) {
+			ctx.SetUserValue(key, true)
+			next(ctx)
+		})
+
+		return func(ctx *fasthttp.RequestCtx) {
+			handler(ctx)
+
+			allowed, _ := ctx.UserValue(key).(bool)
+			decision := Decision{
+				Time:       time.Now().UTC(),
+				Middleware: name,
+				Type:       middlewareType,
+				Allowed:    allowed,
+				Method:     string(ctx.Method()),
+				Path:       string(ctx.Path()),
+				RemoteIP:   ctx.RemoteIP().String(),
+			}
+			if !allowed {
+				decision.StatusCode = ctx.Response.StatusCode()
+			}
+			sink.WriteDecision(decision)
+		}
+	}
+}
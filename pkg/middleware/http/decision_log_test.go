@@ -0,0 +1,98 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+type decisionRecorder struct {
+	decisions []Decision
+}
+
+func (r *decisionRecorder) WriteDecision(decision Decision) {
+	r.decisions = append(r.decisions, decision)
+}
+
+func TestWithDecisionLog(t *testing.T) {
+	denyDelete := func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			if string(ctx.Method()) == fasthttp.MethodDelete {
+				ctx.SetStatusCode(fasthttp.StatusForbidden)
+				return
+			}
+			next(ctx)
+		}
+	}
+
+	request := func(method string) *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(method)
+		ctx.Request.SetRequestURI("/v1.0/state/statestore")
+		return ctx
+	}
+
+	t.Run("allowed request", func(t *testing.T) {
+		recorder := &decisionRecorder{}
+		called := false
+		handler := WithDecisionLog("opa", "middleware.http.opa", denyDelete, recorder)(func(ctx *fasthttp.RequestCtx) {
+			called = true
+		})
+
+		handler(request(fasthttp.MethodGet))
+
+		assert.True(t, called)
+		assert.Len(t, recorder.decisions, 1)
+		decision := recorder.decisions[0]
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, "opa", decision.Middleware)
+		assert.Equal(t, "middleware.http.opa", decision.Type)
+		assert.Equal(t, fasthttp.MethodGet, decision.Method)
+		assert.Equal(t, "/v1.0/state/statestore", decision.Path)
+		assert.Zero(t, decision.StatusCode)
+	})
+
+	t.Run("denied request", func(t *testing.T) {
+		recorder := &decisionRecorder{}
+		called := false
+		handler := WithDecisionLog("opa", "middleware.http.opa", denyDelete, recorder)(func(ctx *fasthttp.RequestCtx) {
+			called = true
+		})
+
+		handler(request(fasthttp.MethodDelete))
+
+		assert.False(t, called)
+		assert.Len(t, recorder.decisions, 1)
+		assert.False(t, recorder.decisions[0].Allowed)
+		assert.Equal(t, fasthttp.StatusForbidden, recorder.decisions[0].StatusCode)
+	})
+}
+
+func TestJSONDecisionSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONDecisionSink(&buf)
+
+	sink.WriteDecision(Decision{Middleware: "opa", Allowed: false, StatusCode: fasthttp.StatusForbidden})
+
+	var decision Decision
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decision))
+	assert.Equal(t, "opa", decision.Middleware)
+	assert.Equal(t, fasthttp.StatusForbidden, decision.StatusCode)
+}
+
+func TestNewDecisionSink(t *testing.T) {
+	sink, err := NewDecisionSink("stdout")
+	assert.NoError(t, err)
+	assert.NotNil(t, sink)
+
+	_, err = NewDecisionSink("otlp")
+	assert.Error(t, err)
+}
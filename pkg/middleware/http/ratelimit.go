@@ -0,0 +1,186 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/dapr/components-contrib/state"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// DistributedRateLimitName is the name of the rate limit middleware sharing its limits across the replicas of an app.
+	DistributedRateLimitName = "ratelimit.distributed"
+	// RateLimitStateStoreKey is the metadata key of the state store holding the token buckets.
+	RateLimitStateStoreKey = "stateStore"
+	// RateLimitMaxRequestsPerSecondKey is the metadata key of the limit of the routes without a limit of their own.
+	RateLimitMaxRequestsPerSecondKey = "maxRequestsPerSecond"
+	// RateLimitRoutesKey is the metadata key of the per route limits, e.g. "/v1.0/state=100,/v1.0/publish=10".
+	RateLimitRoutesKey = "routes"
+
+	rateLimitKeyPrefix = "ratelimit"
+	// rateLimitMaxAttempts bounds the retries of a token bucket update conflicting with another replica.
+	rateLimitMaxAttempts = 3
+)
+
+var log = logger.NewLogger("dapr.runtime.http.middleware")
+
+type routeLimit struct {
+	prefix string
+	limit  float64
+}
+
+type tokenBucket struct {
+	Tokens  float64   `json:"tokens"`
+	Updated time.Time `json:"updated"`
+}
+
+type distributedRateLimiter struct {
+	appID        string
+	store        state.Store
+	defaultLimit float64
+	routes       []routeLimit
+}
+
+// NewDistributedRateLimitMiddleware returns a middleware limiting the requests per second of an app with
+// token buckets kept in a state store, so that the limits apply across all the replicas of the app.
+// The buckets are per app-id and route. Requests over the limit get a 429 response.
+// The middleware runs on the Dapr HTTP API, so it limits the calls of the app to its sidecar: the calls of
+// other apps reach the sidecar over the internal gRPC API and are not limited.
+func NewDistributedRateLimitMiddleware(appID string, store state.Store, metadata map[string]string) (Middleware, error) {
+	if store == nil {
+		return nil, errors.Errorf("state store %s not found", metadata[RateLimitStateStoreKey])
+	}
+
+	limiter := &distributedRateLimiter{
+		appID: appID,
+		store: store,
+	}
+
+	if val := metadata[RateLimitMaxRequestsPerSecondKey]; val != "" {
+		limit, err := strconv.ParseFloat(val, 64)
+		if err != nil || limit < 0 {
+			return nil, errors.Errorf("invalid %s: %s", RateLimitMaxRequestsPerSecondKey, val)
+		}
+		limiter.defaultLimit = limit
+	}
+
+	if val := metadata[RateLimitRoutesKey]; val != "" {
+		for _, route := range strings.Split(val, ",") {
+			parts := strings.SplitN(strings.TrimSpace(route), "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				return nil, errors.Errorf("invalid %s: %s", RateLimitRoutesKey, route)
+			}
+			limit, err := strconv.ParseFloat(parts[1], 64)
+			if err != nil || limit < 0 {
+				return nil, errors.Errorf("invalid %s: %s", RateLimitRoutesKey, route)
+			}
+			limiter.routes = append(limiter.routes, routeLimit{prefix: parts[0], limit: limit})
+		}
+		// the longest matching prefix wins
+		sort.SliceStable(limiter.routes, func(i, j int) bool {
+			return len(limiter.routes[i].prefix) > len(limiter.routes[j].prefix)
+		})
+	}
+
+	return limiter.middleware, nil
+}
+
+func (l *distributedRateLimiter) middleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		if !l.allowed(string(ctx.Path())) {
+			ctx.Error(fasthttp.StatusMessage(fasthttp.StatusTooManyRequests), fasthttp.StatusTooManyRequests)
+			return
+		}
+		next(ctx)
+	}
+}
+
+// allowed returns whether a request to the path is within the limits.
+func (l *distributedRateLimiter) allowed(path string) bool {
+	route, limit := l.routeLimit(path)
+	if limit == 0 {
+		return true
+	}
+
+	allowed, err := l.take(l.bucketKey(route), limit, time.Now().UTC())
+	if err != nil {
+		// the rate limit is best effort, requests are let through when the buckets can't be read
+		log.Warnf("failed to check the rate limit of route %s: %s", route, err)
+		return true
+	}
+	return allowed
+}
+
+// routeLimit returns the route a path is limited by and its requests per second, 0 meaning unlimited.
+func (l *distributedRateLimiter) routeLimit(path string) (string, float64) {
+	for _, r := range l.routes {
+		if strings.HasPrefix(path, r.prefix) {
+			return r.prefix, r.limit
+		}
+	}
+	return "", l.defaultLimit
+}
+
+func (l *distributedRateLimiter) bucketKey(route string) string {
+	return strings.Join([]string{l.appID, rateLimitKeyPrefix, route}, "||")
+}
+
+// take removes a token from the bucket, refilled at limit tokens per second up to limit tokens.
+func (l *distributedRateLimiter) take(key string, limit float64, now time.Time) (bool, error) {
+	for attempt := 0; attempt < rateLimitMaxAttempts; attempt++ {
+		resp, err := l.store.Get(&state.GetRequest{Key: key})
+		if err != nil {
+			return false, err
+		}
+
+		bucket := tokenBucket{Tokens: limit}
+		var etag *string
+		if resp != nil && len(resp.Data) > 0 {
+			if err = json.Unmarshal(resp.Data, &bucket); err != nil {
+				return false, err
+			}
+			elapsed := now.Sub(bucket.Updated).Seconds()
+			if elapsed > 0 {
+				bucket.Tokens = math.Min(limit, bucket.Tokens+elapsed*limit)
+			}
+			etag = resp.ETag
+		}
+
+		if bucket.Tokens < 1 {
+			return false, nil
+		}
+		bucket.Tokens--
+		bucket.Updated = now
+
+		err = l.store.Set(&state.SetRequest{
+			Key:   key,
+			Value: bucket,
+			ETag:  etag,
+			Options: state.SetStateOption{
+				Concurrency: "first-write",
+			},
+		})
+		if err == nil {
+			return true, nil
+		}
+		if _, ok := err.(*state.ETagError); !ok {
+			return false, err
+		}
+	}
+
+	// another replica kept taking the tokens
+	return false, nil
+}
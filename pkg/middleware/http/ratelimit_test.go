@@ -0,0 +1,100 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package http
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	daprt "github.com/dapr/dapr/pkg/testing"
+)
+
+func TestNewDistributedRateLimitMiddleware(t *testing.T) {
+	store := daprt.NewFakeETagStateStore()
+
+	t.Run("state store not found", func(t *testing.T) {
+		_, err := NewDistributedRateLimitMiddleware("app1", nil, map[string]string{RateLimitStateStoreKey: "statestore"})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+		for _, metadata := range []map[string]string{
+			{RateLimitMaxRequestsPerSecondKey: "ten"},
+			{RateLimitMaxRequestsPerSecondKey: "-1"},
+			{RateLimitRoutesKey: "/v1.0/state"},
+			{RateLimitRoutesKey: "=10"},
+			{RateLimitRoutesKey: "/v1.0/state=ten"},
+		} {
+			_, err := NewDistributedRateLimitMiddleware("app1", store, metadata)
+			assert.Error(t, err, metadata)
+		}
+	})
+}
+
+func TestDistributedRateLimitMiddleware(t *testing.T) {
+	request := func(handler fasthttp.RequestHandler, path string) int {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.SetRequestURI(path)
+		handler(ctx)
+		return ctx.Response.StatusCode()
+	}
+	ok := func(ctx *fasthttp.RequestCtx) {}
+
+	t.Run("limits are shared by the replicas of an app", func(t *testing.T) {
+		store := daprt.NewFakeETagStateStore()
+		metadata := map[string]string{RateLimitMaxRequestsPerSecondKey: "2"}
+		replica1, err := NewDistributedRateLimitMiddleware("app1", store, metadata)
+		assert.NoError(t, err)
+		replica2, err := NewDistributedRateLimitMiddleware("app1", store, metadata)
+		assert.NoError(t, err)
+
+		assert.Equal(t, fasthttp.StatusOK, request(replica1(ok), "/v1.0/state/store1"))
+		assert.Equal(t, fasthttp.StatusOK, request(replica2(ok), "/v1.0/state/store1"))
+		assert.Equal(t, fasthttp.StatusTooManyRequests, request(replica1(ok), "/v1.0/state/store1"))
+
+		// other apps have buckets of their own
+		other, err := NewDistributedRateLimitMiddleware("app2", store, metadata)
+		assert.NoError(t, err)
+		assert.Equal(t, fasthttp.StatusOK, request(other(ok), "/v1.0/state/store1"))
+	})
+
+	t.Run("per route limits", func(t *testing.T) {
+		store := daprt.NewFakeETagStateStore()
+		m, err := NewDistributedRateLimitMiddleware("app1", store, map[string]string{
+			RateLimitRoutesKey: "/v1.0/state=1, /v1.0/state/store2=0",
+		})
+		assert.NoError(t, err)
+		handler := m(ok)
+
+		assert.Equal(t, fasthttp.StatusOK, request(handler, "/v1.0/state/store1"))
+		assert.Equal(t, fasthttp.StatusTooManyRequests, request(handler, "/v1.0/state/store1"))
+		// the longest prefix wins, 0 is unlimited
+		assert.Equal(t, fasthttp.StatusOK, request(handler, "/v1.0/state/store2"))
+		assert.Equal(t, fasthttp.StatusOK, request(handler, "/v1.0/state/store2"))
+		// routes without limits
+		assert.Equal(t, fasthttp.StatusOK, request(handler, "/v1.0/publish/pubsub/topic"))
+		assert.Equal(t, fasthttp.StatusOK, request(handler, "/v1.0/publish/pubsub/topic"))
+	})
+
+	t.Run("tokens are refilled", func(t *testing.T) {
+		store := daprt.NewFakeETagStateStore()
+		limiter := &distributedRateLimiter{appID: "app1", store: store}
+		now := time.Now().UTC()
+
+		allowed, err := limiter.take("key", 1, now)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		allowed, err = limiter.take("key", 1, now.Add(500*time.Millisecond))
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		allowed, err = limiter.take("key", 1, now.Add(time.Second))
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+}
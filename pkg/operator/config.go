@@ -12,8 +12,9 @@ import (
 
 // Config returns an operator config options
 type Config struct {
-	MTLSEnabled bool
-	Credentials credentials.TLSCredentials
+	MTLSEnabled     bool
+	Credentials     credentials.TLSCredentials
+	ComponentQuotas []v1alpha1.ComponentQuotaSpec
 }
 
 // LoadConfiguration loads the Kubernetes configuration and returns an Operator Config
@@ -27,6 +28,7 @@ func LoadConfiguration(name string, client client.Client) (*Config, error) {
 		return nil, err
 	}
 	return &Config{
-		MTLSEnabled: conf.Spec.MTLSSpec.Enabled,
+		MTLSEnabled:     conf.Spec.MTLSSpec.Enabled,
+		ComponentQuotas: conf.Spec.ComponentQuotas,
 	}, nil
 }
@@ -7,6 +7,7 @@ package monitoring
 
 import (
 	"context"
+	"time"
 
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	"go.opencensus.io/stats"
@@ -31,6 +32,10 @@ var (
 		"operator/service_updated_total",
 		"The total number of dapr services updated.",
 		stats.UnitDimensionless)
+	issuerCertExpiryTimestamp = stats.Int64(
+		"operator/issuercert/expiry_timestamp",
+		"The unix timestamp, in seconds, when the issuer cert used by the operator will expire.",
+		stats.UnitDimensionless)
 
 	// appIDKey is a tag key for App ID
 	appIDKey = tag.MustNewKey(appID)
@@ -51,12 +56,18 @@ func RecordServiceUpdatedCount(appID string) {
 	stats.RecordWithTags(context.Background(), diag_utils.WithTags(appIDKey, appID), serviceUpdatedTotal.M(1))
 }
 
+// RecordIssuerCertExpiry records the expiry of the issuer cert used by the operator
+func RecordIssuerCertExpiry(expiry time.Time) {
+	stats.Record(context.Background(), issuerCertExpiryTimestamp.M(expiry.Unix()))
+}
+
 // InitMetrics initialize the operator service metrics
 func InitMetrics() error {
 	err := view.Register(
 		diag_utils.NewMeasureView(serviceCreatedTotal, []tag.Key{appIDKey}, view.Count()),
 		diag_utils.NewMeasureView(serviceDeletedTotal, []tag.Key{appIDKey}, view.Count()),
 		diag_utils.NewMeasureView(serviceUpdatedTotal, []tag.Key{appIDKey}, view.Count()),
+		diag_utils.NewMeasureView(issuerCertExpiryTimestamp, []tag.Key{}, view.LastValue()),
 	)
 
 	return err
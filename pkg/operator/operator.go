@@ -7,6 +7,8 @@ package operator
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 
 	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
@@ -16,6 +18,8 @@ import (
 	"github.com/dapr/dapr/pkg/health"
 	"github.com/dapr/dapr/pkg/operator/api"
 	"github.com/dapr/dapr/pkg/operator/handlers"
+	"github.com/dapr/dapr/pkg/operator/monitoring"
+	"github.com/dapr/dapr/pkg/operator/validation"
 	"github.com/dapr/kit/logger"
 	"k8s.io/apimachinery/pkg/runtime"
 	runtimeutil "k8s.io/apimachinery/pkg/util/runtime"
@@ -41,9 +45,10 @@ type operator struct {
 	daprHandler handlers.Handler
 	apiServer   api.Server
 
-	configName    string
-	certChainPath string
-	config        *Config
+	configName     string
+	certChainPath  string
+	webhookCertDir string
+	config         *Config
 
 	mgr    ctrl.Manager
 	client client.Client
@@ -61,8 +66,9 @@ func init() {
 	_ = subscriptionsapi.AddToScheme(scheme)
 }
 
-// NewOperator returns a new Dapr Operator
-func NewOperator(config, certChainPath string, enableLeaderElection bool) Operator {
+// NewOperator returns a new Dapr Operator.
+// The component validation webhook is served when webhookCertDir is set.
+func NewOperator(config, certChainPath string, enableLeaderElection bool, webhookCertDir string) Operator {
 	conf, err := ctrl.GetConfig()
 	if err != nil {
 		log.Fatalf("unable to get controller runtime configuration, err: %s", err)
@@ -82,11 +88,12 @@ func NewOperator(config, certChainPath string, enableLeaderElection bool) Operat
 	}
 
 	o := &operator{
-		daprHandler:   daprHandler,
-		mgr:           mgr,
-		client:        mgr.GetClient(),
-		configName:    config,
-		certChainPath: certChainPath,
+		daprHandler:    daprHandler,
+		mgr:            mgr,
+		client:         mgr.GetClient(),
+		configName:     config,
+		certChainPath:  certChainPath,
+		webhookCertDir: webhookCertDir,
 	}
 	o.apiServer = api.NewAPIServer(o.client)
 	if componentInfomer, err := mgr.GetCache().GetInformer(context.TODO(), &componentsapi.Component{}); err != nil {
@@ -111,6 +118,14 @@ func (o *operator) prepareConfig() {
 	o.config.Credentials = credentials.NewTLSCredentials(o.certChainPath)
 }
 
+func (o *operator) listComponents(ctx context.Context, namespace string) ([]componentsapi.Component, error) {
+	var components componentsapi.ComponentList
+	if err := o.client.List(ctx, &components, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	return components.Items, nil
+}
+
 func (o *operator) syncComponent(obj interface{}) {
 	c, ok := obj.(*componentsapi.Component)
 	if ok {
@@ -119,6 +134,29 @@ func (o *operator) syncComponent(obj interface{}) {
 	}
 }
 
+// watchComponentQuotas keeps the quotas enforced in sync with the operator configuration.
+func (o *operator) watchComponentQuotas(ctx context.Context, quotas *validation.QuotaEnforcer) {
+	informer, err := o.mgr.GetCache().GetInformer(ctx, &configurationapi.Configuration{})
+	if err != nil {
+		log.Errorf("unable to watch the component quotas, err: %s", err)
+		return
+	}
+	sync := func(obj interface{}) {
+		c, ok := obj.(*configurationapi.Configuration)
+		if !ok || c.Name != o.configName || c.Namespace != os.Getenv("NAMESPACE") {
+			return
+		}
+		log.Infof("updating the component quotas from configuration %s", c.Name)
+		quotas.SetQuotas(c.Spec.ComponentQuotas)
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: sync,
+		UpdateFunc: func(_, newObj interface{}) {
+			sync(newObj)
+		},
+	})
+}
+
 func (o *operator) Run(ctx context.Context) {
 	defer runtimeutil.HandleCrash()
 	ctx, cancel := context.WithCancel(ctx)
@@ -168,6 +206,23 @@ func (o *operator) Run(ctx context.Context) {
 		}
 		certChain = chain
 		log.Info("tls certificates loaded successfully")
+		if expiry, err := chain.CertExpiry(); err != nil {
+			log.Warnf("failed to read the issuer cert expiry: %s", err)
+		} else {
+			monitoring.RecordIssuerCertExpiry(expiry)
+		}
+	}
+
+	if o.webhookCertDir != "" {
+		quotas := validation.NewQuotaEnforcer(o.config.ComponentQuotas, o.listComponents)
+		o.watchComponentQuotas(ctx, quotas)
+		webhook := validation.NewWebhook(
+			validation.NewValidator(validation.DefaultRegistry()),
+			quotas,
+			filepath.Join(o.webhookCertDir, "tls.crt"),
+			filepath.Join(o.webhookCertDir, "tls.key"),
+		)
+		go webhook.Run(ctx)
 	}
 
 	go func() {
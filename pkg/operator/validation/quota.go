@@ -0,0 +1,107 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package validation
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+)
+
+const quotaListTimeout = time.Second * 5
+
+// ComponentLister lists the components of a namespace.
+type ComponentLister func(ctx context.Context, namespace string) ([]componentsapi.Component, error)
+
+// QuotaEnforcer checks components against the component quotas of their namespace.
+type QuotaEnforcer struct {
+	quotas []configurationapi.ComponentQuotaSpec
+	lock   *sync.RWMutex
+	lister ComponentLister
+}
+
+// NewQuotaEnforcer returns a new QuotaEnforcer for the given quotas, using lister to count the existing components.
+func NewQuotaEnforcer(quotas []configurationapi.ComponentQuotaSpec, lister ComponentLister) *QuotaEnforcer {
+	return &QuotaEnforcer{
+		quotas: quotas,
+		lock:   &sync.RWMutex{},
+		lister: lister,
+	}
+}
+
+// SetQuotas replaces the quotas enforced, e.g. when the configuration holding them is updated.
+func (q *QuotaEnforcer) SetQuotas(quotas []configurationapi.ComponentQuotaSpec) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.quotas = quotas
+}
+
+// EnforceQuotas adds an error to res for every quota of namespace the component exceeds.
+// The namespace is the one of the admission request, as the component manifest may omit it.
+func (q *QuotaEnforcer) EnforceQuotas(namespace string, component *componentsapi.Component, res *Result) {
+	q.lock.RLock()
+	quotas := q.quotas
+	q.lock.RUnlock()
+
+	var existing []componentsapi.Component
+	listed := false
+
+	for _, quota := range quotas {
+		if !quotaAppliesTo(quota, namespace) {
+			continue
+		}
+
+		for _, t := range quota.DeniedTypes {
+			if strings.EqualFold(t, component.Spec.Type) {
+				res.errorf("component type %s is not allowed in namespace %s", component.Spec.Type, namespace)
+				break
+			}
+		}
+
+		if quota.MaxComponents <= 0 {
+			continue
+		}
+		if !listed {
+			ctx, cancel := context.WithTimeout(context.Background(), quotaListTimeout)
+			var err error
+			existing, err = q.lister(ctx, namespace)
+			cancel()
+			if err != nil {
+				res.errorf("could not check the component quota of namespace %s: %s", namespace, err)
+				return
+			}
+			listed = true
+		}
+
+		// updates of an existing component don't add to the count
+		count := 1
+		for i := range existing {
+			if existing[i].Name != component.Name {
+				count++
+			}
+		}
+		if count > quota.MaxComponents {
+			res.errorf("namespace %s is limited to %d component(s)", namespace, quota.MaxComponents)
+		}
+	}
+}
+
+func quotaAppliesTo(quota configurationapi.ComponentQuotaSpec, namespace string) bool {
+	if len(quota.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range quota.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FieldType is the expected type of a component metadata value.
+type FieldType string
+
+const (
+	FieldTypeString   FieldType = "string"
+	FieldTypeNumber   FieldType = "number"
+	FieldTypeBool     FieldType = "bool"
+	FieldTypeDuration FieldType = "duration"
+)
+
+// FieldSchema describes a single component metadata field.
+type FieldSchema struct {
+	Name          string
+	Type          FieldType
+	Required      bool
+	AllowedValues []string
+	Description   string
+}
+
+// MetadataSchema describes the metadata fields accepted by a component type and version.
+type MetadataSchema struct {
+	Type    string
+	Version string
+	Fields  []FieldSchema
+}
+
+// Registry holds the metadata schemas of known component types.
+type Registry struct {
+	schemas map[string]MetadataSchema
+	lock    *sync.RWMutex
+}
+
+// NewRegistry returns an empty schema registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		schemas: map[string]MetadataSchema{},
+		lock:    &sync.RWMutex{},
+	}
+}
+
+// DefaultRegistry returns a registry populated with the built-in component schemas.
+// The components don't publish the schemas of their metadata, so the built-in schemas only cover
+// state.redis, pubsub.redis, bindings.cron, secretstores.local.file and secretstores.local.env.
+// The metadata of the other component types is not validated, the validator warns about it.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, s := range builtinSchemas {
+		r.Register(s)
+	}
+	return r
+}
+
+// Register adds or replaces the schema for a component type and version.
+func (r *Registry) Register(schema MetadataSchema) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.schemas[schemaKey(schema.Type, schema.Version)] = schema
+}
+
+// Get returns the schema for a component type and version, if one is registered.
+func (r *Registry) Get(componentType, version string) (MetadataSchema, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	s, ok := r.schemas[schemaKey(componentType, version)]
+	return s, ok
+}
+
+func schemaKey(componentType, version string) string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(componentType), strings.ToLower(version))
+}
+
+// builtinSchemas are maintained by hand from the metadata the components read. Adding a component
+// type here makes the webhook reject the components of that type with invalid metadata.
+var builtinSchemas = []MetadataSchema{
+	{
+		Type:    "state.redis",
+		Version: "v1",
+		Fields: []FieldSchema{
+			{Name: "redisHost", Type: FieldTypeString, Required: true, Description: "address of the redis server, in the host:port format"},
+			{Name: "redisPassword", Type: FieldTypeString},
+			{Name: "enableTLS", Type: FieldTypeBool},
+			{Name: "maxRetries", Type: FieldTypeNumber},
+			{Name: "maxRetryBackoff", Type: FieldTypeDuration},
+			{Name: "failover", Type: FieldTypeBool},
+			{Name: "sentinelMasterName", Type: FieldTypeString},
+			{Name: "redeliverInterval", Type: FieldTypeDuration},
+			{Name: "processingTimeout", Type: FieldTypeDuration},
+			{Name: "redisType", Type: FieldTypeString, AllowedValues: []string{"node", "cluster"}},
+			{Name: "redisDB", Type: FieldTypeNumber},
+			{Name: "actorStateStore", Type: FieldTypeBool},
+		},
+	},
+	{
+		Type:    "pubsub.redis",
+		Version: "v1",
+		Fields: []FieldSchema{
+			{Name: "redisHost", Type: FieldTypeString, Required: true, Description: "address of the redis server, in the host:port format"},
+			{Name: "redisPassword", Type: FieldTypeString},
+			{Name: "enableTLS", Type: FieldTypeBool},
+			{Name: "consumerID", Type: FieldTypeString},
+			{Name: "redeliverInterval", Type: FieldTypeDuration},
+			{Name: "processingTimeout", Type: FieldTypeDuration},
+			{Name: "queueDepth", Type: FieldTypeNumber},
+			{Name: "concurrency", Type: FieldTypeNumber},
+			{Name: "maxRetries", Type: FieldTypeNumber},
+			{Name: "maxRetryBackoff", Type: FieldTypeDuration},
+		},
+	},
+	{
+		Type:    "bindings.cron",
+		Version: "v1",
+		Fields: []FieldSchema{
+			{Name: "schedule", Type: FieldTypeString, Required: true, Description: "cron expression or @every interval"},
+		},
+	},
+	{
+		Type:    "secretstores.local.file",
+		Version: "v1",
+		Fields: []FieldSchema{
+			{Name: "secretsFile", Type: FieldTypeString, Required: true, Description: "path to the JSON file holding the secrets"},
+			{Name: "nestedSeparator", Type: FieldTypeString},
+		},
+	},
+	{
+		Type:    "secretstores.local.env",
+		Version: "v1",
+	},
+}
@@ -0,0 +1,164 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+)
+
+// Result holds the outcome of validating a component.
+// Errors reject the component, warnings are surfaced to the user without rejecting it.
+type Result struct {
+	Errors   []string
+	Warnings []string
+}
+
+// Valid returns true if no validation errors were found.
+func (r *Result) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+func (r *Result) errorf(format string, args ...interface{}) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+func (r *Result) warnf(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Validator checks components against the metadata schemas in a registry.
+type Validator struct {
+	registry *Registry
+}
+
+// NewValidator returns a new component validator backed by the given registry.
+func NewValidator(registry *Registry) *Validator {
+	return &Validator{
+		registry: registry,
+	}
+}
+
+// ValidateComponent validates the spec of a component, and its metadata against the registered schema of its type.
+func (v *Validator) ValidateComponent(component *componentsapi.Component) *Result {
+	res := &Result{}
+
+	if component.Spec.Type == "" {
+		res.errorf("spec.type is required")
+	} else if !strings.Contains(component.Spec.Type, ".") {
+		res.errorf("spec.type %q must be in the <building block>.<component> format, e.g. state.redis", component.Spec.Type)
+	}
+	if component.Spec.Version == "" {
+		res.errorf("spec.version is required, e.g. v1")
+	}
+	if component.Spec.InitTimeout != "" {
+		if _, err := time.ParseDuration(component.Spec.InitTimeout); err != nil {
+			res.errorf("spec.initTimeout %q is not a valid duration, e.g. 5s", component.Spec.InitTimeout)
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, item := range component.Spec.Metadata {
+		if item.Name == "" {
+			res.errorf("spec.metadata[%d].name is required", i)
+			continue
+		}
+		if seen[item.Name] {
+			res.errorf("spec.metadata[%d]: duplicate metadata field %q", i, item.Name)
+		}
+		seen[item.Name] = true
+
+		if item.SecretKeyRef.Name != "" && len(item.Value.Raw) > 0 {
+			res.errorf("spec.metadata[%d]: field %q sets both value and secretKeyRef, only one is allowed", i, item.Name)
+		}
+	}
+
+	if !res.Valid() {
+		return res
+	}
+
+	schema, ok := v.registry.Get(component.Spec.Type, component.Spec.Version)
+	if !ok {
+		res.warnf("no metadata schema registered for %s/%s, only the components with a built-in schema have their metadata fields validated", component.Spec.Type, component.Spec.Version)
+		return res
+	}
+
+	items := map[string]componentsapi.MetadataItem{}
+	for _, item := range component.Spec.Metadata {
+		items[item.Name] = item
+	}
+
+	known := map[string]bool{}
+	for _, field := range schema.Fields {
+		known[field.Name] = true
+
+		item, ok := items[field.Name]
+		if !ok {
+			if field.Required {
+				msg := fmt.Sprintf("metadata field %q is required for %s/%s", field.Name, schema.Type, schema.Version)
+				if field.Description != "" {
+					msg = fmt.Sprintf("%s: %s", msg, field.Description)
+				}
+				res.Errors = append(res.Errors, msg)
+			}
+			continue
+		}
+
+		// Values resolved from secrets can't be checked until the sidecar loads them.
+		if item.SecretKeyRef.Name != "" {
+			continue
+		}
+
+		validateField(res, field, item.Value.String())
+	}
+
+	for _, item := range component.Spec.Metadata {
+		if !known[item.Name] {
+			res.warnf("metadata field %q is not part of the %s/%s schema and may be ignored", item.Name, schema.Type, schema.Version)
+		}
+	}
+
+	return res
+}
+
+func validateField(res *Result, field FieldSchema, value string) {
+	if value == "" {
+		if field.Required {
+			res.errorf("metadata field %q is required but has an empty value", field.Name)
+		}
+		return
+	}
+
+	switch field.Type {
+	case FieldTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			res.errorf("metadata field %q must be a number, got %q", field.Name, value)
+		}
+	case FieldTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			res.errorf("metadata field %q must be true or false, got %q", field.Name, value)
+		}
+	case FieldTypeDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			if _, err := strconv.Atoi(value); err != nil {
+				res.errorf("metadata field %q must be a duration such as 10s, got %q", field.Name, value)
+			}
+		}
+	}
+
+	if len(field.AllowedValues) > 0 {
+		for _, allowed := range field.AllowedValues {
+			if strings.EqualFold(allowed, value) {
+				return
+			}
+		}
+		res.errorf("metadata field %q must be one of [%s], got %q", field.Name, strings.Join(field.AllowedValues, ", "), value)
+	}
+}
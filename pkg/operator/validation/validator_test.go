@@ -0,0 +1,251 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/admission/v1"
+	apiextensionsV1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	configurationapi "github.com/dapr/dapr/pkg/apis/configuration/v1alpha1"
+)
+
+func getComponent(componentType string, metadata map[string]string) *componentsapi.Component {
+	c := &componentsapi.Component{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: componentsapi.ComponentSpec{
+			Type:    componentType,
+			Version: "v1",
+		},
+	}
+	for k, v := range metadata {
+		b, _ := json.Marshal(v)
+		c.Spec.Metadata = append(c.Spec.Metadata, componentsapi.MetadataItem{
+			Name:  k,
+			Value: componentsapi.DynamicValue{JSON: apiextensionsV1.JSON{Raw: b}},
+		})
+	}
+	return c
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	// the component types covered by the built-in schemas are listed in the doc of DefaultRegistry
+	componentTypes := []string{"state.redis", "pubsub.redis", "bindings.cron", "secretstores.local.file", "secretstores.local.env"}
+	r := DefaultRegistry()
+	for _, componentType := range componentTypes {
+		_, ok := r.Get(componentType, "v1")
+		assert.True(t, ok, componentType)
+	}
+	assert.Len(t, r.schemas, len(componentTypes))
+}
+
+func TestValidateComponent(t *testing.T) {
+	v := NewValidator(DefaultRegistry())
+
+	t.Run("valid component", func(t *testing.T) {
+		res := v.ValidateComponent(getComponent("state.redis", map[string]string{
+			"redisHost": "localhost:6379",
+			"enableTLS": "false",
+		}))
+		assert.True(t, res.Valid())
+		assert.Empty(t, res.Warnings)
+	})
+
+	t.Run("missing required field", func(t *testing.T) {
+		res := v.ValidateComponent(getComponent("state.redis", map[string]string{}))
+		assert.False(t, res.Valid())
+		assert.Contains(t, res.Errors[0], "redisHost")
+	})
+
+	t.Run("wrong field types", func(t *testing.T) {
+		res := v.ValidateComponent(getComponent("state.redis", map[string]string{
+			"redisHost":  "localhost:6379",
+			"enableTLS":  "yes please",
+			"maxRetries": "three",
+			"redisType":  "sharded",
+		}))
+		assert.False(t, res.Valid())
+		assert.Len(t, res.Errors, 3)
+	})
+
+	t.Run("secret references skip value checks", func(t *testing.T) {
+		c := getComponent("state.redis", map[string]string{})
+		c.Spec.Metadata = append(c.Spec.Metadata, componentsapi.MetadataItem{
+			Name:         "redisHost",
+			SecretKeyRef: componentsapi.SecretKeyRef{Name: "redis", Key: "host"},
+		})
+		res := v.ValidateComponent(c)
+		assert.True(t, res.Valid())
+	})
+
+	t.Run("unknown field warns", func(t *testing.T) {
+		res := v.ValidateComponent(getComponent("state.redis", map[string]string{
+			"redisHost": "localhost:6379",
+			"redisHots": "typo",
+		}))
+		assert.True(t, res.Valid())
+		assert.Len(t, res.Warnings, 1)
+	})
+
+	t.Run("unknown component type warns", func(t *testing.T) {
+		res := v.ValidateComponent(getComponent("state.unknown", map[string]string{"a": "b"}))
+		assert.True(t, res.Valid())
+		assert.Len(t, res.Warnings, 1)
+	})
+
+	t.Run("invalid spec", func(t *testing.T) {
+		c := getComponent("redis", map[string]string{})
+		c.Spec.Version = ""
+		c.Spec.InitTimeout = "soon"
+		res := v.ValidateComponent(c)
+		assert.Len(t, res.Errors, 3)
+	})
+}
+
+func TestReview(t *testing.T) {
+	w := NewWebhook(NewValidator(DefaultRegistry()), nil, "", "").(*webhook)
+
+	getRequest := func(c *componentsapi.Component, dryRun bool) *v1.AdmissionRequest {
+		b, _ := json.Marshal(c)
+		return &v1.AdmissionRequest{
+			Kind:   metav1.GroupVersionKind{Group: "dapr.io", Version: "v1alpha1", Kind: "Component"},
+			Object: runtime.RawExtension{Raw: b},
+			DryRun: &dryRun,
+		}
+	}
+
+	t.Run("rejects invalid component", func(t *testing.T) {
+		resp := w.review(getRequest(getComponent("state.redis", map[string]string{}), false))
+		assert.False(t, resp.Allowed)
+		assert.Contains(t, resp.Result.Message, "redisHost")
+	})
+
+	t.Run("allows valid component", func(t *testing.T) {
+		resp := w.review(getRequest(getComponent("state.redis", map[string]string{"redisHost": "localhost:6379"}), false))
+		assert.True(t, resp.Allowed)
+		assert.Empty(t, resp.Warnings)
+	})
+
+	t.Run("dry run reports diagnostics", func(t *testing.T) {
+		resp := w.review(getRequest(getComponent("state.redis", map[string]string{"redisHost": "localhost:6379"}), true))
+		assert.True(t, resp.Allowed)
+		assert.Len(t, resp.Warnings, 1)
+		assert.Contains(t, resp.Warnings[0], "passed validation")
+	})
+
+	t.Run("dry run reports each validation error", func(t *testing.T) {
+		resp := w.review(getRequest(getComponent("state.redis", map[string]string{"enableTLS": "maybe"}), true))
+		assert.False(t, resp.Allowed)
+		assert.Len(t, resp.Warnings, 2)
+		assert.Contains(t, resp.Warnings[0], "failed validation: metadata field \"redisHost\" is required")
+		assert.Contains(t, resp.Warnings[1], "failed validation: metadata field \"enableTLS\" must be true or false")
+	})
+}
+
+func TestEnforceQuotas(t *testing.T) {
+	existing := []componentsapi.Component{
+		*getComponent("state.redis", nil),
+	}
+	existing[0].Name = "statestore"
+	lister := func(ctx context.Context, namespace string) ([]componentsapi.Component, error) {
+		if namespace != "default" {
+			return nil, nil
+		}
+		return existing, nil
+	}
+	quotas := NewQuotaEnforcer([]configurationapi.ComponentQuotaSpec{
+		{
+			Namespaces:    []string{"default"},
+			MaxComponents: 2,
+		},
+		{
+			DeniedTypes: []string{"bindings.cron"},
+		},
+	}, lister)
+
+	t.Run("denied type", func(t *testing.T) {
+		res := &Result{}
+		quotas.EnforceQuotas("other", getComponent("bindings.cron", nil), res)
+		assert.False(t, res.Valid())
+	})
+
+	t.Run("within the limit", func(t *testing.T) {
+		res := &Result{}
+		quotas.EnforceQuotas("default", getComponent("pubsub.redis", nil), res)
+		assert.True(t, res.Valid())
+	})
+
+	t.Run("over the limit", func(t *testing.T) {
+		existing = append(existing, *getComponent("pubsub.redis", nil))
+		defer func() { existing = existing[:1] }()
+
+		res := &Result{}
+		c := getComponent("bindings.kafka", nil)
+		c.Name = "kafka"
+		quotas.EnforceQuotas("default", c, res)
+		assert.False(t, res.Valid())
+
+		// updating an existing component doesn't count against the limit
+		res = &Result{}
+		quotas.EnforceQuotas("default", getComponent("pubsub.redis", nil), res)
+		assert.True(t, res.Valid())
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		q := NewQuotaEnforcer([]configurationapi.ComponentQuotaSpec{{MaxComponents: 1}}, func(ctx context.Context, namespace string) ([]componentsapi.Component, error) {
+			return nil, errors.New("unavailable")
+		})
+		res := &Result{}
+		q.EnforceQuotas("default", getComponent("state.redis", nil), res)
+		assert.False(t, res.Valid())
+	})
+
+	t.Run("webhook rejects components over quota", func(t *testing.T) {
+		w := NewWebhook(NewValidator(DefaultRegistry()), quotas, "", "").(*webhook)
+		b, _ := json.Marshal(getComponent("bindings.cron", map[string]string{"schedule": "@every 1s"}))
+		resp := w.review(&v1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Group: "dapr.io", Version: "v1alpha1", Kind: "Component"},
+			Operation: v1.Create,
+			Object:    runtime.RawExtension{Raw: b},
+		})
+		assert.False(t, resp.Allowed)
+		assert.Contains(t, resp.Result.Message, "bindings.cron")
+	})
+
+	t.Run("webhook enforces the quotas of the request namespace", func(t *testing.T) {
+		existing = append(existing, *getComponent("pubsub.redis", nil))
+		defer func() { existing = existing[:1] }()
+
+		w := NewWebhook(NewValidator(DefaultRegistry()), quotas, "", "").(*webhook)
+		c := getComponent("state.redis", map[string]string{"redisHost": "localhost:6379"})
+		c.Name = "other"
+		c.Namespace = ""
+		b, _ := json.Marshal(c)
+		resp := w.review(&v1.AdmissionRequest{
+			Kind:      metav1.GroupVersionKind{Group: "dapr.io", Version: "v1alpha1", Kind: "Component"},
+			Operation: v1.Create,
+			Namespace: "default",
+			Object:    runtime.RawExtension{Raw: b},
+		})
+		assert.False(t, resp.Allowed)
+		assert.Contains(t, resp.Result.Message, "namespace default is limited")
+	})
+
+	t.Run("updated quotas", func(t *testing.T) {
+		q := NewQuotaEnforcer(nil, lister)
+		res := &Result{}
+		q.EnforceQuotas("default", getComponent("bindings.cron", nil), res)
+		assert.True(t, res.Valid())
+
+		q.SetQuotas([]configurationapi.ComponentQuotaSpec{{DeniedTypes: []string{"bindings.cron"}}})
+		res = &Result{}
+		q.EnforceQuotas("default", getComponent("bindings.cron", nil), res)
+		assert.False(t, res.Valid())
+	})
+}
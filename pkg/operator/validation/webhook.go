@@ -0,0 +1,192 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	componentsapi "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
+	"github.com/dapr/kit/logger"
+)
+
+const (
+	// WebhookPort is the port the validating webhook listens on.
+	WebhookPort = 19443
+	// ValidatePath is the HTTP path of the component validation endpoint.
+	ValidatePath = "/validate-component"
+)
+
+var log = logger.NewLogger("dapr.operator.validation")
+
+// Webhook is a Kubernetes validating admission webhook for Dapr components.
+type Webhook interface {
+	Run(ctx context.Context)
+}
+
+type webhook struct {
+	validator    *Validator
+	quotas       *QuotaEnforcer
+	deserializer runtime.Decoder
+	server       *http.Server
+	certFile     string
+	keyFile      string
+}
+
+// NewWebhook returns a new component validation webhook serving TLS with the given cert and key files.
+// Components are also checked against the namespace quotas of quotas, if not nil.
+func NewWebhook(validator *Validator, quotas *QuotaEnforcer, certFile, keyFile string) Webhook {
+	mux := http.NewServeMux()
+
+	w := &webhook{
+		validator: validator,
+		quotas:    quotas,
+		deserializer: serializer.NewCodecFactory(
+			runtime.NewScheme(),
+		).UniversalDeserializer(),
+		server: &http.Server{
+			Addr:    fmt.Sprintf(":%d", WebhookPort),
+			Handler: mux,
+		},
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	mux.HandleFunc(ValidatePath, w.handleRequest)
+	return w
+}
+
+func (w *webhook) Run(ctx context.Context) {
+	doneCh := make(chan struct{})
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			log.Info("Component validation webhook is shutting down")
+			shutdownCtx, cancel := context.WithTimeout(
+				context.Background(),
+				time.Second*5,
+			)
+			defer cancel()
+			w.server.Shutdown(shutdownCtx) // nolint: errcheck
+		case <-doneCh:
+		}
+	}()
+
+	log.Infof("Component validation webhook is listening on %s", w.server.Addr)
+	err := w.server.ListenAndServeTLS(w.certFile, w.keyFile)
+	if err != http.ErrServerClosed {
+		log.Errorf("Component validation webhook error: %s", err)
+	}
+	close(doneCh)
+}
+
+func (w *webhook) handleRequest(rw http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(body) == 0 {
+		http.Error(rw, "empty body", http.StatusBadRequest)
+		return
+	}
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+		http.Error(rw, "invalid Content-Type, expect `application/json`", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	ar := v1.AdmissionReview{}
+	_, gvk, err := w.deserializer.Decode(body, nil, &ar)
+	if err != nil || ar.Request == nil {
+		log.Errorf("Can't decode body: %v", err)
+		http.Error(rw, "invalid admission review", http.StatusBadRequest)
+		return
+	}
+
+	admissionReview := v1.AdmissionReview{
+		Response: w.review(ar.Request),
+	}
+	admissionReview.Response.UID = ar.Request.UID
+	admissionReview.SetGroupVersionKind(*gvk)
+
+	respBytes, err := json.Marshal(admissionReview)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if _, err := rw.Write(respBytes); err != nil {
+		log.Error(err)
+	}
+}
+
+func (w *webhook) review(req *v1.AdmissionRequest) *v1.AdmissionResponse {
+	if req.Kind.Kind != "Component" {
+		return &v1.AdmissionResponse{Allowed: true}
+	}
+
+	var component componentsapi.Component
+	if err := json.Unmarshal(req.Object.Raw, &component); err != nil {
+		return &v1.AdmissionResponse{
+			Result: &metav1.Status{
+				Message: errors.Wrap(err, "could not unmarshal component").Error(),
+			},
+		}
+	}
+
+	res := w.validator.ValidateComponent(&component)
+	if w.quotas != nil && req.Operation != v1.Delete {
+		w.quotas.EnforceQuotas(req.Namespace, &component, res)
+	}
+	warnings := res.Warnings
+	dryRun := req.DryRun != nil && *req.DryRun
+	if dryRun {
+		warnings = append(warnings, dryRunDiagnostics(&component, res)...)
+	}
+
+	if !res.Valid() {
+		log.Infof("rejected component %s/%s: %s", component.Namespace, component.Name, strings.Join(res.Errors, "; "))
+		return &v1.AdmissionResponse{
+			Allowed:  false,
+			Warnings: warnings,
+			Result: &metav1.Status{
+				Message: fmt.Sprintf("component %s is invalid: %s", component.Name, strings.Join(res.Errors, "; ")),
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+			},
+		}
+	}
+
+	return &v1.AdmissionResponse{
+		Allowed:  true,
+		Warnings: warnings,
+	}
+}
+
+// dryRunDiagnostics lists every validation error of a component as a warning, so a dry run
+// shows what would fail and why. A component that passes validation gets a single line saying so.
+func dryRunDiagnostics(component *componentsapi.Component, res *Result) []string {
+	if res.Valid() {
+		return []string{fmt.Sprintf("component %s/%s passed validation", component.Namespace, component.Name)}
+	}
+
+	diagnostics := make([]string, 0, len(res.Errors))
+	for _, e := range res.Errors {
+		diagnostics = append(diagnostics, fmt.Sprintf("component %s/%s failed validation: %s", component.Namespace, component.Name, e))
+	}
+	return diagnostics
+}
@@ -9,13 +9,20 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dapr/dapr/pkg/config"
 	"github.com/dapr/dapr/pkg/placement/monitoring"
 	"github.com/dapr/dapr/pkg/placement/raft"
 	v1pb "github.com/dapr/dapr/pkg/proto/placement/v1"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
+	lockOperation        = "lock"
+	unlockOperation      = "unlock"
+	updateOperation      = "update"
+	updateDeltaOperation = "update-delta"
+
 	// raftApplyCommandMaxConcurrency is the max concurrency to apply command log to raft.
 	raftApplyCommandMaxConcurrency = 10
 	barrierWriteTimeout            = 2 * time.Minute
@@ -102,6 +109,12 @@ func (p *Service) establishLeadership() {
 	p.faultyHostDetectDuration = faultyHostDetectInitialDuration
 
 	p.membershipCh = make(chan hostMemberChange, membershipChangeChSize)
+	// the runtimes connecting to the new leader get the full tables first.
+	p.disseminatedTablesLock.Lock()
+	p.disseminatedTables = map[string]*v1pb.PlacementTables{}
+	p.disseminatedTablesLock.Unlock()
+	p.stickyTableChanges = map[string]map[string]time.Time{}
+	p.eagerDissemination.Store(false)
 	p.hasLeadership = true
 }
 
@@ -150,7 +163,7 @@ func (p *Service) membershipChangeWorker(stopCh chan struct{}) {
 			}
 
 			// check if there is actor runtime member change.
-			if p.disseminateNextTime <= t.UnixNano() && len(p.membershipCh) == 0 {
+			if (p.disseminateNextTime <= t.UnixNano() || p.eagerDissemination.Load()) && len(p.membershipCh) == 0 {
 				if cnt := p.memberUpdateCount.Load(); cnt > 0 {
 					log.Debugf("Add raft.TableDisseminate to membershipCh. memberUpdateCount count: %d", cnt)
 					p.membershipCh <- hostMemberChange{cmdType: raft.TableDisseminate}
@@ -222,6 +235,11 @@ func (p *Service) processRaftStateCommand(stopCh chan struct{}) {
 				// Even if ApplyCommand is failed, both commands will retry
 				// until the state is consistent.
 				logApplyConcurrency <- struct{}{}
+				// the removed member is looked up before it is removed, for its namespace and actor types.
+				member := op.host
+				if m, ok := p.raftNode.FSM().State().Members[op.host.Name]; ok && op.cmdType == raft.MemberRemove {
+					member = *m
+				}
 				go func() {
 					updated, raftErr := p.raftNode.ApplyCommand(op.cmdType, op.host)
 					if raftErr != nil {
@@ -238,6 +256,9 @@ func (p *Service) processRaftStateCommand(stopCh chan struct{}) {
 							// it will keep moving the time to disseminate the table, which will
 							// reduce the unnecessary table dissemination.
 							p.disseminateNextTime = time.Now().Add(disseminateTimeout).UnixNano()
+							if p.rebalancesEagerly(member) {
+								p.eagerDissemination.Store(true)
+							}
 						}
 					}
 					<-logApplyConcurrency
@@ -254,15 +275,21 @@ func (p *Service) processRaftStateCommand(stopCh chan struct{}) {
 
 				// ignore dissemination if there is no member update.
 				if cnt := p.memberUpdateCount.Load(); cnt > 0 {
-					state := p.raftNode.FSM().PlacementState()
+					p.eagerDissemination.Store(false)
+					generation := p.raftNode.FSM().State().TableGeneration
 					log.Infof(
-						"Start disseminating tables. memberUpdateCount: %d, streams: %d, targets: %d, table generation: %s",
-						cnt, nStreamConnPool, nTargetConns, state.Version)
-					p.performTablesUpdate(p.streamConnPool, state)
+						"Start disseminating tables. memberUpdateCount: %d, streams: %d, targets: %d, table generation: %d",
+						cnt, nStreamConnPool, nTargetConns, generation)
+					heldUntil := p.disseminateTables(time.Now())
 					log.Infof(
-						"Completed dissemination. memberUpdateCount: %d, streams: %d, targets: %d, table generation: %s",
-						cnt, nStreamConnPool, nTargetConns, state.Version)
+						"Completed dissemination. memberUpdateCount: %d, streams: %d, targets: %d, table generation: %d",
+						cnt, nStreamConnPool, nTargetConns, generation)
 					p.memberUpdateCount.Store(0)
+					if !heldUntil.IsZero() {
+						// disseminate the held back changes of the sticky actor types once they are due.
+						p.memberUpdateCount.Inc()
+						p.disseminateNextTime = heldUntil.UnixNano()
+					}
 
 					// set faultyHostDetectDuration to the default duration.
 					p.faultyHostDetectDuration = faultyHostDetectDefaultDuration
@@ -272,19 +299,190 @@ func (p *Service) processRaftStateCommand(stopCh chan struct{}) {
 	}
 }
 
+// rebalancesEagerly returns true if any actor type of the member rebalances eagerly.
+func (p *Service) rebalancesEagerly(member raft.DaprHostMember) bool {
+	hints := p.placementHints(member.Namespace)
+	for _, actorType := range member.Entities {
+		if hints[actorType] == config.ActorPlacementRebalanceEagerly {
+			return true
+		}
+	}
+	return false
+}
+
+// connectionTables returns the tables sent to a runtime connecting from the namespace. These are the tables
+// disseminated last time, so that the held back changes of the sticky actor types are not sent, or the
+// current tables before the first dissemination.
+func (p *Service) connectionTables(namespace string) *v1pb.PlacementTables {
+	p.disseminatedTablesLock.Lock()
+	tables := p.disseminatedTables[namespace]
+	p.disseminatedTablesLock.Unlock()
+	if tables == nil {
+		return p.raftNode.FSM().PlacementState(namespace)
+	}
+	return tables
+}
+
+// disseminateTables disseminates the latest hashing tables to the runtimes of each namespace.
+// The runtimes which support delta updates and got the previous tables only receive the tables
+// of the changed actor types. The other runtimes receive the full tables. It returns the time
+// the earliest held back change of a sticky actor type is due, or zero if no change is held back.
+func (p *Service) disseminateTables(now time.Time) time.Time {
+	var heldUntil time.Time
+	for ns, conns := range p.streamConnsByNamespace() {
+		tables := p.raftNode.FSM().PlacementState(ns)
+		p.disseminatedTablesLock.Lock()
+		prev := p.disseminatedTables[ns]
+		p.disseminatedTablesLock.Unlock()
+		if due := p.holdStickyTables(ns, prev, tables, p.placementHints(ns), now); !due.IsZero() &&
+			(heldUntil.IsZero() || due.Before(heldUntil)) {
+			heldUntil = due
+		}
+
+		fullConns := []placementGRPCStream{}
+		deltaConns := []placementGRPCStream{}
+		for _, c := range conns {
+			if p.isDeltaSynced(c) {
+				deltaConns = append(deltaConns, c)
+			} else {
+				fullConns = append(fullConns, c)
+			}
+		}
+
+		var err error
+		if len(deltaConns) > 0 {
+			if delta := tablesDelta(prev, tables); delta != nil {
+				err = p.performTablesDeltaUpdate(deltaConns, delta)
+			} else {
+				fullConns = append(fullConns, deltaConns...)
+			}
+		}
+		if len(fullConns) > 0 {
+			if fullErr := p.performTablesUpdate(fullConns, tables); fullErr != nil {
+				err = fullErr
+			}
+		}
+
+		p.disseminatedTablesLock.Lock()
+		p.disseminatedTables[ns] = tables
+		p.disseminatedTablesLock.Unlock()
+		// the runtimes of the namespace get the full tables next time if any of them failed
+		// to receive the update, because it is unknown which tables they hold.
+		p.setDeltaSynced(conns, err == nil)
+	}
+	return heldUntil
+}
+
+// holdStickyTables replaces the changed tables of the sticky actor types in cur with the tables disseminated
+// last time, until they stayed changed for stickyTableHoldDuration. A change that is reverted in the meantime,
+// such as a host leaving and returning, never moves the actors. The tables of new actor types are not held back.
+// It returns the time the earliest held back change is due, or zero if no change is held back.
+func (p *Service) holdStickyTables(namespace string, prev, cur *v1pb.PlacementTables, hints map[string]string, now time.Time) time.Time {
+	changes := p.stickyTableChanges[namespace]
+	if changes == nil {
+		changes = map[string]time.Time{}
+		p.stickyTableChanges[namespace] = changes
+	}
+
+	for actorType := range changes {
+		if hints[actorType] != config.ActorPlacementSticky {
+			delete(changes, actorType)
+		}
+	}
+
+	var heldUntil time.Time
+	for actorType, mode := range hints {
+		if mode != config.ActorPlacementSticky {
+			continue
+		}
+		var prevTable *v1pb.PlacementTable
+		ok := false
+		if prev != nil {
+			prevTable, ok = prev.Entries[actorType]
+		}
+		if !ok || proto.Equal(prevTable, cur.Entries[actorType]) {
+			delete(changes, actorType)
+			continue
+		}
+
+		since, ok := changes[actorType]
+		if !ok {
+			since = now
+			changes[actorType] = since
+		}
+		due := since.Add(stickyTableHoldDuration)
+		if !now.Before(due) {
+			delete(changes, actorType)
+			continue
+		}
+
+		cur.Entries[actorType] = prevTable
+		if heldUntil.IsZero() || due.Before(heldUntil) {
+			heldUntil = due
+		}
+	}
+	return heldUntil
+}
+
+// tablesDelta returns the tables of the actor types changed from prev to cur. The actor types
+// without hosts anymore have an empty table. It returns nil if the delta is not smaller than cur.
+func tablesDelta(prev, cur *v1pb.PlacementTables) *v1pb.PlacementTables {
+	if prev == nil {
+		return nil
+	}
+
+	delta := &v1pb.PlacementTables{
+		Version: cur.Version,
+		Entries: make(map[string]*v1pb.PlacementTable),
+	}
+	for k, v := range cur.Entries {
+		if !proto.Equal(prev.Entries[k], v) {
+			delta.Entries[k] = v
+		}
+	}
+	for k := range prev.Entries {
+		if _, ok := cur.Entries[k]; !ok {
+			delta.Entries[k] = &v1pb.PlacementTable{}
+		}
+	}
+
+	if len(cur.Entries) > 0 && len(delta.Entries) >= len(cur.Entries) {
+		return nil
+	}
+	return delta
+}
+
 // performTablesUpdate updates the connected dapr runtimes using a 3 stage commit.
 // It first locks so no further dapr can be taken it. Once placement table is locked
 // in runtime, it proceeds to update new table to Dapr runtimes and then unlock
 // once all runtimes have been updated.
-func (p *Service) performTablesUpdate(hosts []placementGRPCStream, newTable *v1pb.PlacementTables) {
+func (p *Service) performTablesUpdate(hosts []placementGRPCStream, newTable *v1pb.PlacementTables) error {
+	return p.performTablesOperation(hosts, updateOperation, newTable)
+}
+
+// performTablesDeltaUpdate updates the connected dapr runtimes with the tables of the changed
+// actor types using the same 3 stage commit as performTablesUpdate.
+func (p *Service) performTablesDeltaUpdate(hosts []placementGRPCStream, delta *v1pb.PlacementTables) error {
+	return p.performTablesOperation(hosts, updateDeltaOperation, delta)
+}
+
+func (p *Service) performTablesOperation(hosts []placementGRPCStream, updateOp string, tables *v1pb.PlacementTables) error {
 	p.disseminateLock.Lock()
 	defer p.disseminateLock.Unlock()
 
 	// TODO: error from disseminationOperation needs to be handle properly.
 	// Otherwise, each Dapr runtime will have inconsistent hashing table.
-	p.disseminateOperation(hosts, "lock", nil)
-	p.disseminateOperation(hosts, "update", newTable)
-	p.disseminateOperation(hosts, "unlock", nil)
+	lockErr := p.disseminateOperation(hosts, lockOperation, nil)
+	updateErr := p.disseminateOperation(hosts, updateOp, tables)
+	unlockErr := p.disseminateOperation(hosts, unlockOperation, nil)
+
+	if updateErr != nil {
+		return updateErr
+	}
+	if lockErr != nil {
+		return lockErr
+	}
+	return unlockErr
 }
 
 func (p *Service) disseminateOperation(targets []placementGRPCStream, operation string, tables *v1pb.PlacementTables) error {
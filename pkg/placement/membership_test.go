@@ -224,3 +224,137 @@ func TestPerformTableUpdate(t *testing.T) {
 
 	cleanup()
 }
+
+func TestTablesDelta(t *testing.T) {
+	testTable := func(hosts ...string) *v1pb.PlacementTable {
+		table := &v1pb.PlacementTable{LoadMap: map[string]*v1pb.Host{}}
+		for _, h := range hosts {
+			table.LoadMap[h] = &v1pb.Host{Name: h, Id: "testAppID", Load: 1}
+		}
+		return table
+	}
+	prev := &v1pb.PlacementTables{
+		Version: "1",
+		Entries: map[string]*v1pb.PlacementTable{
+			"DogActor":  testTable("127.0.0.1:50100"),
+			"CatActor":  testTable("127.0.0.1:50100"),
+			"BirdActor": testTable("127.0.0.1:50101"),
+			"AntActor":  testTable("127.0.0.1:50100"),
+		},
+	}
+
+	t.Run("no previous tables", func(t *testing.T) {
+		assert.Nil(t, tablesDelta(nil, prev))
+	})
+
+	t.Run("changed and removed actor types", func(t *testing.T) {
+		cur := &v1pb.PlacementTables{
+			Version: "2",
+			Entries: map[string]*v1pb.PlacementTable{
+				"DogActor":  testTable("127.0.0.1:50100"),
+				"CatActor":  testTable("127.0.0.1:50100", "127.0.0.1:50102"),
+				"FishActor": testTable("127.0.0.1:50102"),
+				"AntActor":  testTable("127.0.0.1:50100"),
+			},
+		}
+
+		delta := tablesDelta(prev, cur)
+		assert.NotNil(t, delta)
+		assert.Equal(t, "2", delta.Version)
+		assert.Equal(t, 3, len(delta.Entries))
+		assert.NotContains(t, delta.Entries, "DogActor")
+		assert.NotContains(t, delta.Entries, "AntActor")
+		assert.Contains(t, delta.Entries, "FishActor")
+		assert.Equal(t, 2, len(delta.Entries["CatActor"].LoadMap))
+		assert.Equal(t, 0, len(delta.Entries["BirdActor"].LoadMap))
+	})
+
+	t.Run("falls back to full tables if all actor types changed", func(t *testing.T) {
+		cur := &v1pb.PlacementTables{
+			Version: "2",
+			Entries: map[string]*v1pb.PlacementTable{
+				"DogActor": testTable("127.0.0.1:50102"),
+			},
+		}
+
+		assert.Nil(t, tablesDelta(prev, cur))
+	})
+}
+
+func TestHoldStickyTables(t *testing.T) {
+	testTable := func(hosts ...string) *v1pb.PlacementTable {
+		table := &v1pb.PlacementTable{LoadMap: map[string]*v1pb.Host{}}
+		for _, h := range hosts {
+			table.LoadMap[h] = &v1pb.Host{Name: h, Id: "testAppID", Load: 1}
+		}
+		return table
+	}
+	prev := &v1pb.PlacementTables{
+		Version: "1",
+		Entries: map[string]*v1pb.PlacementTable{
+			"DogActor": testTable("127.0.0.1:50100", "127.0.0.1:50101"),
+			"CatActor": testTable("127.0.0.1:50100", "127.0.0.1:50101"),
+		},
+	}
+	newCur := func() *v1pb.PlacementTables {
+		return &v1pb.PlacementTables{
+			Version: "2",
+			Entries: map[string]*v1pb.PlacementTable{
+				"DogActor":  testTable("127.0.0.1:50100"),
+				"CatActor":  testTable("127.0.0.1:50100"),
+				"FishActor": testTable("127.0.0.1:50100"),
+			},
+		}
+	}
+	hints := map[string]string{"DogActor": "sticky", "FishActor": "sticky", "CatActor": "rebalance-eagerly"}
+	now := time.Now()
+
+	t.Run("changes of sticky actor types are held back", func(t *testing.T) {
+		testServer := NewPlacementService(testRaftServer, false)
+		cur := newCur()
+
+		heldUntil := testServer.holdStickyTables("", prev, cur, hints, now)
+
+		assert.Equal(t, now.Add(stickyTableHoldDuration), heldUntil)
+		assert.Equal(t, prev.Entries["DogActor"], cur.Entries["DogActor"])
+		assert.Equal(t, 1, len(cur.Entries["CatActor"].LoadMap))
+		// new actor types are not held back
+		assert.Equal(t, 1, len(cur.Entries["FishActor"].LoadMap))
+	})
+
+	t.Run("changes are disseminated once due", func(t *testing.T) {
+		testServer := NewPlacementService(testRaftServer, false)
+		testServer.holdStickyTables("", prev, newCur(), hints, now)
+
+		cur := newCur()
+		heldUntil := testServer.holdStickyTables("", prev, cur, hints, now.Add(stickyTableHoldDuration/2))
+		assert.Equal(t, now.Add(stickyTableHoldDuration), heldUntil, "the hold starts with the first change")
+		assert.Equal(t, prev.Entries["DogActor"], cur.Entries["DogActor"])
+
+		cur = newCur()
+		heldUntil = testServer.holdStickyTables("", prev, cur, hints, now.Add(stickyTableHoldDuration))
+		assert.True(t, heldUntil.IsZero())
+		assert.Equal(t, 1, len(cur.Entries["DogActor"].LoadMap))
+	})
+
+	t.Run("reverted changes restart the hold", func(t *testing.T) {
+		testServer := NewPlacementService(testRaftServer, false)
+		testServer.holdStickyTables("", prev, newCur(), hints, now)
+
+		heldUntil := testServer.holdStickyTables("", prev, prev, hints, now.Add(time.Second))
+		assert.True(t, heldUntil.IsZero())
+
+		heldUntil = testServer.holdStickyTables("", prev, newCur(), hints, now.Add(stickyTableHoldDuration))
+		assert.Equal(t, now.Add(2*stickyTableHoldDuration), heldUntil)
+	})
+
+	t.Run("no previous tables", func(t *testing.T) {
+		testServer := NewPlacementService(testRaftServer, false)
+		cur := newCur()
+
+		heldUntil := testServer.holdStickyTables("", nil, cur, hints, now)
+
+		assert.True(t, heldUntil.IsZero())
+		assert.Equal(t, 1, len(cur.Entries["DogActor"].LoadMap))
+	})
+}
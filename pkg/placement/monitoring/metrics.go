@@ -7,6 +7,7 @@ package monitoring
 
 import (
 	"context"
+	"time"
 
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	"go.opencensus.io/stats"
@@ -23,6 +24,10 @@ var (
 		"placement/actor_runtimes_total",
 		"The total number of actor runtimes reported to placement service.",
 		stats.UnitDimensionless)
+	issuerCertExpiryTimestamp = stats.Int64(
+		"placement/issuercert/expiry_timestamp",
+		"The unix timestamp, in seconds, when the issuer cert used by placement service will expire.",
+		stats.UnitDimensionless)
 
 	noKeys = []tag.Key{}
 )
@@ -37,11 +42,17 @@ func RecordActorRuntimesCount(count int) {
 	stats.Record(context.Background(), actorRuntimesTotal.M(int64(count)))
 }
 
+// RecordIssuerCertExpiry records the expiry of the issuer cert used by placement service.
+func RecordIssuerCertExpiry(expiry time.Time) {
+	stats.Record(context.Background(), issuerCertExpiryTimestamp.M(expiry.Unix()))
+}
+
 // InitMetrics initialize the placement service metrics.
 func InitMetrics() error {
 	err := view.Register(
 		diag_utils.NewMeasureView(runtimesTotal, noKeys, view.LastValue()),
 		diag_utils.NewMeasureView(actorRuntimesTotal, noKeys, view.LastValue()),
+		diag_utils.NewMeasureView(issuerCertExpiryTimestamp, noKeys, view.LastValue()),
 	)
 
 	return err
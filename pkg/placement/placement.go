@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,8 +17,12 @@ import (
 	"go.uber.org/atomic"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
+	"github.com/dapr/dapr/pkg/config"
 	dapr_credentials "github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/placement/raft"
 	placementv1pb "github.com/dapr/dapr/pkg/proto/placement/v1"
@@ -57,6 +62,18 @@ const (
 	// is applied to raft state or each pod is deployed. If we increase disseminateTimeout, it will
 	// reduce the frequency of dissemination, but it will delay the table dissemination.
 	disseminateTimeout = 2 * time.Second
+
+	// namespaceMetadataKey is the gRPC metadata key Dapr runtime reports its namespace with.
+	namespaceMetadataKey = "dapr-namespace"
+	// deltaUpdatesMetadataKey is the gRPC metadata key Dapr runtime reports the support of delta table updates with.
+	deltaUpdatesMetadataKey = "dapr-placement-delta-updates"
+	// placementHintsMetadataKey is the gRPC metadata key Dapr runtime reports the placement hints of its actor
+	// types with, as <actor type>=<mode> values.
+	placementHintsMetadataKey = "dapr-placement-hints"
+
+	// stickyTableHoldDuration is how long the changes of the tables of the sticky actor types are held back,
+	// so that their actors are not moved when a host leaves and returns within it.
+	stickyTableHoldDuration = 30 * time.Second
 )
 
 type hostMemberChange struct {
@@ -72,11 +89,32 @@ type Service struct {
 	grpcServer *grpc.Server
 	// streamConnPool has the stream connections established between placement gRPC server and Dapr runtime.
 	streamConnPool []placementGRPCStream
-	// streamConnPoolLock is the lock for streamConnPool change.
+	// streamConnPoolLock is the lock for streamConnPool, streamConnNamespaces, streamConnHints and deltaSyncedConns change.
 	streamConnPoolLock *sync.Mutex
+	// streamConnNamespaces has the namespaces of the stream connections, read when they connected.
+	streamConnNamespaces map[placementGRPCStream]string
+	// streamConnHints has the placement hints of the actor types of the stream connections, read when they connected.
+	streamConnHints map[placementGRPCStream]map[string]string
+	// deltaSyncedConns has the stream connections which support delta table updates and
+	// hold the tables disseminated last time. Only these receive delta updates.
+	deltaSyncedConns map[placementGRPCStream]bool
+	// disseminatedTables has the tables disseminated last time per namespace.
+	// It is the base of the delta table updates, and the tables sent to the connecting runtimes.
+	disseminatedTables map[string]*placementv1pb.PlacementTables
+	// disseminatedTablesLock is the lock for disseminatedTables.
+	disseminatedTablesLock *sync.Mutex
+	// stickyTableChanges has the time the held back changes of the tables of the sticky actor types
+	// were first seen, per namespace and actor type.
+	stickyTableChanges map[string]map[string]time.Time
+	// eagerDissemination is set when the table of an actor type which rebalances eagerly changed,
+	// to disseminate the tables without waiting for the membership to settle.
+	eagerDissemination atomic.Bool
 
 	// raftNode is the raft server instance.
 	raftNode *raft.Server
+	// namespaceTenancy separates the hashing tables of the Dapr runtimes per namespace.
+	// When it is disabled, all Dapr runtimes share the same hashing tables.
+	namespaceTenancy bool
 
 	// lastHeartBeat represents the last time stamp when runtime sent heartbeat.
 	lastHeartBeat *sync.Map
@@ -107,15 +145,23 @@ type Service struct {
 }
 
 // NewPlacementService returns a new placement service.
-func NewPlacementService(raftNode *raft.Server) *Service {
+// If namespaceTenancy is true, the actors of Dapr runtimes are placed only on hosts of the same namespace.
+func NewPlacementService(raftNode *raft.Server, namespaceTenancy bool) *Service {
 	return &Service{
 		disseminateLock:          &sync.Mutex{},
 		streamConnPool:           []placementGRPCStream{},
 		streamConnPoolLock:       &sync.Mutex{},
+		streamConnNamespaces:     map[placementGRPCStream]string{},
+		streamConnHints:          map[placementGRPCStream]map[string]string{},
+		deltaSyncedConns:         map[placementGRPCStream]bool{},
+		disseminatedTables:       map[string]*placementv1pb.PlacementTables{},
+		disseminatedTablesLock:   &sync.Mutex{},
+		stickyTableChanges:       map[string]map[string]time.Time{},
 		membershipCh:             make(chan hostMemberChange, membershipChangeChSize),
 		hasLeadership:            false,
 		faultyHostDetectDuration: faultyHostDetectInitialDuration,
 		raftNode:                 raftNode,
+		namespaceTenancy:         namespaceTenancy,
 		shutdownCh:               make(chan struct{}),
 		shutdownLock:             &sync.Mutex{},
 		lastHeartBeat:            &sync.Map{},
@@ -170,6 +216,11 @@ TIMEOUT:
 func (p *Service) ReportDaprStatus(stream placementv1pb.Placement_ReportDaprStatusServer) error {
 	registeredMemberID := ""
 	isActorRuntime := false
+	namespace, err := p.streamNamespace(stream)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, "failed to read the namespace of the runtime: %s", err)
+	}
+	hints := streamPlacementHints(stream)
 
 	p.streamConnGroup.Add(1)
 	defer func() {
@@ -183,10 +234,10 @@ func (p *Service) ReportDaprStatus(stream placementv1pb.Placement_ReportDaprStat
 		case nil:
 			if registeredMemberID == "" {
 				registeredMemberID = req.Name
-				p.addStreamConn(stream)
+				p.addStreamConn(stream, namespace, hints)
 				// TODO: If each sidecar can report table version, then placement
 				// doesn't need to disseminate tables to each sidecar.
-				p.performTablesUpdate([]placementGRPCStream{stream}, p.raftNode.FSM().PlacementState())
+				p.performTablesUpdate([]placementGRPCStream{stream}, p.connectionTables(namespace))
 				log.Debugf("Stream connection is established from %s", registeredMemberID)
 			}
 
@@ -208,7 +259,7 @@ func (p *Service) ReportDaprStatus(stream placementv1pb.Placement_ReportDaprStat
 			// the existing member info is unmatched with the incoming member info.
 			upsertRequired := true
 			if m, ok := members[req.Name]; ok {
-				if m.AppID == req.Id && m.Name == req.Name && cmp.Equal(m.Entities, req.Entities) {
+				if m.AppID == req.Id && m.Name == req.Name && m.Namespace == namespace && cmp.Equal(m.Entities, req.Entities) {
 					upsertRequired = false
 				}
 			}
@@ -219,6 +270,7 @@ func (p *Service) ReportDaprStatus(stream placementv1pb.Placement_ReportDaprStat
 					host: raft.DaprHostMember{
 						Name:      req.Name,
 						AppID:     req.Id,
+						Namespace: namespace,
 						Entities:  req.Entities,
 						UpdatedAt: time.Now().UnixNano(),
 					},
@@ -253,14 +305,133 @@ func (p *Service) ReportDaprStatus(stream placementv1pb.Placement_ReportDaprStat
 }
 
 // addStreamConn adds stream connection between runtime and placement to the dissemination pool
-func (p *Service) addStreamConn(conn placementGRPCStream) {
+// with the namespace of the runtime and the placement hints of its actor types.
+func (p *Service) addStreamConn(conn placementGRPCStream, namespace string, hints map[string]string) {
 	p.streamConnPoolLock.Lock()
 	p.streamConnPool = append(p.streamConnPool, conn)
+	p.streamConnNamespaces[conn] = namespace
+	p.streamConnHints[conn] = hints
 	p.streamConnPoolLock.Unlock()
 }
 
+// streamNamespace returns the namespace of the runtime of the stream connection. With mTLS, it is the
+// namespace of the SPIFFE ID of the client certificate, otherwise the namespace reported by the runtime.
+// It is always empty if namespace tenancy is disabled.
+func (p *Service) streamNamespace(stream placementGRPCStream) (string, error) {
+	if !p.namespaceTenancy {
+		return "", nil
+	}
+	if pr, ok := peer.FromContext(stream.Context()); ok && pr.AuthInfo != nil {
+		if tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo); ok {
+			id, err := config.GetAndParseSpiffeIDFromCerts(tlsInfo.State.PeerCertificates)
+			if err != nil {
+				return "", err
+			}
+			return id.Namespace, nil
+		}
+	}
+	md, ok := metadata.FromIncomingContext(stream.Context())
+	if !ok {
+		return "", nil
+	}
+	if v := md.Get(namespaceMetadataKey); len(v) > 0 {
+		return v[0], nil
+	}
+	return "", nil
+}
+
+// streamConnsByNamespace groups the stream connections in the dissemination pool by namespace.
+func (p *Service) streamConnsByNamespace() map[string][]placementGRPCStream {
+	p.streamConnPoolLock.Lock()
+	defer p.streamConnPoolLock.Unlock()
+
+	conns := map[string][]placementGRPCStream{}
+	for _, c := range p.streamConnPool {
+		ns := p.streamConnNamespaces[c]
+		conns[ns] = append(conns[ns], c)
+	}
+	return conns
+}
+
+// streamPlacementHints returns the placement hints of the actor types reported by the runtime of the stream connection.
+// The hints with an unknown mode are ignored.
+func streamPlacementHints(stream placementGRPCStream) map[string]string {
+	hints := map[string]string{}
+	md, ok := metadata.FromIncomingContext(stream.Context())
+	if !ok {
+		return hints
+	}
+	for _, v := range md.Get(placementHintsMetadataKey) {
+		i := strings.LastIndex(v, "=")
+		if i < 0 {
+			continue
+		}
+		if mode := v[i+1:]; mode == config.ActorPlacementSticky || mode == config.ActorPlacementRebalanceEagerly {
+			hints[v[:i]] = mode
+		}
+	}
+	return hints
+}
+
+// placementHints returns the placement hints of the actor types of the runtimes in the namespace.
+// The actor types the runtimes report different hints for have none.
+func (p *Service) placementHints(namespace string) map[string]string {
+	p.streamConnPoolLock.Lock()
+	defer p.streamConnPoolLock.Unlock()
+
+	hints := map[string]string{}
+	conflicts := map[string]bool{}
+	for _, c := range p.streamConnPool {
+		if p.streamConnNamespaces[c] != namespace {
+			continue
+		}
+		for actorType, mode := range p.streamConnHints[c] {
+			if m, ok := hints[actorType]; ok && m != mode {
+				conflicts[actorType] = true
+			}
+			hints[actorType] = mode
+		}
+	}
+	for actorType := range conflicts {
+		delete(hints, actorType)
+	}
+	return hints
+}
+
+// streamSupportsDeltaUpdates returns true if the runtime of the stream connection can apply delta table updates.
+func streamSupportsDeltaUpdates(stream placementGRPCStream) bool {
+	md, ok := metadata.FromIncomingContext(stream.Context())
+	if !ok {
+		return false
+	}
+	v := md.Get(deltaUpdatesMetadataKey)
+	return len(v) > 0 && v[0] == "true"
+}
+
+func (p *Service) isDeltaSynced(conn placementGRPCStream) bool {
+	p.streamConnPoolLock.Lock()
+	defer p.streamConnPoolLock.Unlock()
+	return p.deltaSyncedConns[conn]
+}
+
+// setDeltaSynced marks the stream connections supporting delta updates as synced or not.
+func (p *Service) setDeltaSynced(conns []placementGRPCStream, synced bool) {
+	p.streamConnPoolLock.Lock()
+	defer p.streamConnPoolLock.Unlock()
+	for _, c := range conns {
+		if synced && streamSupportsDeltaUpdates(c) {
+			p.deltaSyncedConns[c] = true
+		} else {
+			delete(p.deltaSyncedConns, c)
+		}
+	}
+}
+
 func (p *Service) deleteStreamConn(conn placementGRPCStream) {
 	p.streamConnPoolLock.Lock()
+	delete(p.deltaSyncedConns, conn)
+	delete(p.streamConnNamespaces, conn)
+	delete(p.streamConnHints, conn)
 	for i, c := range p.streamConnPool {
 		if c == conn {
 			p.streamConnPool = append(p.streamConnPool[:i], p.streamConnPool[i+1:]...)
@@ -7,6 +7,9 @@ package placement
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
 	"os"
 	"strconv"
 	"testing"
@@ -19,6 +22,9 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -34,7 +40,7 @@ func TestMain(m *testing.M) {
 			ID:      "testnode",
 			Address: "127.0.0.1:6060",
 		},
-	}, "")
+	}, "", raft.SnapshotOptions{})
 
 	testRaftServer.StartRaft(nil)
 
@@ -53,7 +59,7 @@ func TestMain(m *testing.M) {
 }
 
 func newTestPlacementServer(raftServer *raft.Server) (string, *Service, func()) {
-	testServer := NewPlacementService(raftServer)
+	testServer := NewPlacementService(raftServer, false)
 
 	port, _ := freeport.GetFreePort()
 	go func() {
@@ -245,3 +251,111 @@ func TestMemberRegistration_Leadership(t *testing.T) {
 
 	cleanup()
 }
+
+type fakeNamespaceStream struct {
+	placementGRPCStream
+	ctx context.Context
+}
+
+func (s *fakeNamespaceStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamNamespace(t *testing.T) {
+	nsStream := &fakeNamespaceStream{
+		ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(namespaceMetadataKey, "ns1")),
+	}
+	noNSStream := &fakeNamespaceStream{ctx: context.Background()}
+
+	t.Run("namespace tenancy disabled", func(t *testing.T) {
+		testServer := NewPlacementService(testRaftServer, false)
+		ns, err := testServer.streamNamespace(nsStream)
+		assert.NoError(t, err)
+		assert.Equal(t, "", ns)
+	})
+
+	t.Run("namespace tenancy enabled", func(t *testing.T) {
+		testServer := NewPlacementService(testRaftServer, true)
+		ns, err := testServer.streamNamespace(nsStream)
+		assert.NoError(t, err)
+		assert.Equal(t, "ns1", ns)
+		ns, err = testServer.streamNamespace(noNSStream)
+		assert.NoError(t, err)
+		assert.Equal(t, "", ns)
+	})
+
+	t.Run("namespace of the mTLS client certificate", func(t *testing.T) {
+		testServer := NewPlacementService(testRaftServer, true)
+		tlsStream := func(uri string) *fakeNamespaceStream {
+			cert := &x509.Certificate{}
+			if uri != "" {
+				u, err := url.Parse(uri)
+				require.NoError(t, err)
+				cert.URIs = []*url.URL{u}
+			}
+			ctx := peer.NewContext(nsStream.ctx, &peer.Peer{
+				AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+			})
+			return &fakeNamespaceStream{ctx: ctx}
+		}
+
+		// the namespace reported in the metadata is ignored
+		ns, err := testServer.streamNamespace(tlsStream("spiffe://cluster.local/ns/ns2/app1"))
+		assert.NoError(t, err)
+		assert.Equal(t, "ns2", ns)
+
+		_, err = testServer.streamNamespace(tlsStream(""))
+		assert.Error(t, err)
+	})
+
+	t.Run("group streams by namespace", func(t *testing.T) {
+		testServer := NewPlacementService(testRaftServer, true)
+		testServer.addStreamConn(nsStream, "ns1", nil)
+		testServer.addStreamConn(noNSStream, "", nil)
+
+		conns := testServer.streamConnsByNamespace()
+		assert.Equal(t, 2, len(conns))
+		assert.Equal(t, []placementGRPCStream{nsStream}, conns["ns1"])
+		assert.Equal(t, []placementGRPCStream{noNSStream}, conns[""])
+
+		testServer.deleteStreamConn(nsStream)
+		conns = testServer.streamConnsByNamespace()
+		assert.Equal(t, 1, len(conns))
+		assert.Empty(t, testServer.streamConnNamespaces[nsStream])
+	})
+}
+
+func TestPlacementHints(t *testing.T) {
+	hintsStream := func(ns string, hints ...string) *fakeNamespaceStream {
+		pairs := []string{namespaceMetadataKey, ns}
+		for _, h := range hints {
+			pairs = append(pairs, placementHintsMetadataKey, h)
+		}
+		return &fakeNamespaceStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))}
+	}
+
+	t.Run("hints of the stream", func(t *testing.T) {
+		hints := streamPlacementHints(hintsStream("ns1", "DogActor=sticky", "CatActor=rebalance-eagerly", "BirdActor=never", "FishActor"))
+		assert.Equal(t, map[string]string{"DogActor": "sticky", "CatActor": "rebalance-eagerly"}, hints)
+	})
+
+	t.Run("hints of the namespace", func(t *testing.T) {
+		testServer := NewPlacementService(testRaftServer, true)
+		s1 := hintsStream("ns1", "DogActor=sticky", "CatActor=rebalance-eagerly")
+		s2 := hintsStream("ns1", "DogActor=sticky", "CatActor=sticky")
+		s3 := hintsStream("ns2", "BirdActor=rebalance-eagerly")
+		testServer.addStreamConn(s1, "ns1", streamPlacementHints(s1))
+		testServer.addStreamConn(s2, "ns1", streamPlacementHints(s2))
+		testServer.addStreamConn(s3, "ns2", streamPlacementHints(s3))
+
+		// the actor types with conflicting hints have none
+		assert.Equal(t, map[string]string{"DogActor": "sticky"}, testServer.placementHints("ns1"))
+		assert.Equal(t, map[string]string{"BirdActor": "rebalance-eagerly"}, testServer.placementHints("ns2"))
+
+		assert.True(t, testServer.rebalancesEagerly(raft.DaprHostMember{Namespace: "ns2", Entities: []string{"DogActor", "BirdActor"}}))
+		assert.False(t, testServer.rebalancesEagerly(raft.DaprHostMember{Namespace: "ns1", Entities: []string{"DogActor", "BirdActor"}}))
+
+		testServer.deleteStreamConn(s3)
+		assert.Empty(t, testServer.placementHints("ns2"))
+	})
+}
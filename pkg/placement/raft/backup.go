@@ -0,0 +1,182 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package raft
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/raft"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ExportedSnapshotFile is the name of the file the placement state snapshot is exported to.
+	ExportedSnapshotFile = "placement-state.snapshot"
+
+	snapshotRestoreTimeout      = 30 * time.Second
+	snapshotLeaderCheckInterval = 1 * time.Second
+)
+
+// SnapshotOptions configures the periodic snapshots of the placement state.
+type SnapshotOptions struct {
+	// Interval is the interval to take snapshots in addition to the ones raft takes
+	// to compact its log. Periodic snapshots are disabled if it is zero.
+	Interval time.Duration
+	// ExportPath is the directory the leader copies every periodic snapshot to,
+	// e.g. a volume backed by object storage. The snapshots are not exported if it is empty.
+	ExportPath string
+	// RestorePath is the path of an exported snapshot the leader restores
+	// if the placement cluster starts without state.
+	RestorePath string
+}
+
+// runSnapshots restores the configured snapshot and takes the periodic snapshots until stopCh is closed.
+func (s *Server) runSnapshots(stopCh chan struct{}) {
+	if s.snapshotOpts.RestorePath != "" {
+		if !s.waitForLeadership(stopCh) {
+			return
+		}
+		if err := s.restoreSnapshot(s.snapshotOpts.RestorePath); err != nil {
+			logging.Errorf("failed to restore snapshot %s: %v", s.snapshotOpts.RestorePath, err)
+		}
+	}
+
+	if s.snapshotOpts.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.snapshotOpts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := s.takeSnapshot(); err != nil {
+				logging.Errorf("failed to take snapshot: %v", err)
+			}
+		}
+	}
+}
+
+// waitForLeadership waits until this node is the leader of a cluster with a known leader.
+// It returns false if a follower node is detected or stopCh is closed.
+func (s *Server) waitForLeadership(stopCh chan struct{}) bool {
+	ticker := time.NewTicker(snapshotLeaderCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if s.IsLeader() {
+			return true
+		}
+		if s.raft.Leader() != "" {
+			// another node is the leader, which restores the snapshot if needed.
+			return false
+		}
+
+		select {
+		case <-stopCh:
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// takeSnapshot takes a raft snapshot and exports it if this node is the leader.
+func (s *Server) takeSnapshot() error {
+	future := s.raft.Snapshot()
+	if err := future.Error(); err != nil {
+		if err == raft.ErrNothingNewToSnapshot {
+			return nil
+		}
+		return err
+	}
+
+	if s.snapshotOpts.ExportPath == "" || !s.IsLeader() {
+		return nil
+	}
+
+	meta, r, err := future.Open()
+	if err != nil {
+		return errors.Wrap(err, "failed to open snapshot")
+	}
+	defer r.Close()
+
+	if err := writeSnapshotFile(s.snapshotOpts.ExportPath, r); err != nil {
+		return errors.Wrap(err, "failed to export snapshot")
+	}
+
+	logging.Infof("snapshot %s exported to %s", meta.ID, s.snapshotOpts.ExportPath)
+	return nil
+}
+
+// restoreSnapshot restores the snapshot file at path if the placement state is empty.
+func (s *Server) restoreSnapshot(path string) error {
+	state := s.fsm.State()
+	if len(state.Members) > 0 || state.TableGeneration > 0 {
+		logging.Infof("placement state exists, skip restoring snapshot %s", path)
+		return nil
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		logging.Infof("snapshot %s does not exist, skip restoring snapshot", path)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	meta := &raft.SnapshotMeta{
+		Version: raft.SnapshotVersionMax,
+		Size:    info.Size(),
+	}
+	if err := s.raft.Restore(meta, f, snapshotRestoreTimeout); err != nil {
+		return err
+	}
+
+	logging.Infof("snapshot %s restored, members: %d", path, len(s.fsm.State().Members))
+	return nil
+}
+
+// writeSnapshotFile writes the snapshot to ExportedSnapshotFile in dir. The file is
+// replaced atomically so that a restore never reads a partially written snapshot.
+func writeSnapshotFile(dir string, r io.Reader) error {
+	if err := ensureDir(dir); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, ExportedSnapshotFile+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, ExportedSnapshotFile))
+}
@@ -0,0 +1,71 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package raft
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSnapshotFile(t *testing.T) {
+	// arrange
+	fsm := newFSM()
+	cmdLog, err := makeRaftLogCommand(MemberUpsert, DaprHostMember{
+		Name:     "127.0.0.1:3030",
+		AppID:    "fakeAppID",
+		Entities: []string{"actorTypeOne", "actorTypeTwo"},
+	})
+	require.NoError(t, err)
+	fsm.Apply(&raft.Log{
+		Index: 1,
+		Term:  1,
+		Type:  raft.LogCommand,
+		Data:  cmdLog,
+	})
+
+	snap, err := fsm.Snapshot()
+	require.NoError(t, err)
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, snap.Persist(&MockSnapShotSink{buf, false}))
+
+	dir, err := ioutil.TempDir("", "placement-snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	exportPath := filepath.Join(dir, "export")
+
+	t.Run("export snapshot", func(t *testing.T) {
+		// act
+		err := writeSnapshotFile(exportPath, bytes.NewReader(buf.Bytes()))
+
+		// assert
+		assert.NoError(t, err)
+		files, err := ioutil.ReadDir(exportPath)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(files))
+		assert.Equal(t, ExportedSnapshotFile, files[0].Name())
+	})
+
+	t.Run("restore exported snapshot", func(t *testing.T) {
+		f, err := os.Open(filepath.Join(exportPath, ExportedSnapshotFile))
+		require.NoError(t, err)
+
+		// act
+		restoredFSM := newFSM()
+		err = restoredFSM.Restore(f)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(restoredFSM.State().Members))
+		assert.Equal(t, 2, len(restoredFSM.State().hashingTableMap))
+	})
+}
@@ -54,8 +54,8 @@ func (c *FSM) State() *DaprHostMemberState {
 	return c.state
 }
 
-// PlacementState returns the current placement tables.
-func (c *FSM) PlacementState() *v1pb.PlacementTables {
+// PlacementState returns the current placement tables of the hosts in namespace.
+func (c *FSM) PlacementState(namespace string) *v1pb.PlacementTables {
 	c.stateLock.RLock()
 	defer c.stateLock.RUnlock()
 
@@ -70,6 +70,11 @@ func (c *FSM) PlacementState() *v1pb.PlacementTables {
 
 	entries := c.state.hashingTableMap
 	for k, v := range entries {
+		ns, actorType := splitHashingTableKey(k)
+		if ns != namespace {
+			continue
+		}
+
 		hosts, sortedSet, loadMap, totalLoad := v.GetInternals()
 		table := v1pb.PlacementTable{
 			Hosts:     make(map[uint64]string),
@@ -93,7 +98,7 @@ func (c *FSM) PlacementState() *v1pb.PlacementTables {
 			}
 			table.LoadMap[lk] = &h
 		}
-		newTable.Entries[k] = &table
+		newTable.Entries[actorType] = &table
 
 		totalHostSize += len(table.Hosts)
 		totalSortedSet += len(table.SortedSet)
@@ -106,7 +106,53 @@ func TestPlacementState(t *testing.T) {
 		Data:  cmdLog,
 	})
 
-	newTable := fsm.PlacementState()
+	newTable := fsm.PlacementState("")
 	assert.Equal(t, "1", newTable.Version)
 	assert.Equal(t, 2, len(newTable.Entries))
 }
+
+func TestPlacementStateWithNamespace(t *testing.T) {
+	fsm := newFSM()
+	members := []DaprHostMember{
+		{
+			Name:      "127.0.0.1:3030",
+			AppID:     "fakeAppID",
+			Namespace: "ns1",
+			Entities:  []string{"actorTypeOne", "actorTypeTwo"},
+		},
+		{
+			Name:      "127.0.0.1:3031",
+			AppID:     "fakeAppID",
+			Namespace: "ns2",
+			Entities:  []string{"actorTypeOne"},
+		},
+	}
+	for i, m := range members {
+		cmdLog, err := makeRaftLogCommand(MemberUpsert, m)
+		assert.NoError(t, err)
+
+		fsm.Apply(&raft.Log{
+			Index: uint64(i + 1),
+			Term:  1,
+			Type:  raft.LogCommand,
+			Data:  cmdLog,
+		})
+	}
+
+	t.Run("tables hold only the hosts of the namespace", func(t *testing.T) {
+		newTable := fsm.PlacementState("ns1")
+		assert.Equal(t, "2", newTable.Version)
+		assert.Equal(t, 2, len(newTable.Entries))
+		assert.Contains(t, newTable.Entries["actorTypeOne"].LoadMap, "127.0.0.1:3030")
+		assert.NotContains(t, newTable.Entries["actorTypeOne"].LoadMap, "127.0.0.1:3031")
+
+		newTable = fsm.PlacementState("ns2")
+		assert.Equal(t, 1, len(newTable.Entries))
+		assert.Contains(t, newTable.Entries["actorTypeOne"].LoadMap, "127.0.0.1:3031")
+	})
+
+	t.Run("no tables for other namespaces", func(t *testing.T) {
+		newTable := fsm.PlacementState("")
+		assert.Equal(t, 0, len(newTable.Entries))
+	})
+}
@@ -54,10 +54,13 @@ type Server struct {
 	snapStore   raft.SnapshotStore
 
 	raftLogStorePath string
+
+	snapshotOpts   SnapshotOptions
+	snapshotStopCh chan struct{}
 }
 
 // New creates Raft server node.
-func New(id string, inMem bool, peers []PeerInfo, logStorePath string) *Server {
+func New(id string, inMem bool, peers []PeerInfo, logStorePath string, snapshotOpts SnapshotOptions) *Server {
 	raftBind := raftAddressForID(id, peers)
 	if raftBind == "" {
 		return nil
@@ -69,6 +72,7 @@ func New(id string, inMem bool, peers []PeerInfo, logStorePath string) *Server {
 		raftBind:         raftBind,
 		peers:            peers,
 		raftLogStorePath: logStorePath,
+		snapshotOpts:     snapshotOpts,
 	}
 }
 
@@ -191,6 +195,11 @@ func (s *Server) StartRaft(config *raft.Config) error {
 
 	logging.Infof("Raft server is starting on %s...", s.raftBind)
 
+	if s.snapshotOpts.Interval > 0 || s.snapshotOpts.RestorePath != "" {
+		s.snapshotStopCh = make(chan struct{})
+		go s.runSnapshots(s.snapshotStopCh)
+	}
+
 	return err
 }
 
@@ -263,6 +272,9 @@ func (s *Server) ApplyCommand(cmdType CommandType, data DaprHostMember) (bool, e
 
 // Shutdown shutdown raft server gracefully
 func (s *Server) Shutdown() {
+	if s.snapshotStopCh != nil {
+		close(s.snapshotStopCh)
+	}
 	if s.raft != nil {
 		s.raftTransport.Close()
 		future := s.raft.Shutdown()
@@ -6,6 +6,8 @@
 package raft
 
 import (
+	"strings"
+
 	"github.com/dapr/dapr/pkg/placement/hashing"
 	"github.com/google/go-cmp/cmp"
 )
@@ -16,6 +18,9 @@ type DaprHostMember struct {
 	Name string
 	// AppID is Dapr runtime app ID.
 	AppID string
+	// Namespace is the namespace of Dapr runtime host. It is empty
+	// unless namespace tenancy is enabled in placement service.
+	Namespace string
 	// Entities is the list of Actor Types which this Dapr runtime supports.
 	Entities []string
 
@@ -36,12 +41,33 @@ type DaprHostMemberState struct {
 	TableGeneration uint64
 
 	// hashingTableMap is the map for storing consistent hashing data
-	// per Actor types. This will be generated when log entries are replayed.
+	// per namespace and Actor types. This will be generated when log entries are replayed.
 	// While snapshotting the state, this member will not be saved. Instead,
 	// hashingTableMap will be recovered in snapshot recovery process.
 	hashingTableMap map[string]*hashing.Consistent
 }
 
+// namespaceTableKeySeparator separates the namespace from the actor type
+// in the keys of hashingTableMap.
+const namespaceTableKeySeparator = "||"
+
+// hashingTableKey returns the hashingTableMap key of actorType in namespace.
+// Actor types of hosts without namespace are stored as is.
+func hashingTableKey(namespace, actorType string) string {
+	if namespace == "" {
+		return actorType
+	}
+	return namespace + namespaceTableKeySeparator + actorType
+}
+
+// splitHashingTableKey returns the namespace and the actor type of a hashingTableMap key.
+func splitHashingTableKey(key string) (string, string) {
+	if i := strings.Index(key, namespaceTableKeySeparator); i >= 0 {
+		return key[:i], key[i+len(namespaceTableKeySeparator):]
+	}
+	return "", key
+}
+
 func newDaprHostMemberState() *DaprHostMemberState {
 	return &DaprHostMemberState{
 		Index:           0,
@@ -62,6 +88,7 @@ func (s *DaprHostMemberState) clone() *DaprHostMemberState {
 		m := &DaprHostMember{
 			Name:      v.Name,
 			AppID:     v.AppID,
+			Namespace: v.Namespace,
 			Entities:  make([]string, len(v.Entities)),
 			UpdatedAt: v.UpdatedAt,
 		}
@@ -73,23 +100,25 @@ func (s *DaprHostMemberState) clone() *DaprHostMemberState {
 
 func (s *DaprHostMemberState) updateHashingTables(host *DaprHostMember) {
 	for _, e := range host.Entities {
-		if _, ok := s.hashingTableMap[e]; !ok {
-			s.hashingTableMap[e] = hashing.NewConsistentHash()
+		key := hashingTableKey(host.Namespace, e)
+		if _, ok := s.hashingTableMap[key]; !ok {
+			s.hashingTableMap[key] = hashing.NewConsistentHash()
 		}
 
-		s.hashingTableMap[e].Add(host.Name, host.AppID, 0)
+		s.hashingTableMap[key].Add(host.Name, host.AppID, 0)
 	}
 }
 
 func (s *DaprHostMemberState) removeHashingTables(host *DaprHostMember) {
 	for _, e := range host.Entities {
-		if t, ok := s.hashingTableMap[e]; ok {
+		key := hashingTableKey(host.Namespace, e)
+		if t, ok := s.hashingTableMap[key]; ok {
 			t.Remove(host.Name)
 
 			// if no dedicated actor service instance for the particular actor type,
 			// we must delete consistent hashing table to avoid the memory leak.
 			if len(t.Hosts()) == 0 {
-				delete(s.hashingTableMap, e)
+				delete(s.hashingTableMap, key)
 			}
 		}
 	}
@@ -104,7 +133,7 @@ func (s *DaprHostMemberState) upsertMember(host *DaprHostMember) bool {
 
 	if m, ok := s.Members[host.Name]; ok {
 		// No need to update consistent hashing table if the same dapr host member exists
-		if m.AppID == host.AppID && m.Name == host.Name && cmp.Equal(m.Entities, host.Entities) {
+		if m.AppID == host.AppID && m.Name == host.Name && m.Namespace == host.Namespace && cmp.Equal(m.Entities, host.Entities) {
 			m.UpdatedAt = host.UpdatedAt
 			return false
 		}
@@ -117,6 +146,7 @@ func (s *DaprHostMemberState) upsertMember(host *DaprHostMember) bool {
 	s.Members[host.Name] = &DaprHostMember{
 		Name:      host.Name,
 		AppID:     host.AppID,
+		Namespace: host.Namespace,
 		UpdatedAt: host.UpdatedAt,
 	}
 
@@ -0,0 +1,258 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/kit/logger"
+)
+
+var log = logger.NewLogger("dapr.profiling")
+
+const (
+	// ProfileTypeCPU is the CPU profile, collected over the first half of the push interval.
+	ProfileTypeCPU = "cpu"
+	// ProfileTypeHeap is the heap profile.
+	ProfileTypeHeap = "heap"
+	// ProfileTypeGoroutine is the profile of the stacks of the current goroutines.
+	ProfileTypeGoroutine = "goroutine"
+	// ProfileTypeMutex is the profile of the holders of contended mutexes.
+	ProfileTypeMutex = "mutex"
+	// ProfileTypeBlock is the profile of the stacks blocked on synchronization primitives.
+	ProfileTypeBlock = "block"
+
+	ingestPath       = "/ingest"
+	pushTimeout      = 10 * time.Second
+	cpuSampleRate    = 100
+	mutexProfileRate = 5
+	blockProfileRate = int(time.Millisecond)
+)
+
+var defaultProfileTypes = []string{ProfileTypeCPU, ProfileTypeHeap}
+
+// Pusher periodically collects the pprof profiles of the process and pushes them
+// to the ingestion API of a Pyroscope compatible server.
+type Pusher struct {
+	appName      string
+	labels       string
+	endpoint     string
+	interval     time.Duration
+	profileTypes []string
+	client       *http.Client
+}
+
+// NewPusher returns a Pusher for the continuous profiling configuration. The profiles are named after
+// appName and tagged with the labels of the configuration, overridden by the given labels.
+func NewPusher(appName string, labels map[string]string, spec config.ProfilingSpec) (*Pusher, error) {
+	if spec.Endpoint == "" {
+		return nil, errors.New("profiling endpoint is empty")
+	}
+	interval, err := spec.GetInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	profileTypes := spec.ProfileTypes
+	if len(profileTypes) == 0 {
+		profileTypes = defaultProfileTypes
+	}
+	for _, profileType := range profileTypes {
+		if !isValidProfileType(profileType) {
+			return nil, errors.Errorf("unsupported profile type %s", profileType)
+		}
+	}
+
+	allLabels := make(map[string]string, len(spec.Labels)+len(labels))
+	for k, v := range spec.Labels {
+		allLabels[k] = v
+	}
+	for k, v := range labels {
+		allLabels[k] = v
+	}
+
+	return &Pusher{
+		appName:      appName,
+		labels:       formatLabels(allLabels),
+		endpoint:     strings.TrimSuffix(spec.Endpoint, "/"),
+		interval:     interval,
+		profileTypes: profileTypes,
+		client:       &http.Client{Timeout: pushTimeout},
+	}, nil
+}
+
+// Run collects the profiles and pushes them every interval until ctx is done.
+// Only one CPU profile can be collected at a time in a process, so the CPU profile is collected
+// over the first half of each interval only. The pprof CPU profile endpoint can be used in the
+// second half, a CPU sample is skipped when the endpoint is in use.
+func (p *Pusher) Run(ctx context.Context) {
+	for _, profileType := range p.profileTypes {
+		switch profileType {
+		case ProfileTypeMutex:
+			runtime.SetMutexProfileFraction(mutexProfileRate)
+		case ProfileTypeBlock:
+			runtime.SetBlockProfileRate(blockProfileRate)
+		}
+	}
+	log.Infof("continuous profiling started. pushing %s profiles to %s every %s",
+		strings.Join(p.profileTypes, ", "), p.endpoint, p.interval)
+
+	for {
+		from := time.Now()
+		cpuProfile := p.startCPUProfile()
+
+		select {
+		case <-ctx.Done():
+			if cpuProfile != nil {
+				pprof.StopCPUProfile()
+			}
+			return
+		case <-time.After(p.interval / 2):
+		}
+
+		if cpuProfile != nil {
+			pprof.StopCPUProfile()
+		}
+		until := time.Now()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.interval - until.Sub(from)):
+		}
+		p.push(ctx, from, until, cpuProfile)
+	}
+}
+
+// startCPUProfile starts the CPU profiling if the CPU profile is collected. It returns the buffer
+// the profile is written to, or nil if CPU profiling is already on, e.g. through the pprof endpoints.
+func (p *Pusher) startCPUProfile() *bytes.Buffer {
+	if !p.collects(ProfileTypeCPU) {
+		return nil
+	}
+	buf := &bytes.Buffer{}
+	if err := pprof.StartCPUProfile(buf); err != nil {
+		log.Debugf("skipping cpu profile: %s", err)
+		return nil
+	}
+	return buf
+}
+
+func (p *Pusher) push(ctx context.Context, from, until time.Time, cpuProfile *bytes.Buffer) {
+	for _, profileType := range p.profileTypes {
+		var profile []byte
+		if profileType == ProfileTypeCPU {
+			if cpuProfile == nil {
+				continue
+			}
+			profile = cpuProfile.Bytes()
+		} else {
+			buf := &bytes.Buffer{}
+			if err := pprof.Lookup(profileType).WriteTo(buf, 0); err != nil {
+				log.Warnf("failed to collect %s profile: %s", profileType, err)
+				continue
+			}
+			profile = buf.Bytes()
+		}
+
+		if err := p.upload(ctx, profileType, profile, from, until); err != nil {
+			log.Warnf("failed to push %s profile: %s", profileType, err)
+		}
+	}
+}
+
+// upload sends the profile in the pprof format to the ingestion API.
+func (p *Pusher) upload(ctx context.Context, profileType string, profile []byte, from, until time.Time) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("profile", "profile.pprof")
+	if err != nil {
+		return err
+	}
+	if _, err = part.Write(profile); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("name", fmt.Sprintf("%s.%s%s", p.appName, profileType, p.labels))
+	query.Set("from", strconv.FormatInt(from.Unix(), 10))
+	query.Set("until", strconv.FormatInt(until.Unix(), 10))
+	query.Set("spyName", "gospy")
+	if profileType == ProfileTypeCPU {
+		query.Set("sampleRate", strconv.Itoa(cpuSampleRate))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.endpoint+ingestPath+"?"+query.Encode(), body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *Pusher) collects(profileType string) bool {
+	for _, t := range p.profileTypes {
+		if t == profileType {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidProfileType(profileType string) bool {
+	switch profileType {
+	case ProfileTypeCPU, ProfileTypeHeap, ProfileTypeGoroutine, ProfileTypeMutex, ProfileTypeBlock:
+		return true
+	}
+	return false
+}
+
+// formatLabels formats the labels in the {key=value,...} form appended to the profile names.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+labels[k])
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
@@ -0,0 +1,121 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dapr/dapr/pkg/config"
+)
+
+func TestNewPusher(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		p, err := NewPusher("daprd", nil, config.ProfilingSpec{Endpoint: "http://pyroscope:4040/"})
+		require.NoError(t, err)
+		assert.Equal(t, "http://pyroscope:4040", p.endpoint)
+		assert.Equal(t, 10*time.Second, p.interval)
+		assert.Equal(t, []string{ProfileTypeCPU, ProfileTypeHeap}, p.profileTypes)
+		assert.Empty(t, p.labels)
+	})
+
+	t.Run("labels", func(t *testing.T) {
+		p, err := NewPusher("daprd", map[string]string{"app_id": "app1"}, config.ProfilingSpec{
+			Endpoint: "http://pyroscope:4040",
+			Labels:   map[string]string{"env": "prod", "app_id": "ignored"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "{app_id=app1,env=prod}", p.labels)
+	})
+
+	t.Run("no endpoint", func(t *testing.T) {
+		_, err := NewPusher("daprd", nil, config.ProfilingSpec{})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		_, err := NewPusher("daprd", nil, config.ProfilingSpec{Endpoint: "http://pyroscope:4040", Interval: "often"})
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported profile type", func(t *testing.T) {
+		_, err := NewPusher("daprd", nil, config.ProfilingSpec{Endpoint: "http://pyroscope:4040", ProfileTypes: []string{"threadcreate"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestPusherRun(t *testing.T) {
+	pushed := make(chan *http.Request, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("profile")
+		if assert.NoError(t, err) {
+			profile, _ := ioutil.ReadAll(file)
+			assert.NotEmpty(t, profile)
+		}
+		pushed <- r
+	}))
+	defer server.Close()
+
+	p, err := NewPusher("daprd", map[string]string{"app_id": "app1"}, config.ProfilingSpec{
+		Endpoint:     server.URL,
+		Interval:     "100ms",
+		ProfileTypes: []string{ProfileTypeHeap, ProfileTypeGoroutine},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	names := map[string]bool{}
+	for len(names) < 2 {
+		select {
+		case r := <-pushed:
+			assert.Equal(t, ingestPath, r.URL.Path)
+			assert.Equal(t, "gospy", r.URL.Query().Get("spyName"))
+			names[r.URL.Query().Get("name")] = true
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "profiles not pushed")
+		}
+	}
+	assert.True(t, names["daprd.heap{app_id=app1}"])
+	assert.True(t, names["daprd.goroutine{app_id=app1}"])
+}
+
+func TestPusherRunLeavesCPUProfilingFree(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	p, err := NewPusher("daprd", nil, config.ProfilingSpec{
+		Endpoint:     server.URL,
+		Interval:     "1s",
+		ProfileTypes: []string{ProfileTypeCPU},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	// the second half of the interval is free for the pprof endpoint.
+	time.Sleep(700 * time.Millisecond)
+	require.NoError(t, pprof.StartCPUProfile(&bytes.Buffer{}))
+	pprof.StopCPUProfile()
+}
+
+func TestFormatLabels(t *testing.T) {
+	assert.Equal(t, "", formatLabels(nil))
+	assert.Equal(t, "{a=1,b=2}", formatLabels(map[string]string{"b": "2", "a": "1"}))
+}
@@ -14,10 +14,13 @@ import (
 
 	"github.com/pkg/errors"
 
+	http_channel "github.com/dapr/dapr/pkg/channel/http"
 	global_config "github.com/dapr/dapr/pkg/config"
 	env "github.com/dapr/dapr/pkg/config/env"
 	"github.com/dapr/dapr/pkg/cors"
 	"github.com/dapr/dapr/pkg/grpc"
+	"github.com/dapr/dapr/pkg/health"
+	"github.com/dapr/dapr/pkg/logsampler"
 	"github.com/dapr/dapr/pkg/metrics"
 	"github.com/dapr/dapr/pkg/modes"
 	"github.com/dapr/dapr/pkg/operator/client"
@@ -48,11 +51,26 @@ func FromFlags() (*DaprRuntime, error) {
 	appMaxConcurrency := flag.Int("app-max-concurrency", -1, "Controls the concurrency level when forwarding requests to user code")
 	enableMTLS := flag.Bool("enable-mtls", false, "Enables automatic mTLS for daprd to daprd communication channels")
 	appSSL := flag.Bool("app-ssl", false, "Sets the URI scheme of the app to https and attempts an SSL connection")
+	appMTLS := flag.Bool("app-mtls", false, "Uses TLS for the daprd to app channel, with daprd presenting its workload certificate signed by Sentry for the app to authenticate with the trust anchors. The certificate of the app is not verified, and the app to daprd API calls are not covered. Requires mTLS to be enabled")
+	appHTTP2 := flag.Bool("app-http2", false, "Uses HTTP/2 for the HTTP app channel, in cleartext (h2c) unless app-ssl or app-mtls is set")
+	appHTTPMaxConns := flag.Int("app-http-max-conns", 0, "Limits the HTTP/1.1 connections to the app, with requests waiting for a free connection. 0 means no limit")
+	appHTTPMaxIdleConnDuration := flag.Duration("app-http-max-idle-conn-duration", 0, "How long an idle HTTP/1.1 connection to the app is kept open. 0 means 10s")
+	appHealthProbe := flag.String("app-health-probe", health.ProbeModeHTTP, "Probe used to check the health of the app: http, grpc or tcp")
+	pubsubShutdownTimeout := flag.Duration("pubsub-shutdown-timeout", DefaultShutdownTimeout, "Time to wait on shutdown for the app to handle the pub/sub messages already delivered")
+	bindingsShutdownTimeout := flag.Duration("bindings-shutdown-timeout", DefaultShutdownTimeout, "Time to wait on shutdown for the app to handle the input binding events already delivered")
+	actorsShutdownTimeout := flag.Duration("actors-shutdown-timeout", DefaultShutdownTimeout, "Time to wait on shutdown for the active actors to be deactivated")
+	apiShutdownTimeout := flag.Duration("api-shutdown-timeout", DefaultShutdownTimeout, "Time to wait on shutdown for the HTTP and gRPC API requests in progress")
+	componentDrainTimeout := flag.Duration("component-drain-timeout", DefaultComponentDrainTimeout, "Time a replaced component instance is given to complete its calls before it is closed")
+	gcPercent := flag.Int("gc-percent", 0, "Sets the garbage collection target percentage, the equivalent of GOGC. Overrides the gc configuration")
+	memorySoftLimit := flag.String("memory-soft-limit", "", "Soft memory limit, e.g. 512Mi, the heap is kept under by collecting garbage more often. Overrides the gc configuration")
 	daprHTTPMaxRequestSize := flag.Int("dapr-http-max-request-size", -1, "Increasing max size of request body in MB to handle uploading of big files. By default 4 MB.")
 
 	loggerOptions := logger.DefaultOptions()
 	loggerOptions.AttachCmdFlags(flag.StringVar, flag.BoolVar)
 
+	logSamplingOptions := logsampler.DefaultOptions()
+	logSamplingOptions.AttachCmdFlags(flag.IntVar, flag.DurationVar)
+
 	metricsExporter := metrics.NewExporter(metrics.DefaultMetricNamespace)
 
 	metricsExporter.Options().AttachCmdFlags(flag.StringVar, flag.BoolVar)
@@ -73,6 +91,7 @@ func FromFlags() (*DaprRuntime, error) {
 	if err := logger.ApplyOptionsToLoggers(&loggerOptions); err != nil {
 		return nil, err
 	}
+	logsampler.ApplyOptionsToLoggers(logSamplingOptions)
 
 	log.Infof("starting Dapr Runtime -- version %s -- commit %s", version.Version(), version.Commit())
 	log.Infof("log level set to: %s", loggerOptions.OutputLevel)
@@ -118,6 +137,22 @@ func FromFlags() (*DaprRuntime, error) {
 		}
 	}
 
+	if !health.IsValidProbeMode(*appHealthProbe) {
+		return nil, errors.Errorf("invalid app-health-probe %s", *appHealthProbe)
+	}
+
+	if *gcPercent < 0 {
+		return nil, errors.Errorf("invalid gc-percent %d", *gcPercent)
+	}
+
+	var memoryLimit uint64
+	if *memorySoftLimit != "" {
+		memoryLimit, err = global_config.ParseMemoryLimit(*memorySoftLimit)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid memory-soft-limit")
+		}
+	}
+
 	var maxRequestBodySize int
 	if *daprHTTPMaxRequestSize != -1 {
 		maxRequestBodySize = *daprHTTPMaxRequestSize
@@ -142,6 +177,22 @@ func FromFlags() (*DaprRuntime, error) {
 
 	runtimeConfig := NewRuntimeConfig(*appID, placementAddresses, *controlPlaneAddress, *allowedOrigins, *config, *componentsPath,
 		appPrtcl, *mode, daprHTTP, daprInternalGRPC, daprAPIGRPC, applicationPort, profPort, *enableProfiling, concurrency, *enableMTLS, *sentryAddress, *appSSL, maxRequestBodySize)
+	runtimeConfig.AppMTLS = *appMTLS
+	runtimeConfig.AppHealthProbe = *appHealthProbe
+	runtimeConfig.AppHTTPChannel = http_channel.ConnectionConfig{
+		MaxConns:            *appHTTPMaxConns,
+		MaxIdleConnDuration: *appHTTPMaxIdleConnDuration,
+		HTTP2:               *appHTTP2,
+	}
+	runtimeConfig.GCPercent = *gcPercent
+	runtimeConfig.MemorySoftLimit = memoryLimit
+	runtimeConfig.Shutdown = ShutdownConfig{
+		PubSubTimeout:   *pubsubShutdownTimeout,
+		BindingsTimeout: *bindingsShutdownTimeout,
+		ActorsTimeout:   *actorsShutdownTimeout,
+		APITimeout:      *apiShutdownTimeout,
+	}
+	runtimeConfig.ComponentDrainTimeout = *componentDrainTimeout
 
 	// set environment variables
 	// TODO - consider adding host address to runtime config and/or caching result in utils package
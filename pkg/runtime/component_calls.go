@@ -0,0 +1,213 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package runtime
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/dapr/components-contrib/secretstores"
+	"github.com/dapr/components-contrib/state"
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
+)
+
+type instanceCalls struct {
+	count int
+	// idle is closed when the count drops to 0, it is nil while no one waits.
+	idle chan struct{}
+}
+
+// componentCalls counts the calls in flight on component instances, so that a replaced
+// instance is closed once the calls it was serving complete.
+type componentCalls struct {
+	lock      *sync.Mutex
+	instances map[interface{}]*instanceCalls
+}
+
+func newComponentCalls() *componentCalls {
+	return &componentCalls{
+		lock:      &sync.Mutex{},
+		instances: map[interface{}]*instanceCalls{},
+	}
+}
+
+// start records a call on the instance. The returned func must be called when the call completes.
+func (c *componentCalls) start(instance interface{}) func() {
+	c.lock.Lock()
+	calls, ok := c.instances[instance]
+	if !ok {
+		calls = &instanceCalls{}
+		c.instances[instance] = calls
+	}
+	calls.count++
+	c.lock.Unlock()
+
+	return func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		calls.count--
+		if calls.count == 0 {
+			if calls.idle != nil {
+				close(calls.idle)
+			}
+			delete(c.instances, instance)
+		}
+	}
+}
+
+// wait waits for the calls in flight on the instance to complete, up to timeout.
+// It returns false if calls were still in flight at the timeout.
+func (c *componentCalls) wait(instance interface{}, timeout time.Duration) bool {
+	c.lock.Lock()
+	calls, ok := c.instances[instance]
+	if !ok {
+		c.lock.Unlock()
+		return true
+	}
+	if calls.idle == nil {
+		calls.idle = make(chan struct{})
+	}
+	idle := calls.idle
+	c.lock.Unlock()
+
+	select {
+	case <-idle:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// trackedComponent is implemented by the wrappers recording the calls made on the component instance they wrap.
+type trackedComponent interface {
+	// trackedInstance returns the instance the calls are recorded for.
+	trackedInstance() interface{}
+}
+
+// callsKey returns the instance the calls made on a component instance are recorded for.
+func callsKey(instance interface{}) interface{} {
+	if tracked, ok := instance.(trackedComponent); ok {
+		return tracked.trackedInstance()
+	}
+	return instance
+}
+
+// closeInstance closes the component instance if it can be closed.
+func closeInstance(instance interface{}) error {
+	if closer, ok := instance.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// trackedStateStore records the calls made on a state store.
+type trackedStateStore struct {
+	state.Store
+	calls *componentCalls
+}
+
+// trackedTransactionalStateStore records the calls made on a transactional state store.
+type trackedTransactionalStateStore struct {
+	*trackedStateStore
+	transactional state.TransactionalStore
+}
+
+// trackStateStore wraps the state store to record the calls made on it.
+func (c *componentCalls) trackStateStore(store state.Store) state.Store {
+	tracked := &trackedStateStore{Store: store, calls: c}
+	if transactional, ok := store.(state.TransactionalStore); ok {
+		return &trackedTransactionalStateStore{trackedStateStore: tracked, transactional: transactional}
+	}
+	return tracked
+}
+
+func (s *trackedStateStore) trackedInstance() interface{} {
+	return s.Store
+}
+
+func (s *trackedStateStore) Get(req *state.GetRequest) (*state.GetResponse, error) {
+	defer s.calls.start(s.Store)()
+	return s.Store.Get(req)
+}
+
+func (s *trackedStateStore) Set(req *state.SetRequest) error {
+	defer s.calls.start(s.Store)()
+	return s.Store.Set(req)
+}
+
+func (s *trackedStateStore) Delete(req *state.DeleteRequest) error {
+	defer s.calls.start(s.Store)()
+	return s.Store.Delete(req)
+}
+
+func (s *trackedStateStore) BulkGet(req []state.GetRequest) (bool, []state.BulkGetResponse, error) {
+	defer s.calls.start(s.Store)()
+	return s.Store.BulkGet(req)
+}
+
+func (s *trackedStateStore) BulkSet(req []state.SetRequest) error {
+	defer s.calls.start(s.Store)()
+	return s.Store.BulkSet(req)
+}
+
+func (s *trackedStateStore) BulkDelete(req []state.DeleteRequest) error {
+	defer s.calls.start(s.Store)()
+	return s.Store.BulkDelete(req)
+}
+
+func (s *trackedStateStore) Close() error {
+	return closeInstance(s.Store)
+}
+
+func (s *trackedTransactionalStateStore) Multi(request *state.TransactionalStateRequest) error {
+	defer s.calls.start(s.Store)()
+	return s.transactional.Multi(request)
+}
+
+// trackedSecretStore records the calls made on a secret store.
+type trackedSecretStore struct {
+	secretstores.SecretStore
+	calls *componentCalls
+}
+
+// trackedCachedSecretStore records the calls made on a secret store caching its secrets.
+type trackedCachedSecretStore struct {
+	*trackedSecretStore
+	cache secretstores_loader.CacheInvalidator
+}
+
+// trackSecretStore wraps the secret store to record the calls made on it.
+func (c *componentCalls) trackSecretStore(store secretstores.SecretStore) secretstores.SecretStore {
+	tracked := &trackedSecretStore{SecretStore: store, calls: c}
+	if cache, ok := store.(secretstores_loader.CacheInvalidator); ok {
+		return &trackedCachedSecretStore{trackedSecretStore: tracked, cache: cache}
+	}
+	return tracked
+}
+
+func (s *trackedSecretStore) trackedInstance() interface{} {
+	return s.SecretStore
+}
+
+func (s *trackedSecretStore) GetSecret(req secretstores.GetSecretRequest) (secretstores.GetSecretResponse, error) {
+	defer s.calls.start(s.SecretStore)()
+	return s.SecretStore.GetSecret(req)
+}
+
+func (s *trackedSecretStore) BulkGetSecret(req secretstores.BulkGetSecretRequest) (secretstores.BulkGetSecretResponse, error) {
+	defer s.calls.start(s.SecretStore)()
+	return s.SecretStore.BulkGetSecret(req)
+}
+
+func (s *trackedSecretStore) Close() error {
+	return closeInstance(s.SecretStore)
+}
+
+func (s *trackedCachedSecretStore) InvalidateCache() {
+	s.cache.InvalidateCache()
+}
@@ -0,0 +1,104 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/components-contrib/state"
+	secretstores_loader "github.com/dapr/dapr/pkg/components/secretstores"
+	daprt "github.com/dapr/dapr/pkg/testing"
+)
+
+func TestComponentCalls(t *testing.T) {
+	instance := &mockStateStore{}
+
+	t.Run("no calls in flight", func(t *testing.T) {
+		calls := newComponentCalls()
+		assert.True(t, calls.wait(instance, time.Millisecond))
+	})
+
+	t.Run("calls complete before the timeout", func(t *testing.T) {
+		calls := newComponentCalls()
+		done1 := calls.start(instance)
+		done2 := calls.start(instance)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			done1()
+			done2()
+		}()
+
+		assert.True(t, calls.wait(instance, time.Second))
+		assert.Empty(t, calls.instances)
+	})
+
+	t.Run("calls in flight at the timeout", func(t *testing.T) {
+		calls := newComponentCalls()
+		done := calls.start(instance)
+		defer done()
+
+		assert.False(t, calls.wait(instance, 10*time.Millisecond))
+	})
+
+	t.Run("calls on other instances are not waited for", func(t *testing.T) {
+		calls := newComponentCalls()
+		done := calls.start(&mockStateStore{})
+		defer done()
+
+		assert.True(t, calls.wait(instance, time.Millisecond))
+	})
+}
+
+func TestTrackedComponents(t *testing.T) {
+	t.Run("state store calls are recorded", func(t *testing.T) {
+		calls := newComponentCalls()
+		store := daprt.NewFakeETagStateStore()
+		tracked := calls.trackStateStore(store)
+		store.BeforeSet = func() {
+			assert.False(t, calls.wait(callsKey(tracked), time.Millisecond))
+		}
+
+		assert.NoError(t, tracked.Set(&state.SetRequest{Key: "key1", Value: "value1"}))
+		assert.True(t, calls.wait(callsKey(tracked), time.Millisecond))
+	})
+
+	t.Run("transactional state store stays transactional", func(t *testing.T) {
+		calls := newComponentCalls()
+		tracked := calls.trackStateStore(daprt.NewFakeETagStateStore())
+		_, ok := tracked.(state.TransactionalStore)
+		assert.True(t, ok)
+
+		tracked = calls.trackStateStore(&mockStateStore{})
+		_, ok = tracked.(state.TransactionalStore)
+		assert.False(t, ok)
+	})
+
+	t.Run("replaced state store is closed", func(t *testing.T) {
+		closed := make(chan struct{})
+		tracked := newComponentCalls().trackStateStore(&closingStateStore{Store: &mockStateStore{}, closed: closed})
+
+		assert.NoError(t, closeInstance(tracked))
+		select {
+		case <-closed:
+		default:
+			assert.Fail(t, "wrapped state store not closed")
+		}
+	})
+
+	t.Run("cached secret store keeps its cache", func(t *testing.T) {
+		calls := newComponentCalls()
+		tracked := calls.trackSecretStore(secretstores_loader.NewCachedSecretStore(daprt.FakeSecretStore{}, time.Minute, 0))
+		_, ok := tracked.(secretstores_loader.CacheInvalidator)
+		assert.True(t, ok)
+
+		tracked = calls.trackSecretStore(daprt.FakeSecretStore{})
+		_, ok = tracked.(secretstores_loader.CacheInvalidator)
+		assert.False(t, ok)
+	})
+}
@@ -6,6 +6,9 @@
 package runtime
 
 import (
+	"time"
+
+	http_channel "github.com/dapr/dapr/pkg/channel/http"
 	config "github.com/dapr/dapr/pkg/config/modes"
 	"github.com/dapr/dapr/pkg/credentials"
 	"github.com/dapr/dapr/pkg/modes"
@@ -29,6 +32,10 @@ const (
 	DefaultMetricsPort = 9090
 	// DefaultMaxRequestBodySize is the default option for the maximum body size in MB for Dapr HTTP servers
 	DefaultMaxRequestBodySize = 4
+	// DefaultShutdownTimeout is the default time to wait for the in-flight work of each subsystem on shutdown
+	DefaultShutdownTimeout = 5 * time.Second
+	// DefaultComponentDrainTimeout is the default time a replaced component instance is given to complete its calls before it is closed
+	DefaultComponentDrainTimeout = 5 * time.Second
 )
 
 // Config holds the Dapr Runtime configuration
@@ -52,7 +59,27 @@ type Config struct {
 	SentryServiceAddress string
 	CertChain            *credentials.CertChain
 	AppSSL               bool
+	AppMTLS              bool
 	MaxRequestBodySize   int
+	AppHealthProbe       string
+	AppHTTPChannel       http_channel.ConnectionConfig
+	Shutdown             ShutdownConfig
+	GCPercent            int
+	MemorySoftLimit      uint64
+	// ComponentDrainTimeout is the time a replaced component instance is given to complete its calls before it is closed.
+	ComponentDrainTimeout time.Duration
+}
+
+// ShutdownConfig holds the time to wait for the in-flight work of each subsystem on shutdown.
+type ShutdownConfig struct {
+	// PubSubTimeout is the time to wait for the app to handle the pub/sub messages delivered before the shutdown.
+	PubSubTimeout time.Duration
+	// BindingsTimeout is the time to wait for the app to handle the input binding events delivered before the shutdown.
+	BindingsTimeout time.Duration
+	// ActorsTimeout is the time to wait for the active actors to be deactivated.
+	ActorsTimeout time.Duration
+	// APITimeout is the time to wait for the HTTP and gRPC API requests in progress.
+	APITimeout time.Duration
 }
 
 // NewRuntimeConfig returns a new runtime config
@@ -85,5 +112,12 @@ func NewRuntimeConfig(
 		SentryServiceAddress: sentryAddress,
 		AppSSL:               appSSL,
 		MaxRequestBodySize:   maxRequestBodySize,
+		Shutdown: ShutdownConfig{
+			PubSubTimeout:   DefaultShutdownTimeout,
+			BindingsTimeout: DefaultShutdownTimeout,
+			ActorsTimeout:   DefaultShutdownTimeout,
+			APITimeout:      DefaultShutdownTimeout,
+		},
+		ComponentDrainTimeout: DefaultComponentDrainTimeout,
 	}
 }
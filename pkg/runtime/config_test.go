@@ -34,4 +34,8 @@ func TestNewConfig(t *testing.T) {
 	assert.Equal(t, "localhost:5052", c.SentryServiceAddress)
 	assert.Equal(t, true, c.AppSSL)
 	assert.Equal(t, 4, c.MaxRequestBodySize)
+	assert.Equal(t, DefaultShutdownTimeout, c.Shutdown.PubSubTimeout)
+	assert.Equal(t, DefaultShutdownTimeout, c.Shutdown.BindingsTimeout)
+	assert.Equal(t, DefaultShutdownTimeout, c.Shutdown.ActorsTimeout)
+	assert.Equal(t, DefaultShutdownTimeout, c.Shutdown.APITimeout)
 }
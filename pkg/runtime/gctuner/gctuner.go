@@ -0,0 +1,103 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package gctuner
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/dapr/kit/logger"
+)
+
+var log = logger.NewLogger("dapr.runtime.gctuner")
+
+const (
+	// defaultGCPercent is the GC percent of the Go runtime when GOGC is not set.
+	defaultGCPercent = 100
+	// minGCPercent bounds the GC percent near the soft memory limit so that the GC doesn't thrash.
+	minGCPercent = 10
+)
+
+// tuner adjusts the GC percent after every garbage collection so that the heap grows up to
+// the soft memory limit before the next collection, as Go 1.16 has no native memory limit (GOMEMLIMIT).
+type tuner struct {
+	maxGCPercent int
+	limit        uint64
+	gcPercent    int
+}
+
+// finalizer is unreachable and is collected by every garbage collection, which runs its finalizer.
+type finalizer struct {
+	tuner *tuner
+}
+
+func onGC(f *finalizer) {
+	f.tuner.tune()
+	runtime.SetFinalizer(f, onGC)
+}
+
+// Start sets the GC percent to gcPercent if it is positive. If limit is not zero, the GC percent is then
+// lowered as the live heap approaches limit bytes, and raised back up to gcPercent when it shrinks.
+func Start(gcPercent int, limit uint64) {
+	if gcPercent > 0 {
+		debug.SetGCPercent(gcPercent)
+		log.Infof("gc percent set to %d", gcPercent)
+	} else {
+		gcPercent = currentGCPercent()
+	}
+
+	if limit == 0 {
+		return
+	}
+
+	t := &tuner{
+		maxGCPercent: gcPercent,
+		limit:        limit,
+		gcPercent:    gcPercent,
+	}
+	runtime.SetFinalizer(&finalizer{tuner: t}, onGC)
+	log.Infof("memory soft limit set to %d bytes", limit)
+}
+
+func (t *tuner) tune() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	gcPercent := t.percent(m.HeapInuse)
+	if gcPercent != t.gcPercent {
+		debug.SetGCPercent(gcPercent)
+		t.gcPercent = gcPercent
+	}
+}
+
+// percent returns the GC percent that triggers the next collection when the heap reaches the limit.
+func (t *tuner) percent(inuse uint64) int {
+	if inuse == 0 {
+		return t.maxGCPercent
+	}
+	if inuse >= t.limit {
+		return minGCPercent
+	}
+
+	percent := int((t.limit - inuse) * 100 / inuse)
+	if percent > t.maxGCPercent {
+		return t.maxGCPercent
+	}
+	if percent < minGCPercent {
+		return minGCPercent
+	}
+	return percent
+}
+
+func currentGCPercent() int {
+	percent := debug.SetGCPercent(defaultGCPercent)
+	debug.SetGCPercent(percent)
+	if percent <= 0 {
+		// GOGC=off, keep the default for the tuning.
+		return defaultGCPercent
+	}
+	return percent
+}
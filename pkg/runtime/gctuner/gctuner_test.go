@@ -0,0 +1,53 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package gctuner
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercent(t *testing.T) {
+	tr := &tuner{
+		maxGCPercent: 100,
+		limit:        1000,
+	}
+
+	t.Run("far from the limit", func(t *testing.T) {
+		assert.Equal(t, 100, tr.percent(100))
+	})
+
+	t.Run("approaching the limit", func(t *testing.T) {
+		assert.Equal(t, 66, tr.percent(600))
+	})
+
+	t.Run("near the limit", func(t *testing.T) {
+		assert.Equal(t, minGCPercent, tr.percent(950))
+	})
+
+	t.Run("above the limit", func(t *testing.T) {
+		assert.Equal(t, minGCPercent, tr.percent(2000))
+	})
+}
+
+func TestStart(t *testing.T) {
+	defer debug.SetGCPercent(currentGCPercent())
+
+	Start(200, 0)
+	assert.Equal(t, 200, currentGCPercent())
+
+	// a limit far below the heap makes the GC as aggressive as allowed after the next collection
+	Start(200, 1)
+	runtime.GC()
+	runtime.GC()
+	assert.Eventually(t, func() bool {
+		return currentGCPercent() == minGCPercent
+	}, time.Second, 10*time.Millisecond)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/dapr/dapr/pkg/components/pubsub"
 	"github.com/dapr/dapr/pkg/components/secretstores"
 	"github.com/dapr/dapr/pkg/components/state"
+	"github.com/dapr/dapr/pkg/grpc"
 )
 
 type (
@@ -19,6 +20,7 @@ type (
 		inputBindings   []bindings.InputBinding
 		outputBindings  []bindings.OutputBinding
 		httpMiddleware  []http.Middleware
+		grpcMiddleware  []grpc.Middleware
 	}
 
 	// Option is a function that customizes the runtime.
@@ -73,3 +75,10 @@ func WithHTTPMiddleware(httpMiddleware ...http.Middleware) Option {
 		o.httpMiddleware = append(o.httpMiddleware, httpMiddleware...)
 	}
 }
+
+// WithGRPCMiddleware adds gRPC middleware components to the runtime.
+func WithGRPCMiddleware(grpcMiddleware ...grpc.Middleware) Option {
+	return func(o *runtimeOpts) {
+		o.grpcMiddleware = append(o.grpcMiddleware, grpcMiddleware...)
+	}
+}
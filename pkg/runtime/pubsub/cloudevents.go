@@ -6,6 +6,11 @@
 package pubsub
 
 import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
 	contrib_contenttype "github.com/dapr/components-contrib/contenttype"
 	contrib_pubsub "github.com/dapr/components-contrib/pubsub"
 	"github.com/google/uuid"
@@ -19,13 +24,71 @@ type CloudEvent struct {
 	Pubsub          string
 	DataContentType string
 	TraceID         string
+	Baggage         string
 }
 
+const (
+	// BaggageField is the cloudevent extension carrying the W3C baggage of the publisher
+	BaggageField = "baggage"
+	// TimeField is the cloudevent attribute carrying the time the event was published
+	TimeField = "time"
+	// TTLInSecondsKey is the subscription metadata key dropping events published longer ago
+	TTLInSecondsKey = "ttlInSeconds"
+)
+
 // NewCloudEvent encapusalates the creation of a Dapr cloudevent from an existing cloudevent or a raw payload
 func NewCloudEvent(req *CloudEvent) (map[string]interface{}, error) {
+	var envelope map[string]interface{}
 	if contrib_contenttype.IsCloudEventContentType(req.DataContentType) {
-		return contrib_pubsub.FromCloudEvent(req.Data, req.Topic, req.Pubsub, req.TraceID)
+		var err error
+		envelope, err = contrib_pubsub.FromCloudEvent(req.Data, req.Topic, req.Pubsub, req.TraceID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		envelope = contrib_pubsub.NewCloudEventsEnvelope(uuid.New().String(), req.ID, contrib_pubsub.DefaultCloudEventType, "", req.Topic, req.Pubsub,
+			req.DataContentType, req.Data, req.TraceID)
+	}
+
+	if req.Baggage != "" {
+		envelope[BaggageField] = req.Baggage
+	}
+	if _, ok := envelope[TimeField]; !ok {
+		envelope[TimeField] = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	return envelope, nil
+}
+
+// ParseTTL reads the TTL from the metadata of a subscription. It returns 0 when the TTL is not set.
+func ParseTTL(metadata map[string]string) (time.Duration, error) {
+	val, ok := metadata[TTLInSecondsKey]
+	if !ok || val == "" {
+		return 0, nil
 	}
-	return contrib_pubsub.NewCloudEventsEnvelope(uuid.New().String(), req.ID, contrib_pubsub.DefaultCloudEventType, "", req.Topic, req.Pubsub,
-		req.DataContentType, req.Data, req.TraceID), nil
+
+	ttlInSeconds, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || ttlInSeconds <= 0 {
+		return 0, errors.Errorf("invalid %s: %s", TTLInSecondsKey, val)
+	}
+
+	return time.Duration(ttlInSeconds) * time.Second, nil
+}
+
+// HasExceededTTL returns true when the cloudevent was published more than ttl ago.
+// Events without a valid time attribute never exceed the TTL.
+func HasExceededTTL(cloudEvent map[string]interface{}, ttl time.Duration) bool {
+	if ttl <= 0 {
+		return false
+	}
+
+	publishedAt, ok := cloudEvent[TimeField].(string)
+	if !ok {
+		return false
+	}
+	published, err := time.Parse(time.RFC3339Nano, publishedAt)
+	if err != nil {
+		return false
+	}
+
+	return time.Since(published) > ttl
 }
@@ -8,6 +8,7 @@ package pubsub
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -69,4 +70,90 @@ func TestNewCloudEvent(t *testing.T) {
 		assert.Equal(t, "trace1", ce["traceid"].(string))
 		assert.Equal(t, "pubsub", ce["pubsubname"].(string))
 	})
+
+	t.Run("baggage", func(t *testing.T) {
+		ce, err := NewCloudEvent(&CloudEvent{
+			ID:      "a",
+			Topic:   "b",
+			Data:    []byte("hello"),
+			Pubsub:  "c",
+			TraceID: "d",
+			Baggage: "userId=alice,tenant=contoso",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "userId=alice,tenant=contoso", ce[BaggageField].(string))
+	})
+
+	t.Run("no baggage", func(t *testing.T) {
+		ce, err := NewCloudEvent(&CloudEvent{
+			ID:     "a",
+			Topic:  "b",
+			Data:   []byte("hello"),
+			Pubsub: "c",
+		})
+		assert.NoError(t, err)
+		assert.NotContains(t, ce, BaggageField)
+	})
+}
+
+func TestCloudEventTime(t *testing.T) {
+	t.Run("set when missing", func(t *testing.T) {
+		ce, err := NewCloudEvent(&CloudEvent{
+			ID:     "a",
+			Topic:  "b",
+			Data:   []byte("hello"),
+			Pubsub: "c",
+		})
+		assert.NoError(t, err)
+		published, err := time.Parse(time.RFC3339Nano, ce[TimeField].(string))
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), published, time.Minute)
+	})
+
+	t.Run("kept from custom cloudevent", func(t *testing.T) {
+		b, _ := json.Marshal(map[string]interface{}{
+			"specversion":     "1.0",
+			"id":              "event",
+			"datacontenttype": "text/plain",
+			"data":            "world",
+			"time":            "2021-01-01T00:00:00Z",
+		})
+
+		ce, err := NewCloudEvent(&CloudEvent{
+			Data:            b,
+			DataContentType: "application/cloudevents+json",
+			Topic:           "topic1",
+			Pubsub:          "pubsub",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "2021-01-01T00:00:00Z", ce[TimeField])
+	})
+}
+
+func TestParseTTL(t *testing.T) {
+	ttl, err := ParseTTL(map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), ttl)
+
+	ttl, err = ParseTTL(map[string]string{TTLInSecondsKey: "30"})
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, ttl)
+
+	_, err = ParseTTL(map[string]string{TTLInSecondsKey: "30s"})
+	assert.Error(t, err)
+
+	_, err = ParseTTL(map[string]string{TTLInSecondsKey: "0"})
+	assert.Error(t, err)
+}
+
+func TestHasExceededTTL(t *testing.T) {
+	published := func(ago time.Duration) map[string]interface{} {
+		return map[string]interface{}{TimeField: time.Now().Add(-ago).UTC().Format(time.RFC3339Nano)}
+	}
+
+	assert.True(t, HasExceededTTL(published(time.Minute), 30*time.Second))
+	assert.False(t, HasExceededTTL(published(time.Second), 30*time.Second))
+	assert.False(t, HasExceededTTL(published(time.Minute), 0))
+	assert.False(t, HasExceededTTL(map[string]interface{}{}, 30*time.Second))
+	assert.False(t, HasExceededTTL(map[string]interface{}{TimeField: "yesterday"}, 30*time.Second))
 }
@@ -0,0 +1,128 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package pubsub
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/atomic"
+
+	contrib_pubsub "github.com/dapr/components-contrib/pubsub"
+)
+
+const (
+	// MaxConcurrentHandlersKey is the subscription metadata key limiting how many messages of the topic
+	// are delivered to the app at the same time.
+	MaxConcurrentHandlersKey = "maxConcurrentHandlers"
+	// PrefetchKey is the subscription metadata key limiting how many messages of the topic can wait for
+	// a free handler. Messages over the limit are rejected so that the broker redelivers them later.
+	PrefetchKey = "prefetch"
+	// HandlerTimeoutKey is the subscription metadata key limiting how long a message of the topic
+	// can wait for and spend in the app.
+	HandlerTimeoutKey = "handlerTimeout"
+)
+
+// ErrPrefetchLimitReached is returned to the broker when a message is rejected because too many
+// messages of the topic are already waiting for a handler.
+var ErrPrefetchLimitReached = errors.New("prefetch limit of the subscription reached")
+
+// HandlerOptions controls how the messages of a subscription are delivered to the app,
+// regardless of the underlying broker.
+type HandlerOptions struct {
+	MaxConcurrentHandlers int
+	Prefetch              int
+	HandlerTimeout        time.Duration
+}
+
+// ParseHandlerOptions reads the handler options from the metadata of a subscription.
+// Options that are not set are left at zero, which means unlimited.
+func ParseHandlerOptions(metadata map[string]string) (HandlerOptions, error) {
+	var (
+		opts HandlerOptions
+		err  error
+	)
+
+	if val, ok := metadata[MaxConcurrentHandlersKey]; ok && val != "" {
+		opts.MaxConcurrentHandlers, err = strconv.Atoi(val)
+		if err != nil || opts.MaxConcurrentHandlers < 0 {
+			return opts, errors.Errorf("invalid %s: %s", MaxConcurrentHandlersKey, val)
+		}
+	}
+
+	if val, ok := metadata[PrefetchKey]; ok && val != "" {
+		opts.Prefetch, err = strconv.Atoi(val)
+		if err != nil || opts.Prefetch < 0 {
+			return opts, errors.Errorf("invalid %s: %s", PrefetchKey, val)
+		}
+		if opts.MaxConcurrentHandlers == 0 {
+			return opts, errors.Errorf("%s requires %s to be set", PrefetchKey, MaxConcurrentHandlersKey)
+		}
+	}
+
+	if val, ok := metadata[HandlerTimeoutKey]; ok && val != "" {
+		opts.HandlerTimeout, err = time.ParseDuration(val)
+		if err != nil || opts.HandlerTimeout < 0 {
+			return opts, errors.Errorf("invalid %s: %s", HandlerTimeoutKey, val)
+		}
+	}
+
+	return opts, nil
+}
+
+// Wrap returns a handler that delivers messages to handler honoring the options.
+func (o HandlerOptions) Wrap(handler contrib_pubsub.Handler) contrib_pubsub.Handler {
+	if o.MaxConcurrentHandlers == 0 && o.HandlerTimeout == 0 {
+		return handler
+	}
+
+	var slots chan struct{}
+	if o.MaxConcurrentHandlers > 0 {
+		slots = make(chan struct{}, o.MaxConcurrentHandlers)
+	}
+	var waiting atomic.Int32
+
+	return func(ctx context.Context, msg *contrib_pubsub.NewMessage) error {
+		if o.HandlerTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, o.HandlerTimeout)
+			defer cancel()
+		}
+
+		if slots != nil {
+			if err := o.acquire(ctx, slots, &waiting); err != nil {
+				return err
+			}
+			defer func() { <-slots }()
+		}
+
+		return handler(ctx, msg)
+	}
+}
+
+// acquire takes a handler slot, waiting for one to free up unless the prefetch limit is reached.
+func (o HandlerOptions) acquire(ctx context.Context, slots chan struct{}, waiting *atomic.Int32) error {
+	select {
+	case slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if w := waiting.Inc(); o.Prefetch > 0 && int(w) > o.Prefetch {
+		waiting.Dec()
+		return ErrPrefetchLimitReached
+	}
+	defer waiting.Dec()
+
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,95 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	contrib_pubsub "github.com/dapr/components-contrib/pubsub"
+)
+
+func TestParseHandlerOptions(t *testing.T) {
+	t.Run("no options", func(t *testing.T) {
+		opts, err := ParseHandlerOptions(map[string]string{"rawPayload": "true"})
+		assert.NoError(t, err)
+		assert.Equal(t, HandlerOptions{}, opts)
+	})
+
+	t.Run("all options", func(t *testing.T) {
+		opts, err := ParseHandlerOptions(map[string]string{
+			MaxConcurrentHandlersKey: "2",
+			PrefetchKey:              "10",
+			HandlerTimeoutKey:        "30s",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, HandlerOptions{MaxConcurrentHandlers: 2, Prefetch: 10, HandlerTimeout: 30 * time.Second}, opts)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, metadata := range []map[string]string{
+			{MaxConcurrentHandlersKey: "two"},
+			{MaxConcurrentHandlersKey: "-1"},
+			{MaxConcurrentHandlersKey: "2", PrefetchKey: "ten"},
+			{PrefetchKey: "10"},
+			{HandlerTimeoutKey: "30"},
+		} {
+			_, err := ParseHandlerOptions(metadata)
+			assert.Error(t, err, metadata)
+		}
+	})
+}
+
+func TestHandlerOptionsWrap(t *testing.T) {
+	msg := &contrib_pubsub.NewMessage{Topic: "topic1"}
+
+	t.Run("limits concurrent handlers and rejects over prefetch", func(t *testing.T) {
+		release := make(chan struct{})
+		started := make(chan struct{}, 2)
+		handler := HandlerOptions{MaxConcurrentHandlers: 1, Prefetch: 1}.Wrap(
+			func(ctx context.Context, msg *contrib_pubsub.NewMessage) error {
+				started <- struct{}{}
+				<-release
+				return nil
+			})
+
+		done := make(chan error, 2)
+		go func() { done <- handler(context.Background(), msg) }()
+		<-started
+
+		// waits for the busy handler
+		go func() { done <- handler(context.Background(), msg) }()
+		assert.Eventually(t, func() bool {
+			// times out instead when it waits before the other message
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			return handler(ctx, msg) == ErrPrefetchLimitReached
+		}, time.Second, 10*time.Millisecond)
+
+		select {
+		case <-started:
+			assert.Fail(t, "second message delivered while the handler is busy")
+		default:
+		}
+
+		close(release)
+		assert.NoError(t, <-done)
+		assert.NoError(t, <-done)
+	})
+
+	t.Run("handler timeout", func(t *testing.T) {
+		handler := HandlerOptions{HandlerTimeout: 10 * time.Millisecond}.Wrap(
+			func(ctx context.Context, msg *contrib_pubsub.NewMessage) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+
+		assert.Equal(t, context.DeadlineExceeded, handler(context.Background(), msg))
+	})
+}
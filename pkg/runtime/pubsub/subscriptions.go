@@ -9,6 +9,9 @@ import (
 	"path/filepath"
 
 	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	subscriptionsapi "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
@@ -23,10 +26,12 @@ const (
 	getTopicsError         = "error getting topic list from app: %s"
 	deserializeTopicsError = "error getting topics from app: %s"
 	noSubscriptionsError   = "user app did not subscribe to any topic"
+	statusCodeError        = "app returned http status code %v from subscription endpoint"
 	subscriptionKind       = "Subscription"
 )
 
-func GetSubscriptionsHTTP(channel channel.AppChannel, log logger.Logger) []Subscription {
+// GetSubscriptionsHTTP reads the subscriptions of the app from its subscription endpoint.
+func GetSubscriptionsHTTP(channel channel.AppChannel, log logger.Logger) ([]Subscription, error) {
 	var subscriptions []Subscription
 
 	req := invokev1.NewInvokeMethodRequest("dapr/subscribe")
@@ -37,25 +42,24 @@ func GetSubscriptionsHTTP(channel channel.AppChannel, log logger.Logger) []Subsc
 	ctx := context.Background()
 	resp, err := channel.InvokeMethod(ctx, req)
 	if err != nil {
-		log.Errorf(getTopicsError, err)
+		return nil, errors.Errorf(getTopicsError, err)
 	}
 
 	switch resp.Status().Code {
 	case http.StatusOK:
 		_, body := resp.RawData()
 		if err := json.Unmarshal(body, &subscriptions); err != nil {
-			log.Errorf(deserializeTopicsError, err)
+			return nil, errors.Errorf(deserializeTopicsError, err)
 		}
 	case http.StatusNotFound:
 		log.Debug(noSubscriptionsError)
 
 	default:
-		// Unexpected response: both GRPC and HTTP have to log the same level.
-		log.Errorf("app returned http status code %v from subscription endpoint", resp.Status().Code)
+		return nil, errors.Errorf(statusCodeError, resp.Status().Code)
 	}
 
 	log.Debugf("app responded with subscriptions %v", subscriptions)
-	return filterSubscriptions(subscriptions, log)
+	return filterSubscriptions(subscriptions, log), nil
 }
 
 func filterSubscriptions(subscriptions []Subscription, log logger.Logger) []Subscription {
@@ -68,27 +72,32 @@ func filterSubscriptions(subscriptions []Subscription, log logger.Logger) []Subs
 	return subscriptions
 }
 
-func GetSubscriptionsGRPC(channel runtimev1pb.AppCallbackClient, log logger.Logger) []Subscription {
+// GetSubscriptionsGRPC reads the subscriptions of the app from its ListTopicSubscriptions callback.
+func GetSubscriptionsGRPC(channel runtimev1pb.AppCallbackClient, log logger.Logger) ([]Subscription, error) {
 	var subscriptions []Subscription
 
 	resp, err := channel.ListTopicSubscriptions(context.Background(), &emptypb.Empty{})
 	if err != nil {
-		// Unexpected response: both GRPC and HTTP have to log the same level.
-		log.Errorf(getTopicsError, err)
-	} else {
-		if resp == nil || resp.Subscriptions == nil || len(resp.Subscriptions) == 0 {
+		if status.Code(err) == codes.Unimplemented {
+			// the app doesn't implement the callback when it doesn't subscribe to any topic.
 			log.Debug(noSubscriptionsError)
-		} else {
-			for _, s := range resp.Subscriptions {
-				subscriptions = append(subscriptions, Subscription{
-					PubsubName: s.PubsubName,
-					Topic:      s.GetTopic(),
-					Metadata:   s.GetMetadata(),
-				})
-			}
+			return subscriptions, nil
 		}
+		return nil, errors.Errorf(getTopicsError, err)
 	}
-	return subscriptions
+
+	if resp == nil || resp.Subscriptions == nil || len(resp.Subscriptions) == 0 {
+		log.Debug(noSubscriptionsError)
+	} else {
+		for _, s := range resp.Subscriptions {
+			subscriptions = append(subscriptions, Subscription{
+				PubsubName: s.PubsubName,
+				Topic:      s.GetTopic(),
+				Metadata:   s.GetMetadata(),
+			})
+		}
+	}
+	return subscriptions, nil
 }
 
 // DeclarativeSelfHosted loads subscriptions from the given components path
@@ -139,6 +148,7 @@ func marshalSubscription(b []byte) (*Subscription, error) {
 		Topic:      sub.Spec.Topic,
 		PubsubName: sub.Spec.Pubsubname,
 		Route:      sub.Spec.Route,
+		Metadata:   sub.Spec.Metadata,
 		Scopes:     sub.Scopes,
 	}, nil
 }
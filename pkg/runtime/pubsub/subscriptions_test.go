@@ -3,15 +3,20 @@ package pubsub
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	subscriptionsapi "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
+	channelt "github.com/dapr/dapr/pkg/channel/testing"
+	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	"github.com/dapr/kit/logger"
 )
 
@@ -57,6 +62,45 @@ func writeSubscriptionToDisk(subscription subscriptionsapi.Subscription, filePat
 	ioutil.WriteFile(filePath, b, 0600)
 }
 
+func TestGetSubscriptionsHTTP(t *testing.T) {
+	t.Run("subscriptions of the app", func(t *testing.T) {
+		mockAppChannel := new(channelt.MockAppChannel)
+		resp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		resp.WithRawData([]byte(`[{"pubsubname":"pubsub","topic":"topic1","route":"topic1"}]`), "application/json")
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).Return(resp, nil)
+
+		subs, err := GetSubscriptionsHTTP(mockAppChannel, log)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(subs))
+		assert.Equal(t, "topic1", subs[0].Topic)
+	})
+
+	t.Run("no subscription endpoint", func(t *testing.T) {
+		mockAppChannel := new(channelt.MockAppChannel)
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).Return(invokev1.NewInvokeMethodResponse(http.StatusNotFound, "Not Found", nil), nil)
+
+		subs, err := GetSubscriptionsHTTP(mockAppChannel, log)
+		assert.NoError(t, err)
+		assert.Empty(t, subs)
+	})
+
+	t.Run("app channel error", func(t *testing.T) {
+		mockAppChannel := new(channelt.MockAppChannel)
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+
+		_, err := GetSubscriptionsHTTP(mockAppChannel, log)
+		assert.Error(t, err)
+	})
+
+	t.Run("app error", func(t *testing.T) {
+		mockAppChannel := new(channelt.MockAppChannel)
+		mockAppChannel.On("InvokeMethod", mock.Anything, mock.Anything).Return(invokev1.NewInvokeMethodResponse(http.StatusInternalServerError, "Internal Error", nil), nil)
+
+		_, err := GetSubscriptionsHTTP(mockAppChannel, log)
+		assert.Error(t, err)
+	})
+}
+
 func TestDeclarativeSubscriptions(t *testing.T) {
 	dir := filepath.Join(".", "components")
 	os.Mkdir(dir, 0777)
@@ -65,6 +109,7 @@ func TestDeclarativeSubscriptions(t *testing.T) {
 	t.Run("load single valid subscription", func(t *testing.T) {
 		s := testDeclarativeSubscription()
 		s.Scopes = []string{"scope1"}
+		s.Spec.Metadata = map[string]string{MaxConcurrentHandlersKey: "2"}
 
 		filePath := filepath.Join(".", "components", "sub.yaml")
 		writeSubscriptionToDisk(s, filePath)
@@ -75,6 +120,7 @@ func TestDeclarativeSubscriptions(t *testing.T) {
 		assert.Equal(t, "myroute", subs[0].Route)
 		assert.Equal(t, "pubsub", subs[0].PubsubName)
 		assert.Equal(t, "scope1", subs[0].Scopes[0])
+		assert.Equal(t, "2", subs[0].Metadata[MaxConcurrentHandlersKey])
 	})
 
 	t.Run("load multiple subscriptions", func(t *testing.T) {
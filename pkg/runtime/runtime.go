@@ -7,14 +7,16 @@ package runtime
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/tls"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net"
 	nethttp "net/http"
 	"os"
-	"reflect"
 	"strconv"
 	"strings"
 	"sync"
@@ -28,7 +30,10 @@ import (
 	zipkinreporter "github.com/openzipkin/zipkin-go/reporter/http"
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
+	"go.uber.org/atomic"
+	grpc_go "google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -56,14 +61,18 @@ import (
 	diag "github.com/dapr/dapr/pkg/diagnostics"
 	diag_utils "github.com/dapr/dapr/pkg/diagnostics/utils"
 	"github.com/dapr/dapr/pkg/grpc"
+	"github.com/dapr/dapr/pkg/health"
 	"github.com/dapr/dapr/pkg/http"
+	"github.com/dapr/dapr/pkg/logsampler"
 	"github.com/dapr/dapr/pkg/messaging"
 	invokev1 "github.com/dapr/dapr/pkg/messaging/v1"
 	http_middleware "github.com/dapr/dapr/pkg/middleware/http"
 	"github.com/dapr/dapr/pkg/modes"
 	"github.com/dapr/dapr/pkg/operator/client"
+	"github.com/dapr/dapr/pkg/profiling"
 	operatorv1pb "github.com/dapr/dapr/pkg/proto/operator/v1"
 	runtimev1pb "github.com/dapr/dapr/pkg/proto/runtime/v1"
+	"github.com/dapr/dapr/pkg/runtime/gctuner"
 	runtime_pubsub "github.com/dapr/dapr/pkg/runtime/pubsub"
 	"github.com/dapr/dapr/pkg/runtime/security"
 	"github.com/dapr/dapr/pkg/scopes"
@@ -90,6 +99,7 @@ const (
 	stateComponent              ComponentCategory = "state"
 	middlewareComponent         ComponentCategory = "middleware"
 	defaultComponentInitTimeout                   = time.Second * 5
+	daprNamespaceEnvVar                           = "DAPR_NAMESPACE"
 )
 
 var componentCategoriesNeedProcess = []ComponentCategory{
@@ -100,11 +110,12 @@ var componentCategoriesNeedProcess = []ComponentCategory{
 	middlewareComponent,
 }
 
-var log = logger.NewLogger("dapr.runtime")
+var log = logsampler.NewLogger(logger.NewLogger("dapr.runtime"))
 
 type Route struct {
 	path     string
 	metadata map[string]string
+	ttl      time.Duration
 }
 
 type TopicRoute struct {
@@ -118,6 +129,7 @@ type DaprRuntime struct {
 	accessControlList      *config.AccessControlList
 	componentsLock         *sync.RWMutex
 	components             []components_v1alpha1.Component
+	componentStatus        *components.StatusStore
 	grpc                   *grpc.Manager
 	appChannel             channel.AppChannel
 	appConfig              config.ApplicationConfig
@@ -130,6 +142,8 @@ type DaprRuntime struct {
 	bindingsRegistry       bindings_loader.Registry
 	subscribeBindingList   []string
 	inputBindings          map[string]bindings.InputBinding
+	pausedInputBindings    map[string]chan struct{}
+	inputBindingsLock      *sync.RWMutex
 	outputBindings         map[string]bindings.OutputBinding
 	secretStores           map[string]secretstores.SecretStore
 	pubSubRegistry         pubsub_loader.Registry
@@ -137,6 +151,7 @@ type DaprRuntime struct {
 	nameResolver           nr.Resolver
 	json                   jsoniter.API
 	httpMiddlewareRegistry http_middleware_loader.Registry
+	grpcMiddlewareRegistry grpc.MiddlewareRegistry
 	hostAddress            string
 	actorStateStoreName    string
 	actorStateStoreCount   int
@@ -148,11 +163,37 @@ type DaprRuntime struct {
 	daprHTTPAPI            http.API
 	operatorClient         operatorv1pb.OperatorClient
 	topicRoutes            map[string]TopicRoute
+	// subscribedTopics has the topics each pubsub component instance is subscribed to.
+	subscribedTopics map[pubsub.PubSub]map[string]bool
+	// topicRoutesLock guards topicRoutes and subscribedTopics, which change when the subscriptions are refreshed.
+	topicRoutesLock *sync.RWMutex
+	// subscriptionsRefreshLock serializes the subscription refreshes.
+	subscriptionsRefreshLock *sync.Mutex
+	// topicRoutesStale is set while the subscriptions are refreshed, and after the subscriptions of the app couldn't be read.
+	topicRoutesStale atomic.Bool
 
 	secretsConfiguration map[string]config.SecretsScope
 
 	pendingComponents          chan components_v1alpha1.Component
+	pendingComponentGroups     chan []components_v1alpha1.Component
 	pendingComponentDependents map[string][]components_v1alpha1.Component
+	// componentsInitLock guards the runtime state written by components initialized concurrently.
+	componentsInitLock *sync.Mutex
+
+	// shuttingDown is set when the runtime stops accepting pub/sub messages and input binding events.
+	shuttingDown atomic.Bool
+	// shutdownC is closed with shuttingDown set, to release the input binding events held by a pause
+	// and stop the recovery sweeps of the transaction outboxes.
+	shutdownC chan struct{}
+	// inflightPubSubMessages and inflightBindingEvents count the deliveries to the app the shutdown waits for.
+	inflightPubSubMessages atomic.Int32
+	inflightBindingEvents  atomic.Int32
+	// componentCalls counts the calls in flight on the component instances, which replaced instances are drained of.
+	componentCalls *componentCalls
+	// apiServers are the HTTP and gRPC API servers, stopped on shutdown.
+	apiServers []apiServer
+	// appHealthy is the result of the app health probe, reported by the detailed healthz endpoint and to placement.
+	appHealthy atomic.Bool
 }
 
 type componentPreprocessRes struct {
@@ -167,9 +208,12 @@ func NewDaprRuntime(runtimeConfig *Config, globalConfig *config.Configuration, a
 		accessControlList:      accessControlList,
 		componentsLock:         &sync.RWMutex{},
 		components:             make([]components_v1alpha1.Component, 0),
+		componentStatus:        components.NewStatusStore(),
 		grpc:                   grpc.NewGRPCManager(runtimeConfig.Mode),
 		json:                   jsoniter.ConfigFastest,
 		inputBindings:          map[string]bindings.InputBinding{},
+		pausedInputBindings:    map[string]chan struct{}{},
+		inputBindingsLock:      &sync.RWMutex{},
 		outputBindings:         map[string]bindings.OutputBinding{},
 		secretStores:           map[string]secretstores.SecretStore{},
 		stateStores:            map[string]state.Store{},
@@ -180,6 +224,11 @@ func NewDaprRuntime(runtimeConfig *Config, globalConfig *config.Configuration, a
 		secretStoresRegistry:   secretstores_loader.NewRegistry(),
 		nameResolutionRegistry: nr_loader.NewRegistry(),
 		httpMiddlewareRegistry: http_middleware_loader.NewRegistry(),
+		grpcMiddlewareRegistry: grpc.NewMiddlewareRegistry(),
+
+		subscribedTopics:         map[pubsub.PubSub]map[string]bool{},
+		topicRoutesLock:          &sync.RWMutex{},
+		subscriptionsRefreshLock: &sync.Mutex{},
 
 		scopedSubscriptions: map[string][]string{},
 		scopedPublishings:   map[string][]string{},
@@ -188,7 +237,11 @@ func NewDaprRuntime(runtimeConfig *Config, globalConfig *config.Configuration, a
 		secretsConfiguration: map[string]config.SecretsScope{},
 
 		pendingComponents:          make(chan components_v1alpha1.Component),
+		pendingComponentGroups:     make(chan []components_v1alpha1.Component),
 		pendingComponentDependents: map[string][]components_v1alpha1.Component{},
+		componentsInitLock:         &sync.Mutex{},
+		componentCalls:             newComponentCalls(),
+		shutdownC:                  make(chan struct{}),
 	}
 }
 
@@ -214,12 +267,19 @@ func (a *DaprRuntime) Run(opts ...Option) error {
 	if a.daprHTTPAPI != nil {
 		// gRPC server start failure is logged as Fatal in initRuntime method. Setting the status only when runtime is initialized.
 		a.daprHTTPAPI.MarkStatusAsReady()
+		// the output bindings are loaded, so that the invocations left in the transaction outboxes can be recovered.
+		a.daprHTTPAPI.StartTransactionOutboxRecovery(a.shutdownC)
 	}
 
 	return nil
 }
 
 func (a *DaprRuntime) getNamespace() string {
+	if a.runtimeConfig.Mode == modes.StandaloneMode {
+		if ns := os.Getenv(daprNamespaceEnvVar); ns != "" {
+			return ns
+		}
+	}
 	return os.Getenv("NAMESPACE")
 }
 
@@ -255,10 +315,44 @@ func (a *DaprRuntime) setupTracing(hostAddress string, exporters traceExporterSt
 	return nil
 }
 
+// startContinuousProfiling pushes the profiles of the runtime to the endpoint of the profiling configuration.
+func (a *DaprRuntime) startContinuousProfiling() {
+	labels := map[string]string{"app_id": a.runtimeConfig.ID}
+	if a.namespace != "" {
+		labels["namespace"] = a.namespace
+	}
+	pusher, err := profiling.NewPusher("daprd", labels, a.globalConfig.Spec.Profiling)
+	if err != nil {
+		log.Warnf("failed to start continuous profiling: %s", err)
+		return
+	}
+	go pusher.Run(context.Background())
+}
+
+// startGCTuning applies the gc configuration, with the gc-percent and memory-soft-limit flags taking precedence.
+func (a *DaprRuntime) startGCTuning() {
+	gcPercent := a.globalConfig.Spec.GC.Percent
+	if a.runtimeConfig.GCPercent > 0 {
+		gcPercent = a.runtimeConfig.GCPercent
+	}
+	limit := a.runtimeConfig.MemorySoftLimit
+	if limit == 0 {
+		var err error
+		if limit, err = a.globalConfig.Spec.GC.GetMemoryLimit(); err != nil {
+			log.Warnf("failed to apply the memory soft limit: %s", err)
+		}
+	}
+	if gcPercent > 0 || limit > 0 {
+		gctuner.Start(gcPercent, limit)
+	}
+}
+
 func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
+	a.startGCTuning()
+
 	// Initialize metrics only if MetricSpec is enabled.
 	if a.globalConfig.Spec.MetricSpec.Enabled {
-		if err := diag.InitMetrics(a.runtimeConfig.ID); err != nil {
+		if err := diag.InitMetrics(a.runtimeConfig.ID, a.globalConfig.Spec.MetricSpec); err != nil {
 			log.Errorf("failed to initialize metrics: %v", err)
 		}
 	}
@@ -279,6 +373,9 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	if err = a.setupTracing(a.hostAddress, openCensusExporterStore{}); err != nil {
 		return errors.Wrap(err, "failed to setup tracing")
 	}
+	if a.globalConfig.Spec.Profiling.Endpoint != "" {
+		a.startContinuousProfiling()
+	}
 	// Register and initialize name resolution for service discovery.
 	a.nameResolutionRegistry.Register(opts.nameResolutions...)
 	err = a.initNameResolution()
@@ -292,6 +389,8 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	a.bindingsRegistry.RegisterInputBindings(opts.inputBindings...)
 	a.bindingsRegistry.RegisterOutputBindings(opts.outputBindings...)
 	a.httpMiddlewareRegistry.Register(opts.httpMiddleware...)
+	a.httpMiddlewareRegistry.Register(http_middleware_loader.New(http_middleware.DistributedRateLimitName, a.newDistributedRateLimitMiddleware))
+	a.grpcMiddlewareRegistry.Register(opts.grpcMiddleware...)
 
 	go a.processComponents()
 	err = a.beginComponentsUpdates()
@@ -308,22 +407,33 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 
 	pipeline, err := a.buildHTTPPipeline()
 	if err != nil {
-		log.Warnf("failed to build HTTP pipeline: %s", err)
+		return errors.Wrap(err, "failed to build HTTP pipeline")
+	}
+	outboundPipeline, err := a.buildOutboundHTTPPipeline()
+	if err != nil {
+		return errors.Wrap(err, "failed to build outbound HTTP pipeline")
 	}
 
 	// Setup allow/deny list for secrets
 	a.populateSecretsConfiguration()
 	// Create and start internal and external gRPC servers
-	grpcAPI := a.getGRPCAPI()
+	grpcAPI := a.getGRPCAPI(outboundPipeline)
 
-	err = a.startGRPCAPIServer(grpcAPI, a.runtimeConfig.APIGRPCPort)
+	grpcPipeline, err := a.buildGRPCPipeline()
+	if err != nil {
+		return errors.Wrap(err, "failed to build gRPC pipeline")
+	}
+	err = a.startGRPCAPIServer(grpcAPI, a.runtimeConfig.APIGRPCPort, grpcPipeline)
 	if err != nil {
 		log.Fatalf("failed to start API gRPC server: %s", err)
 	}
 	log.Infof("API gRPC server is running on port %v", a.runtimeConfig.APIGRPCPort)
 
 	// Start HTTP Server
-	a.startHTTPServer(a.runtimeConfig.HTTPPort, a.runtimeConfig.ProfilePort, a.runtimeConfig.AllowedOrigins, pipeline)
+	err = a.startHTTPServer(a.runtimeConfig.HTTPPort, a.runtimeConfig.ProfilePort, a.runtimeConfig.AllowedOrigins, pipeline, outboundPipeline)
+	if err != nil {
+		log.Fatalf("failed to start HTTP server: %s", err)
+	}
 	log.Infof("http server is running on port %v", a.runtimeConfig.HTTPPort)
 	log.Infof("The request body size parameter is: %v", a.runtimeConfig.MaxRequestBodySize)
 
@@ -341,6 +451,7 @@ func (a *DaprRuntime) initRuntime(opts *runtimeOpts) error {
 	}
 	a.daprHTTPAPI.SetAppChannel(a.appChannel)
 	grpcAPI.SetAppChannel(a.appChannel)
+	a.startAppHealthCheck()
 
 	a.loadAppConfiguration()
 
@@ -373,28 +484,88 @@ func (a *DaprRuntime) populateSecretsConfiguration() {
 }
 
 func (a *DaprRuntime) buildHTTPPipeline() (http_middleware.Pipeline, error) {
+	if a.globalConfig == nil {
+		return http_middleware.Pipeline{}, nil
+	}
+	return a.buildHTTPPipelineFromSpec(a.globalConfig.Spec.HTTPPipelineSpec.Handlers)
+}
+
+// newDistributedRateLimitMiddleware creates the rate limit middleware keeping its token buckets in a state store of the runtime.
+func (a *DaprRuntime) newDistributedRateLimitMiddleware(metadata middleware.Metadata) http_middleware.Middleware {
+	store := a.stateStores[metadata.Properties[http_middleware.RateLimitStateStoreKey]]
+	handler, err := http_middleware.NewDistributedRateLimitMiddleware(a.runtimeConfig.ID, store, metadata.Properties)
+	if err != nil {
+		// no middleware fails the construction of the pipeline
+		log.Errorf("failed to create distributed rate limit middleware: %s", err)
+		return nil
+	}
+	return handler
+}
+
+// buildOutboundHTTPPipeline builds the pipeline applied to the service invocation requests sent to other apps.
+func (a *DaprRuntime) buildOutboundHTTPPipeline() (http_middleware.Pipeline, error) {
+	if a.globalConfig == nil {
+		return http_middleware.Pipeline{}, nil
+	}
+	return a.buildHTTPPipelineFromSpec(a.globalConfig.Spec.HTTPPipelineSpec.Outbound)
+}
+
+func (a *DaprRuntime) buildHTTPPipelineFromSpec(middlewareSpecs []config.HandlerSpec) (http_middleware.Pipeline, error) {
 	var handlers []http_middleware.Middleware
 
+	var decisionSink http_middleware.DecisionSink
+	if sinkName := a.globalConfig.Spec.HTTPPipelineSpec.DecisionLog.Sink; sinkName != "" && len(middlewareSpecs) > 0 {
+		sink, err := http_middleware.NewDecisionSink(sinkName)
+		if err != nil {
+			return http_middleware.Pipeline{}, err
+		}
+		decisionSink = sink
+	}
+
+	for _, middlewareSpec := range middlewareSpecs {
+		component, exists := a.getComponent(middlewareSpec.Type, middlewareSpec.Name)
+		if !exists {
+			return http_middleware.Pipeline{}, errors.Errorf("couldn't find middleware component with name %s and type %s/%s",
+				middlewareSpec.Name,
+				middlewareSpec.Type,
+				middlewareSpec.Version)
+		}
+		handler, err := a.httpMiddlewareRegistry.Create(middlewareSpec.Type, middlewareSpec.Version,
+			middleware.Metadata{Properties: a.convertMetadataItemsToProperties(component.Spec.Metadata)})
+		if err != nil {
+			return http_middleware.Pipeline{}, err
+		}
+		if decisionSink != nil {
+			handler = http_middleware.WithDecisionLog(middlewareSpec.Name, middlewareSpec.Type, handler, decisionSink)
+		}
+		log.Infof("enabled %s/%s http middleware", middlewareSpec.Type, middlewareSpec.Version)
+		handlers = append(handlers, handler)
+	}
+	return http_middleware.Pipeline{Handlers: handlers}, nil
+}
+
+func (a *DaprRuntime) buildGRPCPipeline() (grpc.Pipeline, error) {
+	var interceptors []grpc_go.UnaryServerInterceptor
+
 	if a.globalConfig != nil {
-		for i := 0; i < len(a.globalConfig.Spec.HTTPPipelineSpec.Handlers); i++ {
-			middlewareSpec := a.globalConfig.Spec.HTTPPipelineSpec.Handlers[i]
+		for _, middlewareSpec := range a.globalConfig.Spec.GRPCPipelineSpec.Handlers {
 			component, exists := a.getComponent(middlewareSpec.Type, middlewareSpec.Name)
 			if !exists {
-				return http_middleware.Pipeline{}, errors.Errorf("couldn't find middleware component with name %s and type %s/%s",
+				return grpc.Pipeline{}, errors.Errorf("couldn't find middleware component with name %s and type %s/%s",
 					middlewareSpec.Name,
 					middlewareSpec.Type,
 					middlewareSpec.Version)
 			}
-			handler, err := a.httpMiddlewareRegistry.Create(middlewareSpec.Type, middlewareSpec.Version,
+			interceptor, err := a.grpcMiddlewareRegistry.Create(middlewareSpec.Type, middlewareSpec.Version,
 				middleware.Metadata{Properties: a.convertMetadataItemsToProperties(component.Spec.Metadata)})
 			if err != nil {
-				return http_middleware.Pipeline{}, err
+				return grpc.Pipeline{}, err
 			}
-			log.Infof("enabled %s/%s http middleware", middlewareSpec.Type, middlewareSpec.Version)
-			handlers = append(handlers, handler)
+			log.Infof("enabled %s/%s grpc middleware", middlewareSpec.Type, middlewareSpec.Version)
+			interceptors = append(interceptors, interceptor)
 		}
 	}
-	return http_middleware.Pipeline{Handlers: handlers}, nil
+	return grpc.Pipeline{Interceptors: interceptors}, nil
 }
 
 func (a *DaprRuntime) initBinding(c components_v1alpha1.Component) error {
@@ -431,29 +602,128 @@ func (a *DaprRuntime) beginPubSub(name string, ps pubsub.PubSub) error {
 		return nil
 	}
 	for topic, route := range v.routes {
+		if a.isTopicSubscribed(ps, topic) {
+			continue
+		}
+
 		allowed := a.isPubSubOperationAllowed(name, topic, a.scopedSubscriptions[name])
 		if !allowed {
 			log.Warnf("subscription to topic %s on pubsub %s is not allowed", topic, name)
 			continue
 		}
 
+		handlerOptions, err := runtime_pubsub.ParseHandlerOptions(route.metadata)
+		if err != nil {
+			log.Warnf("failed to subscribe to topic %s: %s", topic, err)
+			continue
+		}
+
 		log.Debugf("subscribing to topic=%s on pubsub=%s", topic, name)
 
 		if err := ps.Subscribe(pubsub.SubscribeRequest{
 			Topic:    topic,
 			Metadata: route.metadata,
-		}, func(ctx context.Context, msg *pubsub.NewMessage) error {
+		}, handlerOptions.Wrap(func(ctx context.Context, msg *pubsub.NewMessage) error {
 			if msg.Metadata == nil {
 				msg.Metadata = make(map[string]string, 1)
 			}
 
 			msg.Metadata[pubsubName] = name
+
+			a.inflightPubSubMessages.Inc()
+			defer a.inflightPubSubMessages.Dec()
+			defer a.componentCalls.start(ps)()
+			if a.shuttingDown.Load() {
+				// the message is redelivered after the restart.
+				return errors.New("runtime is shutting down")
+			}
+			if a.GetPubSub(name) != ps {
+				// the message is redelivered to the instance that replaced this one.
+				return errors.Errorf("pubsub %s was replaced", name)
+			}
+
+			if _, ok := a.getTopicRoute(name, msg.Topic); !ok {
+				return a.handleUnsubscribedTopicMessage(name, msg.Topic)
+			}
 			return publishFunc(ctx, msg)
-		}); err != nil {
+		})); err != nil {
 			log.Warnf("failed to subscribe to topic %s: %s", topic, err)
+			continue
 		}
+		a.setTopicSubscribed(ps, topic)
+	}
+
+	return nil
+}
+
+func (a *DaprRuntime) isTopicSubscribed(ps pubsub.PubSub, topic string) bool {
+	a.topicRoutesLock.RLock()
+	defer a.topicRoutesLock.RUnlock()
+	return a.subscribedTopics[ps][topic]
+}
+
+func (a *DaprRuntime) setTopicSubscribed(ps pubsub.PubSub, topic string) {
+	a.topicRoutesLock.Lock()
+	defer a.topicRoutesLock.Unlock()
+	if _, ok := a.subscribedTopics[ps]; !ok {
+		a.subscribedTopics[ps] = map[string]bool{}
+	}
+	a.subscribedTopics[ps][topic] = true
+}
+
+// getTopicRoute returns the route of the app subscription to the topic.
+func (a *DaprRuntime) getTopicRoute(pubsubName, topic string) (Route, bool) {
+	a.topicRoutesLock.RLock()
+	defer a.topicRoutesLock.RUnlock()
+	route, ok := a.topicRoutes[pubsubName].routes[topic]
+	return route, ok
+}
+
+// handleUnsubscribedTopicMessage handles a message of a topic the app unsubscribed from since the subscription
+// was created. While the subscriptions of the app are unknown, the message is rejected for redelivery.
+// Once they were read without the topic, the message is dropped, as pubsub components can't unsubscribe.
+func (a *DaprRuntime) handleUnsubscribedTopicMessage(pubsubName, topic string) error {
+	if a.topicRoutesStale.Load() {
+		return errors.Errorf("subscriptions of the app to pubsub %s are being refreshed, rejecting message of topic %s", pubsubName, topic)
 	}
+	log.Warnf("dropping message of topic %s on pubsub %s, the app is no longer subscribed to it", topic, pubsubName)
+	return nil
+}
+
+// RefreshSubscriptions reloads the subscriptions of the app and subscribes to the topics added since
+// they were last loaded. Pubsub components can't unsubscribe, so the messages of the removed topics are
+// dropped. Changes to the metadata of existing subscriptions take effect after a restart.
+// When the subscriptions of the app can't be read, the current subscriptions are kept.
+func (a *DaprRuntime) RefreshSubscriptions() error {
+	if a.appChannel == nil {
+		return errors.New("app channel not initialized")
+	}
+	if len(a.pubSubs) == 0 {
+		return errors.New("no pubsub is configured")
+	}
+
+	a.subscriptionsRefreshLock.Lock()
+	defer a.subscriptionsRefreshLock.Unlock()
 
+	// the messages of the topics missing from the current routes are rejected until the refresh
+	// succeeds, as the app may have subscribed to them again.
+	a.topicRoutesStale.Store(true)
+	// the routes are replaced at once, so that the messages of the topics the app
+	// is still subscribed to are delivered during the refresh.
+	topicRoutes, err := a.loadTopicRoutes()
+	if err != nil {
+		return err
+	}
+	a.topicRoutesLock.Lock()
+	a.topicRoutes = topicRoutes
+	a.topicRoutesLock.Unlock()
+	a.topicRoutesStale.Store(false)
+
+	for name, ps := range a.pubSubs {
+		if err := a.beginPubSub(name, ps); err != nil {
+			return errors.Wrapf(err, "error subscribing to pubsub %s", name)
+		}
+	}
 	return nil
 }
 
@@ -467,7 +737,8 @@ func (a *DaprRuntime) initDirectMessaging(resolver nr.Resolver) {
 		a.grpc.GetGRPCConnection,
 		resolver,
 		a.globalConfig.Spec.TracingSpec,
-		a.runtimeConfig.MaxRequestBodySize)
+		a.runtimeConfig.MaxRequestBodySize,
+		a.globalConfig.Spec.ServiceInvocation.ResponseHeaders)
 }
 
 func (a *DaprRuntime) beginComponentsUpdates() error {
@@ -510,12 +781,51 @@ func (a *DaprRuntime) beginComponentsUpdates() error {
 
 func (a *DaprRuntime) onComponentUpdated(component components_v1alpha1.Component) {
 	oldComp, exists := a.getComponent(component.Spec.Type, component.Name)
-	if exists && reflect.DeepEqual(oldComp.Spec.Metadata, component.Spec.Metadata) {
+	if exists && componentSpecHash(oldComp) == a.effectiveComponentSpecHash(component) {
+		log.Debugf("component %s is unchanged, skipping re-init", component.Name)
 		return
 	}
 	a.pendingComponents <- component
 }
 
+// effectiveComponentSpecHash returns the spec hash of the component once its secrets are resolved,
+// or an empty string when they can't be resolved yet.
+func (a *DaprRuntime) effectiveComponentSpecHash(component components_v1alpha1.Component) string {
+	resolved, unreadySecretsStore := a.processComponentSecrets(*component.DeepCopy())
+	if unreadySecretsStore != "" {
+		return ""
+	}
+	return componentSpecHash(resolved)
+}
+
+func componentSpecHash(component components_v1alpha1.Component) string {
+	b, err := json.Marshal(component.Spec)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// drainComponent closes an instance replaced by a hot reload once the calls it was still serving
+// complete, or at the timeout. New calls are served by the replacing instance in the meantime,
+// and a replaced pub/sub instance no longer delivers messages to the app.
+func (a *DaprRuntime) drainComponent(name string, instance interface{}, timeout time.Duration) {
+	closer, ok := instance.(io.Closer)
+	if !ok {
+		return
+	}
+
+	go func() {
+		if !a.componentCalls.wait(callsKey(instance), timeout) {
+			log.Warnf("closing replaced instance of component %s with calls still in flight after %s", name, timeout)
+		}
+		if err := closer.Close(); err != nil {
+			log.Warnf("error closing replaced instance of component %s: %s", name, err)
+		}
+	}()
+}
+
 func (a *DaprRuntime) sendBatchOutputBindingsParallel(to []string, data []byte) {
 	for _, dst := range to {
 		go func(name string) {
@@ -552,6 +862,7 @@ func (a *DaprRuntime) sendToOutputBinding(name string, req *bindings.InvokeReque
 		ops := binding.Operations()
 		for _, o := range ops {
 			if o == req.Operation {
+				defer a.componentCalls.start(binding)()
 				return binding.Invoke(req)
 			}
 		}
@@ -701,6 +1012,16 @@ func (a *DaprRuntime) sendBindingEventToApp(bindingName string, data []byte, met
 func (a *DaprRuntime) readFromBinding(name string, binding bindings.InputBinding) error {
 	err := binding.Read(func(resp *bindings.ReadResponse) ([]byte, error) {
 		if resp != nil {
+			// the event is in flight while it is held by a pause, so that the shutdown waits for it
+			a.inflightBindingEvents.Inc()
+			defer a.inflightBindingEvents.Dec()
+			if err := a.waitInputBindingResumed(name, binding); err != nil {
+				return nil, err
+			}
+			if a.shuttingDown.Load() {
+				return nil, errors.New("runtime is shutting down")
+			}
+
 			b, err := a.sendBindingEventToApp(name, resp.Data, resp.Metadata)
 			if err != nil {
 				log.Debugf("error from app consumer for binding [%s]: %s", name, err)
@@ -713,27 +1034,112 @@ func (a *DaprRuntime) readFromBinding(name string, binding bindings.InputBinding
 	return err
 }
 
-func (a *DaprRuntime) startHTTPServer(port, profilePort int, allowedOrigins string, pipeline http_middleware.Pipeline) {
-	a.daprHTTPAPI = http.NewAPI(a.runtimeConfig.ID, a.appChannel, a.directMessaging, a.getComponents, a.stateStores, a.secretStores,
-		a.secretsConfiguration, a.getPublishAdapter(), a.actor, a.sendToOutputBinding, a.globalConfig.Spec.TracingSpec, a.ShutdownWithWait)
+// waitInputBindingResumed blocks while the input binding is paused. Holding the event back
+// stops the binding from reading more events until the binding is resumed. It returns an error,
+// so that the event is redelivered, when the runtime shuts down or the binding is re-initialized
+// while the event is held.
+func (a *DaprRuntime) waitInputBindingResumed(name string, binding bindings.InputBinding) error {
+	a.inputBindingsLock.RLock()
+	resumed, paused := a.pausedInputBindings[name]
+	a.inputBindingsLock.RUnlock()
+
+	if !paused {
+		return nil
+	}
+	select {
+	case <-resumed:
+	case <-a.shutdownC:
+		return errors.New("runtime is shutting down")
+	}
+
+	a.inputBindingsLock.RLock()
+	current := a.inputBindings[name]
+	a.inputBindingsLock.RUnlock()
+	if current != binding {
+		return errors.Errorf("input binding %s was re-initialized while paused", name)
+	}
+	return nil
+}
+
+// setInputBindingPaused pauses or resumes the delivery of the events of an input binding to the app.
+func (a *DaprRuntime) setInputBindingPaused(name string, paused bool) error {
+	a.inputBindingsLock.Lock()
+	defer a.inputBindingsLock.Unlock()
+
+	if _, ok := a.inputBindings[name]; !ok {
+		return errors.Errorf("input binding %s not found", name)
+	}
+
+	resumed, isPaused := a.pausedInputBindings[name]
+	if paused && !isPaused {
+		a.pausedInputBindings[name] = make(chan struct{})
+		log.Infof("paused input binding %s", name)
+	} else if !paused && isPaused {
+		close(resumed)
+		delete(a.pausedInputBindings, name)
+		log.Infof("resumed input binding %s", name)
+	}
+	return nil
+}
+
+func (a *DaprRuntime) startHTTPServer(port, profilePort int, allowedOrigins string, pipeline, outboundPipeline http_middleware.Pipeline) error {
+	a.daprHTTPAPI = http.NewAPI(a.runtimeConfig.ID, a.appChannel, a.directMessaging, a.getComponents, a.componentStatus.List, a.stateStores, a.secretStores,
+		a.secretsConfiguration, a.getPublishAdapter(), a.actor, a.sendToOutputBinding, a.setInputBindingPaused, a.RefreshSubscriptions, outboundPipeline, a.globalConfig.Spec.TracingSpec, a.ShutdownWithWait)
 	serverConf := http.NewServerConfig(a.runtimeConfig.ID, a.hostAddress, port, profilePort, allowedOrigins, a.runtimeConfig.EnableProfiling, a.runtimeConfig.MaxRequestBodySize)
 
-	server := http.NewServer(a.daprHTTPAPI, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, pipeline)
+	idempotencyWindow, err := a.globalConfig.Spec.Idempotency.GetWindow()
+	if err != nil {
+		return err
+	}
+	var idempotencyStore http.IdempotencyStore
+	if idempotencyWindow > 0 {
+		idempotencySpec := a.globalConfig.Spec.Idempotency
+		if idempotencySpec.Store != "" {
+			store, ok := a.stateStores[idempotencySpec.Store]
+			if !ok {
+				return errors.Errorf("state store %s of the idempotency keys not found", idempotencySpec.Store)
+			}
+			idempotencyStore, err = http.NewStateIdempotencyStore(store, a.runtimeConfig.ID)
+			if err != nil {
+				return err
+			}
+		} else {
+			idempotencyStore = http.NewMemoryIdempotencyStore(idempotencySpec.MaxKeys)
+		}
+	}
+
+	server, err := http.NewServer(a.daprHTTPAPI, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, a.globalConfig.Spec.MaxBodySize,
+		idempotencyStore, idempotencyWindow, pipeline)
+	if err != nil {
+		return err
+	}
 	server.StartNonBlocking()
+	a.apiServers = append(a.apiServers, server)
+	a.daprHTTPAPI.SetProfilingToggler(server.SetProfilingEnabled)
+	return nil
 }
 
 func (a *DaprRuntime) startGRPCInternalServer(api grpc.API, port int) error {
 	serverConf := a.getNewServerConfig(port)
 	server := grpc.NewInternalServer(api, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, a.authenticator)
 	err := server.StartNonBlocking()
+	if err == nil && a.authenticator != nil {
+		a.daprHTTPAPI.SetWorkloadCertRotator(server.RotateWorkloadCert)
+	}
 	return err
 }
 
-func (a *DaprRuntime) startGRPCAPIServer(api grpc.API, port int) error {
+func (a *DaprRuntime) startGRPCAPIServer(api grpc.API, port int, pipeline grpc.Pipeline) error {
 	serverConf := a.getNewServerConfig(port)
-	server := grpc.NewAPIServer(api, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec)
-	err := server.StartNonBlocking()
-	return err
+	server, err := grpc.NewAPIServer(api, serverConf, a.globalConfig.Spec.TracingSpec, a.globalConfig.Spec.MetricSpec, pipeline)
+	if err != nil {
+		return err
+	}
+	if err := server.StartNonBlocking(); err != nil {
+		return err
+	}
+	a.apiServers = append(a.apiServers, server)
+	return nil
 }
 
 func (a *DaprRuntime) getNewServerConfig(port int) grpc.ServerConfig {
@@ -746,10 +1152,10 @@ func (a *DaprRuntime) getNewServerConfig(port int) grpc.ServerConfig {
 	return grpc.NewServerConfig(a.runtimeConfig.ID, a.hostAddress, port, a.namespace, trustDomain, a.runtimeConfig.MaxRequestBodySize)
 }
 
-func (a *DaprRuntime) getGRPCAPI() grpc.API {
+func (a *DaprRuntime) getGRPCAPI(outboundPipeline http_middleware.Pipeline) grpc.API {
 	return grpc.NewAPI(a.runtimeConfig.ID, a.appChannel, a.stateStores, a.secretStores, a.secretsConfiguration,
 		a.getPublishAdapter(), a.directMessaging, a.actor,
-		a.sendToOutputBinding, a.globalConfig.Spec.TracingSpec, a.accessControlList, string(a.runtimeConfig.ApplicationProtocol), a.getComponents, a.ShutdownWithWait)
+		a.sendToOutputBinding, a.globalConfig.Spec.TracingSpec, a.accessControlList, a.globalConfig.Spec.ServiceInvocation.ResponseHeaders, string(a.runtimeConfig.ApplicationProtocol), a.getComponents, outboundPipeline, a.ShutdownWithWait)
 }
 
 func (a *DaprRuntime) getPublishAdapter() runtime_pubsub.Adapter {
@@ -814,7 +1220,15 @@ func (a *DaprRuntime) initInputBinding(c components_v1alpha1.Component) error {
 	}
 
 	log.Infof("successful init for input binding %s (%s/%s)", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version)
+	a.inputBindingsLock.Lock()
 	a.inputBindings[c.Name] = binding
+	// a re-initialized binding starts unpaused, the events held by the replaced instance are released with an error
+	if resumed, paused := a.pausedInputBindings[c.Name]; paused {
+		close(resumed)
+		delete(a.pausedInputBindings, c.Name)
+		log.Infof("input binding %s re-initialized, it is no longer paused", c.Name)
+	}
+	a.inputBindingsLock.Unlock()
 	diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
 	return nil
 }
@@ -838,7 +1252,9 @@ func (a *DaprRuntime) initOutputBinding(c components_v1alpha1.Component) error {
 			return err
 		}
 		log.Infof("successful init for output binding %s (%s/%s)", c.ObjectMeta.Name, c.Spec.Type, c.Spec.Version)
+		a.componentsInitLock.Lock()
 		a.outputBindings[c.ObjectMeta.Name] = binding
+		a.componentsInitLock.Unlock()
 		diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
 	}
 	return nil
@@ -863,7 +1279,9 @@ func (a *DaprRuntime) initState(s components_v1alpha1.Component) error {
 			return err
 		}
 
-		a.stateStores[s.ObjectMeta.Name] = store
+		a.componentsInitLock.Lock()
+		defer a.componentsInitLock.Unlock()
+		a.stateStores[s.ObjectMeta.Name] = a.componentCalls.trackStateStore(store)
 		err = state_loader.SaveStateConfiguration(s.ObjectMeta.Name, props)
 		if err != nil {
 			diag.DefaultMonitoring.ComponentInitFailed(s.Spec.Type, "init")
@@ -921,10 +1339,30 @@ func (a *DaprRuntime) getDeclarativeSubscriptions() []runtime_pubsub.Subscriptio
 }
 
 func (a *DaprRuntime) getTopicRoutes() (map[string]TopicRoute, error) {
-	if a.topicRoutes != nil {
-		return a.topicRoutes, nil
+	a.topicRoutesLock.RLock()
+	topicRoutes := a.topicRoutes
+	a.topicRoutesLock.RUnlock()
+	if topicRoutes != nil {
+		return topicRoutes, nil
 	}
 
+	topicRoutes, err := a.loadTopicRoutes()
+	if err != nil {
+		// the declarative subscriptions are still loaded. The subscriptions of the app are
+		// added when they are refreshed.
+		log.Error(err)
+	}
+	a.topicRoutesStale.Store(err != nil)
+	a.topicRoutesLock.Lock()
+	a.topicRoutes = topicRoutes
+	a.topicRoutesLock.Unlock()
+	return topicRoutes, nil
+}
+
+// loadTopicRoutes reads the subscriptions of the app and the declarative subscriptions.
+// When the subscriptions of the app can't be read, it returns the declarative subscriptions with the error.
+func (a *DaprRuntime) loadTopicRoutes() (map[string]TopicRoute, error) {
+
 	var topicRoutes map[string]TopicRoute = make(map[string]TopicRoute)
 
 	if a.appChannel == nil {
@@ -932,13 +1370,14 @@ func (a *DaprRuntime) getTopicRoutes() (map[string]TopicRoute, error) {
 	}
 
 	var subscriptions []runtime_pubsub.Subscription
+	var err error
 
 	// handle app subscriptions
 	if a.runtimeConfig.ApplicationProtocol == HTTPProtocol {
-		subscriptions = runtime_pubsub.GetSubscriptionsHTTP(a.appChannel, log)
+		subscriptions, err = runtime_pubsub.GetSubscriptionsHTTP(a.appChannel, log)
 	} else if a.runtimeConfig.ApplicationProtocol == GRPCProtocol {
 		client := runtimev1pb.NewAppCallbackClient(a.grpc.AppClient)
-		subscriptions = runtime_pubsub.GetSubscriptionsGRPC(client, log)
+		subscriptions, err = runtime_pubsub.GetSubscriptionsGRPC(client, log)
 	}
 
 	// handle declarative subscriptions
@@ -966,7 +1405,12 @@ func (a *DaprRuntime) getTopicRoutes() (map[string]TopicRoute, error) {
 			topicRoutes[s.PubsubName] = TopicRoute{routes: make(map[string]Route)}
 		}
 
-		topicRoutes[s.PubsubName].routes[s.Topic] = Route{path: s.Route, metadata: s.Metadata}
+		ttl, err := runtime_pubsub.ParseTTL(s.Metadata)
+		if err != nil {
+			log.Warnf("ignoring the TTL of the subscription to topic %s on pubsub %s: %s", s.Topic, s.PubsubName, err)
+		}
+
+		topicRoutes[s.PubsubName].routes[s.Topic] = Route{path: s.Route, metadata: s.Metadata, ttl: ttl}
 	}
 
 	if len(topicRoutes) > 0 {
@@ -978,8 +1422,7 @@ func (a *DaprRuntime) getTopicRoutes() (map[string]TopicRoute, error) {
 			log.Infof("app is subscribed to the following topics: %v through pubsub=%s", topics, pubsubName)
 		}
 	}
-	a.topicRoutes = topicRoutes
-	return topicRoutes, nil
+	return topicRoutes, err
 }
 
 func (a *DaprRuntime) initPubSub(c components_v1alpha1.Component) error {
@@ -1008,10 +1451,12 @@ func (a *DaprRuntime) initPubSub(c components_v1alpha1.Component) error {
 
 	pubsubName := c.ObjectMeta.Name
 
+	a.componentsInitLock.Lock()
 	a.scopedSubscriptions[pubsubName] = scopes.GetScopedTopics(scopes.SubscriptionScopes, a.runtimeConfig.ID, properties)
 	a.scopedPublishings[pubsubName] = scopes.GetScopedTopics(scopes.PublishingScopes, a.runtimeConfig.ID, properties)
 	a.allowedTopics[pubsubName] = scopes.GetAllowedTopics(properties)
 	a.pubSubs[pubsubName] = pubSub
+	a.componentsInitLock.Unlock()
 	diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
 
 	return nil
@@ -1030,7 +1475,8 @@ func (a *DaprRuntime) Publish(req *pubsub.PublishRequest) error {
 		return runtime_pubsub.NotAllowedError{Topic: req.Topic, ID: a.runtimeConfig.ID}
 	}
 
-	return a.pubSubs[req.PubsubName].Publish(req)
+	defer a.componentCalls.start(thepubsub)()
+	return thepubsub.Publish(req)
 }
 
 // GetPubSub is an adapter method to find a pubsub by name
@@ -1121,6 +1567,22 @@ func (a *DaprRuntime) initNameResolution() error {
 	return nil
 }
 
+// isEventExpired returns true when the event expired, either at the expiration set by the publisher
+// or after the TTL of the subscription.
+func (a *DaprRuntime) isEventExpired(msg *pubsub.NewMessage, cloudEvent map[string]interface{}) bool {
+	route, _ := a.getTopicRoute(msg.Metadata[pubsubName], msg.Topic)
+	if pubsub.HasExpired(cloudEvent) {
+		log.Warnf("dropping expired pub/sub event %v as of %v", cloudEvent[pubsub.IDField].(string), cloudEvent[pubsub.ExpirationField].(string))
+	} else if runtime_pubsub.HasExceededTTL(cloudEvent, route.ttl) {
+		log.Warnf("dropping pub/sub event %v published at %v, older than the subscription TTL of %v", cloudEvent[pubsub.IDField].(string), cloudEvent[runtime_pubsub.TimeField], route.ttl)
+	} else {
+		return false
+	}
+
+	diag.DefaultMonitoring.PubsubEventExpired(msg.Metadata[pubsubName], msg.Topic)
+	return true
+}
+
 func (a *DaprRuntime) publishMessageHTTP(ctx context.Context, msg *pubsub.NewMessage) error {
 	var cloudEvent map[string]interface{}
 	err := a.json.Unmarshal(msg.Data, &cloudEvent)
@@ -1129,17 +1591,19 @@ func (a *DaprRuntime) publishMessageHTTP(ctx context.Context, msg *pubsub.NewMes
 		return err
 	}
 
-	if pubsub.HasExpired(cloudEvent) {
-		log.Warnf("dropping expired pub/sub event %v as of %v", cloudEvent[pubsub.IDField].(string), cloudEvent[pubsub.ExpirationField].(string))
+	if a.isEventExpired(msg, cloudEvent) {
 		return nil
 	}
 
 	var span *trace.Span
 
-	route := a.topicRoutes[msg.Metadata[pubsubName]].routes[msg.Topic]
+	route, _ := a.getTopicRoute(msg.Metadata[pubsubName], msg.Topic)
 	req := invokev1.NewInvokeMethodRequest(route.path)
 	req.WithHTTPExtension(nethttp.MethodPost, "")
 	req.WithRawData(msg.Data, contenttype.CloudEventContentType)
+	if baggage, ok := cloudEvent[runtime_pubsub.BaggageField].(string); ok && baggage != "" {
+		req.WithMetadata(map[string][]string{diag.BaggageHeader: {baggage}})
+	}
 
 	if cloudEvent[pubsub.TraceIDField] != nil {
 		traceID := cloudEvent[pubsub.TraceIDField].(string)
@@ -1211,8 +1675,7 @@ func (a *DaprRuntime) publishMessageGRPC(ctx context.Context, msg *pubsub.NewMes
 		return err
 	}
 
-	if pubsub.HasExpired(cloudEvent) {
-		log.Warnf("dropping expired pub/sub event %v as of %v", cloudEvent[pubsub.IDField].(string), cloudEvent[pubsub.ExpirationField].(string))
+	if a.isEventExpired(msg, cloudEvent) {
 		return nil
 	}
 
@@ -1254,6 +1717,9 @@ func (a *DaprRuntime) publishMessageGRPC(ctx context.Context, msg *pubsub.NewMes
 		ctx, span = diag.StartInternalCallbackSpan(ctx, spanName, sc, a.globalConfig.Spec.TracingSpec)
 		ctx = diag.SpanContextToGRPCMetadata(ctx, span.SpanContext())
 	}
+	if baggage, ok := cloudEvent[runtime_pubsub.BaggageField].(string); ok && baggage != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, diag.BaggageHeader, baggage)
+	}
 
 	// call appcallback
 	clientV1 := runtimev1pb.NewAppCallbackClient(a.grpc.AppClient)
@@ -1302,6 +1768,21 @@ func (a *DaprRuntime) initActors() error {
 	}
 	actorConfig := actors.NewConfig(a.hostAddress, a.runtimeConfig.ID, a.runtimeConfig.PlacementAddresses, a.appConfig.Entities,
 		a.runtimeConfig.InternalGRPCPort, a.appConfig.ActorScanInterval, a.appConfig.ActorIdleTimeout, a.appConfig.DrainOngoingCallTimeout, a.appConfig.DrainRebalancedActors, a.namespace)
+	actorConfig.StateStoreName = a.actorStateStoreName
+	actorConfig.AppHealthProbe = a.runtimeConfig.AppHealthProbe
+	// Only the hosts of actors leave placement while the app is unhealthy.
+	if a.appChannel != nil && a.hostingActors() {
+		actorConfig.AppHealthFn = a.appHealthy.Load
+	}
+	actorConfig.SetEntitiesConfig(a.appConfig.EntitiesConfig)
+	actorConfig.DeactivationWarningWindows, err = a.globalConfig.Spec.Actors.GetDeactivationWarningWindows()
+	if err != nil {
+		return err
+	}
+	actorConfig.PlacementHints, err = a.globalConfig.Spec.Actors.GetPlacementHints()
+	if err != nil {
+		return err
+	}
 	act := actors.NewActors(a.stateStores[a.actorStateStoreName], a.appChannel, a.grpc.GetGRPCConnection, actorConfig, a.runtimeConfig.CertChain, a.globalConfig.Spec.TracingSpec)
 	err = act.Init()
 	a.actor = act
@@ -1324,7 +1805,9 @@ func (a *DaprRuntime) getAuthorizedComponents(components []components_v1alpha1.C
 }
 
 func (a *DaprRuntime) isComponentAuthorized(component components_v1alpha1.Component) bool {
-	if a.namespace == "" || (a.namespace != "" && component.ObjectMeta.Namespace == a.namespace) {
+	// in self-hosted mode, the components without a namespace are shared by the apps of all namespaces
+	sharedComponent := a.runtimeConfig.Mode == modes.StandaloneMode && component.ObjectMeta.Namespace == ""
+	if a.namespace == "" || sharedComponent || component.ObjectMeta.Namespace == a.namespace {
 		if len(component.Scopes) == 0 {
 			return true
 		}
@@ -1367,13 +1850,32 @@ func (a *DaprRuntime) loadComponents(opts *runtimeOpts) error {
 	copy(a.components, authorizedComps)
 	a.componentsLock.Unlock()
 
-	for _, comp := range authorizedComps {
-		a.pendingComponents <- comp
+	for _, group := range componentInitGroups(authorizedComps) {
+		a.pendingComponentGroups <- group
 	}
 
 	return nil
 }
 
+// componentInitGroups splits components into groups that are processed one after the other, the components
+// of a group being initialized concurrently. Secret stores come first and one at a time since they can reference
+// each other, then all the other components, which can only depend on secret stores, are initialized together.
+func componentInitGroups(comps []components_v1alpha1.Component) [][]components_v1alpha1.Component {
+	var groups [][]components_v1alpha1.Component
+	var others []components_v1alpha1.Component
+	for _, comp := range comps {
+		if strings.HasPrefix(comp.Spec.Type, fmt.Sprintf("%s.", secretStoreComponent)) {
+			groups = append(groups, []components_v1alpha1.Component{comp})
+		} else {
+			others = append(others, comp)
+		}
+	}
+	if len(others) > 0 {
+		groups = append(groups, others)
+	}
+	return groups
+}
+
 func (a *DaprRuntime) appendOrReplaceComponents(component components_v1alpha1.Component) {
 	a.componentsLock.Lock()
 	defer a.componentsLock.Unlock()
@@ -1402,25 +1904,47 @@ func (a *DaprRuntime) extractComponentCategory(component components_v1alpha1.Com
 }
 
 func (a *DaprRuntime) processComponents() {
-	for comp := range a.pendingComponents {
-		if comp.Name == "" {
-			continue
+	for {
+		select {
+		case comp := <-a.pendingComponents:
+			if comp.Name == "" {
+				continue
+			}
+			a.processComponent(comp)
+		case group := <-a.pendingComponentGroups:
+			a.processComponentGroup(group)
 		}
+	}
+}
 
-		err := a.processComponentAndDependents(comp)
-		if err != nil {
-			e := fmt.Sprintf("process component %s error: %s", comp.Name, err.Error())
-			if !comp.Spec.IgnoreErrors {
-				log.Fatalf(e)
-			}
-			log.Errorf(e)
+// processComponentGroup initializes the components of the group concurrently and waits for all of them,
+// so that loading many components takes about as long as the slowest one instead of the sum of all.
+func (a *DaprRuntime) processComponentGroup(comps []components_v1alpha1.Component) {
+	var wg sync.WaitGroup
+	wg.Add(len(comps))
+	for _, comp := range comps {
+		go func(comp components_v1alpha1.Component) {
+			defer wg.Done()
+			a.processComponent(comp)
+		}(comp)
+	}
+	wg.Wait()
+}
+
+func (a *DaprRuntime) processComponent(comp components_v1alpha1.Component) {
+	err := a.processComponentAndDependents(comp)
+	if err != nil {
+		e := fmt.Sprintf("process component %s error: %s", comp.Name, err.Error())
+		if !comp.Spec.IgnoreErrors {
+			log.Fatalf(e)
 		}
+		log.Errorf(e)
 	}
 }
 
 func (a *DaprRuntime) flushOutstandingComponents() {
 	log.Info("waiting for all outstanding components to be processed")
-	// We flush by sending a no-op component. Since the processComponents goroutine only reads one component at a time,
+	// We flush by sending a no-op component. Since the processComponents goroutine only reads one component or group at a time,
 	// We know that once the no-op component is read from the channel, all previous components will have been fully processed.
 	a.pendingComponents <- components_v1alpha1.Component{}
 	log.Info("all outstanding components processed")
@@ -1430,7 +1954,9 @@ func (a *DaprRuntime) processComponentAndDependents(comp components_v1alpha1.Com
 	log.Debugf("loading component. name: %s, type: %s/%s", comp.ObjectMeta.Name, comp.Spec.Type, comp.Spec.Version)
 	res := a.preprocessOneComponent(&comp)
 	if res.unreadyDependency != "" {
+		a.componentsInitLock.Lock()
 		a.pendingComponentDependents[res.unreadyDependency] = append(a.pendingComponentDependents[res.unreadyDependency], comp)
+		a.componentsInitLock.Unlock()
 		return nil
 	}
 
@@ -1447,26 +1973,61 @@ func (a *DaprRuntime) processComponentAndDependents(comp components_v1alpha1.Com
 		timeout = defaultComponentInitTimeout
 	}
 
+	replaced := a.componentInstances(compCategory, comp.Name)
+
+	status := components.Status{
+		Name:       comp.Name,
+		Type:       comp.Spec.Type,
+		Version:    comp.Spec.Version,
+		InitStatus: components.InitStatusInitializing,
+	}
+	a.componentStatus.Set(status)
+	start := time.Now()
+
 	go func() {
 		ch <- a.doProcessOneComponent(compCategory, comp)
 	}()
 
 	select {
-	case err := <-ch:
-		if err != nil {
-			return err
-		}
+	case err = <-ch:
 	case <-time.After(timeout):
-		return fmt.Errorf("init timeout for component %s exceeded after %s", comp.Name, timeout.String())
+		err = fmt.Errorf("init timeout for component %s exceeded after %s", comp.Name, timeout.String())
 	}
 
+	status.InitDuration = time.Since(start)
+	if err != nil {
+		status.InitStatus = components.InitStatusFailed
+		status.Error = err.Error()
+		a.componentStatus.Set(status)
+		return err
+	}
+	status.InitStatus = components.InitStatusSucceeded
+	a.componentStatus.Set(status)
+
 	log.Infof("component loaded. name: %s, type: %s/%s", comp.ObjectMeta.Name, comp.Spec.Type, comp.Spec.Version)
 	a.appendOrReplaceComponents(comp)
+	current := a.componentInstances(compCategory, comp.Name)
+	for _, instance := range replaced {
+		if !containsInstance(current, instance) {
+			a.drainComponent(comp.Name, instance, a.runtimeConfig.ComponentDrainTimeout)
+		}
+	}
+	if compCategory == pubsubComponent && len(replaced) > 0 {
+		// the replacing instance takes over the subscriptions of the replaced one
+		if ps := a.GetPubSub(comp.Name); ps != nil {
+			if err := a.beginPubSub(comp.Name, ps); err != nil {
+				log.Warnf("failed to subscribe to pubsub %s after the reload: %s", comp.Name, err)
+			}
+		}
+	}
 	diag.DefaultMonitoring.ComponentLoaded()
 
 	dependency := componentDependency(compCategory, comp.Name)
-	if deps, ok := a.pendingComponentDependents[dependency]; ok {
-		delete(a.pendingComponentDependents, dependency)
+	a.componentsInitLock.Lock()
+	deps, ok := a.pendingComponentDependents[dependency]
+	delete(a.pendingComponentDependents, dependency)
+	a.componentsInitLock.Unlock()
+	if ok {
 		for _, dependent := range deps {
 			if err := a.processComponentAndDependents(dependent); err != nil {
 				return err
@@ -1477,6 +2038,51 @@ func (a *DaprRuntime) processComponentAndDependents(comp components_v1alpha1.Com
 	return nil
 }
 
+// componentInstances returns the initialized instances of the component, if any.
+func (a *DaprRuntime) componentInstances(category ComponentCategory, name string) []interface{} {
+	var instances []interface{}
+
+	if category == bindingsComponent {
+		a.inputBindingsLock.RLock()
+		if binding, ok := a.inputBindings[name]; ok {
+			instances = append(instances, binding)
+		}
+		a.inputBindingsLock.RUnlock()
+	}
+
+	a.componentsInitLock.Lock()
+	defer a.componentsInitLock.Unlock()
+
+	switch category {
+	case bindingsComponent:
+		if binding, ok := a.outputBindings[name]; ok {
+			instances = append(instances, binding)
+		}
+	case pubsubComponent:
+		if ps, ok := a.pubSubs[name]; ok {
+			instances = append(instances, ps)
+		}
+	case secretStoreComponent:
+		if store, ok := a.secretStores[name]; ok {
+			instances = append(instances, store)
+		}
+	case stateComponent:
+		if store, ok := a.stateStores[name]; ok {
+			instances = append(instances, store)
+		}
+	}
+	return instances
+}
+
+func containsInstance(instances []interface{}, instance interface{}) bool {
+	for _, i := range instances {
+		if i == instance {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *DaprRuntime) doProcessOneComponent(category ComponentCategory, comp components_v1alpha1.Component) error {
 	switch category {
 	case bindingsComponent:
@@ -1569,16 +2175,6 @@ func (a *DaprRuntime) shutdownComponents() error {
 	return merr
 }
 
-// ShutdownWithWait will gracefully stop runtime and wait outstanding operations
-func (a *DaprRuntime) ShutdownWithWait() {
-	a.stopActor()
-	gracefulShutdownDuration := 5 * time.Second
-	log.Infof("dapr shutting down. Waiting %s to finish outstanding operations", gracefulShutdownDuration)
-	<-time.After(gracefulShutdownDuration)
-	a.shutdownComponents()
-	os.Exit(0)
-}
-
 func (a *DaprRuntime) processComponentSecrets(component components_v1alpha1.Component) (components_v1alpha1.Component, string) {
 	cache := map[string]secretstores.GetSecretResponse{}
 
@@ -1587,48 +2183,71 @@ func (a *DaprRuntime) processComponentSecrets(component components_v1alpha1.Comp
 			continue
 		}
 
-		secretStoreName := a.authSecretStoreOrDefault(component)
-		secretStore := a.getSecretStore(secretStoreName)
-		if secretStore == nil {
-			log.Warnf("component %s references a secret store that isn't loaded: %s", component.Name, secretStoreName)
-			return component, secretStoreName
-		}
-
-		resp, ok := cache[m.SecretKeyRef.Name]
-		if !ok {
-			r, err := secretStore.GetSecret(secretstores.GetSecretRequest{
-				Name: m.SecretKeyRef.Name,
-				Metadata: map[string]string{
-					"namespace": component.ObjectMeta.Namespace,
-				},
-			})
-			if err != nil {
-				log.Errorf("error getting secret: %s", err)
-				continue
-			}
-			resp = r
-		}
-
 		// Use the SecretKeyRef.Name key if SecretKeyRef.Key is not given
 		secretKeyName := m.SecretKeyRef.Key
 		if secretKeyName == "" {
 			secretKeyName = m.SecretKeyRef.Name
 		}
 
-		val, ok := resp.Data[secretKeyName]
-		if ok {
-			component.Spec.Metadata[i].Value = components_v1alpha1.DynamicValue{
-				JSON: v1.JSON{
-					Raw: []byte(val),
-				},
+		// The secret stores are tried in order until one of them has the secret key.
+		for _, secretStoreName := range a.componentSecretStores(component, m.SecretKeyRef) {
+			secretStore := a.getSecretStore(secretStoreName)
+			if secretStore == nil {
+				log.Warnf("component %s references a secret store that isn't loaded: %s", component.Name, secretStoreName)
+				return component, secretStoreName
 			}
-		}
 
-		cache[m.SecretKeyRef.Name] = resp
+			cacheKey := secretStoreName + "/" + m.SecretKeyRef.Name
+			resp, ok := cache[cacheKey]
+			if !ok {
+				r, err := secretStore.GetSecret(secretstores.GetSecretRequest{
+					Name: m.SecretKeyRef.Name,
+					Metadata: map[string]string{
+						"namespace": component.ObjectMeta.Namespace,
+					},
+				})
+				if err != nil {
+					log.Errorf("error getting secret from secret store %s: %s", secretStoreName, err)
+					continue
+				}
+				resp = r
+				cache[cacheKey] = resp
+			}
+
+			val, ok := resp.Data[secretKeyName]
+			if ok {
+				component.Spec.Metadata[i].Value = components_v1alpha1.DynamicValue{
+					JSON: v1.JSON{
+						Raw: []byte(val),
+					},
+				}
+				break
+			}
+		}
 	}
 	return component, ""
 }
 
+// componentSecretStores returns the secret stores to resolve a secret reference from, in order of precedence:
+// the store of the reference, or else the secret store of the component, followed by the fallback secret stores.
+func (a *DaprRuntime) componentSecretStores(component components_v1alpha1.Component, ref components_v1alpha1.SecretKeyRef) []string {
+	secretStoreName := ref.Store
+	if secretStoreName == "" {
+		secretStoreName = a.authSecretStoreOrDefault(component)
+	}
+
+	var stores []string
+	if secretStoreName != "" {
+		stores = append(stores, secretStoreName)
+	}
+	for _, fallback := range component.FallbackSecretStores {
+		if fallback != "" && fallback != secretStoreName {
+			stores = append(stores, fallback)
+		}
+	}
+	return stores
+}
+
 func (a *DaprRuntime) authSecretStoreOrDefault(comp components_v1alpha1.Component) string {
 	if comp.SecretStore == "" {
 		switch a.runtimeConfig.Mode {
@@ -1731,18 +2350,30 @@ func (a *DaprRuntime) getConfigurationGRPC() (*config.ApplicationConfig, error)
 
 func (a *DaprRuntime) createAppChannel() error {
 	if a.runtimeConfig.ApplicationPort > 0 {
-		var channelCreatorFn func(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool) (channel.AppChannel, error)
+		var channelCreatorFn func(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, tlsConfig *tls.Config) (channel.AppChannel, error)
 
 		switch a.runtimeConfig.ApplicationProtocol {
 		case GRPCProtocol:
 			channelCreatorFn = a.grpc.CreateLocalChannel
 		case HTTPProtocol:
-			channelCreatorFn = http_channel.CreateLocalChannel
+			channelCreatorFn = func(port, maxConcurrency int, spec config.TracingSpec, sslEnabled bool, tlsConfig *tls.Config) (channel.AppChannel, error) {
+				return http_channel.CreateLocalChannelWithConnectionConfig(port, maxConcurrency, spec, sslEnabled, tlsConfig, a.runtimeConfig.AppHTTPChannel)
+			}
 		default:
 			return errors.Errorf("cannot create app channel for protocol %s", string(a.runtimeConfig.ApplicationProtocol))
 		}
 
-		ch, err := channelCreatorFn(a.runtimeConfig.ApplicationPort, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, a.runtimeConfig.AppSSL)
+		sslEnabled := a.runtimeConfig.AppSSL
+		var tlsConfig *tls.Config
+		if a.runtimeConfig.AppMTLS {
+			if a.authenticator == nil {
+				return errors.New("app mTLS requires mTLS to be enabled")
+			}
+			sslEnabled = true
+			tlsConfig = security.GetAppChannelTLSConfig(a.authenticator)
+		}
+
+		ch, err := channelCreatorFn(a.runtimeConfig.ApplicationPort, a.runtimeConfig.MaxConcurrency, a.globalConfig.Spec.TracingSpec, sslEnabled, tlsConfig)
 		if err != nil {
 			return err
 		}
@@ -1755,6 +2386,25 @@ func (a *DaprRuntime) createAppChannel() error {
 	return nil
 }
 
+// startAppHealthCheck probes the health of the app with the configured probe mode.
+func (a *DaprRuntime) startAppHealthCheck() {
+	if a.appChannel == nil {
+		return
+	}
+
+	a.appHealthy.Store(true)
+	ch := health.StartAppHealthCheck(a.runtimeConfig.AppHealthProbe, a.appChannel.GetBaseAddress(),
+		health.WithFailureThreshold(4),
+		health.WithInterval(5*time.Second),
+		health.WithRequestTimeout(2*time.Second))
+	go func() {
+		for healthy := range ch {
+			a.appHealthy.Store(healthy)
+		}
+	}()
+	a.daprHTTPAPI.SetAppHealthChecker(a.appHealthy.Load)
+}
+
 func (a *DaprRuntime) appendBuiltinSecretStore() {
 	for _, comp := range a.builtinSecretStore() {
 		a.pendingComponents <- comp
@@ -1795,11 +2445,35 @@ func (a *DaprRuntime) initSecretStore(c components_v1alpha1.Component) error {
 		return err
 	}
 
+	secretStore = a.componentCalls.trackSecretStore(a.cacheSecretStore(c.ObjectMeta.Name, secretStore))
+	a.componentsInitLock.Lock()
 	a.secretStores[c.ObjectMeta.Name] = secretStore
+	a.componentsInitLock.Unlock()
 	diag.DefaultMonitoring.ComponentInitialized(c.Spec.Type)
 	return nil
 }
 
+// cacheSecretStore wraps the secret store with a cache when secrets caching is configured for it.
+func (a *DaprRuntime) cacheSecretStore(name string, secretStore secretstores.SecretStore) secretstores.SecretStore {
+	for _, scope := range a.globalConfig.Spec.Secrets.Scopes {
+		if scope.StoreName != name {
+			continue
+		}
+
+		ttl, err := scope.Cache.GetTTL()
+		if err != nil {
+			log.Warnf("secrets of secret store %s are not cached: %s", name, err)
+			return secretStore
+		}
+		if ttl == 0 {
+			return secretStore
+		}
+		log.Infof("caching secrets of secret store %s for %s", name, ttl)
+		return secretstores_loader.NewCachedSecretStore(secretStore, ttl, scope.Cache.MaxEntries)
+	}
+	return secretStore
+}
+
 func (a *DaprRuntime) convertMetadataItemsToProperties(items []components_v1alpha1.MetadataItem) map[string]string {
 	properties := map[string]string{}
 	for _, c := range items {
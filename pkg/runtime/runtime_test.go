@@ -16,6 +16,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.opencensus.io/trace"
+	"go.uber.org/atomic"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -45,6 +47,7 @@ import (
 	components_v1alpha1 "github.com/dapr/dapr/pkg/apis/components/v1alpha1"
 	subscriptionsapi "github.com/dapr/dapr/pkg/apis/subscriptions/v1alpha1"
 	channelt "github.com/dapr/dapr/pkg/channel/testing"
+	"github.com/dapr/dapr/pkg/components"
 	bindings_loader "github.com/dapr/dapr/pkg/components/bindings"
 	nr_loader "github.com/dapr/dapr/pkg/components/nameresolution"
 	pubsub_loader "github.com/dapr/dapr/pkg/components/pubsub"
@@ -613,6 +616,48 @@ func TestConsumerID(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestComponentInitStatus(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+
+	okPubSub := new(daprt.MockPubSub)
+	okPubSub.On("Init", mock.Anything).Return(nil)
+	failingPubSub := new(daprt.MockPubSub)
+	failingPubSub.On("Init", mock.Anything).Return(errors.New("connection refused"))
+
+	rt.pubSubRegistry.Register(
+		pubsub_loader.New("okPubSub", func() pubsub.PubSub {
+			return okPubSub
+		}),
+		pubsub_loader.New("failingPubSub", func() pubsub.PubSub {
+			return failingPubSub
+		}),
+	)
+
+	err := rt.processComponentAndDependents(components_v1alpha1.Component{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "ok"},
+		Spec:       components_v1alpha1.ComponentSpec{Type: "pubsub.okPubSub", Version: "v1"},
+	})
+	assert.NoError(t, err)
+
+	err = rt.processComponentAndDependents(components_v1alpha1.Component{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "failing"},
+		Spec:       components_v1alpha1.ComponentSpec{Type: "pubsub.failingPubSub", Version: "v1"},
+	})
+	assert.Error(t, err)
+
+	statuses := rt.componentStatus.List()
+	if assert.Len(t, statuses, 2) {
+		assert.Equal(t, "ok", statuses[0].Name)
+		assert.Equal(t, components.InitStatusSucceeded, statuses[0].InitStatus)
+		assert.Empty(t, statuses[0].Error)
+		assert.Equal(t, "failing", statuses[1].Name)
+		assert.Equal(t, components.InitStatusFailed, statuses[1].InitStatus)
+		assert.Contains(t, statuses[1].Error, "connection refused")
+	}
+	assert.Len(t, rt.getComponents(), 1)
+}
+
 func TestInitPubSub(t *testing.T) {
 	rt := NewTestDaprRuntime(modes.StandaloneMode)
 	defer stopRuntime(t, rt)
@@ -714,6 +759,85 @@ func TestInitPubSub(t *testing.T) {
 		mockAppChannel.AssertNumberOfCalls(t, "InvokeMethod", 1)
 	})
 
+	t.Run("refresh subscriptions subscribes to the added topics", func(t *testing.T) {
+		mockPubSub, mockPubSub2 := initMockPubSubForRuntime(rt)
+
+		mockAppChannel := new(channelt.MockAppChannel)
+		rt.appChannel = mockAppChannel
+
+		fakeReq := invokev1.NewInvokeMethodRequest("dapr/subscribe")
+		fakeReq.WithHTTPExtension(http.MethodGet, "")
+		fakeReq.WithRawData(nil, "application/json")
+
+		fakeResp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		fakeResp.WithRawData([]byte(getSubscriptionsJSONString([]string{"topic0"}, []string{})), "application/json")
+		refreshedResp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		refreshedResp.WithRawData([]byte(getSubscriptionsJSONString([]string{"topic1"}, []string{"topic0"})), "application/json")
+
+		mockAppChannel.On("InvokeMethod", mock.AnythingOfType("*context.emptyCtx"), fakeReq).Return(fakeResp, nil).Once()
+		mockAppChannel.On("InvokeMethod", mock.AnythingOfType("*context.emptyCtx"), fakeReq).Return(refreshedResp, nil).Once()
+
+		for _, comp := range pubsubComponents {
+			err := rt.processComponentAndDependents(comp)
+			assert.Nil(t, err)
+		}
+		rt.startSubscribing()
+		mockPubSub.AssertNumberOfCalls(t, "Subscribe", 1)
+
+		// act
+		err := rt.RefreshSubscriptions()
+
+		// assert
+		assert.NoError(t, err)
+		mockPubSub.AssertNumberOfCalls(t, "Subscribe", 2)
+		mockPubSub2.AssertNumberOfCalls(t, "Subscribe", 1)
+		mockAppChannel.AssertNumberOfCalls(t, "InvokeMethod", 2)
+
+		_, ok := rt.getTopicRoute(TestPubsubName, "topic0")
+		assert.False(t, ok, "topic0 was removed from the subscriptions")
+		_, ok = rt.getTopicRoute(TestPubsubName, "topic1")
+		assert.True(t, ok)
+		assert.NoError(t, rt.handleUnsubscribedTopicMessage(TestPubsubName, "topic0"), "messages of removed topics are dropped")
+	})
+
+	t.Run("refresh subscriptions keeps the subscriptions when the app fails", func(t *testing.T) {
+		mockPubSub, _ := initMockPubSubForRuntime(rt)
+
+		mockAppChannel := new(channelt.MockAppChannel)
+		rt.appChannel = mockAppChannel
+
+		fakeReq := invokev1.NewInvokeMethodRequest("dapr/subscribe")
+		fakeReq.WithHTTPExtension(http.MethodGet, "")
+		fakeReq.WithRawData(nil, "application/json")
+
+		fakeResp := invokev1.NewInvokeMethodResponse(200, "OK", nil)
+		fakeResp.WithRawData([]byte(getSubscriptionsJSONString([]string{"topic0"}, []string{})), "application/json")
+		failedResp := invokev1.NewInvokeMethodResponse(500, "Internal Error", nil)
+
+		mockAppChannel.On("InvokeMethod", mock.AnythingOfType("*context.emptyCtx"), fakeReq).Return(fakeResp, nil).Once()
+		mockAppChannel.On("InvokeMethod", mock.AnythingOfType("*context.emptyCtx"), fakeReq).Return(failedResp, nil).Once()
+		mockAppChannel.On("InvokeMethod", mock.AnythingOfType("*context.emptyCtx"), fakeReq).Return(nil, errors.New("connection refused")).Once()
+
+		for _, comp := range pubsubComponents {
+			err := rt.processComponentAndDependents(comp)
+			assert.Nil(t, err)
+		}
+		rt.startSubscribing()
+		mockPubSub.AssertNumberOfCalls(t, "Subscribe", 1)
+
+		// act
+		err := rt.RefreshSubscriptions()
+		assert.Error(t, err)
+		err = rt.RefreshSubscriptions()
+		assert.Error(t, err)
+
+		// assert
+		_, ok := rt.getTopicRoute(TestPubsubName, "topic0")
+		assert.True(t, ok)
+		mockPubSub.AssertNumberOfCalls(t, "Subscribe", 1)
+		assert.Error(t, rt.handleUnsubscribedTopicMessage(TestPubsubName, "topic1"), "messages are redelivered until the refresh succeeds")
+	})
+
 	t.Run("subscribe to topic with custom route", func(t *testing.T) {
 		mockPubSub, _ := initMockPubSubForRuntime(rt)
 
@@ -1400,6 +1524,87 @@ func TestProcessComponentSecrets(t *testing.T) {
 		assert.Equal(t, "value1", mod.Spec.Metadata[0].Value.String())
 		assert.Empty(t, unready)
 	})
+
+	t.Run("Secret store override and fallback secret stores", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.secretStores["kubernetes"] = NewMockKubernetesStore()
+		rt.secretStores["vault"] = daprt.FakeSecretStore{}
+
+		component := components_v1alpha1.Component{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: "mockBinding",
+			},
+			Spec: components_v1alpha1.ComponentSpec{
+				Type:    "bindings.mock",
+				Version: "v1",
+				Metadata: []components_v1alpha1.MetadataItem{
+					{
+						Name: "password",
+						SecretKeyRef: components_v1alpha1.SecretKeyRef{
+							Name:  "good-key",
+							Store: "vault",
+						},
+					},
+					{
+						Name: "cert",
+						SecretKeyRef: components_v1alpha1.SecretKeyRef{
+							Key:  "key1",
+							Name: "name1",
+						},
+					},
+					{
+						Name: "token",
+						SecretKeyRef: components_v1alpha1.SecretKeyRef{
+							Name: "error-key",
+						},
+					},
+				},
+			},
+			Auth: components_v1alpha1.Auth{
+				SecretStore:          "vault",
+				FallbackSecretStores: []string{"kubernetes"},
+			},
+		}
+
+		mod, unready := rt.processComponentSecrets(component)
+		assert.Empty(t, unready)
+		assert.Equal(t, "life is good", mod.Spec.Metadata[0].Value.String())
+		assert.Equal(t, "value1", mod.Spec.Metadata[1].Value.String())
+		assert.Equal(t, "", mod.Spec.Metadata[2].Value.String())
+	})
+
+	t.Run("Fallback secret store not loaded", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.secretStores["vault"] = daprt.FakeSecretStore{}
+
+		component := components_v1alpha1.Component{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: "mockBinding",
+			},
+			Spec: components_v1alpha1.ComponentSpec{
+				Type:    "bindings.mock",
+				Version: "v1",
+				Metadata: []components_v1alpha1.MetadataItem{
+					{
+						Name: "cert",
+						SecretKeyRef: components_v1alpha1.SecretKeyRef{
+							Key:  "key1",
+							Name: "name1",
+						},
+					},
+				},
+			},
+			Auth: components_v1alpha1.Auth{
+				SecretStore:          "vault",
+				FallbackSecretStores: []string{"kubernetes"},
+			},
+		}
+
+		_, unready := rt.processComponentSecrets(component)
+		assert.Equal(t, "kubernetes", unready)
+	})
 }
 
 func TestExtractComponentCategory(t *testing.T) {
@@ -1569,6 +1774,215 @@ func TestFlushOutstandingComponent(t *testing.T) {
 		assert.True(t, wasCalledChild)
 		assert.True(t, wasCalledGrandChild)
 	})
+	t.Run("flushOutstandingComponents waits for component groups initialized concurrently", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		var started sync.WaitGroup
+		started.Add(2)
+		concurrent := atomic.NewInt32(0)
+		cb := func() {
+			started.Done()
+			done := make(chan struct{})
+			go func() {
+				started.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+				concurrent.Inc()
+			case <-time.After(time.Second):
+			}
+		}
+		for _, name := range []string{"kubernetesMock1", "kubernetesMock2"} {
+			m := NewMockKubernetesStoreWithInitCallback(cb)
+			rt.secretStoresRegistry.Register(
+				secretstores_loader.New(name, func() secretstores.SecretStore {
+					return m
+				}))
+		}
+
+		go rt.processComponents()
+		rt.pendingComponentGroups <- []components_v1alpha1.Component{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name: "kubernetesMock1",
+				},
+				Spec: components_v1alpha1.ComponentSpec{
+					Type:    "secretstores.kubernetesMock1",
+					Version: "v1",
+				},
+			},
+			{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name: "kubernetesMock2",
+				},
+				Spec: components_v1alpha1.ComponentSpec{
+					Type:    "secretstores.kubernetesMock2",
+					Version: "v1",
+				},
+			},
+		}
+		rt.flushOutstandingComponents()
+		assert.Equal(t, int32(2), concurrent.Load())
+		assert.Len(t, rt.secretStores, 2)
+	})
+}
+
+func TestComponentHotReload(t *testing.T) {
+	comp := func(value string) components_v1alpha1.Component {
+		return components_v1alpha1.Component{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "statestore"},
+			Spec: components_v1alpha1.ComponentSpec{
+				Type:    "state.mockState",
+				Version: "v1",
+				Metadata: []components_v1alpha1.MetadataItem{
+					{
+						Name: "host",
+						Value: components_v1alpha1.DynamicValue{
+							JSON: v1.JSON{Raw: []byte(value)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("unchanged spec is not re-initialized", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.appendOrReplaceComponents(comp("localhost"))
+
+		done := make(chan struct{})
+		go func() {
+			rt.onComponentUpdated(comp("localhost"))
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-rt.pendingComponents:
+			assert.Fail(t, "unchanged component queued for re-init")
+		case <-time.After(time.Second):
+			assert.Fail(t, "timeout")
+		}
+	})
+
+	t.Run("changed spec is re-initialized", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.appendOrReplaceComponents(comp("localhost"))
+
+		go rt.onComponentUpdated(comp("remotehost"))
+
+		select {
+		case c := <-rt.pendingComponents:
+			assert.Equal(t, comp("remotehost"), c)
+		case <-time.After(time.Second):
+			assert.Fail(t, "changed component not queued for re-init")
+		}
+	})
+
+	t.Run("replaced instance without calls in flight is closed", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		closed := make(chan struct{})
+		store := &mockStateStore{}
+
+		rt.drainComponent("statestore", &closingStateStore{Store: store, closed: closed}, time.Minute)
+
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			assert.Fail(t, "not closed without calls in flight")
+		}
+	})
+
+	t.Run("replaced instance is closed once its calls complete", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		closed := make(chan struct{})
+		instance := &closingStateStore{Store: &mockStateStore{}, closed: closed}
+		done := rt.componentCalls.start(instance)
+
+		rt.drainComponent("statestore", instance, time.Minute)
+
+		select {
+		case <-closed:
+			assert.Fail(t, "closed with a call in flight")
+		case <-time.After(10 * time.Millisecond):
+		}
+		done()
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			assert.Fail(t, "not closed after the call completed")
+		}
+	})
+
+	t.Run("replaced instance is closed at the drain timeout", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		closed := make(chan struct{})
+		instance := &closingStateStore{Store: &mockStateStore{}, closed: closed}
+		done := rt.componentCalls.start(instance)
+		defer done()
+
+		rt.drainComponent("statestore", instance, 50*time.Millisecond)
+
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			assert.Fail(t, "not closed after the drain timeout")
+		}
+	})
+
+	t.Run("instances of the component", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		store := &mockStateStore{}
+		rt.stateStores["statestore"] = store
+
+		assert.Equal(t, []interface{}{store}, rt.componentInstances(stateComponent, "statestore"))
+		assert.Empty(t, rt.componentInstances(stateComponent, "other"))
+		assert.Empty(t, rt.componentInstances(pubsubComponent, "statestore"))
+	})
+}
+
+type closingStateStore struct {
+	state.Store
+	closed chan struct{}
+}
+
+func (s *closingStateStore) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func TestComponentInitGroups(t *testing.T) {
+	comp := func(name, componentType string) components_v1alpha1.Component {
+		return components_v1alpha1.Component{
+			ObjectMeta: meta_v1.ObjectMeta{Name: name},
+			Spec:       components_v1alpha1.ComponentSpec{Type: componentType},
+		}
+	}
+
+	t.Run("secret stores first, one at a time", func(t *testing.T) {
+		groups := componentInitGroups([]components_v1alpha1.Component{
+			comp("state1", "state.redis"),
+			comp("secrets1", "secretstores.local.file"),
+			comp("pubsub1", "pubsub.redis"),
+			comp("secrets2", "secretstores.kubernetes"),
+		})
+		assert.Equal(t, [][]components_v1alpha1.Component{
+			{comp("secrets1", "secretstores.local.file")},
+			{comp("secrets2", "secretstores.kubernetes")},
+			{comp("state1", "state.redis"), comp("pubsub1", "pubsub.redis")},
+		}, groups)
+	})
+
+	t.Run("no components", func(t *testing.T) {
+		assert.Empty(t, componentInitGroups(nil))
+	})
 }
 
 // Test InitSecretStore if secretstore.* refers to Kubernetes secret store
@@ -2372,6 +2786,30 @@ func TestNamespace(t *testing.T) {
 
 		assert.Equal(t, "a", ns)
 	})
+
+	t.Run("standalone mode, DAPR_NAMESPACE", func(t *testing.T) {
+		os.Setenv("NAMESPACE", "a")
+		os.Setenv("DAPR_NAMESPACE", "b")
+		defer os.Clearenv()
+
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		ns := rt.getNamespace()
+
+		assert.Equal(t, "b", ns)
+	})
+
+	t.Run("kubernetes mode ignores DAPR_NAMESPACE", func(t *testing.T) {
+		os.Setenv("NAMESPACE", "a")
+		os.Setenv("DAPR_NAMESPACE", "b")
+		defer os.Clearenv()
+
+		rt := NewTestDaprRuntime(modes.KubernetesMode)
+		defer stopRuntime(t, rt)
+		ns := rt.getNamespace()
+
+		assert.Equal(t, "a", ns)
+	})
 }
 
 func TestAuthorizedComponents(t *testing.T) {
@@ -2401,6 +2839,43 @@ func TestAuthorizedComponents(t *testing.T) {
 		assert.True(t, len(comps) == 0)
 	})
 
+	t.Run("standalone mode, component without namespace is shared", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.namespace = "a"
+
+		component := components_v1alpha1.Component{}
+		component.ObjectMeta.Name = testCompName
+
+		comps := rt.getAuthorizedComponents([]components_v1alpha1.Component{component})
+		assert.True(t, len(comps) == 1)
+	})
+
+	t.Run("standalone mode, component without namespace not in scope", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		defer stopRuntime(t, rt)
+		rt.namespace = "a"
+
+		component := components_v1alpha1.Component{}
+		component.ObjectMeta.Name = testCompName
+		component.Scopes = []string{"other"}
+
+		comps := rt.getAuthorizedComponents([]components_v1alpha1.Component{component})
+		assert.True(t, len(comps) == 0)
+	})
+
+	t.Run("kubernetes mode, namespace mismatch", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.KubernetesMode)
+		defer stopRuntime(t, rt)
+		rt.namespace = "a"
+
+		component := components_v1alpha1.Component{}
+		component.ObjectMeta.Name = testCompName
+
+		comps := rt.getAuthorizedComponents([]components_v1alpha1.Component{component})
+		assert.True(t, len(comps) == 0)
+	})
+
 	t.Run("namespace match", func(t *testing.T) {
 		rt := NewTestDaprRuntime(modes.StandaloneMode)
 		defer stopRuntime(t, rt)
@@ -2528,6 +3003,77 @@ func TestInitActors(t *testing.T) {
 	})
 }
 
+func TestPauseInputBinding(t *testing.T) {
+	rt := NewTestDaprRuntime(modes.StandaloneMode)
+	defer stopRuntime(t, rt)
+	rt.inputBindings["inputbinding"] = &mockBinding{}
+
+	// waitResumed waits for the binding to be resumed in the background, like an event read by the binding.
+	waitResumed := func() chan error {
+		done := make(chan error, 1)
+		rt.inputBindingsLock.RLock()
+		binding := rt.inputBindings["inputbinding"]
+		rt.inputBindingsLock.RUnlock()
+		go func() {
+			done <- rt.waitInputBindingResumed("inputbinding", binding)
+		}()
+		select {
+		case <-done:
+			assert.Fail(t, "event delivered while the binding is paused")
+		case <-time.After(100 * time.Millisecond):
+		}
+		return done
+	}
+	released := func(done chan error) error {
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(time.Second):
+			assert.Fail(t, "event still held")
+			return nil
+		}
+	}
+
+	t.Run("unknown input binding", func(t *testing.T) {
+		assert.Error(t, rt.setInputBindingPaused("unknown", true))
+	})
+
+	t.Run("events wait until the binding is resumed", func(t *testing.T) {
+		assert.NoError(t, rt.setInputBindingPaused("inputbinding", true))
+		// pausing twice is a no-op
+		assert.NoError(t, rt.setInputBindingPaused("inputbinding", true))
+
+		done := waitResumed()
+		assert.NoError(t, rt.setInputBindingPaused("inputbinding", false))
+		assert.NoError(t, released(done))
+	})
+
+	t.Run("events are released with an error when the binding is re-initialized", func(t *testing.T) {
+		rt.bindingsRegistry.RegisterInputBindings(
+			bindings_loader.NewInput("testInputBinding", func() bindings.InputBinding {
+				return &daprt.MockBinding{}
+			}),
+		)
+		assert.NoError(t, rt.setInputBindingPaused("inputbinding", true))
+
+		done := waitResumed()
+		c := components_v1alpha1.Component{}
+		c.ObjectMeta.Name = "inputbinding"
+		c.Spec.Type = "bindings.testInputBinding"
+		assert.NoError(t, rt.initInputBinding(c))
+		assert.Error(t, released(done))
+		assert.NotContains(t, rt.pausedInputBindings, "inputbinding")
+	})
+
+	t.Run("events are released with an error on shutdown", func(t *testing.T) {
+		assert.NoError(t, rt.setInputBindingPaused("inputbinding", true))
+
+		done := waitResumed()
+		rt.stopAcceptingWork()
+		assert.Error(t, released(done))
+	})
+}
+
 func TestInitBindings(t *testing.T) {
 	t.Run("single input binding", func(t *testing.T) {
 		r := NewDaprRuntime(&Config{}, &config.Configuration{}, &config.AccessControlList{})
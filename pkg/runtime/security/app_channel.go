@@ -0,0 +1,34 @@
+package security
+
+import (
+	"crypto/tls"
+
+	"github.com/pkg/errors"
+)
+
+// GetAppChannelTLSConfig returns the TLS config for the app channel when mTLS is enabled for it.
+// daprd presents its current workload certificate signed by Sentry, so that the app can authenticate
+// it with the trust anchors. Sentry doesn't issue certificates to apps, so as with app SSL the
+// certificate the app serves is not verified. The app to daprd API calls are not covered.
+func GetAppChannelTLSConfig(authenticator Authenticator) *tls.Config {
+	// nolint:gosec
+	return &tls.Config{
+		InsecureSkipVerify: true,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return currentWorkloadCert(authenticator)
+		},
+	}
+}
+
+// currentWorkloadCert is read on each handshake, so that it follows the rotation of the workload certificate.
+func currentWorkloadCert(authenticator Authenticator) (*tls.Certificate, error) {
+	signedCert := authenticator.GetCurrentSignedCert()
+	if signedCert == nil {
+		return nil, errors.New("no workload certificate signed by sentry")
+	}
+	cert, err := tls.X509KeyPair(signedCert.WorkloadCert, signedCert.PrivateKeyPem)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating x509 Key Pair")
+	}
+	return &cert, nil
+}
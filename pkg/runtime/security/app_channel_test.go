@@ -0,0 +1,37 @@
+package security
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAppChannelTLSConfig(t *testing.T) {
+	t.Run("app certificate is not verified", func(t *testing.T) {
+		a := getTestAuthenticator()
+		tlsConfig := GetAppChannelTLSConfig(a)
+
+		assert.True(t, tlsConfig.InsecureSkipVerify)
+	})
+
+	t.Run("without signed certificate", func(t *testing.T) {
+		a := getTestAuthenticator()
+		tlsConfig := GetAppChannelTLSConfig(a)
+
+		_, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+		assert.Error(t, err)
+	})
+
+	t.Run("with invalid signed certificate", func(t *testing.T) {
+		a := getTestAuthenticator()
+		a.(*authenticator).currentSignedCert = &SignedCertificate{
+			WorkloadCert:  []byte("cert"),
+			PrivateKeyPem: []byte("key"),
+		}
+		tlsConfig := GetAppChannelTLSConfig(a)
+
+		_, err := tlsConfig.GetClientCertificate(&tls.CertificateRequestInfo{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,211 @@
+package security
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	crlBlockType           = "X509 CRL"
+	crlFetchTimeout        = time.Second * 5
+	crlRefreshInterval     = time.Minute * 5
+	crlNegativeCacheTTL    = time.Second * 30
+	crlMaxResponseSizeByte = 10 << 20
+)
+
+// DefaultRevocationChecker is the revocation checker shared by the sidecar's mTLS servers and clients.
+var DefaultRevocationChecker = NewRevocationChecker(crlRefreshInterval)
+
+// RevocationChecker verifies peer certificates against the revocation lists published by Sentry.
+type RevocationChecker interface {
+	VerifyPeerCertificate(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error
+}
+
+type cachedCRL struct {
+	revoked    map[string]struct{}
+	nextUpdate time.Time
+	fetchedAt  time.Time
+}
+
+// crlEntry holds the last good CRL of a distribution point and the state of its fetch.
+type crlEntry struct {
+	crl *cachedCRL
+	// err is the error of the last failed fetch, cached until failedAt plus the negative cache TTL.
+	err      error
+	failedAt time.Time
+	// fetching is closed when the fetch in progress completes, nil when no fetch is in progress.
+	fetching chan struct{}
+}
+
+type revocationChecker struct {
+	client           *http.Client
+	refreshInterval  time.Duration
+	negativeCacheTTL time.Duration
+	cache            map[string]*crlEntry
+	lock             *sync.Mutex
+}
+
+// NewRevocationChecker returns a RevocationChecker that caches fetched CRLs for refreshInterval.
+func NewRevocationChecker(refreshInterval time.Duration) RevocationChecker {
+	return &revocationChecker{
+		client:           &http.Client{Timeout: crlFetchTimeout},
+		refreshInterval:  refreshInterval,
+		negativeCacheTTL: crlNegativeCacheTTL,
+		cache:            map[string]*crlEntry{},
+		lock:             &sync.Mutex{},
+	}
+}
+
+// VerifyPeerCertificate rejects a peer whose leaf certificate is listed in the CRL of its issuer.
+// It is meant to be used as the tls.Config VerifyPeerCertificate callback, after chain verification succeeded.
+// A CRL that can't be fetched is logged and skipped so that a Sentry outage doesn't break service invocation.
+// Expired CRLs are refreshed in the background while the last good list is used, so only the first
+// handshakes checked against a distribution point wait for its list to be fetched.
+func (r *revocationChecker) VerifyPeerCertificate(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	for _, chain := range verifiedChains {
+		if len(chain) < 2 {
+			continue
+		}
+
+		leaf, issuer := chain[0], chain[1]
+		for _, url := range leaf.CRLDistributionPoints {
+			crl, err := r.getCRL(url, issuer)
+			if err != nil {
+				log.Warnf("unable to check certificate revocation status: %s", err)
+				continue
+			}
+
+			if _, ok := crl.revoked[leaf.SerialNumber.String()]; ok {
+				return errors.Errorf("peer certificate with serial number %s has been revoked", leaf.SerialNumber.String())
+			}
+		}
+	}
+	return nil
+}
+
+func (r *revocationChecker) getCRL(url string, issuer *x509.Certificate) (*cachedCRL, error) {
+	r.lock.Lock()
+	entry, ok := r.cache[url]
+	if !ok {
+		entry = &crlEntry{}
+		r.cache[url] = entry
+	}
+
+	now := time.Now().UTC()
+	failedRecently := now.Sub(entry.failedAt) < r.negativeCacheTTL
+	if entry.crl != nil {
+		if r.isExpired(entry.crl, now) && entry.fetching == nil && !failedRecently {
+			r.startFetch(url, issuer, entry)
+		}
+		crl := entry.crl
+		r.lock.Unlock()
+		return crl, nil
+	}
+
+	if failedRecently {
+		err := entry.err
+		r.lock.Unlock()
+		return nil, err
+	}
+	if entry.fetching == nil {
+		r.startFetch(url, issuer, entry)
+	}
+	fetching := entry.fetching
+	r.lock.Unlock()
+
+	<-fetching
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if entry.crl == nil {
+		return nil, entry.err
+	}
+	return entry.crl, nil
+}
+
+func (r *revocationChecker) isExpired(crl *cachedCRL, now time.Time) bool {
+	return now.Sub(crl.fetchedAt) >= r.refreshInterval || (!crl.nextUpdate.IsZero() && !now.Before(crl.nextUpdate))
+}
+
+// startFetch fetches the CRL of the entry in the background. It must be called with the lock held.
+func (r *revocationChecker) startFetch(url string, issuer *x509.Certificate, entry *crlEntry) {
+	done := make(chan struct{})
+	entry.fetching = done
+
+	go func() {
+		fetched, err := r.fetchCRL(url, issuer)
+
+		r.lock.Lock()
+		if err != nil {
+			if entry.crl != nil {
+				// Keep using the last known list rather than failing open on a transient error.
+				log.Warnf("error refreshing certificate revocation list, using cached list: %s", err)
+			}
+			entry.err = err
+			entry.failedAt = time.Now().UTC()
+		} else {
+			entry.crl = fetched
+			entry.err = nil
+			entry.failedAt = time.Time{}
+		}
+		entry.fetching = nil
+		r.lock.Unlock()
+
+		close(done)
+	}()
+}
+
+func (r *revocationChecker) fetchCRL(url string, issuer *x509.Certificate) (*cachedCRL, error) {
+	resp, err := r.client.Get(url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error fetching crl from %s", url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("error fetching crl from %s: status code %d", url, resp.StatusCode)
+	}
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, crlMaxResponseSizeByte))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading crl from %s", url)
+	}
+
+	crl, err := parseCRL(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing crl from %s", url)
+	}
+
+	if err = issuer.CheckCRLSignature(crl); err != nil {
+		return nil, errors.Wrapf(err, "invalid crl signature from %s", url)
+	}
+
+	revoked := make(map[string]struct{}, len(crl.TBSCertList.RevokedCertificates))
+	for _, c := range crl.TBSCertList.RevokedCertificates {
+		revoked[c.SerialNumber.String()] = struct{}{}
+	}
+
+	return &cachedCRL{
+		revoked:    revoked,
+		nextUpdate: crl.TBSCertList.NextUpdate,
+		fetchedAt:  time.Now().UTC(),
+	}, nil
+}
+
+func parseCRL(b []byte) (*pkix.CertificateList, error) {
+	if block, _ := pem.Decode(b); block != nil {
+		if block.Type != crlBlockType {
+			return nil, errors.Errorf("unexpected PEM block type %s", block.Type)
+		}
+		b = block.Bytes
+	}
+	return x509.ParseDERCRL(b)
+}
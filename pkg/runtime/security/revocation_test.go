@@ -0,0 +1,170 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+)
+
+func createTestIssuer(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "cluster.local"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	b, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(b)
+	assert.NoError(t, err)
+	return cert, key
+}
+
+func createTestLeaf(t *testing.T, issuer *x509.Certificate, issuerKey *ecdsa.PrivateKey, serial int64, crlURL string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		CRLDistributionPoints: []string{crlURL},
+	}
+	b, err := x509.CreateCertificate(rand.Reader, tmpl, issuer, &key.PublicKey, issuerKey)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(b)
+	assert.NoError(t, err)
+	return cert
+}
+
+func TestVerifyPeerCertificate(t *testing.T) {
+	issuer, issuerKey := createTestIssuer(t)
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now(),
+		NextUpdate: time.Now().Add(time.Hour),
+		RevokedCertificates: []pkix.RevokedCertificate{
+			{SerialNumber: big.NewInt(42), RevocationTime: time.Now()},
+		},
+	}, issuer, issuerKey)
+	assert.NoError(t, err)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(crl)
+	}))
+	defer srv.Close()
+
+	t.Run("revoked certificate", func(t *testing.T) {
+		checker := NewRevocationChecker(time.Minute)
+		leaf := createTestLeaf(t, issuer, issuerKey, 42, srv.URL)
+
+		err := checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, issuer}})
+		assert.Error(t, err)
+	})
+
+	t.Run("valid certificate, crl is cached", func(t *testing.T) {
+		requests = 0
+		checker := NewRevocationChecker(time.Minute)
+		leaf := createTestLeaf(t, issuer, issuerKey, 7, srv.URL)
+
+		assert.NoError(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, issuer}}))
+		assert.NoError(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, issuer}}))
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("crl signed by another issuer is ignored", func(t *testing.T) {
+		otherIssuer, otherKey := createTestIssuer(t)
+		checker := NewRevocationChecker(time.Minute)
+		leaf := createTestLeaf(t, otherIssuer, otherKey, 42, srv.URL)
+
+		assert.NoError(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, otherIssuer}}))
+	})
+
+	t.Run("unreachable crl", func(t *testing.T) {
+		checker := NewRevocationChecker(time.Minute)
+		leaf := createTestLeaf(t, issuer, issuerKey, 42, "http://127.0.0.1:1/crl")
+
+		assert.NoError(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, issuer}}))
+	})
+}
+
+func TestCRLRefresh(t *testing.T) {
+	issuer, issuerKey := createTestIssuer(t)
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now(),
+		NextUpdate: time.Now().Add(time.Hour),
+		RevokedCertificates: []pkix.RevokedCertificate{
+			{SerialNumber: big.NewInt(42), RevocationTime: time.Now()},
+		},
+	}, issuer, issuerKey)
+	assert.NoError(t, err)
+
+	requests := atomic.NewInt32(0)
+	failing := atomic.NewBool(false)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Inc()
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(crl)
+	}))
+	defer srv.Close()
+
+	t.Run("expired crl is refreshed in the background", func(t *testing.T) {
+		requests.Store(0)
+		failing.Store(false)
+		checker := NewRevocationChecker(time.Millisecond)
+		leaf := createTestLeaf(t, issuer, issuerKey, 42, srv.URL)
+
+		assert.Error(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, issuer}}))
+		assert.Equal(t, int32(1), requests.Load())
+
+		// the refresh fails, the last good list is still used
+		failing.Store(true)
+		time.Sleep(10 * time.Millisecond)
+		assert.Error(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, issuer}}))
+		assert.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, 10*time.Millisecond)
+		assert.Error(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, issuer}}))
+	})
+
+	t.Run("failed fetch is cached", func(t *testing.T) {
+		requests.Store(0)
+		failing.Store(true)
+		checker := NewRevocationChecker(time.Minute)
+		leaf := createTestLeaf(t, issuer, issuerKey, 42, srv.URL)
+
+		assert.NoError(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, issuer}}))
+		assert.NoError(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, issuer}}))
+		assert.Equal(t, int32(1), requests.Load())
+
+		// the list is fetched again after the negative cache TTL
+		failing.Store(false)
+		checker.(*revocationChecker).negativeCacheTTL = 0
+		assert.Error(t, checker.VerifyPeerCertificate(nil, [][]*x509.Certificate{{leaf, issuer}}))
+		assert.Equal(t, int32(2), requests.Load())
+	})
+}
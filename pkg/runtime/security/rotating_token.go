@@ -0,0 +1,173 @@
+package security
+
+import (
+	"context"
+	"crypto/subtle"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/fswatcher"
+)
+
+// TokenRotationGracePeriod is how long the previous token is still accepted after a token file changes.
+const TokenRotationGracePeriod = time.Minute * 5
+
+var (
+	fileTokensLock = &sync.Mutex{}
+	// fileTokens holds the tokens read from files by path, so that each token file is watched once
+	// however many servers and channels load the token.
+	fileTokens = map[string]*Token{}
+)
+
+// Token is an api token that is either static or read from a file. A token read from a file is
+// reloaded when the file changes and the previous token is still accepted for a grace period,
+// so the other side can switch to the new token without restarts.
+type Token struct {
+	lock            sync.RWMutex
+	current         string
+	previous        string
+	previousExpires time.Time
+	gracePeriod     time.Duration
+	now             func() time.Time
+	stopWatch       context.CancelFunc
+}
+
+// NewToken returns a token with the given value.
+func NewToken(value string) *Token {
+	return &Token{
+		current:     value,
+		gracePeriod: TokenRotationGracePeriod,
+		now:         time.Now,
+	}
+}
+
+// LoadAPIToken returns the api token read from the file in the DAPR_API_TOKEN_FILE environment
+// variable, which is watched for changes, or else from the DAPR_API_TOKEN environment variable.
+func LoadAPIToken() (*Token, error) {
+	return loadToken(APITokenEnvVar, APITokenFileEnvVar)
+}
+
+// LoadAppToken returns the app api token read from the file in the APP_API_TOKEN_FILE environment
+// variable, which is watched for changes, or else from the APP_API_TOKEN environment variable.
+func LoadAppToken() (*Token, error) {
+	return loadToken(AppAPITokenEnvVar, AppAPITokenFileEnvVar)
+}
+
+func loadToken(envVar, fileEnvVar string) (*Token, error) {
+	path := os.Getenv(fileEnvVar)
+	if path == "" {
+		return NewToken(os.Getenv(envVar)), nil
+	}
+
+	fileTokensLock.Lock()
+	defer fileTokensLock.Unlock()
+
+	if t, ok := fileTokens[path]; ok {
+		return t, nil
+	}
+	value, err := readTokenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		// the token is read when the file is written, no value is accepted in the meantime
+		log.Warnf("token file %s is empty, no token is accepted until it holds one", path)
+	}
+	t := NewToken(value)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.stopWatch = cancel
+	go t.watchFile(ctx, path)
+	fileTokens[path] = t
+	return t, nil
+}
+
+// StopTokenWatches stops watching the token files. Tokens loaded afterwards are read from the files again.
+func StopTokenWatches() {
+	fileTokensLock.Lock()
+	defer fileTokensLock.Unlock()
+
+	for path, t := range fileTokens {
+		t.stopWatch()
+		delete(fileTokens, path)
+	}
+}
+
+// Value returns the current value of the token.
+func (t *Token) Value() string {
+	if t == nil {
+		return ""
+	}
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.current
+}
+
+// Accepts returns true if the value matches the current token, or the previous token
+// within the grace period after a rotation.
+func (t *Token) Accepts(value string) bool {
+	if t == nil || value == "" {
+		return false
+	}
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	if subtle.ConstantTimeCompare([]byte(value), []byte(t.current)) == 1 {
+		return true
+	}
+	return t.previous != "" && t.now().Before(t.previousExpires) &&
+		subtle.ConstantTimeCompare([]byte(value), []byte(t.previous)) == 1
+}
+
+func (t *Token) rotate(value string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if value == t.current {
+		return
+	}
+	t.previous = t.current
+	t.previousExpires = t.now().Add(t.gracePeriod)
+	t.current = value
+}
+
+func (t *Token) watchFile(ctx context.Context, path string) {
+	eventCh := make(chan struct{})
+	go func() {
+		if err := fswatcher.Watch(ctx, filepath.Dir(path), eventCh); err != nil {
+			log.Errorf("failed to watch token file %s: %s", path, err)
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-eventCh:
+			value, err := readTokenFile(path)
+			if err != nil {
+				log.Warnf("failed to reload token: %s", err)
+				continue
+			}
+			if value == "" {
+				log.Warnf("token file %s is empty, the token is not reloaded", path)
+				continue
+			}
+			t.rotate(value)
+		}
+	}
+}
+
+func readTokenFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read token file %s", path)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
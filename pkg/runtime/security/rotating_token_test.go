@@ -0,0 +1,100 @@
+package security
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenAccepts(t *testing.T) {
+	now := time.Now()
+	token := NewToken("token1")
+	token.now = func() time.Time { return now }
+
+	t.Run("current token", func(t *testing.T) {
+		assert.True(t, token.Accepts("token1"))
+		assert.False(t, token.Accepts("token2"))
+		assert.False(t, token.Accepts(""))
+	})
+
+	t.Run("previous token within grace period", func(t *testing.T) {
+		token.rotate("token2")
+		assert.Equal(t, "token2", token.Value())
+		assert.True(t, token.Accepts("token2"))
+		assert.True(t, token.Accepts("token1"))
+	})
+
+	t.Run("previous token after grace period", func(t *testing.T) {
+		now = now.Add(TokenRotationGracePeriod)
+		assert.True(t, token.Accepts("token2"))
+		assert.False(t, token.Accepts("token1"))
+	})
+
+	t.Run("nil token", func(t *testing.T) {
+		var nilToken *Token
+		assert.Equal(t, "", nilToken.Value())
+		assert.False(t, nilToken.Accepts("token1"))
+	})
+}
+
+func TestLoadAppToken(t *testing.T) {
+	t.Run("token from environment variable", func(t *testing.T) {
+		os.Setenv(AppAPITokenEnvVar, "token1")
+		defer os.Clearenv()
+
+		token, err := LoadAppToken()
+		assert.NoError(t, err)
+		assert.Equal(t, "token1", token.Value())
+	})
+
+	t.Run("token from file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "token")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "token")
+		assert.NoError(t, ioutil.WriteFile(path, []byte("token2\n"), 0600))
+		os.Setenv(AppAPITokenEnvVar, "token1")
+		os.Setenv(AppAPITokenFileEnvVar, path)
+		defer os.Clearenv()
+		defer StopTokenWatches()
+
+		token, err := LoadAppToken()
+		assert.NoError(t, err)
+		assert.Equal(t, "token2", token.Value())
+
+		// the token file is watched once
+		again, err := LoadAppToken()
+		assert.NoError(t, err)
+		assert.Same(t, token, again)
+	})
+
+	t.Run("empty token file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "token")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "token")
+		assert.NoError(t, ioutil.WriteFile(path, []byte("\n"), 0600))
+		os.Setenv(AppAPITokenFileEnvVar, path)
+		defer os.Clearenv()
+		defer StopTokenWatches()
+
+		token, err := LoadAppToken()
+		assert.NoError(t, err)
+		assert.Equal(t, "", token.Value())
+		assert.False(t, token.Accepts(""))
+	})
+
+	t.Run("missing token file", func(t *testing.T) {
+		os.Setenv(AppAPITokenFileEnvVar, "/non-existent/token")
+		defer os.Clearenv()
+
+		_, err := LoadAppToken()
+		assert.Error(t, err)
+	})
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/dapr/dapr/pkg/credentials"
 	diag "github.com/dapr/dapr/pkg/diagnostics"
+	"github.com/dapr/dapr/pkg/logsampler"
 	"github.com/dapr/dapr/pkg/sentry/certs"
 	"github.com/dapr/kit/logger"
 )
@@ -19,7 +20,7 @@ const (
 	ecPKType = "EC PRIVATE KEY"
 )
 
-var log = logger.NewLogger("dapr.runtime.security")
+var log = logsampler.NewLogger(logger.NewLogger("dapr.runtime.security"))
 
 func CertPool(certPem []byte) (*x509.CertPool, error) {
 	cp := x509.NewCertPool()
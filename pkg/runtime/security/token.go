@@ -10,6 +10,9 @@ const (
 	// APITokenEnvVar is the environment variable for the api token
 	APITokenEnvVar    = "DAPR_API_TOKEN"
 	AppAPITokenEnvVar = "APP_API_TOKEN"
+	// APITokenFileEnvVar and AppAPITokenFileEnvVar are the environment variables for files holding the tokens
+	APITokenFileEnvVar    = "DAPR_API_TOKEN_FILE"
+	AppAPITokenFileEnvVar = "APP_API_TOKEN_FILE"
 	// APITokenHeader is header name for http/gRPC calls to hold the token
 	APITokenHeader = "dapr-api-token"
 )
@@ -21,6 +24,11 @@ func GetAPIToken() string {
 	return os.Getenv(APITokenEnvVar)
 }
 
+// APITokenConfigured returns whether the Dapr APIs require an api token, from an environment variable or a file
+func APITokenConfigured() bool {
+	return os.Getenv(APITokenEnvVar) != "" || os.Getenv(APITokenFileEnvVar) != ""
+}
+
 // GetAppToken returns the value of the app api token from an environment variable
 func GetAppToken() string {
 	return os.Getenv(AppAPITokenEnvVar)
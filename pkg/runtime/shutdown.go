@@ -0,0 +1,122 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package runtime
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	diag "github.com/dapr/dapr/pkg/diagnostics"
+	"github.com/dapr/dapr/pkg/runtime/security"
+)
+
+const (
+	shutdownPhaseStopAccepting   = "stop-accepting"
+	shutdownPhaseDrain           = "drain"
+	shutdownPhaseCloseComponents = "close-components"
+
+	inflightWorkCheckInterval = 100 * time.Millisecond
+)
+
+// apiServer is an API server of the runtime, stopped on shutdown.
+type apiServer interface {
+	StopAccepting()
+	InflightRequests() int32
+}
+
+// ShutdownWithWait will gracefully stop runtime and wait outstanding operations
+func (a *DaprRuntime) ShutdownWithWait() {
+	a.shutdown()
+	os.Exit(0)
+}
+
+// shutdown stops the runtime in sequence: it stops accepting new work, waits for the in-flight
+// work of each subsystem up to the configured deadline and then closes the components.
+func (a *DaprRuntime) shutdown() {
+	log.Info("dapr shutting down")
+	a.runShutdownPhase(shutdownPhaseStopAccepting, a.stopAcceptingWork)
+	a.runShutdownPhase(shutdownPhaseDrain, a.drainInflightWork)
+	a.runShutdownPhase(shutdownPhaseCloseComponents, func() {
+		a.shutdownComponents()
+	})
+	security.StopTokenWatches()
+	log.Info("dapr shut down")
+}
+
+func (a *DaprRuntime) runShutdownPhase(phase string, fn func()) {
+	log.Infof("shutdown phase %s started", phase)
+	start := time.Now()
+	fn()
+	elapsed := time.Since(start)
+	diag.DefaultMonitoring.ShutdownPhaseCompleted(phase, elapsed)
+	log.Infof("shutdown phase %s completed in %s", phase, elapsed)
+}
+
+// stopAcceptingWork stops the API servers, rejects the pub/sub messages and input binding events
+// delivered from now on, so that they are redelivered after the restart, and moves the actors to other hosts.
+func (a *DaprRuntime) stopAcceptingWork() {
+	for _, s := range a.apiServers {
+		s.StopAccepting()
+	}
+	if a.shuttingDown.CAS(false, true) {
+		close(a.shutdownC)
+	}
+	if a.actor != nil {
+		a.actor.Drain()
+	}
+}
+
+// drainInflightWork waits for the in-flight work of the subsystems, each up to its own deadline.
+func (a *DaprRuntime) drainInflightWork() {
+	var wg sync.WaitGroup
+	wait := func(subsystem string, timeout time.Duration, done func() bool) {
+		defer wg.Done()
+		if waitUntil(timeout, done) {
+			log.Infof("%s: in-flight work completed", subsystem)
+		} else {
+			log.Warnf("%s: timed out after %s waiting for the in-flight work", subsystem, timeout)
+		}
+	}
+
+	wg.Add(3)
+	go wait("api", a.runtimeConfig.Shutdown.APITimeout, func() bool {
+		for _, s := range a.apiServers {
+			if s.InflightRequests() > 0 {
+				return false
+			}
+		}
+		return true
+	})
+	go wait("pub/sub", a.runtimeConfig.Shutdown.PubSubTimeout, func() bool {
+		return a.inflightPubSubMessages.Load() == 0
+	})
+	go wait("input bindings", a.runtimeConfig.Shutdown.BindingsTimeout, func() bool {
+		return a.inflightBindingEvents.Load() == 0
+	})
+	if a.actor != nil {
+		wg.Add(1)
+		go wait("actors", a.runtimeConfig.Shutdown.ActorsTimeout, func() bool {
+			return a.actor.GetDrainStatus(context.Background()).Completed
+		})
+	}
+	wg.Wait()
+
+	a.stopActor()
+}
+
+// waitUntil polls done until it returns true. It returns false if the timeout elapses first.
+func waitUntil(timeout time.Duration, done func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for !done() {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		time.Sleep(inflightWorkCheckInterval)
+	}
+	return true
+}
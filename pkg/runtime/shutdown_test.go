@@ -0,0 +1,122 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package runtime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	"github.com/dapr/dapr/pkg/modes"
+)
+
+type fakeAPIServer struct {
+	stopped  atomic.Bool
+	inflight atomic.Int32
+}
+
+func (f *fakeAPIServer) StopAccepting() {
+	f.stopped.Store(true)
+}
+
+func (f *fakeAPIServer) InflightRequests() int32 {
+	return f.inflight.Load()
+}
+
+func TestWaitUntil(t *testing.T) {
+	t.Run("done", func(t *testing.T) {
+		assert.True(t, waitUntil(time.Second, func() bool { return true }))
+	})
+
+	t.Run("done before the timeout", func(t *testing.T) {
+		start := time.Now()
+		assert.True(t, waitUntil(time.Second, func() bool { return time.Since(start) > 200*time.Millisecond }))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		start := time.Now()
+		assert.False(t, waitUntil(300*time.Millisecond, func() bool { return false }))
+		assert.True(t, time.Since(start) >= 300*time.Millisecond)
+	})
+}
+
+func TestShutdownSequence(t *testing.T) {
+	t.Run("stop accepting work", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		server := &fakeAPIServer{}
+		rt.apiServers = []apiServer{server}
+		rt.stopAcceptingWork()
+
+		assert.True(t, rt.shuttingDown.Load())
+		assert.True(t, server.stopped.Load())
+	})
+
+	t.Run("wait for in-flight API requests", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		rt.runtimeConfig.Shutdown = ShutdownConfig{
+			APITimeout: time.Second,
+		}
+		httpServer := &fakeAPIServer{}
+		grpcServer := &fakeAPIServer{}
+		rt.apiServers = []apiServer{httpServer, grpcServer}
+		httpServer.inflight.Inc()
+		grpcServer.inflight.Inc()
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			httpServer.inflight.Dec()
+			time.Sleep(100 * time.Millisecond)
+			grpcServer.inflight.Dec()
+		}()
+
+		start := time.Now()
+		rt.drainInflightWork()
+		elapsed := time.Since(start)
+
+		assert.True(t, elapsed >= 200*time.Millisecond)
+		assert.True(t, elapsed < time.Second)
+	})
+
+	t.Run("wait for in-flight pub/sub messages and input binding events", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		rt.runtimeConfig.Shutdown = ShutdownConfig{
+			PubSubTimeout:   time.Second,
+			BindingsTimeout: time.Second,
+		}
+		rt.inflightPubSubMessages.Inc()
+		rt.inflightBindingEvents.Inc()
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			rt.inflightPubSubMessages.Dec()
+			rt.inflightBindingEvents.Dec()
+		}()
+
+		start := time.Now()
+		rt.drainInflightWork()
+		elapsed := time.Since(start)
+
+		assert.True(t, elapsed >= 200*time.Millisecond)
+		assert.True(t, elapsed < time.Second)
+	})
+
+	t.Run("stop waiting at the deadline of each subsystem", func(t *testing.T) {
+		rt := NewTestDaprRuntime(modes.StandaloneMode)
+		rt.runtimeConfig.Shutdown = ShutdownConfig{
+			PubSubTimeout:   300 * time.Millisecond,
+			BindingsTimeout: 100 * time.Millisecond,
+		}
+		rt.inflightPubSubMessages.Inc()
+		rt.inflightBindingEvents.Inc()
+
+		start := time.Now()
+		rt.drainInflightWork()
+		elapsed := time.Since(start)
+
+		assert.True(t, elapsed >= 300*time.Millisecond)
+		assert.True(t, elapsed < time.Second)
+	})
+}
@@ -3,6 +3,7 @@ package ca
 import (
 	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"os"
 	"sync"
@@ -36,15 +37,21 @@ type CertificateAuthority interface {
 	GetCACertBundle() TrustRootBundler
 	SignCSR(csrPem []byte, subject string, identity *identity.Bundle, ttl time.Duration, isCA bool) (*SignedCertificate, error)
 	ValidateCSR(csr *x509.CertificateRequest) error
+	GetRevocationList() ([]byte, error)
 }
 
 func NewCertificateAuthority(config config.SentryConfig) (CertificateAuthority, error) {
 	// Load future external CAs from components-contrib.
 	switch config.CAStore {
 	default:
+		signer, err := NewSigner(config)
+		if err != nil {
+			return nil, err
+		}
 		return &defaultCA{
 			config:     config,
 			issuerLock: &sync.RWMutex{},
+			signer:     signer,
 		}, nil
 	}
 }
@@ -53,11 +60,15 @@ type defaultCA struct {
 	bundle     *trustRootBundle
 	config     config.SentryConfig
 	issuerLock *sync.RWMutex
+	revoked    []pkix.RevokedCertificate
+	signer     Signer
 }
 
 type SignedCertificate struct {
 	Certificate *x509.Certificate
 	CertPEM     []byte
+	// TrustChain holds the PEM encoded certificates of the chain from the issuer of the certificate to the root CA.
+	TrustChain [][]byte
 }
 
 // LoadOrStoreTrustBundle loads the root cert and issuer cert from the configured secret store.
@@ -69,7 +80,24 @@ func (c *defaultCA) LoadOrStoreTrustBundle() error {
 		return err
 	}
 
+	revoked, err := loadRevokedSerials(c.config.RevokedSerialsPath)
+	if err != nil {
+		return err
+	}
+	if len(revoked) > 0 {
+		log.Infof("loaded %d revoked certificate serial numbers", len(revoked))
+	}
+
 	c.bundle = bundle
+	c.revoked = revoked
+	if c.signer == nil {
+		c.signer = &localSigner{bundle: bundle}
+	} else {
+		if err = c.signer.CheckTrustAnchors(bundle.trustAnchors); err != nil {
+			return errors.Wrapf(err, "error checking the trust anchors of the %s signer", c.config.Signer)
+		}
+		log.Infof("workload certificates are issued by the %s signer", c.config.Signer)
+	}
 	return nil
 }
 
@@ -92,15 +120,30 @@ func (c *defaultCA) SignCSR(csrPem []byte, subject string, identity *identity.Bu
 
 	certLifetime += c.config.AllowedClockSkew
 
-	signingCert := c.bundle.issuerCreds.Certificate
-	signingKey := c.bundle.issuerCreds.PrivateKey
-
 	cert, err := certs.ParsePemCSR(csrPem)
 	if err != nil {
 		return nil, errors.Wrap(err, "error parsing csr pem")
 	}
 
-	crtb, err := csr.GenerateCSRCertificate(cert, subject, identity, signingCert, cert.PublicKey, signingKey.Key, certLifetime, isCA)
+	if identity != nil {
+		// Copy the bundle so the configured SAN layout doesn't leak into the caller's identity.
+		withSANs := *identity
+		withSANs.SPIFFEPathTemplate = c.config.SPIFFEPathTemplate
+		withSANs.ExtraURISANs = c.config.ExtraURISANs
+		if c.config.CRLDistributionURL != "" {
+			withSANs.CRLDistributionPoints = []string{c.config.CRLDistributionURL}
+		}
+		identity = &withSANs
+	}
+
+	crtb, trustChain, err := c.signer.Sign(&SignRequest{
+		CSR:      cert,
+		CSRPem:   csrPem,
+		Subject:  subject,
+		Identity: identity,
+		TTL:      certLifetime,
+		IsCA:     isCA,
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "error signing csr")
 	}
@@ -118,6 +161,7 @@ func (c *defaultCA) SignCSR(csrPem []byte, subject string, identity *identity.Bu
 	return &SignedCertificate{
 		Certificate: csrCert,
 		CertPEM:     certPem,
+		TrustChain:  trustChain,
 	}, nil
 }
 
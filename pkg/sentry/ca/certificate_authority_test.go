@@ -131,6 +131,7 @@ func TestSignCSR(t *testing.T) {
 		assert.Nil(t, err)
 		assert.NotNil(t, resp)
 		assert.Equal(t, time.Now().UTC().Add(time.Hour*24+allowedClockSkew).Day(), resp.Certificate.NotAfter.UTC().Day())
+		assert.Equal(t, [][]byte{certAuth.GetCACertBundle().GetIssuerCertPem(), certAuth.GetCACertBundle().GetRootCertPem()}, resp.TrustChain)
 	})
 
 	t.Run("valid csr negative ttl", func(t *testing.T) {
@@ -211,6 +212,64 @@ func TestSignCSR(t *testing.T) {
 			t.Error("SAN extension not found in certificate")
 		}
 	})
+
+	t.Run("custom spiffe path and extra uri sans", func(t *testing.T) {
+		writeTestCredentialsToDisk()
+		defer cleanupCredentials()
+
+		csr := getTestCSR("test.a.com")
+		pk, _ := getECDSAPrivateKey()
+		csrb, _ := x509.CreateCertificateRequest(rand.Reader, csr, pk)
+		certPem := pem.EncodeToMemory(&pem.Block{Type: certs.Certificate, Bytes: csrb})
+
+		conf, _ := config.FromConfigName("")
+		conf.RootCertPath = "./ca.crt"
+		conf.IssuerCertPath = "./issuer.crt"
+		conf.IssuerKeyPath = "./issuer.key"
+		conf.SPIFFEPathTemplate = "/acme/{namespace}/{appID}/workload"
+		conf.ExtraURISANs = []string{"urn:acme:{namespace}:{appID}", "https://{trustDomain}/apps/{appID}"}
+		certAuth, _ := NewCertificateAuthority(conf)
+		certAuth.LoadOrStoreTrustBundle()
+
+		bundle := identity.NewBundle("app", "default", "public")
+		resp, err := certAuth.SignCSR(certPem, "test-subject", bundle, time.Hour*24, false)
+		assert.Nil(t, err)
+		assert.NotNil(t, resp)
+
+		oidSubjectAlternativeName := asn1.ObjectIdentifier{2, 5, 29, 17}
+
+		var uris []string
+		for _, ext := range resp.Certificate.Extensions {
+			if ext.Id.Equal(oidSubjectAlternativeName) {
+				var sequence asn1.RawValue
+				_, err := asn1.Unmarshal(ext.Value, &sequence)
+				assert.NoError(t, err)
+
+				for bytes := sequence.Bytes; len(bytes) > 0; {
+					var rawValue asn1.RawValue
+					var err error
+
+					bytes, err = asn1.Unmarshal(bytes, &rawValue)
+					assert.NoError(t, err)
+
+					// GeneralName uniformResourceIdentifier
+					if rawValue.Tag == 6 {
+						uris = append(uris, string(rawValue.Bytes))
+					}
+				}
+			}
+		}
+
+		assert.Equal(t, []string{
+			"spiffe://public/acme/default/app/workload",
+			"urn:acme:default:app",
+			"https://public/apps/app",
+		}, uris)
+
+		// The bundle passed by the caller is left untouched.
+		assert.Empty(t, bundle.SPIFFEPathTemplate)
+		assert.Empty(t, bundle.ExtraURISANs)
+	})
 }
 
 func TestCACertsGeneration(t *testing.T) {
@@ -0,0 +1,93 @@
+package ca
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// CRLBlockType is the PEM block type of an encoded certificate revocation list.
+	CRLBlockType = "X509 CRL"
+
+	crlValidity = time.Hour * 24
+)
+
+// loadRevokedSerials reads the revoked certificate serial numbers from path.
+// The file holds one hex encoded serial number per line. Blank lines and lines starting with # are ignored.
+// A missing file results in an empty revocation list.
+func loadRevokedSerials(path string) ([]pkix.RevokedCertificate, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "error reading revoked serials")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading revoked serials")
+	}
+
+	revoked := []pkix.RevokedCertificate{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		serial, ok := new(big.Int).SetString(strings.ReplaceAll(line, ":", ""), 16)
+		if !ok {
+			return nil, errors.Errorf("invalid revoked serial number: %s", line)
+		}
+		revoked = append(revoked, pkix.RevokedCertificate{
+			SerialNumber:   serial,
+			RevocationTime: info.ModTime().UTC(),
+		})
+	}
+	return revoked, scanner.Err()
+}
+
+// GetRevocationList returns a PEM encoded CRL signed by the issuer, listing the revoked workload certificates.
+func (c *defaultCA) GetRevocationList() ([]byte, error) {
+	c.issuerLock.RLock()
+	defer c.issuerLock.RUnlock()
+
+	signer, ok := c.bundle.issuerCreds.PrivateKey.Key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("issuer key cannot sign certificate revocation lists")
+	}
+
+	now := time.Now().UTC()
+	crlb, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:              big.NewInt(now.UnixNano()),
+		ThisUpdate:          now,
+		NextUpdate:          now.Add(crlValidity),
+		RevokedCertificates: c.revoked,
+	}, c.bundle.issuerCreds.Certificate, signer)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating certificate revocation list")
+	}
+
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  CRLBlockType,
+		Bytes: crlb,
+	}), nil
+}
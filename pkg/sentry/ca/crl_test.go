@@ -0,0 +1,62 @@
+package ca
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRevokedSerials(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		revoked, err := loadRevokedSerials("./does-not-exist")
+		assert.NoError(t, err)
+		assert.Empty(t, revoked)
+	})
+
+	t.Run("valid serials", func(t *testing.T) {
+		ioutil.WriteFile("revoked-serials", []byte("# compromised\n0a1b\n\nFF:01\n"), 0644) // nolint:gosec
+		defer os.Remove("revoked-serials")
+
+		revoked, err := loadRevokedSerials("revoked-serials")
+		assert.NoError(t, err)
+		assert.Len(t, revoked, 2)
+		assert.Equal(t, int64(0x0a1b), revoked[0].SerialNumber.Int64())
+		assert.Equal(t, int64(0xff01), revoked[1].SerialNumber.Int64())
+	})
+
+	t.Run("invalid serial", func(t *testing.T) {
+		ioutil.WriteFile("revoked-serials", []byte("not-a-serial\n"), 0644) // nolint:gosec
+		defer os.Remove("revoked-serials")
+
+		_, err := loadRevokedSerials("revoked-serials")
+		assert.Error(t, err)
+	})
+}
+
+func TestGetRevocationList(t *testing.T) {
+	writeTestCredentialsToDisk()
+	defer cleanupCredentials()
+	ioutil.WriteFile("revoked-serials", []byte("0a1b\n"), 0644) // nolint:gosec
+	defer os.Remove("revoked-serials")
+
+	certAuth := getTestCertAuth()
+	certAuth.(*defaultCA).config.RevokedSerialsPath = "revoked-serials"
+	assert.NoError(t, certAuth.LoadOrStoreTrustBundle())
+
+	crlPem, err := certAuth.GetRevocationList()
+	assert.NoError(t, err)
+
+	block, _ := pem.Decode(crlPem)
+	assert.NotNil(t, block)
+	assert.Equal(t, CRLBlockType, block.Type)
+
+	crl, err := x509.ParseDERCRL(block.Bytes)
+	assert.NoError(t, err)
+	assert.NoError(t, certAuth.(*defaultCA).bundle.issuerCreds.Certificate.CheckCRLSignature(crl))
+	assert.Len(t, crl.TBSCertList.RevokedCertificates, 1)
+	assert.Equal(t, int64(0x0a1b), crl.TBSCertList.RevokedCertificates[0].SerialNumber.Int64())
+}
@@ -0,0 +1,282 @@
+package ca
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+	"github.com/dapr/dapr/pkg/sentry/config"
+	"github.com/dapr/dapr/pkg/sentry/csr"
+	"github.com/dapr/dapr/pkg/sentry/identity"
+)
+
+const (
+	// VaultSigner is the name of the signer issuing certificates with the sign endpoint of a Vault PKI secrets engine.
+	VaultSigner = "vault"
+
+	vaultTokenHeader   = "X-Vault-Token"
+	vaultSignTimeout   = time.Second * 10
+	vaultResponseLimit = 1 << 20
+)
+
+// SignRequest holds a parsed CSR and the properties of the certificate to issue for it.
+type SignRequest struct {
+	CSR      *x509.CertificateRequest
+	CSRPem   []byte
+	Subject  string
+	Identity *identity.Bundle
+	TTL      time.Duration
+	IsCA     bool
+}
+
+// Signer issues certificates for validated CSRs.
+// The built-in signer uses the issuer credentials of the trust bundle, other signers delegate to an external CA.
+type Signer interface {
+	// Sign returns the DER encoded certificate issued for the request, and the PEM encoded
+	// certificates of the chain from its issuer to the root CA.
+	Sign(req *SignRequest) ([]byte, [][]byte, error)
+	// CheckTrustAnchors checks at startup that the certificates the signer issues chain up to the trust anchors.
+	CheckTrustAnchors(trustAnchors *x509.CertPool) error
+}
+
+// NewSigner returns the external signer configured for Sentry, or nil if certificates are issued by the built-in issuer.
+func NewSigner(conf config.SentryConfig) (Signer, error) {
+	switch strings.ToLower(conf.Signer) {
+	case "":
+		return nil, nil
+	case VaultSigner:
+		if conf.SignerAddress == "" || conf.SignerPath == "" {
+			return nil, errors.New("vault signer requires an address and a sign path")
+		}
+		path := strings.Trim(conf.SignerPath, "/")
+		if !strings.Contains(path, "/sign/") {
+			return nil, errors.Errorf("vault sign path %s is not of the form <mount>/sign/<role>", conf.SignerPath)
+		}
+		return &vaultSigner{
+			address:   strings.TrimSuffix(conf.SignerAddress, "/"),
+			path:      path,
+			tokenPath: conf.SignerTokenPath,
+			client:    &http.Client{Timeout: vaultSignTimeout},
+		}, nil
+	}
+	return nil, errors.Errorf("signer %s is not supported", conf.Signer)
+}
+
+type localSigner struct {
+	bundle *trustRootBundle
+}
+
+func (s *localSigner) Sign(req *SignRequest) ([]byte, [][]byte, error) {
+	crtb, err := csr.GenerateCSRCertificate(req.CSR, req.Subject, req.Identity, s.bundle.issuerCreds.Certificate, req.CSR.PublicKey, s.bundle.issuerCreds.PrivateKey.Key, req.TTL, req.IsCA)
+	if err != nil {
+		return nil, nil, err
+	}
+	return crtb, [][]byte{s.bundle.issuerCertPem, s.bundle.rootCertPem}, nil
+}
+
+func (s *localSigner) CheckTrustAnchors(trustAnchors *x509.CertPool) error {
+	return nil
+}
+
+// vaultSigner issues workload certificates with the sign endpoint of a Vault PKI secrets engine,
+// e.g. pki/sign/dapr. The Vault role must allow the SPIFFE ID and the other URI SANs of the workloads.
+// The CA chain of the PKI mount must end at the root cert of the trust bundle, as the sidecars only
+// trust the certificates chaining up to it. This is checked at startup and for every issued certificate.
+type vaultSigner struct {
+	address      string
+	path         string
+	tokenPath    string
+	client       *http.Client
+	trustAnchors *x509.CertPool
+}
+
+type vaultSignRequest struct {
+	CSR        string `json:"csr"`
+	CommonName string `json:"common_name"`
+	AltNames   string `json:"alt_names,omitempty"`
+	URISANs    string `json:"uri_sans,omitempty"`
+	TTL        string `json:"ttl"`
+}
+
+type vaultSignResponse struct {
+	Data struct {
+		Certificate string   `json:"certificate"`
+		IssuingCA   string   `json:"issuing_ca"`
+		CAChain     []string `json:"ca_chain"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+func (s *vaultSigner) Sign(req *SignRequest) ([]byte, [][]byte, error) {
+	if req.IsCA {
+		return nil, nil, errors.New("vault signer can't issue CA certificates")
+	}
+
+	body := vaultSignRequest{
+		CSR:        string(req.CSRPem),
+		CommonName: req.Subject,
+		TTL:        fmt.Sprintf("%ds", int64(req.TTL.Seconds())),
+	}
+	if req.Identity != nil {
+		spiffeID, err := identity.CreateSPIFFEIDFromTemplate(req.Identity.SPIFFEPathTemplate, req.Identity.TrustDomain, req.Identity.Namespace, req.Identity.ID)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "error generating spiffe id")
+		}
+		uriSANs, err := identity.CreateURISANs(req.Identity.ExtraURISANs, req.Identity.TrustDomain, req.Identity.Namespace, req.Identity.ID)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "error generating uri sans")
+		}
+		body.URISANs = strings.Join(append([]string{spiffeID}, uriSANs...), ",")
+		body.AltNames = fmt.Sprintf("%s.%s.svc.cluster.local", req.Subject, req.Identity.Namespace)
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	httpReq, err := s.newRequest(http.MethodPost, s.path, bytes.NewReader(b))
+	if err != nil {
+		return nil, nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error calling vault")
+	}
+	defer resp.Body.Close()
+
+	var signResp vaultSignResponse
+	if err = json.NewDecoder(io.LimitReader(resp.Body, vaultResponseLimit)).Decode(&signResp); err != nil {
+		return nil, nil, errors.Wrapf(err, "error decoding vault response, status code: %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, errors.Errorf("vault failed to sign the csr, status code: %d, errors: %s", resp.StatusCode, strings.Join(signResp.Errors, "; "))
+	}
+
+	block, _ := pem.Decode([]byte(signResp.Data.Certificate))
+	if block == nil {
+		return nil, nil, errors.New("vault response holds no PEM certificate")
+	}
+
+	// the CA chain holds the issuing CA and its parents when the chain of the PKI mount is configured
+	chainPems := signResp.Data.CAChain
+	if len(chainPems) == 0 {
+		chainPems = []string{signResp.Data.IssuingCA}
+	}
+	chain := make([][]byte, 0, len(chainPems))
+	for _, c := range chainPems {
+		caBlock, _ := pem.Decode([]byte(c))
+		if caBlock == nil {
+			return nil, nil, errors.New("vault response holds no PEM issuing CA certificate")
+		}
+		// re-encoded so that each certificate ends with a newline and the chain can be concatenated
+		chain = append(chain, pem.EncodeToMemory(caBlock))
+	}
+
+	if s.trustAnchors != nil {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "error parsing the certificate issued by vault")
+		}
+		if err = verifyTrustChain(cert, chain, s.trustAnchors); err != nil {
+			return nil, nil, errors.Wrap(err, "certificate issued by vault doesn't chain up to the trust anchors")
+		}
+	}
+	return block.Bytes, chain, nil
+}
+
+// CheckTrustAnchors reads the CA chain of the PKI mount, or its CA when no chain is configured,
+// and checks that it ends at the trust anchors.
+func (s *vaultSigner) CheckTrustAnchors(trustAnchors *x509.CertPool) error {
+	mount := s.path[:strings.LastIndex(s.path, "/sign/")]
+	chainPem, err := s.getPEM(mount + "/ca_chain")
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(chainPem)) == 0 {
+		if chainPem, err = s.getPEM(mount + "/ca/pem"); err != nil {
+			return err
+		}
+	}
+
+	chain, err := certs.DecodePEMCertificates(chainPem)
+	if err != nil {
+		return errors.Wrapf(err, "error decoding the ca chain of vault mount %s", mount)
+	}
+	if len(chain) == 0 {
+		return errors.Errorf("vault mount %s has no ca certificate", mount)
+	}
+	intermediates := make([][]byte, 0, len(chain)-1)
+	for _, c := range chain[1:] {
+		intermediates = append(intermediates, pem.EncodeToMemory(&pem.Block{Type: certs.Certificate, Bytes: c.Raw}))
+	}
+	if err = verifyTrustChain(chain[0], intermediates, trustAnchors); err != nil {
+		return errors.Wrapf(err, "ca chain of vault mount %s doesn't end at the trust anchors, the root cert of the trust bundle must be the root of vault", mount)
+	}
+
+	s.trustAnchors = trustAnchors
+	return nil
+}
+
+// getPEM reads a PEM endpoint of vault, which responds with the PEM encoded certificates in the body.
+func (s *vaultSigner) getPEM(path string) ([]byte, error) {
+	httpReq, err := s.newRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return nil, errors.Wrap(err, "error calling vault")
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, vaultResponseLimit))
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading vault response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("vault failed to return %s, status code: %d", path, resp.StatusCode)
+	}
+	return b, nil
+}
+
+func (s *vaultSigner) newRequest(method, path string, body io.Reader) (*http.Request, error) {
+	httpReq, err := http.NewRequest(method, fmt.Sprintf("%s/v1/%s", s.address, path), body)
+	if err != nil {
+		return nil, err
+	}
+	if s.tokenPath != "" {
+		// the token is read on every request so that rotated tokens are picked up
+		token, err := ioutil.ReadFile(s.tokenPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading vault token")
+		}
+		httpReq.Header.Set(vaultTokenHeader, strings.TrimSpace(string(token)))
+	}
+	return httpReq, nil
+}
+
+// verifyTrustChain verifies a certificate with the PEM encoded certificates of its chain against the trust anchors.
+func verifyTrustChain(cert *x509.Certificate, chain [][]byte, trustAnchors *x509.CertPool) error {
+	intermediates := x509.NewCertPool()
+	for _, c := range chain {
+		intermediates.AppendCertsFromPEM(c)
+	}
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         trustAnchors,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err
+}
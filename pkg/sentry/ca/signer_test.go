@@ -0,0 +1,148 @@
+package ca
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dapr/dapr/pkg/sentry/certs"
+	"github.com/dapr/dapr/pkg/sentry/config"
+	"github.com/dapr/dapr/pkg/sentry/identity"
+)
+
+func TestNewSigner(t *testing.T) {
+	t.Run("built-in issuer", func(t *testing.T) {
+		s, err := NewSigner(config.SentryConfig{})
+		assert.NoError(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("vault without address", func(t *testing.T) {
+		_, err := NewSigner(config.SentryConfig{Signer: VaultSigner})
+		assert.Error(t, err)
+	})
+
+	t.Run("vault path without sign endpoint", func(t *testing.T) {
+		_, err := NewSigner(config.SentryConfig{Signer: VaultSigner, SignerAddress: "http://vault:8200", SignerPath: "/sign/dapr"})
+		assert.Error(t, err)
+	})
+
+	t.Run("unsupported signer", func(t *testing.T) {
+		_, err := NewSigner(config.SentryConfig{Signer: "pca"})
+		assert.Error(t, err)
+	})
+}
+
+func TestVaultSigner(t *testing.T) {
+	writeTestCredentialsToDisk()
+	defer cleanupCredentials()
+
+	// vault is faked with the issuer of the test trust bundle
+	certAuth := getTestCertAuth().(*defaultCA)
+	assert.NoError(t, certAuth.LoadOrStoreTrustBundle())
+	issuer := &localSigner{bundle: certAuth.bundle}
+
+	var received vaultSignRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get(vaultTokenHeader))
+		if r.URL.Path == "/v1/pki/ca_chain" {
+			chain := bytes.Join([][]byte{certAuth.bundle.issuerCertPem, certAuth.bundle.rootCertPem}, []byte("\n"))
+			w.Write(chain) // nolint:errcheck
+			return
+		}
+		assert.Equal(t, "/v1/pki/sign/dapr", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		block, _ := pem.Decode([]byte(received.CSR))
+		csr, err := x509.ParseCertificateRequest(block.Bytes)
+		assert.NoError(t, err)
+		crtb, chain, err := issuer.Sign(&SignRequest{CSR: csr, Subject: received.CommonName, TTL: time.Hour})
+		assert.NoError(t, err)
+
+		resp := vaultSignResponse{}
+		resp.Data.Certificate = string(pem.EncodeToMemory(&pem.Block{Type: certs.Certificate, Bytes: crtb}))
+		// vault returns the certificates of the chain without a trailing newline
+		for _, c := range chain {
+			resp.Data.CAChain = append(resp.Data.CAChain, strings.TrimSpace(string(c)))
+		}
+		resp.Data.IssuingCA = resp.Data.CAChain[0]
+		json.NewEncoder(w).Encode(resp) // nolint:errcheck
+	}))
+	defer server.Close()
+
+	tokenFile, err := ioutil.TempFile("", "token")
+	assert.NoError(t, err)
+	defer os.Remove(tokenFile.Name())
+	tokenFile.WriteString("token\n") // nolint:errcheck
+	tokenFile.Close()
+
+	signer, err := NewSigner(config.SentryConfig{
+		Signer:          VaultSigner,
+		SignerAddress:   server.URL + "/",
+		SignerPath:      "/pki/sign/dapr",
+		SignerTokenPath: tokenFile.Name(),
+	})
+	assert.NoError(t, err)
+	certAuth.signer = signer
+
+	pk, _ := getECDSAPrivateKey()
+	csrb, _ := x509.CreateCertificateRequest(rand.Reader, getTestCSR("test.a.com"), pk)
+	csrPem := pem.EncodeToMemory(&pem.Block{Type: certs.Certificate, Bytes: csrb})
+
+	t.Run("ca chain ends at the trust anchors", func(t *testing.T) {
+		assert.NoError(t, signer.CheckTrustAnchors(certAuth.bundle.trustAnchors))
+	})
+
+	t.Run("ca chain doesn't end at the trust anchors", func(t *testing.T) {
+		assert.Error(t, signer.CheckTrustAnchors(x509.NewCertPool()))
+	})
+
+	t.Run("workload certificate", func(t *testing.T) {
+		bundle := identity.NewBundle("app", "default", "public")
+		resp, err := certAuth.SignCSR(csrPem, "app", bundle, time.Hour, false)
+		assert.NoError(t, err)
+		assert.NotNil(t, resp.Certificate)
+		assert.Equal(t, "app", received.CommonName)
+		assert.True(t, strings.HasPrefix(received.URISANs, "spiffe://public/ns/default/app"))
+		assert.Equal(t, "app.default.svc.cluster.local", received.AltNames)
+
+		// the certificate verifies with the chain returned by vault
+		assert.Equal(t, 2, len(resp.TrustChain))
+		roots := x509.NewCertPool()
+		intermediates := x509.NewCertPool()
+		assert.True(t, intermediates.AppendCertsFromPEM(resp.TrustChain[0]))
+		assert.True(t, roots.AppendCertsFromPEM(resp.TrustChain[1]))
+		_, err = resp.Certificate.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("issued certificate doesn't chain up to the trust anchors", func(t *testing.T) {
+		vault := signer.(*vaultSigner)
+		trustAnchors := vault.trustAnchors
+		vault.trustAnchors = x509.NewCertPool()
+		defer func() { vault.trustAnchors = trustAnchors }()
+
+		_, err := certAuth.SignCSR(csrPem, "app", identity.NewBundle("app", "default", "public"), time.Hour, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("ca certificate", func(t *testing.T) {
+		_, err := certAuth.SignCSR(csrPem, "app", nil, time.Hour, true)
+		assert.Error(t, err)
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	scheme "github.com/dapr/dapr/pkg/client/clientset/versioned"
 	dapr_config "github.com/dapr/dapr/pkg/config"
+	"github.com/dapr/dapr/pkg/sentry/identity"
 	"github.com/dapr/dapr/utils"
 	"github.com/dapr/kit/logger"
 )
@@ -38,6 +39,29 @@ type SentryConfig struct {
 	RootCertPath     string
 	IssuerCertPath   string
 	IssuerKeyPath    string
+	// SPIFFEPathTemplate customizes the path of the SPIFFE ID in workload certs.
+	SPIFFEPathTemplate string
+	// ExtraURISANs are URI SAN templates added to workload certs.
+	ExtraURISANs []string
+	// RevokedSerialsPath is the file listing the serial numbers of revoked workload certs.
+	RevokedSerialsPath string
+	// CRLPort is the port the certificate revocation list is served on. 0 disables it.
+	CRLPort int
+	// CRLDistributionURL is embedded in workload certs so peers know where to fetch the CRL.
+	CRLDistributionURL string
+	// Signer is the external CA issuing workload certs, e.g. vault. Empty uses the built-in issuer.
+	// The root cert of the trust bundle must be the root of the external CA.
+	Signer string
+	// SignerAddress is the address of the external CA.
+	SignerAddress string
+	// SignerPath is the path of the signing endpoint of the external CA, e.g. pki/sign/dapr.
+	SignerPath string
+	// SignerTokenPath is the file holding the token authenticating Sentry with the external CA.
+	SignerTokenPath string
+	// AuditSink is where the audit events of the processed CSRs are written, e.g. stdout. Empty disables them.
+	AuditSink string
+	// Profiling configures the continuous profiling of Sentry.
+	Profiling dapr_config.ProfilingSpec
 }
 
 var configGetters = map[string]func(string) (SentryConfig, error){
@@ -154,5 +178,23 @@ func parseConfiguration(conf SentryConfig, daprConfig *dapr_config.Configuration
 		conf.AllowedClockSkew = d
 	}
 
+	if daprConfig.Spec.MTLSSpec.SPIFFEPathTemplate != "" {
+		if err := identity.ValidateSPIFFEPathTemplate(daprConfig.Spec.MTLSSpec.SPIFFEPathTemplate); err != nil {
+			return conf, errors.Wrap(err, "error parsing SPIFFEPathTemplate")
+		}
+
+		conf.SPIFFEPathTemplate = daprConfig.Spec.MTLSSpec.SPIFFEPathTemplate
+	}
+
+	for _, san := range daprConfig.Spec.MTLSSpec.ExtraURISANs {
+		if err := identity.ValidateURISANTemplate(san); err != nil {
+			return conf, errors.Wrap(err, "error parsing ExtraURISANs")
+		}
+
+		conf.ExtraURISANs = append(conf.ExtraURISANs, san)
+	}
+
+	conf.Profiling = daprConfig.Spec.Profiling
+
 	return conf, nil
 }
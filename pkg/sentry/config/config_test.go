@@ -39,4 +39,36 @@ func TestConfig(t *testing.T) {
 		assert.Equal(t, "5s", conf.WorkloadCertTTL.String())
 		assert.Equal(t, "1h0m0s", conf.AllowedClockSkew.String())
 	})
+	t.Run("parse custom san configuration", func(t *testing.T) {
+		daprConfig := dapr_config.Configuration{
+			Spec: dapr_config.ConfigurationSpec{
+				MTLSSpec: dapr_config.MTLSSpec{
+					Enabled:            true,
+					SPIFFEPathTemplate: "/org/{namespace}/{appID}",
+					ExtraURISANs:       []string{"urn:acme:{appID}"},
+				},
+			},
+		}
+
+		defaultConfig := getDefaultConfig()
+		conf, err := parseConfiguration(defaultConfig, &daprConfig)
+		assert.Nil(t, err)
+		assert.Equal(t, "/org/{namespace}/{appID}", conf.SPIFFEPathTemplate)
+		assert.Equal(t, []string{"urn:acme:{appID}"}, conf.ExtraURISANs)
+	})
+
+	t.Run("parse invalid spiffe path template", func(t *testing.T) {
+		daprConfig := dapr_config.Configuration{
+			Spec: dapr_config.ConfigurationSpec{
+				MTLSSpec: dapr_config.MTLSSpec{
+					Enabled:            true,
+					SPIFFEPathTemplate: "org/{appID}",
+				},
+			},
+		}
+
+		defaultConfig := getDefaultConfig()
+		_, err := parseConfiguration(defaultConfig, &daprConfig)
+		assert.Error(t, err)
+	})
 }
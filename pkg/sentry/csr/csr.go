@@ -22,6 +22,8 @@ const (
 	blockTypePrivateKey   = "PRIVATE KEY"    // PKCS#8 plain private key
 	encodeMsgCSR          = "CERTIFICATE REQUEST"
 	encodeMsgCert         = "CERTIFICATE"
+
+	uriSANTag = 6 // GeneralName uniformResourceIdentifier
 )
 
 var (
@@ -143,11 +145,16 @@ func GenerateCSRCertificate(csr *x509.CertificateRequest, subject string, identi
 	cert.SignatureAlgorithm = csr.SignatureAlgorithm
 
 	if identityBundle != nil {
-		spiffeID, err := identity.CreateSPIFFEID(identityBundle.TrustDomain, identityBundle.Namespace, identityBundle.ID)
+		spiffeID, err := identity.CreateSPIFFEIDFromTemplate(identityBundle.SPIFFEPathTemplate, identityBundle.TrustDomain, identityBundle.Namespace, identityBundle.ID)
 		if err != nil {
 			return nil, errors.Wrap(err, "error generating spiffe id")
 		}
 
+		uriSANs, err := identity.CreateURISANs(identityBundle.ExtraURISANs, identityBundle.TrustDomain, identityBundle.Namespace, identityBundle.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "error generating uri sans")
+		}
+
 		rv := []asn1.RawValue{
 			{
 				Bytes: []byte(spiffeID),
@@ -160,12 +167,21 @@ func GenerateCSRCertificate(csr *x509.CertificateRequest, subject string, identi
 				Tag:   2,
 			},
 		}
+		for _, san := range uriSANs {
+			rv = append(rv, asn1.RawValue{
+				Bytes: []byte(san),
+				Class: asn1.ClassContextSpecific,
+				Tag:   uriSANTag,
+			})
+		}
 
 		b, err := asn1.Marshal(rv)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to marshal asn1 raw value for spiffe id")
 		}
 
+		cert.CRLDistributionPoints = identityBundle.CRLDistributionPoints
+
 		cert.ExtraExtensions = append(cert.ExtraExtensions, pkix.Extension{
 			Id:       oidSubjectAlternativeName,
 			Value:    b,
@@ -5,6 +5,12 @@ type Bundle struct {
 	ID          string
 	Namespace   string
 	TrustDomain string
+	// SPIFFEPathTemplate, when set, overrides the default SPIFFE ID path layout.
+	SPIFFEPathTemplate string
+	// ExtraURISANs holds URI SAN templates added to the issued certificate alongside the SPIFFE ID.
+	ExtraURISANs []string
+	// CRLDistributionPoints lists the URLs peers use to fetch the revocation list for the issued certificate.
+	CRLDistributionPoints []string
 }
 
 // NewBundle returns a new identity bundle.
@@ -2,13 +2,29 @@ package identity
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/pkg/errors"
 )
 
+const (
+	// DefaultSPIFFEPathTemplate is the path layout used for SPIFFE IDs when no custom template is configured.
+	DefaultSPIFFEPathTemplate = "/ns/{namespace}/{appID}"
+
+	trustDomainPlaceholder = "{trustDomain}"
+	namespacePlaceholder   = "{namespace}"
+	appIDPlaceholder       = "{appID}"
+)
+
 // CreateSPIFFEID returns a SPIFFE standard unique id for the given trust domain, namespace and appID.
 func CreateSPIFFEID(trustDomain, namespace, appID string) (string, error) {
+	return CreateSPIFFEIDFromTemplate(DefaultSPIFFEPathTemplate, trustDomain, namespace, appID)
+}
+
+// CreateSPIFFEIDFromTemplate returns a SPIFFE ID for the given trust domain, namespace and appID
+// using pathTemplate to lay out the path component. An empty template falls back to the default layout.
+func CreateSPIFFEIDFromTemplate(pathTemplate, trustDomain, namespace, appID string) (string, error) {
 	if trustDomain == "" {
 		return "", errors.New("can't create spiffe id: trust domain is empty")
 	}
@@ -27,9 +43,102 @@ func CreateSPIFFEID(trustDomain, namespace, appID string) (string, error) {
 		return "", errors.New("trust domain cannot exceed 255 bytes")
 	}
 
-	id := fmt.Sprintf("spiffe://%s/ns/%s/%s", trustDomain, namespace, appID)
+	if pathTemplate == "" {
+		pathTemplate = DefaultSPIFFEPathTemplate
+	}
+	if pathTemplate != DefaultSPIFFEPathTemplate {
+		if err := ValidateSPIFFEPathTemplate(pathTemplate); err != nil {
+			return "", err
+		}
+		if err := validateIdentitySegments(namespace, appID); err != nil {
+			return "", errors.Wrap(err, "can't create spiffe id")
+		}
+	}
+
+	id := fmt.Sprintf("spiffe://%s%s", trustDomain, expandTemplate(pathTemplate, trustDomain, namespace, appID))
 	if len([]byte(id)) > 2048 {
 		return "", errors.New("spiffe id cannot exceed 2048 bytes")
 	}
 	return id, nil
 }
+
+// CreateURISANs expands the given URI SAN templates for a workload identity.
+// Templates may reference the {trustDomain}, {namespace} and {appID} placeholders.
+func CreateURISANs(templates []string, trustDomain, namespace, appID string) ([]string, error) {
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	if err := validateIdentitySegments(namespace, appID); err != nil {
+		return nil, errors.Wrap(err, "can't create uri sans")
+	}
+
+	sans := make([]string, 0, len(templates))
+	for _, t := range templates {
+		san := expandTemplate(t, trustDomain, namespace, appID)
+		if err := validateURISAN(san); err != nil {
+			return nil, err
+		}
+		sans = append(sans, san)
+	}
+	return sans, nil
+}
+
+// ValidateSPIFFEPathTemplate checks that a SPIFFE path template is well formed and uniquely identifies a workload.
+// Sidecars read the namespace and app id from the second and third path segments of a peer's SPIFFE ID,
+// so the template may only customize the first segment and append literal segments after the app id.
+func ValidateSPIFFEPathTemplate(pathTemplate string) error {
+	if !strings.HasPrefix(pathTemplate, "/") {
+		return errors.Errorf("spiffe path template %q must start with /", pathTemplate)
+	}
+
+	segments := strings.Split(pathTemplate[1:], "/")
+	if len(segments) < 3 || segments[1] != namespacePlaceholder || segments[2] != appIDPlaceholder {
+		return errors.Errorf("spiffe path template %q must have the form /<prefix>/%s/%s", pathTemplate, namespacePlaceholder, appIDPlaceholder)
+	}
+	for i, segment := range segments {
+		if segment == "" || segment == "." || segment == ".." {
+			return errors.Errorf("spiffe path template %q contains an invalid path segment", pathTemplate)
+		}
+		if i != 1 && i != 2 && strings.ContainsAny(segment, "{}") {
+			return errors.Errorf("spiffe path template %q can only reference %s and %s once each", pathTemplate, namespacePlaceholder, appIDPlaceholder)
+		}
+	}
+	return nil
+}
+
+// ValidateURISANTemplate checks that a URI SAN template expands to an absolute URI.
+func ValidateURISANTemplate(sanTemplate string) error {
+	return validateURISAN(expandTemplate(sanTemplate, "td", "ns", "app"))
+}
+
+func validateURISAN(san string) error {
+	u, err := url.Parse(san)
+	if err != nil {
+		return errors.Wrapf(err, "invalid uri san %q", san)
+	}
+	if u.Scheme == "" {
+		return errors.Errorf("invalid uri san %q: missing scheme", san)
+	}
+	if strings.EqualFold(u.Scheme, "spiffe") {
+		return errors.Errorf("invalid uri san %q: spiffe ids must be configured via the path template", san)
+	}
+	return nil
+}
+
+// validateIdentitySegments makes sure the requesting identity can't escape its position in a templated URI.
+func validateIdentitySegments(namespace, appID string) error {
+	for _, v := range []string{namespace, appID} {
+		if v == "." || v == ".." || strings.ContainsAny(v, "/{}?#%") {
+			return errors.Errorf("identity %q contains characters not allowed in a uri path", v)
+		}
+	}
+	return nil
+}
+
+func expandTemplate(template, trustDomain, namespace, appID string) string {
+	return strings.NewReplacer(
+		trustDomainPlaceholder, trustDomain,
+		namespacePlaceholder, namespace,
+		appIDPlaceholder, appID,
+	).Replace(template)
+}
@@ -59,4 +59,72 @@ func TestSPIFFEID(t *testing.T) {
 		assert.Error(t, err)
 		assert.Empty(t, id)
 	})
+	t.Run("custom path template", func(t *testing.T) {
+		id, err := CreateSPIFFEIDFromTemplate("/acme/{namespace}/{appID}/workload", "td1", "ns1", "app1")
+		assert.NoError(t, err)
+		assert.Equal(t, "spiffe://td1/acme/ns1/app1/workload", id)
+	})
+
+	t.Run("empty path template uses default", func(t *testing.T) {
+		id, err := CreateSPIFFEIDFromTemplate("", "td1", "ns1", "app1")
+		assert.NoError(t, err)
+		assert.Equal(t, "spiffe://td1/ns/ns1/app1", id)
+	})
+
+	t.Run("path template missing app id", func(t *testing.T) {
+		id, err := CreateSPIFFEIDFromTemplate("/ns/{namespace}", "td1", "ns1", "app1")
+		assert.Error(t, err)
+		assert.Empty(t, id)
+	})
+
+	t.Run("path template moving the namespace and app id", func(t *testing.T) {
+		id, err := CreateSPIFFEIDFromTemplate("/org/acme/{namespace}/workload/{appID}", "td1", "ns1", "app1")
+		assert.Error(t, err)
+		assert.Empty(t, id)
+	})
+
+	t.Run("path template with colliding placeholders", func(t *testing.T) {
+		for _, tmpl := range []string{
+			"/{namespace}{appID}",
+			"/x-{namespace}-{appID}",
+			"/ns/{namespace}{appID}/x",
+			"/ns/{namespace}/{appID}/{appID}",
+		} {
+			assert.Error(t, ValidateSPIFFEPathTemplate(tmpl), tmpl)
+		}
+	})
+
+	t.Run("app id escaping a custom path", func(t *testing.T) {
+		id, err := CreateSPIFFEIDFromTemplate("/acme/{namespace}/{appID}", "td1", "ns1", "../app1")
+		assert.Error(t, err)
+		assert.Empty(t, id)
+	})
+
+	t.Run("default path keeps accepting special characters", func(t *testing.T) {
+		id, err := CreateSPIFFEID("td1", "ns1", "app%{1}")
+		assert.NoError(t, err)
+		assert.Equal(t, "spiffe://td1/ns/ns1/app%{1}", id)
+	})
+}
+
+func TestURISANs(t *testing.T) {
+	t.Run("no templates", func(t *testing.T) {
+		sans, err := CreateURISANs(nil, "td1", "ns1", "app1")
+		assert.NoError(t, err)
+		assert.Empty(t, sans)
+	})
+
+	t.Run("expands placeholders", func(t *testing.T) {
+		sans, err := CreateURISANs([]string{"urn:acme:{trustDomain}:{namespace}:{appID}"}, "td1", "ns1", "app1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"urn:acme:td1:ns1:app1"}, sans)
+	})
+
+	t.Run("rejects spiffe uris", func(t *testing.T) {
+		assert.Error(t, ValidateURISANTemplate("spiffe://td1/{appID}"))
+	})
+
+	t.Run("rejects relative uris", func(t *testing.T) {
+		assert.Error(t, ValidateURISANTemplate("/{namespace}/{appID}"))
+	})
 }
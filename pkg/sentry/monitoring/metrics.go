@@ -32,13 +32,23 @@ var (
 		"sentry/issuercert/changed_total",
 		"The number of issuer cert updates, when issuer cert or key is changed",
 		stats.UnitDimensionless)
+	certSignDecisionTotal = stats.Int64(
+		"sentry/cert/sign/decision_total",
+		"The number of CSRs processed, by decision and identity validator.",
+		stats.UnitDimensionless)
 	issuerCertExpiryTimestamp = stats.Int64(
 		"sentry/issuercert/expiry_timestamp",
 		"The unix timestamp, in seconds, when issuer/root cert will expire.",
 		stats.UnitDimensionless)
+	serverCertExpiryTimestamp = stats.Int64(
+		"sentry/servercert/expiry_timestamp",
+		"The unix timestamp, in seconds, when the server TLS cert of sentry will expire.",
+		stats.UnitDimensionless)
 
 	// Metrics Tags
 	failedReasonKey = tag.MustNewKey("reason")
+	decisionKey     = tag.MustNewKey("decision")
+	validatorKey    = tag.MustNewKey("validator")
 	noKeys          = []tag.Key{}
 )
 
@@ -60,11 +70,24 @@ func CertSignFailed(reason string) {
 		certSignFailedTotal.M(1))
 }
 
+// CertSignDecision counts the CSRs processed by decision and identity validator.
+func CertSignDecision(decision, validator string) {
+	stats.RecordWithTags(
+		context.Background(),
+		diag_utils.WithTags(decisionKey, decision, validatorKey, validator),
+		certSignDecisionTotal.M(1))
+}
+
 // IssuerCertExpiry records root cert expiry
 func IssuerCertExpiry(expiry time.Time) {
 	stats.Record(context.Background(), issuerCertExpiryTimestamp.M(expiry.Unix()))
 }
 
+// ServerCertExpiry records the server TLS cert expiry
+func ServerCertExpiry(expiry time.Time) {
+	stats.Record(context.Background(), serverCertExpiryTimestamp.M(expiry.Unix()))
+}
+
 // ServerCertIssueFailed records server cert issue failure.
 func ServerCertIssueFailed(reason string) {
 	stats.Record(context.Background(), serverTLSCertIssueFailedTotal.M(1))
@@ -81,8 +104,10 @@ func InitMetrics() error {
 		diag_utils.NewMeasureView(csrReceivedTotal, noKeys, view.Count()),
 		diag_utils.NewMeasureView(certSignSuccessTotal, noKeys, view.Count()),
 		diag_utils.NewMeasureView(certSignFailedTotal, []tag.Key{failedReasonKey}, view.Count()),
+		diag_utils.NewMeasureView(certSignDecisionTotal, []tag.Key{decisionKey, validatorKey}, view.Count()),
 		diag_utils.NewMeasureView(serverTLSCertIssueFailedTotal, []tag.Key{failedReasonKey}, view.Count()),
 		diag_utils.NewMeasureView(issuerCertChangedTotal, noKeys, view.Count()),
 		diag_utils.NewMeasureView(issuerCertExpiryTimestamp, noKeys, view.LastValue()),
+		diag_utils.NewMeasureView(serverCertExpiryTimestamp, noKeys, view.LastValue()),
 	)
 }
@@ -50,14 +50,23 @@ func (s *sentry) Run(ctx context.Context, conf config.SentryConfig, readyCh chan
 	monitoring.IssuerCertExpiry(certAuth.GetCACertBundle().GetIssuerCertExpiry())
 
 	// Create identity validator
-	v, err := createValidator()
+	v, validatorName, err := createValidator()
 	if err != nil {
 		log.Fatalf("error creating validator: %s", err)
 	}
 	log.Info("validator created")
 
+	var auditSink server.AuditSink
+	if conf.AuditSink != "" {
+		auditSink, err = server.NewAuditSink(conf.AuditSink)
+		if err != nil {
+			log.Fatalf("error creating audit sink: %s", err)
+		}
+		log.Infof("certificate issuance audit log written to %s", conf.AuditSink)
+	}
+
 	// Run the CA server
-	s.server = server.NewCAServer(certAuth, v)
+	s.server = server.NewCAServer(certAuth, v, validatorName, auditSink)
 
 	go func() {
 		<-ctx.Done()
@@ -65,6 +74,14 @@ func (s *sentry) Run(ctx context.Context, conf config.SentryConfig, readyCh chan
 		s.server.Shutdown() // nolint: errcheck
 	}()
 
+	if conf.CRLPort > 0 {
+		go func() {
+			if err := s.server.ServeCRL(conf.CRLPort); err != nil {
+				log.Errorf("error serving certificate revocation list: %s", err)
+			}
+		}()
+	}
+
 	if readyCh != nil {
 		readyCh <- true
 		s.reloading = false
@@ -77,16 +94,17 @@ func (s *sentry) Run(ctx context.Context, conf config.SentryConfig, readyCh chan
 	}
 }
 
-func createValidator() (identity.Validator, error) {
+// createValidator returns the identity validator of the hosting environment and its name.
+func createValidator() (identity.Validator, string, error) {
 	if config.IsKubernetesHosted() {
 		// we're in Kubernetes, create client and init a new serviceaccount token validator
 		kubeClient, err := k8s.GetClient()
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to create kubernetes client")
+			return nil, "", errors.Wrap(err, "failed to create kubernetes client")
 		}
-		return kubernetes.NewValidator(kubeClient), nil
+		return kubernetes.NewValidator(kubeClient), "kubernetes", nil
 	}
-	return selfhosted.NewValidator(), nil
+	return selfhosted.NewValidator(), "selfhosted", nil
 }
 
 func (s *sentry) Restart(ctx context.Context, conf config.SentryConfig) {
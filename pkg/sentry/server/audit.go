@@ -0,0 +1,64 @@
+package server
+
+import (
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dapr/dapr/pkg/jsonlines"
+)
+
+const (
+	// StdoutAuditSink is the audit sink writing the events to stdout as JSON lines.
+	StdoutAuditSink = jsonlines.StdoutSink
+
+	// AuditDecisionIssued is the decision of the CSRs a certificate was issued for.
+	AuditDecisionIssued = "issued"
+	// AuditDecisionDenied is the decision of the CSRs no certificate was issued for.
+	AuditDecisionDenied = "denied"
+)
+
+// AuditEvent records the decision taken by Sentry for a CSR.
+type AuditEvent struct {
+	Time        time.Time  `json:"time"`
+	ID          string     `json:"id"`
+	Namespace   string     `json:"namespace"`
+	TrustDomain string     `json:"trustDomain"`
+	Peer        string     `json:"peer,omitempty"`
+	Validator   string     `json:"validator"`
+	Decision    string     `json:"decision"`
+	Reason      string     `json:"reason,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Serial      string     `json:"serial,omitempty"`
+	ValidUntil  *time.Time `json:"validUntil,omitempty"`
+}
+
+// AuditSink receives the audit events of the CSRs processed by Sentry.
+type AuditSink interface {
+	WriteAuditEvent(event AuditEvent)
+}
+
+type jsonAuditSink struct {
+	writer *jsonlines.Writer
+}
+
+// NewAuditSink returns the audit sink with the given name.
+func NewAuditSink(name string) (AuditSink, error) {
+	writer, ok := jsonlines.NewSinkWriter(name)
+	if !ok {
+		return nil, errors.Errorf("audit sink %s is not supported", name)
+	}
+	return &jsonAuditSink{writer: writer}, nil
+}
+
+// NewJSONAuditSink returns an audit sink writing the events to w as JSON lines.
+func NewJSONAuditSink(w io.Writer) AuditSink {
+	return &jsonAuditSink{writer: jsonlines.NewWriter(w)}
+}
+
+func (s *jsonAuditSink) WriteAuditEvent(event AuditEvent) {
+	if err := s.writer.Write(event); err != nil {
+		log.Errorf("error writing audit event: %s", err)
+	}
+}
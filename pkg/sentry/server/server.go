@@ -3,13 +3,18 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"net"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	sentryv1pb "github.com/dapr/dapr/pkg/proto/sentry/v1"
@@ -23,6 +28,9 @@ import (
 
 const (
 	serverCertExpiryBuffer = time.Minute * 15
+
+	// CRLPath is the HTTP path the certificate revocation list is served on.
+	CRLPath = "/crl"
 )
 
 var log = logger.NewLogger("dapr.sentry.server")
@@ -30,21 +38,31 @@ var log = logger.NewLogger("dapr.sentry.server")
 // CAServer is an interface for the Certificate Authority server
 type CAServer interface {
 	Run(port int, trustBundle ca.TrustRootBundler) error
+	ServeCRL(port int) error
 	Shutdown()
 }
 
 type server struct {
-	certificate *tls.Certificate
-	certAuth    ca.CertificateAuthority
-	srv         *grpc.Server
-	validator   identity.Validator
+	certificate   *tls.Certificate
+	certAuth      ca.CertificateAuthority
+	srv           *grpc.Server
+	crlSrv        *http.Server
+	crlLock       *sync.Mutex
+	shutdown      bool
+	validator     identity.Validator
+	validatorName string
+	auditSink     AuditSink
 }
 
-// NewCAServer returns a new CA Server running a gRPC server
-func NewCAServer(ca ca.CertificateAuthority, validator identity.Validator) CAServer {
+// NewCAServer returns a new CA Server running a gRPC server.
+// The decisions taken for the CSRs are written to auditSink, if not nil.
+func NewCAServer(ca ca.CertificateAuthority, validator identity.Validator, validatorName string, auditSink AuditSink) CAServer {
 	return &server{
-		certAuth:  ca,
-		validator: validator,
+		certAuth:      ca,
+		crlLock:       &sync.Mutex{},
+		validator:     validator,
+		validatorName: validatorName,
+		auditSink:     auditSink,
 	}
 }
 
@@ -107,13 +125,15 @@ func (s *server) getServerCertificate() (*tls.Certificate, error) {
 	}
 
 	certPem := resp.CertPEM
-	certPem = append(certPem, s.certAuth.GetCACertBundle().GetIssuerCertPem()...)
-	certPem = append(certPem, s.certAuth.GetCACertBundle().GetRootCertPem()...)
+	for _, c := range resp.TrustChain {
+		certPem = append(certPem, c...)
+	}
 
 	cert, err := tls.X509KeyPair(certPem, pkPem)
 	if err != nil {
 		return nil, err
 	}
+	monitoring.ServerCertExpiry(resp.Certificate.NotAfter)
 
 	return &cert, nil
 }
@@ -124,15 +144,21 @@ func (s *server) getServerCertificate() (*tls.Certificate, error) {
 func (s *server) SignCertificate(ctx context.Context, req *sentryv1pb.SignCertificateRequest) (*sentryv1pb.SignCertificateResponse, error) {
 	monitoring.CertSignRequestRecieved()
 
+	resp, reason, err := s.signCertificate(req)
+	s.audit(ctx, req, resp, reason, err)
+	return resp, err
+}
+
+// signCertificate returns the signed certificate for the request, or the reason and error of the failure.
+func (s *server) signCertificate(req *sentryv1pb.SignCertificateRequest) (*sentryv1pb.SignCertificateResponse, string, error) {
 	csrPem := req.GetCertificateSigningRequest()
 
 	csr, err := certs.ParsePemCSR(csrPem)
-
 	if err != nil {
 		err = errors.Wrap(err, "cannot parse certificate signing request pem")
 		log.Error(err)
 		monitoring.CertSignFailed("cert_parse")
-		return nil, err
+		return nil, "cert_parse", err
 	}
 
 	err = s.certAuth.ValidateCSR(csr)
@@ -140,7 +166,7 @@ func (s *server) SignCertificate(ctx context.Context, req *sentryv1pb.SignCertif
 		err = errors.Wrap(err, "error validating csr")
 		log.Error(err)
 		monitoring.CertSignFailed("cert_validation")
-		return nil, err
+		return nil, "cert_validation", err
 	}
 
 	err = s.validator.Validate(req.GetId(), req.GetToken(), req.GetNamespace())
@@ -148,7 +174,7 @@ func (s *server) SignCertificate(ctx context.Context, req *sentryv1pb.SignCertif
 		err = errors.Wrap(err, "error validating requester identity")
 		log.Error(err)
 		monitoring.CertSignFailed("req_id_validation")
-		return nil, err
+		return nil, "req_id_validation", err
 	}
 
 	identity := identity.NewBundle(csr.Subject.CommonName, req.GetNamespace(), req.GetTrustDomain())
@@ -157,40 +183,113 @@ func (s *server) SignCertificate(ctx context.Context, req *sentryv1pb.SignCertif
 		err = errors.Wrap(err, "error signing csr")
 		log.Error(err)
 		monitoring.CertSignFailed("cert_sign")
-		return nil, err
+		return nil, "cert_sign", err
 	}
 
+	// the chain is the one of the signer, which differs from the trust bundle of Sentry with an external CA
 	certPem := signed.CertPEM
-	issuerCert := s.certAuth.GetCACertBundle().GetIssuerCertPem()
-	rootCert := s.certAuth.GetCACertBundle().GetRootCertPem()
-
-	certPem = append(certPem, issuerCert...)
-	certPem = append(certPem, rootCert...)
+	for _, c := range signed.TrustChain {
+		certPem = append(certPem, c...)
+	}
 
 	if len(certPem) == 0 {
 		err = errors.New("insufficient data in certificate signing request, no certs signed")
 		log.Error(err)
 		monitoring.CertSignFailed("insufficient_data")
-		return nil, err
+		return nil, "insufficient_data", err
 	}
 
 	expiry := timestamppb.New(signed.Certificate.NotAfter)
 	if err = expiry.CheckValid(); err != nil {
-		return nil, errors.Wrap(err, "could not validate certificate validity")
+		return nil, "cert_validity", errors.Wrap(err, "could not validate certificate validity")
 	}
 
 	resp := &sentryv1pb.SignCertificateResponse{
 		WorkloadCertificate:    certPem,
-		TrustChainCertificates: [][]byte{issuerCert, rootCert},
+		TrustChainCertificates: signed.TrustChain,
 		ValidUntil:             expiry,
 	}
 
 	monitoring.CertSignSucceed()
 
-	return resp, nil
+	return resp, "", nil
+}
+
+// audit writes the decision taken for a CSR to the audit sink and counts it by outcome.
+func (s *server) audit(ctx context.Context, req *sentryv1pb.SignCertificateRequest, resp *sentryv1pb.SignCertificateResponse, reason string, err error) {
+	event := AuditEvent{
+		Time:        time.Now().UTC(),
+		ID:          req.GetId(),
+		Namespace:   req.GetNamespace(),
+		TrustDomain: req.GetTrustDomain(),
+		Validator:   s.validatorName,
+		Decision:    AuditDecisionIssued,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		event.Peer = p.Addr.String()
+	}
+	if err != nil {
+		event.Decision = AuditDecisionDenied
+		event.Reason = reason
+		event.Error = err.Error()
+	} else {
+		validUntil := resp.GetValidUntil().AsTime()
+		event.ValidUntil = &validUntil
+		if block, _ := pem.Decode(resp.GetWorkloadCertificate()); block != nil {
+			if cert, parseErr := x509.ParseCertificate(block.Bytes); parseErr == nil {
+				event.Serial = cert.SerialNumber.String()
+			}
+		}
+	}
+
+	monitoring.CertSignDecision(event.Decision, s.validatorName)
+	if s.auditSink != nil {
+		s.auditSink.WriteAuditEvent(event)
+	}
+}
+
+// ServeCRL starts an HTTP server distributing the issuer signed certificate revocation list.
+// The list is public information, so the endpoint is served without client authentication.
+func (s *server) ServeCRL(port int) error {
+	router := http.NewServeMux()
+	router.HandleFunc(CRLPath, func(w http.ResponseWriter, r *http.Request) {
+		crl, err := s.certAuth.GetRevocationList()
+		if err != nil {
+			log.Errorf("error generating certificate revocation list: %s", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pkix-crl")
+		w.Write(crl) // nolint: errcheck
+	})
+
+	crlSrv := &http.Server{
+		Addr:    fmt.Sprintf(":%v", port),
+		Handler: router,
+	}
+	s.crlLock.Lock()
+	if s.shutdown {
+		s.crlLock.Unlock()
+		return nil
+	}
+	s.crlSrv = crlSrv
+	s.crlLock.Unlock()
+
+	log.Infof("certificate revocation list is served on %s%s", crlSrv.Addr, CRLPath)
+	if err := crlSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return errors.Wrap(err, "crl serve error")
+	}
+	return nil
 }
 
 func (s *server) Shutdown() {
+	s.crlLock.Lock()
+	s.shutdown = true
+	if s.crlSrv != nil {
+		s.crlSrv.Close() // nolint: errcheck
+	}
+	s.crlLock.Unlock()
 	s.srv.Stop()
 }
 
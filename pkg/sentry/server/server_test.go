@@ -0,0 +1,102 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	sentryv1pb "github.com/dapr/dapr/pkg/proto/sentry/v1"
+	"github.com/dapr/dapr/pkg/sentry/ca"
+	"github.com/dapr/dapr/pkg/sentry/certs"
+	"github.com/dapr/dapr/pkg/sentry/config"
+)
+
+type auditRecorder struct {
+	events []AuditEvent
+}
+
+func (r *auditRecorder) WriteAuditEvent(event AuditEvent) {
+	r.events = append(r.events, event)
+}
+
+type denyValidator struct{}
+
+func (denyValidator) Validate(id, token, namespace string) error {
+	return errors.New("invalid token")
+}
+
+func TestSignCertificateAudit(t *testing.T) {
+	certAuth, err := ca.NewCertificateAuthority(config.SentryConfig{})
+	assert.NoError(t, err)
+
+	t.Run("invalid csr", func(t *testing.T) {
+		recorder := &auditRecorder{}
+		s := NewCAServer(certAuth, denyValidator{}, "test", recorder).(*server)
+
+		_, err := s.SignCertificate(context.Background(), &sentryv1pb.SignCertificateRequest{
+			Id:                        "app",
+			Namespace:                 "default",
+			CertificateSigningRequest: []byte("csr"),
+		})
+
+		assert.Error(t, err)
+		assert.Len(t, recorder.events, 1)
+		event := recorder.events[0]
+		assert.Equal(t, AuditDecisionDenied, event.Decision)
+		assert.Equal(t, "cert_parse", event.Reason)
+		assert.Equal(t, "app", event.ID)
+		assert.Equal(t, "default", event.Namespace)
+		assert.Equal(t, "test", event.Validator)
+	})
+
+	t.Run("invalid requester identity", func(t *testing.T) {
+		pk, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		csrb, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "app"}}, pk)
+		assert.NoError(t, err)
+
+		recorder := &auditRecorder{}
+		s := NewCAServer(certAuth, denyValidator{}, "test", recorder).(*server)
+
+		_, err = s.SignCertificate(context.Background(), &sentryv1pb.SignCertificateRequest{
+			Id:                        "app",
+			CertificateSigningRequest: pem.EncodeToMemory(&pem.Block{Type: certs.Certificate, Bytes: csrb}),
+		})
+
+		assert.Error(t, err)
+		assert.Len(t, recorder.events, 1)
+		assert.Equal(t, "req_id_validation", recorder.events[0].Reason)
+		assert.Contains(t, recorder.events[0].Error, "invalid token")
+	})
+}
+
+func TestJSONAuditSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONAuditSink(&buf)
+
+	sink.WriteAuditEvent(AuditEvent{ID: "app", Decision: AuditDecisionIssued})
+
+	var event AuditEvent
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &event))
+	assert.Equal(t, "app", event.ID)
+	assert.Equal(t, AuditDecisionIssued, event.Decision)
+}
+
+func TestNewAuditSink(t *testing.T) {
+	sink, err := NewAuditSink("stdout")
+	assert.NoError(t, err)
+	assert.NotNil(t, sink)
+
+	_, err = NewAuditSink("otlp")
+	assert.Error(t, err)
+}
@@ -234,3 +234,99 @@ func (_m *MockActors) GetActiveActorsCount(ctx context.Context) []actors.ActiveA
 		},
 	}
 }
+
+// IsPlacementConnected provides a mock function with given fields:
+func (_m *MockActors) IsPlacementConnected() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// IsPlacementTableReady provides a mock function with given fields:
+func (_m *MockActors) IsPlacementTableReady() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// GetActorStateKey provides a mock function with given fields: storeName, actorType, actorID, key
+func (_m *MockActors) GetActorStateKey(storeName string, actorType string, actorID string, key string) (string, error) {
+	ret := _m.Called(storeName, actorType, actorID, key)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string, string, string, string) string); ok {
+		r0 = rf(storeName, actorType, actorID, key)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, string) error); ok {
+		r1 = rf(storeName, actorType, actorID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Drain provides a mock function with given fields:
+func (_m *MockActors) Drain() {
+	_m.Called()
+}
+
+// Undrain provides a mock function with given fields:
+func (_m *MockActors) Undrain() {
+	_m.Called()
+}
+
+// GetDrainStatus provides a mock function with given fields: ctx
+func (_m *MockActors) GetDrainStatus(ctx context.Context) actors.DrainStatus {
+	ret := _m.Called(ctx)
+
+	var r0 actors.DrainStatus
+	if rf, ok := ret.Get(0).(func(context.Context) actors.DrainStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(actors.DrainStatus)
+	}
+
+	return r0
+}
+
+// Broadcast provides a mock function with given fields: req
+func (_m *MockActors) Broadcast(ctx context.Context, req *actors.BroadcastRequest) (*actors.BroadcastResponse, error) {
+	ret := _m.Called(req)
+
+	var r0 *actors.BroadcastResponse
+	if rf, ok := ret.Get(0).(func(*actors.BroadcastRequest) *actors.BroadcastResponse); ok {
+		r0 = rf(req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*actors.BroadcastResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*actors.BroadcastRequest) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,174 @@
+// ------------------------------------------------------------
+// Copyright (c) Microsoft Corporation and Dapr Contributors.
+// Licensed under the MIT License.
+// ------------------------------------------------------------
+
+package testing
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/dapr/components-contrib/state"
+)
+
+// FakeETagStateStore is an in-memory transactional state store enforcing ETags and first-write concurrency.
+type FakeETagStateStore struct {
+	lock sync.Mutex
+	// Items holds the saved values, ETags their versions and Metadata the metadata they were saved with.
+	Items    map[string][]byte
+	ETags    map[string]int
+	Metadata map[string]map[string]string
+	// NoETag makes the store advertise no ETag nor transaction support.
+	NoETag bool
+	// Err fails all the operations, MultiErr the transactions.
+	Err      error
+	MultiErr error
+	// BeforeSet is called before each Set, outside the lock of the store.
+	BeforeSet func()
+}
+
+// NewFakeETagStateStore returns an empty FakeETagStateStore.
+func NewFakeETagStateStore() *FakeETagStateStore {
+	return &FakeETagStateStore{
+		Items:    map[string][]byte{},
+		ETags:    map[string]int{},
+		Metadata: map[string]map[string]string{},
+	}
+}
+
+func (f *FakeETagStateStore) Init(metadata state.Metadata) error {
+	return nil
+}
+
+func (f *FakeETagStateStore) Features() []state.Feature {
+	if f.NoETag {
+		return nil
+	}
+	return []state.Feature{state.FeatureETag, state.FeatureTransactional}
+}
+
+func (f *FakeETagStateStore) Get(req *state.GetRequest) (*state.GetResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	data, ok := f.Items[req.Key]
+	if !ok {
+		return &state.GetResponse{}, nil
+	}
+	etag := strconv.Itoa(f.ETags[req.Key])
+	return &state.GetResponse{Data: data, ETag: &etag}, nil
+}
+
+func (f *FakeETagStateStore) Set(req *state.SetRequest) error {
+	if f.BeforeSet != nil {
+		f.BeforeSet()
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.set(req)
+}
+
+func (f *FakeETagStateStore) set(req *state.SetRequest) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	if err := f.checkETag(req.Key, req.ETag, req.Options.Concurrency); err != nil {
+		return err
+	}
+	data, err := json.Marshal(req.Value)
+	if err != nil {
+		return err
+	}
+	f.Items[req.Key] = data
+	f.ETags[req.Key]++
+	f.Metadata[req.Key] = req.Metadata
+	return nil
+}
+
+func (f *FakeETagStateStore) Delete(req *state.DeleteRequest) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.delete(req)
+}
+
+func (f *FakeETagStateStore) delete(req *state.DeleteRequest) error {
+	if f.Err != nil {
+		return f.Err
+	}
+	if err := f.checkETag(req.Key, req.ETag, req.Options.Concurrency); err != nil {
+		return err
+	}
+	delete(f.Items, req.Key)
+	return nil
+}
+
+func (f *FakeETagStateStore) checkETag(key string, etag *string, concurrency string) error {
+	_, exists := f.Items[key]
+	if etag != nil {
+		if !exists || *etag != strconv.Itoa(f.ETags[key]) {
+			return state.NewETagError(state.ETagMismatch, nil)
+		}
+	} else if exists && concurrency == "first-write" {
+		return state.NewETagError(state.ETagMismatch, nil)
+	}
+	return nil
+}
+
+func (f *FakeETagStateStore) BulkGet(req []state.GetRequest) (bool, []state.BulkGetResponse, error) {
+	return false, nil, nil
+}
+
+func (f *FakeETagStateStore) BulkSet(req []state.SetRequest) error {
+	for i := range req {
+		if err := f.Set(&req[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *FakeETagStateStore) BulkDelete(req []state.DeleteRequest) error {
+	for i := range req {
+		if err := f.Delete(&req[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *FakeETagStateStore) Multi(request *state.TransactionalStateRequest) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.MultiErr != nil {
+		return f.MultiErr
+	}
+	for _, o := range request.Operations {
+		var err error
+		switch req := o.Request.(type) {
+		case state.SetRequest:
+			err = f.set(&req)
+		case state.DeleteRequest:
+			err = f.delete(&req)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Has returns whether the store holds a value for the key.
+func (f *FakeETagStateStore) Has(key string) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	_, ok := f.Items[key]
+	return ok
+}